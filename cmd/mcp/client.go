@@ -0,0 +1,435 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// errToolFailed is returned when a called tool returns an error result
+var errToolFailed = errors.New("tool returned an error")
+
+// clientCommand is a subcommand sending requests to a server
+type clientCommand struct {
+	// usage describes the arguments after the flags
+	usage string
+	// args is the maximum number of arguments. In the shell, the last one
+	// takes the rest of the line, so JSON arguments may contain spaces.
+	args int
+	run  func(ctx context.Context, c *client.Client, out *output, args []string) error
+}
+
+// clientCommands are the subcommands talking to a server as a client, also
+// available in the shell
+var clientCommands = map[string]*clientCommand{
+	"tools":     {usage: "", run: listTools},
+	"resources": {usage: "", run: listResources},
+	"templates": {usage: "", run: listTemplates},
+	"prompts":   {usage: "", run: listPrompts},
+	"call":      {usage: "<tool> [json arguments]", args: 2, run: callTool},
+	"read":      {usage: "<uri>", args: 1, run: readResource},
+	"prompt":    {usage: "<name> [json arguments]", args: 2, run: getPrompt},
+}
+
+// clientConfig holds the flags of the client subcommands
+type clientConfig struct {
+	url     string
+	headers headerFlags
+	timeout time.Duration
+	json    bool
+	verbose bool
+	command []string
+}
+
+// headerFlags collects repeated -header flags
+type headerFlags http.Header
+
+func (h headerFlags) String() string {
+	return fmt.Sprint(http.Header(h))
+}
+
+func (h headerFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("header %q is not in the form Name: value", value)
+	}
+	http.Header(h).Add(strings.TrimSpace(key), strings.TrimSpace(val))
+	return nil
+}
+
+// runClient runs a client subcommand, or the shell reading commands from
+// stdin, against the server named by the -url flag or the command after
+// "--", printing results to stdout
+func runClient(name string, args []string, stdin io.Reader, stdout io.Writer) error {
+	config := &clientConfig{headers: headerFlags{}}
+	if i := slices.Index(args, "--"); i >= 0 {
+		args, config.command = args[:i], args[i+1:]
+	}
+
+	command := clientCommands[name]
+	usage := ""
+	if command != nil {
+		usage = command.usage
+	}
+
+	fs := flag.NewFlagSet("mcp "+name, flag.ContinueOnError)
+	config.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: mcp %s [flags] %s [-- server command]\n", name, usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	out := &output{w: stdout, json: config.json}
+	if command != nil && fs.NArg() > command.args {
+		fs.Usage()
+		return fmt.Errorf("too many arguments")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.timeout)
+	c, err := config.connect(ctx, name == "shell")
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if command == nil {
+		return runShell(c, config, out, stdin)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), config.timeout)
+	defer cancel()
+	return command.run(ctx, c, out, fs.Args())
+}
+
+// register defines the flags of config on fs
+func (config *clientConfig) register(fs *flag.FlagSet) {
+	fs.StringVar(&config.url, "url", "", "URL of the server: http(s):// for streamable HTTP, ws(s):// for WebSocket or tcp://host:port")
+	fs.Var(config.headers, "header", "HTTP header sent to the server, as \"Name: value\" (repeatable)")
+	fs.DurationVar(&config.timeout, "timeout", 30*time.Second, "Timeout of each request")
+	fs.BoolVar(&config.json, "json", false, "Print results as JSON")
+	fs.BoolVar(&config.verbose, "v", false, "Show the stderr of the server command and the notifications it sends")
+}
+
+// connect connects to the server and initializes the session. Server
+// notifications are printed to stderr in verbose mode or in the shell.
+func (config *clientConfig) connect(ctx context.Context, shell bool) (*client.Client, error) {
+	var options []client.Option
+	if config.verbose || shell {
+		options = append(options, client.WithNotificationHandler(func(method string, params json.RawMessage) {
+			fmt.Fprintf(os.Stderr, "<- %s %s\n", method, params)
+		}))
+	}
+
+	conn, err := config.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.Connect(ctx, conn, options...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// dial opens a connection to the server
+func (config *clientConfig) dial(ctx context.Context) (client.Conn, error) {
+	switch {
+	case config.url != "" && len(config.command) > 0:
+		return nil, fmt.Errorf("give either -url or a server command, not both")
+	case len(config.command) > 0:
+		cmd := exec.Command(config.command[0], config.command[1:]...)
+		if config.verbose {
+			cmd.Stderr = os.Stderr
+		}
+		return client.NewCommandConn(cmd)
+	case config.url == "":
+		return nil, fmt.Errorf("no server: give -url or a server command after --")
+	}
+
+	u, err := url.Parse(config.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+	header := http.Header(config.headers)
+	switch u.Scheme {
+	case "http", "https":
+		return client.NewStreamableHTTPConn(config.url, nil, header), nil
+	case "ws", "wss":
+		return client.DialWebSocket(ctx, config.url, header)
+	case "tcp":
+		return client.DialTCP(ctx, u.Host)
+	default:
+		return nil, fmt.Errorf("unsupported server URL scheme %q", u.Scheme)
+	}
+}
+
+// runShell reads commands from in until it ends or exit is entered
+func runShell(c *client.Client, config *clientConfig, out *output, in io.Reader) error {
+	info := c.InitializeResult().ServerInfo
+	fmt.Fprintf(out.w, "Connected to %s %s. Type help for the commands.\n", info.Name, info.Version)
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for {
+		fmt.Fprint(out.w, "mcp> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out.w)
+			return scanner.Err()
+		}
+
+		name, rest, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		switch name {
+		case "":
+			continue
+		case "exit", "quit":
+			return nil
+		case "help":
+			printShellHelp(out.w)
+			continue
+		}
+
+		command, ok := clientCommands[name]
+		if !ok {
+			fmt.Fprintf(out.w, "unknown command %q; type help for the commands\n", name)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), config.timeout)
+		err := command.run(ctx, c, out, splitArgs(rest, command.args))
+		cancel()
+		if err != nil {
+			fmt.Fprintf(out.w, "error: %v\n", err)
+		}
+		select {
+		case <-c.Done():
+			return fmt.Errorf("connection to the server closed")
+		default:
+		}
+	}
+}
+
+// printShellHelp lists the commands of the shell
+func printShellHelp(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, name := range []string{"tools", "resources", "templates", "prompts", "call", "read", "prompt"} {
+		fmt.Fprintln(tw, "  "+strings.TrimSpace(name+" "+clientCommands[name].usage))
+	}
+	fmt.Fprintln(tw, "  exit")
+	tw.Flush()
+}
+
+// splitArgs splits a shell line into at most n arguments, the last taking
+// the rest of the line
+func splitArgs(line string, n int) []string {
+	var args []string
+	for line = strings.TrimSpace(line); line != "" && len(args) < n-1; line = strings.TrimSpace(line) {
+		var arg string
+		arg, line, _ = strings.Cut(line, " ")
+		args = append(args, arg)
+	}
+	if line != "" && n > 0 {
+		args = append(args, line)
+	}
+	return args
+}
+
+// listTools prints the server's tools
+func listTools(ctx context.Context, c *client.Client, out *output, args []string) error {
+	tools, err := c.Tools(ctx)
+	if err != nil {
+		return err
+	}
+	return out.table(tools, len(tools), func(row func(...string)) {
+		for _, tool := range tools {
+			row(tool.Name, tool.Description)
+		}
+	})
+}
+
+// listResources prints the server's resources
+func listResources(ctx context.Context, c *client.Client, out *output, args []string) error {
+	resources, err := c.Resources(ctx)
+	if err != nil {
+		return err
+	}
+	return out.table(resources, len(resources), func(row func(...string)) {
+		for _, resource := range resources {
+			row(resource.URI, resource.MimeType, resource.Description)
+		}
+	})
+}
+
+// listTemplates prints the server's resource templates
+func listTemplates(ctx context.Context, c *client.Client, out *output, args []string) error {
+	templates, err := c.ResourceTemplates(ctx)
+	if err != nil {
+		return err
+	}
+	return out.table(templates, len(templates), func(row func(...string)) {
+		for _, template := range templates {
+			row(template.URITemplate, template.MimeType, template.Description)
+		}
+	})
+}
+
+// listPrompts prints the server's prompts with their arguments
+func listPrompts(ctx context.Context, c *client.Client, out *output, args []string) error {
+	prompts, err := c.Prompts(ctx)
+	if err != nil {
+		return err
+	}
+	return out.table(prompts, len(prompts), func(row func(...string)) {
+		for _, prompt := range prompts {
+			var names []string
+			for _, arg := range prompt.Arguments {
+				names = append(names, arg.Name)
+			}
+			row(prompt.Name, strings.Join(names, ","), prompt.Description)
+		}
+	})
+}
+
+// callTool calls a tool with a JSON object of arguments and prints its
+// content
+func callTool(ctx context.Context, c *client.Client, out *output, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing tool name")
+	}
+	var arguments map[string]interface{}
+	if len(args) > 1 {
+		if err := json.Unmarshal([]byte(args[1]), &arguments); err != nil {
+			return fmt.Errorf("arguments must be a JSON object: %w", err)
+		}
+	}
+
+	result, err := c.CallTool(ctx, args[0], arguments)
+	if err != nil {
+		return err
+	}
+	if out.json {
+		err = out.printJSON(result)
+	} else {
+		err = out.content(result.Content)
+		if err == nil && result.StructuredContent != nil && len(result.Content) == 0 {
+			err = out.printJSON(result.StructuredContent)
+		}
+	}
+	if err == nil && result.IsError {
+		err = errToolFailed
+	}
+	return err
+}
+
+// readResource reads a resource and prints its contents
+func readResource(ctx context.Context, c *client.Client, out *output, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing resource URI")
+	}
+	result, err := c.ReadResource(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if out.json {
+		return out.printJSON(result)
+	}
+	return out.content(result.Contents)
+}
+
+// getPrompt gets a prompt with a JSON object of string arguments and
+// prints its messages
+func getPrompt(ctx context.Context, c *client.Client, out *output, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing prompt name")
+	}
+	var arguments map[string]string
+	if len(args) > 1 {
+		if err := json.Unmarshal([]byte(args[1]), &arguments); err != nil {
+			return fmt.Errorf("arguments must be a JSON object of strings: %w", err)
+		}
+	}
+
+	result, err := c.GetPrompt(ctx, args[0], arguments)
+	if err != nil {
+		return err
+	}
+	if out.json {
+		return out.printJSON(result)
+	}
+	for _, message := range result.Messages {
+		fmt.Fprintf(out.w, "[%s]\n", message.Role)
+		if err := out.content([]interface{}{message.Content}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// output prints results for people, or as JSON
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+// printJSON prints v as indented JSON
+func (o *output) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(o.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// table prints rows aligned in columns, or v as JSON
+func (o *output) table(v interface{}, n int, rows func(row func(...string))) error {
+	if o.json {
+		return o.printJSON(v)
+	}
+	if n == 0 {
+		fmt.Fprintln(o.w, "(none)")
+		return nil
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	rows(func(columns ...string) {
+		fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	})
+	return tw.Flush()
+}
+
+// content prints text content and resource contents as is, and other
+// content as JSON
+func (o *output) content(items []interface{}) error {
+	for _, item := range items {
+		var err error
+		switch item := item.(type) {
+		case protocol.TextContent:
+			_, err = fmt.Fprintln(o.w, item.Text)
+		case protocol.TextResourceContents:
+			_, err = fmt.Fprintln(o.w, item.Text)
+		default:
+			err = o.printJSON(item)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
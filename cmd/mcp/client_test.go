@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/transport"
+)
+
+// testServerEnv makes the test binary serve testServer over stdio, so the
+// client subcommands can run it as their server command
+const testServerEnv = "MCP_CLIENT_TEST_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testServerEnv) == "1" {
+		serveTestServer()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// serveTestServer serves a server with a tool, resource, template and
+// prompt of each kind over stdio until stdin is closed
+func serveTestServer() {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := server.NewServer("cli-test", server.WithLogger(logger))
+	srv.AddTool("greet", func(name string) string { return "Hello, " + name + "!" }, "Greets a person")
+	srv.AddTool("fail", func() (string, error) { return "", errors.New("out of coffee") }, "Always fails")
+	srv.AddResource("info://about", func() string { return "About this server" }, "About the server")
+	srv.AddResource("users://{id}", func(id string) string { return "User " + id }, "A user")
+	srv.AddPrompt("summarize", func(text string) string { return "Summarize: " + text }, "Summarizes text")
+
+	session := srv.NewSession(context.Background())
+	if err := transport.NewStdioTransport(session, transport.WithLogger(logger)).Start(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// runTestClient runs a client subcommand against the test server, returning
+// what it printed
+func runTestClient(t *testing.T, name string, args []string, stdin string) (string, error) {
+	t.Helper()
+	t.Setenv(testServerEnv, "1")
+
+	var out strings.Builder
+	args = append(append(args, "--"), os.Args[0])
+	err := runClient(name, args, strings.NewReader(stdin), &out)
+	return out.String(), err
+}
+
+func TestClientCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"tools", nil, []string{"greet  Greets a person", "fail   Always fails"}},
+		{"resources", nil, []string{"info://about", "About the server"}},
+		{"templates", nil, []string{"users://{id}", "A user"}},
+		{"prompts", nil, []string{"summarize", "Summarizes text"}},
+		{"call", []string{"greet", `{"arg0": "Ann"}`}, []string{"Hello, Ann!"}},
+		{"read", []string{"info://about"}, []string{"About this server"}},
+		{"read", []string{"users://42"}, []string{"User 42"}},
+		{"prompt", []string{"summarize", `{"arg0": "Go is fun."}`}, []string{"[assistant]", "Summarize: Go is fun."}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(append([]string{tt.name}, tt.args...), " "), func(t *testing.T) {
+			out, err := runTestClient(t, tt.name, tt.args, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected output containing %q, got:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestClientCommandsJSON(t *testing.T) {
+	out, err := runTestClient(t, "call", []string{"-json", "greet", `{"arg0": "Ann"}`}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil || len(result.Content) != 1 || result.Content[0].Text != "Hello, Ann!" {
+		t.Errorf("expected the result as JSON, got %s (%v)", out, err)
+	}
+
+	out, err = runTestClient(t, "tools", []string{"-json"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tools []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &tools); err != nil || len(tools) != 2 {
+		t.Errorf("expected the tools as JSON, got %s (%v)", out, err)
+	}
+}
+
+func TestClientCommandErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"call", []string{"fail"}, errToolFailed.Error()},
+		{"call", []string{"greet", "not json"}, "arguments must be a JSON object"},
+		{"call", nil, "missing tool name"},
+		{"read", nil, "missing resource URI"},
+		{"prompt", nil, "missing prompt name"},
+		{"read", []string{"info://about", "extra"}, "too many arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(append([]string{tt.name}, tt.args...), " "), func(t *testing.T) {
+			_, err := runTestClient(t, tt.name, tt.args, "")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// The failing tool's error text is still printed
+	if out, _ := runTestClient(t, "call", []string{"fail"}, ""); !strings.Contains(out, "out of coffee") {
+		t.Errorf("expected the tool's error text, got %q", out)
+	}
+
+	if err := runClient("tools", nil, nil, io.Discard); err == nil || !strings.Contains(err.Error(), "no server") {
+		t.Errorf("expected an error without a server, got %v", err)
+	}
+	if err := runClient("tools", []string{"-url", "tcp://localhost:1", "--", "server"}, nil, io.Discard); err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("expected an error with both a URL and a command, got %v", err)
+	}
+}
+
+func TestShell(t *testing.T) {
+	stdin := strings.Join([]string{
+		"help",
+		"tools",
+		`call greet {"arg0": "Ann Lee"}`,
+		"read",
+		"dance",
+		"",
+		"exit",
+		"tools",
+	}, "\n")
+	out, err := runTestClient(t, "shell", nil, stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Connected to cli-test",
+		"call <tool> [json arguments]",
+		"greet  Greets a person",
+		"Hello, Ann Lee!",
+		"error: missing resource URI",
+		`unknown command "dance"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output containing %q, got:\n%s", want, out)
+		}
+	}
+	// Nothing runs after exit
+	if strings.Count(out, "greet  Greets a person") != 1 {
+		t.Errorf("expected the shell to stop at exit, got:\n%s", out)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		n    int
+		want []string
+	}{
+		{"", 2, nil},
+		{"greet", 2, []string{"greet"}},
+		{`greet {"arg0": "Ann Lee"}`, 2, []string{"greet", `{"arg0": "Ann Lee"}`}},
+		{"  info://about  ", 1, []string{"info://about"}},
+		{"ignored", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := splitArgs(tt.line, tt.n); strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("splitArgs(%q, %d) = %q, want %q", tt.line, tt.n, got, tt.want)
+		}
+	}
+}
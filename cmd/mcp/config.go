@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// defaultCommandTimeout bounds command tools without a configured timeout
+const defaultCommandTimeout = 30 * time.Second
+
+// Config declares a server's tools and resources, so servers wrapping
+// existing commands and files need no Go code. Configs are JSON; YAML is
+// not supported.
+type Config struct {
+	Name         string           `json:"name,omitempty"`
+	Instructions string           `json:"instructions,omitempty"`
+	Tools        []ToolConfig     `json:"tools,omitempty"`
+	Resources    []ResourceConfig `json:"resources,omitempty"`
+}
+
+// ToolConfig declares a tool running a command. Each element of Command is
+// a text/template executed with the tool arguments, such as
+// "{{.path}}"; the command runs without a shell, so argument values are
+// never interpreted by one. Optional arguments left out of a call expand to
+// the empty string, and templates may only refer to declared arguments.
+type ToolConfig struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Command     []string         `json:"command"`
+	Arguments   []ArgumentConfig `json:"arguments,omitempty"`
+	Dir         string           `json:"dir,omitempty"`
+	Timeout     string           `json:"timeout,omitempty"`
+}
+
+// ArgumentConfig declares a tool argument
+type ArgumentConfig struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// ResourceConfig declares the files matching a glob as resources, served
+// under file:// URIs of their absolute paths
+type ResourceConfig struct {
+	Glob        string `json:"glob"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// loadConfig reads a JSON config file. YAML files are rejected up front,
+// rather than failing with a JSON syntax error.
+func loadConfig(path string) (*Config, error) {
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		return nil, fmt.Errorf("config %s: YAML is not supported, write the config as JSON", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// apply registers the configured tools and resources on srv
+func (c *Config) apply(srv *server.Server) error {
+	for _, tool := range c.Tools {
+		if err := tool.register(srv); err != nil {
+			return fmt.Errorf("tool %q: %w", tool.Name, err)
+		}
+	}
+	for _, resource := range c.Resources {
+		if err := resource.register(srv); err != nil {
+			return fmt.Errorf("resource %q: %w", resource.Glob, err)
+		}
+	}
+	return nil
+}
+
+// register adds the tool to srv
+func (t ToolConfig) register(srv *server.Server) error {
+	if len(t.Command) == 0 {
+		return errors.New("command must not be empty")
+	}
+
+	templates := make([]*template.Template, len(t.Command))
+	for i, arg := range t.Command {
+		tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return fmt.Errorf("invalid command template: %w", err)
+		}
+		templates[i] = tmpl
+	}
+
+	// Check the templates refer to declared arguments only
+	if _, err := t.expand(templates, nil); err != nil {
+		return err
+	}
+
+	timeout := defaultCommandTimeout
+	if t.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(t.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+	}
+
+	properties := make(map[string]interface{}, len(t.Arguments))
+	required := []string{}
+	for _, arg := range t.Arguments {
+		argType := arg.Type
+		if argType == "" {
+			argType = "string"
+		}
+		property := map[string]interface{}{"type": argType}
+		if arg.Description != "" {
+			property["description"] = arg.Description
+		}
+		properties[arg.Name] = property
+		if arg.Required {
+			required = append(required, arg.Name)
+		}
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+
+	handler := func(ctx context.Context, arguments map[string]interface{}) (protocol.CallToolResult, error) {
+		for _, name := range required {
+			if _, ok := arguments[name]; !ok {
+				return protocol.CallToolResult{}, fmt.Errorf("missing required argument %q", name)
+			}
+		}
+
+		argv, err := t.expand(templates, arguments)
+		if err != nil {
+			return protocol.CallToolResult{}, err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+		cmd.Dir = t.Dir
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				err = fmt.Errorf("command timed out after %v", timeout)
+			}
+			text := err.Error()
+			if stderr.Len() > 0 {
+				text += "\n" + stderr.String()
+			}
+			return protocol.CallToolResult{
+				Content: []interface{}{protocol.NewTextContent(text)},
+				IsError: true,
+			}, nil
+		}
+
+		return protocol.CallToolResult{
+			Content: []interface{}{protocol.NewTextContent(stdout.String())},
+		}, nil
+	}
+
+	return srv.AddTool(t.Name, server.ToolHandlerFunc(handler), t.Description, server.WithInputSchema(schema))
+}
+
+// expand executes the command templates with arguments, in which declared
+// arguments left out are empty
+func (t ToolConfig) expand(templates []*template.Template, arguments map[string]interface{}) ([]string, error) {
+	data := make(map[string]interface{}, len(t.Arguments))
+	for _, arg := range t.Arguments {
+		data[arg.Name] = ""
+	}
+	for name, value := range arguments {
+		data[name] = value
+	}
+
+	argv := make([]string, len(templates))
+	for i, tmpl := range templates {
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to build command: %w", err)
+		}
+		argv[i] = buf.String()
+	}
+	return argv, nil
+}
+
+// register adds a provider serving the matching files to srv
+func (r ResourceConfig) register(srv *server.Server) error {
+	glob, err := filepath.Abs(r.Glob)
+	if err != nil {
+		return err
+	}
+	if _, err := filepath.Match(glob, ""); err != nil {
+		return fmt.Errorf("invalid glob: %w", err)
+	}
+	srv.AddResourceProvider(&globProvider{glob: glob, config: r})
+	return nil
+}
+
+// globProvider serves the files matching a glob as resources
+type globProvider struct {
+	glob   string
+	config ResourceConfig
+}
+
+// List returns a resource for every regular file matching the glob
+func (p *globProvider) List(ctx context.Context) ([]protocol.Resource, error) {
+	paths, err := filepath.Glob(p.glob)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []protocol.Resource
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		mimeType := p.config.MimeType
+		if mimeType == "" {
+			mimeType = mime.TypeByExtension(filepath.Ext(path))
+		}
+		size := info.Size()
+		resources = append(resources, protocol.Resource{
+			URI:         "file://" + filepath.ToSlash(path),
+			Name:        filepath.Base(path),
+			Description: p.config.Description,
+			MimeType:    mimeType,
+			Size:        &size,
+		})
+	}
+	return resources, nil
+}
+
+// Read returns the contents of a file matching the glob
+func (p *globProvider) Read(ctx context.Context, uri string) (interface{}, error) {
+	path, ok := strings.CutPrefix(uri, "file://")
+	if !ok {
+		return nil, server.ErrResourceNotFound
+	}
+	path = filepath.FromSlash(path)
+	if path != filepath.Clean(path) {
+		return nil, server.ErrResourceNotFound
+	}
+	if matched, _ := filepath.Match(p.glob, path); !matched {
+		return nil, server.ErrResourceNotFound
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, server.ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if utf8.Valid(data) {
+		return string(data), nil
+	}
+	return data, nil
+}
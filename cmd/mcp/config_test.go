@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// configServer creates a server with the tools and resources of config
+func configServer(t *testing.T, config *Config) *server.Server {
+	t.Helper()
+	srv := server.NewServer("config")
+	if err := config.apply(srv); err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func TestConfigCommandTools(t *testing.T) {
+	for _, command := range []string{"echo", "sh", "sleep"} {
+		if _, err := exec.LookPath(command); err != nil {
+			t.Skipf("%s not found", command)
+		}
+	}
+
+	srv := configServer(t, &Config{Tools: []ToolConfig{
+		{
+			Name:    "echo",
+			Command: []string{"echo", "{{.text}}", "[{{.suffix}}]"},
+			Arguments: []ArgumentConfig{
+				{Name: "text", Required: true},
+				{Name: "suffix"},
+			},
+		},
+		{Name: "fail", Command: []string{"sh", "-c", "echo oops >&2; exit 3"}},
+		{Name: "slow", Command: []string{"sleep", "5"}, Timeout: "50ms"},
+	}})
+	c := mcptest.NewClient(t, srv)
+
+	c.ExpectToolResult("echo", map[string]interface{}{"text": "hi", "suffix": "!"}, "hi [!]\n")
+
+	// Optional arguments left out are empty
+	c.ExpectToolResult("echo", map[string]interface{}{"text": "hi"}, "hi []\n")
+
+	// Argument values are not interpreted by a shell
+	c.ExpectToolResult("echo", map[string]interface{}{"text": "$HOME; ls"}, "$HOME; ls []\n")
+
+	c.ExpectToolError("echo", map[string]interface{}{}, `missing required argument "text"`)
+	c.ExpectToolError("fail", nil, "oops")
+	c.ExpectToolError("slow", nil, "timed out")
+
+	tools := c.Tools()
+	if len(tools) != 3 {
+		t.Fatalf("expected 3 tools, got %d", len(tools))
+	}
+	for _, tool := range tools {
+		if tool.Name == "echo" {
+			schema := tool.InputSchema
+			if required, _ := schema["required"].([]interface{}); len(required) != 1 || required[0] != "text" {
+				t.Errorf("expected text to be required, got %v", schema["required"])
+			}
+		}
+	}
+}
+
+func TestConfigGlobResources(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0o644)
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte{0xff, 0xfe}, 0o644)
+	os.WriteFile(filepath.Join(dir, "c.md"), []byte("# c"), 0o644)
+	os.Mkdir(filepath.Join(dir, "d.txt"), 0o755)
+
+	srv := configServer(t, &Config{Resources: []ResourceConfig{
+		{Glob: filepath.Join(dir, "*.txt"), Description: "Text files"},
+	}})
+	c := mcptest.NewClient(t, srv)
+
+	// Only the regular files matching the glob are listed
+	resources, err := c.Client.Resources(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 resources, got %+v", resources)
+	}
+	for _, resource := range resources {
+		if !strings.HasPrefix(resource.URI, "file://") || resource.Description != "Text files" {
+			t.Errorf("unexpected resource %+v", resource)
+		}
+		if resource.Name == "a.txt" && !strings.HasPrefix(resource.MimeType, "text/plain") {
+			t.Errorf("expected a text/plain MIME type, got %q", resource.MimeType)
+		}
+	}
+
+	uri := "file://" + filepath.ToSlash(filepath.Join(dir, "a.txt"))
+	if text, ok := c.ReadResource(uri).Contents[0].(protocol.TextResourceContents); !ok || text.Text != "hello" {
+		t.Errorf("expected the file's text, got %+v", text)
+	}
+	binary := c.ReadResource("file://" + filepath.ToSlash(filepath.Join(dir, "b.txt"))).Contents[0]
+	if _, ok := binary.(protocol.BlobResourceContents); !ok {
+		t.Errorf("expected binary contents as a blob, got %+v", binary)
+	}
+
+	// Files outside the glob are not served
+	for _, path := range []string{"c.md", "../a.txt", "x/../a.txt"} {
+		uri := "file://" + filepath.ToSlash(dir) + "/" + path
+		if _, err := c.Client.ReadResource(context.Background(), uri); err == nil {
+			t.Errorf("expected %s not to be served", uri)
+		}
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{"empty command", Config{Tools: []ToolConfig{{Name: "t"}}}, "command must not be empty"},
+		{"invalid template", Config{Tools: []ToolConfig{{Name: "t", Command: []string{"echo", "{{.x"}}}}, "invalid command template"},
+		{"undeclared argument", Config{Tools: []ToolConfig{{Name: "t", Command: []string{"echo", "{{.x}}"}}}}, "failed to build command"},
+		{"invalid timeout", Config{Tools: []ToolConfig{{Name: "t", Command: []string{"true"}, Timeout: "soon"}}}, "invalid timeout"},
+		{"invalid glob", Config{Resources: []ResourceConfig{{Glob: "["}}}, "invalid glob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.apply(server.NewServer("config"))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	os.WriteFile(path, []byte(`{"name": "files", "tools": [{"name": "date", "command": ["date"]}]}`), 0o644)
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Name != "files" || len(config.Tools) != 1 || config.Tools[0].Command[0] != "date" {
+		t.Errorf("unexpected config %+v", config)
+	}
+
+	// Unknown fields are rejected rather than silently ignored
+	os.WriteFile(path, []byte(`{"tools": [{"name": "date", "cmd": ["date"]}]}`), 0o644)
+	if _, err := loadConfig(path); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	if _, err := loadConfig(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	// YAML configs are rejected by name
+	yamlPath := filepath.Join(dir, "config.yaml")
+	os.WriteFile(yamlPath, []byte("name: files\n"), 0o644)
+	if _, err := loadConfig(yamlPath); err == nil || !strings.Contains(err.Error(), "YAML is not supported") {
+		t.Errorf("expected an error for a YAML config, got %v", err)
+	}
+}
@@ -2,31 +2,74 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
 
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/fastmcp"
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/transport"
 )
 
 func main() {
+	// Run a subcommand, or serve the example server
+	if len(os.Args) > 1 {
+		name := os.Args[1]
+		var run func(args []string) error
+		switch {
+		case name == "new":
+			run = runNew
+		case name == "validate":
+			run = runValidate
+		case name == "shell" || clientCommands[name] != nil:
+			run = func(args []string) error { return runClient(name, args, os.Stdin, os.Stdout) }
+		}
+		if run != nil {
+			if err := run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "mcp %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	serve()
+}
+
+// serve runs the example server, with the tools, resources and plugins
+// given by the flags
+func serve() {
 	// Parse command line flags
-	transportType := flag.String("transport", "stdio", "Transport type (stdio, sse, or websocket)")
-	addr := flag.String("addr", ":8080", "Address to listen on for HTTP transports")
+	flag.Usage = usage
+	flag.String("transport", fastmcp.DefaultTransport, "Transport type (stdio, sse, websocket, tcp, streamable or http)")
+	flag.String("addr", fastmcp.DefaultAddr, "Address to listen on for network transports")
+	pluginDir := flag.String("plugins", "", "Directory of Go plugins (.so) registering tools, resources and prompts")
+	configPath := flag.String("config", "", "JSON config declaring command tools and file resources (YAML is not supported)")
+	printManifest := flag.Bool("manifest", false, "Print the server manifest as JSON and exit")
+	wireTap := flag.String("wire-tap", "", "File every JSON-RPC frame is appended to, for debugging")
+	record := flag.String("record", "", "File JSON-RPC frames are recorded to, for replaying in golden tests")
 	flag.Parse()
 
-	log.Printf("Starting MCP server with %s transport", *transportType)
+	// Load the config
+	config := &Config{}
+	if *configPath != "" {
+		var err error
+		if config, err = loadConfig(*configPath); err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+	}
+	name := config.Name
+	if name == "" {
+		name = "MCP Example Server"
+	}
 
-	// Create a new server
-	srv := server.NewServer("MCP Example Server")
+	// Create a new app
+	app := fastmcp.New(name, server.WithInstructions(config.Instructions))
 	log.Printf("Created server")
 
 	// Register tools
-	srv.AddTool("reverseText", func(text string) (string, error) {
+	app.Tool("reverseText", func(text string) (string, error) {
 		log.Printf("Received request: %+v", text)
 
 		log.Printf("Received text: %s", text)
@@ -40,51 +83,79 @@ func main() {
 		return string(runes), nil
 	}, "Reverses the input text")
 	log.Printf("Registered tool: reverseText")
+	srv := app.Server()
 
-	// Create a session
-	session := server.NewSession(context.Background(), srv)
-	log.Printf("Created session")
-
-	// Create the transport
-	var t transport.Transport
-	switch *transportType {
-	case "stdio":
-		t = transport.NewStdioTransport(session)
-	case "sse":
-		t = transport.NewSSETransport(session, transport.WithAddress(*addr))
-	case "websocket":
-		t = transport.NewWebSocketTransport(session, transport.WithAddress(*addr))
-	default:
-		log.Fatalf("Unknown transport type: %s", *transportType)
+	// Register configured tools and resources
+	if err := config.apply(srv); err != nil {
+		log.Fatalf("Failed to apply config: %v", err)
 	}
-	log.Printf("Created transport")
-
-	// Handle shutdown gracefully
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	// Start the transport in a goroutine
-	errChan := make(chan error, 1)
-	go func() {
-		log.Printf("Starting transport...")
-		if err := t.Start(); err != nil {
-			errChan <- fmt.Errorf("transport error: %w", err)
+
+	// Load plugins
+	if *pluginDir != "" {
+		if err := srv.LoadPlugins(*pluginDir); err != nil {
+			log.Fatalf("Failed to load plugins: %v", err)
 		}
-	}()
-	log.Printf("Server ready to accept connections")
-
-	// Wait for shutdown signal or error
-	select {
-	case <-sigChan:
-		log.Println("Shutting down...")
-		if httpT, ok := t.(transport.HTTPTransport); ok {
-			if err := httpT.Stop(context.Background()); err != nil {
-				log.Printf("Error stopping transport: %v", err)
-			}
+	}
+
+	// Print the manifest
+	if *printManifest {
+		manifest, err := srv.Manifest(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to build manifest: %v", err)
 		}
-	case err := <-errChan:
-		log.Printf("Error: %v", err)
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(manifest); err != nil {
+			log.Fatalf("Failed to write manifest: %v", err)
+		}
+		return
+	}
+
+	// Mirror the frames to the wire tap
+	if *wireTap != "" {
+		f, err := os.OpenFile(*wireTap, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			log.Fatalf("Failed to open wire tap: %v", err)
+		}
+		defer f.Close()
+		app.TransportOptions(transport.WithWireTap(f))
+	}
+
+	// Record the frames for replaying
+	if *record != "" {
+		f, err := os.OpenFile(*record, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			log.Fatalf("Failed to open recording: %v", err)
+		}
+		defer f.Close()
+		app.TransportOptions(transport.WithRecorder(transport.NewRecorder(f)))
 	}
+
+	// Serve until the transport ends or a shutdown signal is received
+	if err := app.Run(); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+// usage describes the server flags and the subcommands
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprint(out, `Usage:
+  mcp [flags]                        serve the example server
+  mcp tools|resources|templates|prompts [flags] [-- server command]
+  mcp call [flags] <tool> [json arguments] [-- server command]
+  mcp read [flags] <uri> [-- server command]
+  mcp prompt [flags] <name> [json arguments] [-- server command]
+  mcp shell [flags] [-- server command]
+  mcp validate [flags] [-- server command]
+  mcp new [flags] <dir>              create a server project
+
+The client subcommands and validate connect to the server at -url or run
+the command after --. Run "mcp <subcommand> -h" for their flags.
+
+Server flags:
+`)
+	flag.PrintDefaults()
 }
 
 // Example requests:
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"text/template"
+)
+
+// sdkModule is the module path of the SDK the generated servers require
+const sdkModule = "github.com/SetiabudiResearch/mcp-go-sdk"
+
+// goVersion is the go directive of the generated go.mod, the minimum
+// version of the SDK
+const goVersion = "1.23.3"
+
+//go:embed templates/new/*.tmpl
+var newTemplates embed.FS
+
+// scaffold maps the templates of a new project to the files they generate
+var scaffold = []struct {
+	template, path string
+}{
+	{"go.mod.tmpl", "go.mod"},
+	{"main.go.tmpl", "main.go"},
+	{"main_test.go.tmpl", "main_test.go"},
+	{"Makefile.tmpl", "Makefile"},
+	{"README.md.tmpl", "README.md"},
+	{"claude_desktop_config.json.tmpl", "claude_desktop_config.json"},
+	{"gitignore.tmpl", ".gitignore"},
+}
+
+// project holds the values the templates of a new project are executed with
+type project struct {
+	// Name is the name the server reports to clients
+	Name string
+	// Module is the module path of the project
+	Module string
+	// Binary is the name of the built server
+	Binary string
+	// Command is the absolute path of the built server, run by hosts
+	Command string
+	// GoVersion is the go directive of go.mod
+	GoVersion string
+	// SDKVersion is the required SDK version, empty when unknown
+	SDKVersion string
+	// Replace is a local SDK checkout replacing the required version
+	Replace string
+}
+
+// runNew generates a new server project in the directory given by args
+func runNew(args []string) error {
+	p := &project{GoVersion: goVersion, SDKVersion: sdkVersion()}
+
+	fs := flag.NewFlagSet("mcp new", flag.ContinueOnError)
+	fs.StringVar(&p.Module, "module", "", "Module path of the project (default the directory name)")
+	fs.StringVar(&p.Name, "name", "", "Name the server reports to clients (default the directory name)")
+	fs.StringVar(&p.Replace, "replace", "", "Local checkout of the SDK to build against")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: mcp new [flags] <dir>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one directory")
+	}
+
+	dir, err := filepath.Abs(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if p.Module == "" {
+		p.Module = filepath.Base(dir)
+	}
+	p.Binary = path.Base(p.Module)
+	if p.Name == "" {
+		p.Name = p.Binary
+	}
+	p.Command = filepath.Join(dir, "bin", p.Binary)
+	if p.Replace != "" {
+		if p.Replace, err = filepath.Abs(p.Replace); err != nil {
+			return err
+		}
+	}
+
+	if err := p.generate(dir); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s in %s\n\nNext steps:\n  cd %s\n  go mod tidy\n  make test\n", p.Name, dir, fs.Arg(0))
+	fmt.Println("\nMerge claude_desktop_config.json into Claude Desktop's config after make build.")
+	return nil
+}
+
+// generate writes the files of the project to dir, refusing to overwrite
+// existing ones
+func (p *project) generate(dir string) error {
+	templates, err := template.New("").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).ParseFS(newTemplates, "templates/new/*.tmpl")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range scaffold {
+		if _, err := os.Stat(filepath.Join(dir, file.path)); err == nil {
+			return fmt.Errorf("%s already exists", filepath.Join(dir, file.path))
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, file := range scaffold {
+		var b strings.Builder
+		if err := templates.ExecuteTemplate(&b, file.template, p); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", file.path, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file.path), []byte(b.String()), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sdkVersion returns the SDK version this command was built from, or ""
+// for development builds, where go mod tidy resolves the latest version.
+// Builds of modified checkouts have a +dirty version no proxy serves.
+func sdkVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Path != sdkModule || info.Main.Version == "(devel)" || strings.Contains(info.Main.Version, "+") {
+		return ""
+	}
+	return info.Main.Version
+}
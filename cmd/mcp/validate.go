@@ -0,0 +1,902 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// validateQuietPeriod is how long validation waits for the messages the
+// server sends after the last check
+const validateQuietPeriod = 500 * time.Millisecond
+
+// maxPages bounds the pages validation lists, against servers that never
+// stop returning cursors
+const maxPages = 1000
+
+// Levels of validation findings
+const (
+	levelPass = "pass"
+	levelSkip = "skip"
+	levelWarn = "warn"
+	levelFail = "fail"
+)
+
+// finding is the outcome of a validation check. A check passes when it
+// has no other findings.
+type finding struct {
+	Check   string `json:"check"`
+	Level   string `json:"level"`
+	Message string `json:"message,omitempty"`
+}
+
+// callFlags collects repeated -call flags
+type callFlags []string
+
+func (c *callFlags) String() string {
+	return strings.Join(*c, " ")
+}
+
+func (c *callFlags) Set(value string) error {
+	*c = append(*c, value)
+	return nil
+}
+
+// runValidate connects to the server named by the flags, exercises it and
+// reports the violations of the specification it shows
+func runValidate(args []string) error {
+	config := &clientConfig{headers: headerFlags{}}
+	if i := slices.Index(args, "--"); i >= 0 {
+		args, config.command = args[:i], args[i+1:]
+	}
+
+	var calls callFlags
+	fs := flag.NewFlagSet("mcp validate", flag.ContinueOnError)
+	config.register(fs)
+	fs.Var(&calls, "call", "Call a tool, as name or name={json arguments}, and validate its result (repeatable)")
+	strict := fs.Bool("strict", false, "Fail on warnings too")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: mcp validate [flags] [-- server command]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("too many arguments")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.timeout)
+	conn, err := config.dial(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+	v := newValidator(conn, config.timeout)
+	findings := v.run(calls)
+	conn.Close()
+
+	out := &output{w: os.Stdout, json: config.json}
+	if err := printFindings(out, findings); err != nil {
+		return err
+	}
+	violations := 0
+	for _, f := range findings {
+		if f.Level == levelFail || *strict && f.Level == levelWarn {
+			violations++
+		}
+	}
+	if violations > 0 {
+		return fmt.Errorf("%d violations of the specification", violations)
+	}
+	return nil
+}
+
+// printFindings prints the findings and a summary, or the findings as JSON
+func printFindings(out *output, findings []finding) error {
+	if out.json {
+		return out.printJSON(findings)
+	}
+	counts := map[string]int{}
+	tw := tabwriter.NewWriter(out.w, 0, 4, 2, ' ', 0)
+	for _, f := range findings {
+		counts[f.Level]++
+		row := strings.ToUpper(f.Level) + "\t" + f.Check
+		if f.Message != "" {
+			row += "\t" + f.Message
+		}
+		fmt.Fprintln(tw, row)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out.w, "\n%d passed, %d skipped, %d warnings, %d failures\n",
+		counts[levelPass], counts[levelSkip], counts[levelWarn], counts[levelFail])
+	return err
+}
+
+// wireMessage is a JSON-RPC message received from the server under
+// validation
+type wireMessage struct {
+	JSONRPC string              `json:"jsonrpc"`
+	ID      json.RawMessage     `json:"id,omitempty"`
+	Method  string              `json:"method,omitempty"`
+	Params  json.RawMessage     `json:"params,omitempty"`
+	Result  json.RawMessage     `json:"result,omitempty"`
+	Error   *protocol.ErrorData `json:"error,omitempty"`
+}
+
+// validator exercises a server over a raw connection, so it sees exactly
+// what the server sends, and records the violations it observes
+type validator struct {
+	conn     client.Conn
+	timeout  time.Duration
+	messages chan []byte
+	done     chan struct{}
+	nextID   int
+
+	// check is the name of the running check
+	check       string
+	findings    []finding
+	closed      bool
+	initialized bool
+
+	capabilities protocol.ServerCapabilities
+	tools        map[string]protocol.Tool
+}
+
+// newValidator starts reading the messages of conn
+func newValidator(conn client.Conn, timeout time.Duration) *validator {
+	v := &validator{
+		conn:     conn,
+		timeout:  timeout,
+		messages: make(chan []byte, 64),
+		done:     make(chan struct{}),
+		tools:    map[string]protocol.Tool{},
+	}
+	go v.read()
+	return v
+}
+
+// read queues the messages of the connection until it closes or
+// validation ends
+func (v *validator) read() {
+	defer close(v.messages)
+	for {
+		data, err := v.conn.Read()
+		if err != nil {
+			return
+		}
+		select {
+		case v.messages <- data:
+		case <-v.done:
+			return
+		}
+	}
+}
+
+// run runs the checks of the capabilities the server advertises, calls
+// the tools given by calls and returns the findings
+func (v *validator) run(calls []string) []finding {
+	defer close(v.done)
+
+	if !v.perform("initialize", v.checkInitialize) {
+		return v.findings
+	}
+	v.perform("ping", v.checkPing)
+	v.perform("errors/method-not-found", v.checkMethodNotFound)
+	v.perform("errors/parse-error", v.checkParseError)
+
+	if v.capabilities.Tools != nil {
+		v.perform("tools/list", v.checkTools)
+		v.perform("tools/unknown", v.checkUnknownTool)
+		v.perform("tools/invalid-cursor", v.checkInvalidCursor("tools/list"))
+		for _, call := range calls {
+			v.perform("tools/call "+strings.SplitN(call, "=", 2)[0], func() error { return v.checkCall(call) })
+		}
+	} else if len(calls) > 0 {
+		v.perform("tools/call", func() error {
+			v.fail("tools were given to call but the server does not advertise the tools capability")
+			return nil
+		})
+	}
+	if v.capabilities.Resources != nil {
+		v.perform("resources/list", v.checkResources)
+		v.perform("resources/templates", v.checkResourceTemplates)
+		v.perform("resources/unknown", v.checkUnknownResource)
+	}
+	if v.capabilities.Prompts != nil {
+		v.perform("prompts/list", v.checkPrompts)
+		v.perform("prompts/unknown", v.checkUnknownPrompt)
+	}
+	if v.capabilities.Logging != nil {
+		v.perform("logging/set-level", v.checkSetLevel)
+	}
+	v.perform("capabilities/gating", v.checkCapabilityGating)
+	v.perform("notifications", v.checkQuiet)
+	v.perform("jsonrpc", func() error { return nil })
+	return v.findings
+}
+
+// perform runs a check, recording it as passed unless it records
+// findings. It reports whether the check did not fail, and fails checks
+// once the server has closed the connection.
+func (v *validator) perform(name string, check func() error) bool {
+	v.check = name
+	if v.closed {
+		v.fail("connection to the server closed")
+		return false
+	}
+	if err := check(); err != nil {
+		v.fail("%v", err)
+	}
+
+	recorded, failed := false, false
+	for _, f := range v.findings {
+		if f.Check == name {
+			recorded = true
+			failed = failed || f.Level == levelFail
+		}
+	}
+	if !recorded {
+		v.findings = append(v.findings, finding{Check: name, Level: levelPass})
+	}
+	return !failed
+}
+
+// record records a finding of a check, once
+func (v *validator) record(check, level, format string, args ...interface{}) {
+	f := finding{Check: check, Level: level, Message: fmt.Sprintf(format, args...)}
+	if !slices.Contains(v.findings, f) {
+		v.findings = append(v.findings, f)
+	}
+}
+
+// fail records a violation of the running check
+func (v *validator) fail(format string, args ...interface{}) {
+	v.record(v.check, levelFail, format, args...)
+}
+
+// warn records a deviation from a recommendation of the specification
+func (v *validator) warn(format string, args ...interface{}) {
+	v.record(v.check, levelWarn, format, args...)
+}
+
+// skip records that the running check could not be performed
+func (v *validator) skip(format string, args ...interface{}) {
+	v.record(v.check, levelSkip, format, args...)
+}
+
+// send sends a message to the server
+func (v *validator) send(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return v.sendRaw(data)
+}
+
+// sendRaw sends data to the server as is
+func (v *validator) sendRaw(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), v.timeout)
+	defer cancel()
+	return v.conn.Write(ctx, data)
+}
+
+// call sends a request and returns its response, handling the messages
+// the server sends in the meantime
+func (v *validator) call(method string, params interface{}) (*wireMessage, error) {
+	v.nextID++
+	id := json.RawMessage(strconv.Itoa(v.nextID))
+	req := map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method}
+	if params != nil {
+		req["params"] = params
+	}
+	if err := v.send(req); err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", method, err)
+	}
+	return v.await(id, method)
+}
+
+// await returns the response with the given ID, handling other messages.
+// A nil ID matches responses without an ID, such as parse errors.
+func (v *validator) await(id json.RawMessage, method string) (*wireMessage, error) {
+	timeout := time.NewTimer(v.timeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case data, ok := <-v.messages:
+			if !ok {
+				v.closed = true
+				return nil, fmt.Errorf("connection closed waiting for the response to %s", method)
+			}
+			if msg := v.handle(data); msg != nil {
+				if idString(msg.ID) == idString(id) {
+					return msg, nil
+				}
+				v.record("jsonrpc", levelFail, "server answered unknown request ID %s", idString(msg.ID))
+			}
+		case <-timeout.C:
+			return nil, fmt.Errorf("no response to %s within %s", method, v.timeout)
+		}
+	}
+}
+
+// handle checks a message from the server and answers its requests. It
+// returns responses for the caller to match.
+func (v *validator) handle(data []byte) *wireMessage {
+	msg := new(wireMessage)
+	if err := json.Unmarshal(data, msg); err != nil {
+		v.record("jsonrpc", levelFail, "server sent invalid JSON %.100q", data)
+		return nil
+	}
+	if msg.JSONRPC != "2.0" {
+		v.record("jsonrpc", levelFail, "server sent a message with jsonrpc %q, want \"2.0\"", msg.JSONRPC)
+	}
+
+	switch {
+	case msg.Method != "" && msg.ID != nil:
+		v.handleRequest(msg)
+		return nil
+	case msg.Method != "":
+		v.handleNotification(msg)
+		return nil
+	case msg.Result != nil && msg.Error != nil:
+		v.record("jsonrpc", levelFail, "response to %s has both a result and an error", idString(msg.ID))
+	case msg.Result == nil && msg.Error == nil:
+		v.record("jsonrpc", levelFail, "response to %s has neither a result nor an error", idString(msg.ID))
+	}
+	return msg
+}
+
+// clientFeatures maps the requests servers send to clients to the client
+// capabilities they require. Validation declares none of them.
+var clientFeatures = map[string]string{
+	"sampling/createMessage": "sampling",
+	"roots/list":             "roots",
+	"elicitation/create":     "elicitation",
+}
+
+// handleRequest answers a request from the server, which may only ping a
+// client declaring no capabilities
+func (v *validator) handleRequest(msg *wireMessage) {
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": msg.ID}
+	if msg.Method == "ping" {
+		resp["result"] = struct{}{}
+	} else {
+		if capability, ok := clientFeatures[msg.Method]; ok {
+			v.record("notifications", levelFail, "server sent a %s request, but the client did not declare the %s capability", msg.Method, capability)
+		} else {
+			v.record("notifications", levelFail, "server sent unknown request %s", msg.Method)
+		}
+		resp["error"] = protocol.NewError(protocol.MethodNotFound, "method not found")
+	}
+	if err := v.send(resp); err != nil {
+		v.record("notifications", levelFail, "failed to answer the %s request of the server: %v", msg.Method, err)
+	}
+}
+
+// handleNotification checks that a notification from the server was
+// negotiated
+func (v *validator) handleNotification(msg *wireMessage) {
+	if !v.initialized && msg.Method != "notifications/message" {
+		v.record("notifications", levelFail, "server sent %s before initialization", msg.Method)
+	}
+
+	listChanged := func(capability string, enabled bool) {
+		if !enabled {
+			v.record("notifications", levelFail, "server sent %s without advertising %s.listChanged", msg.Method, capability)
+		}
+	}
+	switch msg.Method {
+	case "notifications/message":
+		if v.capabilities.Logging == nil {
+			v.record("notifications", levelFail, "server sent a log message without advertising the logging capability")
+		}
+	case "notifications/tools/list_changed":
+		listChanged("tools", v.capabilities.Tools != nil && isTrue(v.capabilities.Tools.ListChanged))
+	case "notifications/resources/list_changed":
+		listChanged("resources", v.capabilities.Resources != nil && isTrue(v.capabilities.Resources.ListChanged))
+	case "notifications/prompts/list_changed":
+		listChanged("prompts", v.capabilities.Prompts != nil && isTrue(v.capabilities.Prompts.ListChanged))
+	case "notifications/resources/updated":
+		v.record("notifications", levelFail, "server sent a resource update without a subscription")
+	case "notifications/progress":
+		v.record("notifications", levelFail, "server sent progress for a request without a progress token")
+	case "notifications/cancelled":
+	default:
+		v.record("notifications", levelWarn, "server sent unknown notification %s", msg.Method)
+	}
+}
+
+// isTrue reports whether an optional flag is set
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+// result sends a request and decodes its result into result, returning
+// error responses as errors
+func (v *validator) result(method string, params, result interface{}) error {
+	resp, err := v.call(method, params)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s failed: %d %s", method, resp.Error.Code, resp.Error.Message)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("invalid %s result %.200s: %w", method, resp.Result, err)
+	}
+	return nil
+}
+
+// expectError sends a request that must fail with the given code
+func (v *validator) expectError(method string, params interface{}, code int) error {
+	resp, err := v.call(method, params)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.Error == nil:
+		v.fail("%s succeeded, want error code %d", method, code)
+	case resp.Error.Code != code:
+		v.fail("%s failed with code %d (%s), want %d", method, resp.Error.Code, resp.Error.Message, code)
+	}
+	return nil
+}
+
+// list returns the items of every page of a list method, checking the
+// cursors on the way
+func (v *validator) list(method, field string) ([]json.RawMessage, error) {
+	var items []json.RawMessage
+	seen := map[string]bool{}
+	var params interface{}
+	for page := 0; page < maxPages; page++ {
+		var result struct {
+			NextCursor *string `json:"nextCursor"`
+		}
+		var fields map[string]json.RawMessage
+		if err := v.result(method, params, &fields); err != nil {
+			return nil, err
+		}
+		var pageItems []json.RawMessage
+		raw, ok := fields[field]
+		if !ok {
+			return nil, fmt.Errorf("%s result has no %s", method, field)
+		}
+		if err := json.Unmarshal(raw, &pageItems); err != nil {
+			return nil, fmt.Errorf("%s result has invalid %s: %w", method, field, err)
+		}
+		items = append(items, pageItems...)
+
+		if raw, ok := fields["nextCursor"]; ok {
+			if err := json.Unmarshal(raw, &result.NextCursor); err != nil {
+				return nil, fmt.Errorf("%s result has invalid nextCursor %s", method, raw)
+			}
+		}
+		if result.NextCursor == nil {
+			return items, nil
+		}
+		if seen[*result.NextCursor] {
+			return nil, fmt.Errorf("%s returned cursor %q twice", method, *result.NextCursor)
+		}
+		seen[*result.NextCursor] = true
+		params = map[string]string{"cursor": *result.NextCursor}
+	}
+	return nil, fmt.Errorf("%s returned more than %d pages", method, maxPages)
+}
+
+// checkInitialize initializes the session, offering the latest revision
+func (v *validator) checkInitialize() error {
+	var result protocol.InitializeResult
+	err := v.result("initialize", protocol.InitializeRequestParams{
+		ProtocolVersion: protocol.LatestProtocolVersion,
+		ClientInfo:      protocol.Implementation{Name: "mcp validate", Version: "1.0.0"},
+	}, &result)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !protocol.IsSupportedProtocolVersion(result.ProtocolVersion):
+		v.fail("server answered unknown protocol version %q to %q", result.ProtocolVersion, protocol.LatestProtocolVersion)
+	case result.ProtocolVersion != protocol.LatestProtocolVersion:
+		v.warn("server answered protocol version %q to %q; the checks assume the latest revision", result.ProtocolVersion, protocol.LatestProtocolVersion)
+	}
+	if result.ServerInfo.Name == "" {
+		v.fail("server info has no name")
+	}
+	v.capabilities = result.Capabilities
+
+	if err := v.send(map[string]interface{}{"jsonrpc": "2.0", "method": "notifications/initialized"}); err != nil {
+		return fmt.Errorf("failed to send notifications/initialized: %w", err)
+	}
+	v.initialized = true
+	return nil
+}
+
+// checkPing checks that pings are answered with an empty result
+func (v *validator) checkPing() error {
+	var result map[string]interface{}
+	if err := v.result("ping", nil, &result); err != nil {
+		return err
+	}
+	if result == nil {
+		v.fail("ping answered null, want an empty object")
+	}
+	return nil
+}
+
+// checkMethodNotFound checks the error for unknown methods
+func (v *validator) checkMethodNotFound() error {
+	return v.expectError("validate/unknown", nil, protocol.MethodNotFound)
+}
+
+// checkParseError checks the error for invalid JSON. Transports may
+// refuse it before it reaches the server.
+func (v *validator) checkParseError() error {
+	if err := v.sendRaw([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "ping"`)); err != nil {
+		v.skip("transport refuses invalid JSON: %v", err)
+		return nil
+	}
+	resp, err := v.await(nil, "invalid JSON")
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.Error == nil:
+		v.fail("invalid JSON answered with a result, want error code %d", protocol.ParseError)
+	case resp.Error.Code != protocol.ParseError:
+		v.fail("invalid JSON answered with code %d (%s), want %d", resp.Error.Code, resp.Error.Message, protocol.ParseError)
+	}
+	return nil
+}
+
+// checkTools checks the listed tools and their schemas
+func (v *validator) checkTools() error {
+	items, err := v.list("tools/list", "tools")
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		var tool protocol.Tool
+		if err := json.Unmarshal(item, &tool); err != nil || tool.Name == "" {
+			v.fail("invalid tool %.200s", item)
+			continue
+		}
+		if _, ok := v.tools[tool.Name]; ok {
+			v.fail("tool %q is listed twice", tool.Name)
+		}
+		v.tools[tool.Name] = tool
+
+		switch {
+		case tool.InputSchema == nil:
+			v.fail("tool %q has no input schema", tool.Name)
+		case tool.InputSchema["type"] != "object":
+			v.fail("tool %q has an input schema of type %v, want object", tool.Name, tool.InputSchema["type"])
+		}
+		if tool.OutputSchema != nil && tool.OutputSchema["type"] != "object" {
+			v.fail("tool %q has an output schema of type %v, want object", tool.Name, tool.OutputSchema["type"])
+		}
+		if tool.Description == "" {
+			v.warn("tool %q has no description", tool.Name)
+		}
+	}
+	return nil
+}
+
+// checkUnknownTool checks the error for calls of unknown tools
+func (v *validator) checkUnknownTool() error {
+	resp, err := v.call("tools/call", map[string]interface{}{"name": "validate_unknown_tool"})
+	if err != nil {
+		return err
+	}
+	if resp.Error == nil {
+		var result protocol.CallToolResult
+		if json.Unmarshal(resp.Result, &result) == nil && result.IsError {
+			v.warn("unknown tool answered with an error result, want error code %d", protocol.InvalidParams)
+		} else {
+			v.fail("unknown tool answered with a successful result")
+		}
+		return nil
+	}
+	if resp.Error.Code != protocol.InvalidParams {
+		v.fail("unknown tool answered with code %d (%s), want %d", resp.Error.Code, resp.Error.Message, protocol.InvalidParams)
+	}
+	return nil
+}
+
+// checkInvalidCursor returns a check of the error for an invalid cursor
+func (v *validator) checkInvalidCursor(method string) func() error {
+	return func() error {
+		resp, err := v.call(method, map[string]string{"cursor": "!not a cursor!"})
+		if err != nil {
+			return err
+		}
+		switch {
+		case resp.Error == nil:
+			v.warn("invalid cursor was accepted, want error code %d", protocol.InvalidParams)
+		case resp.Error.Code != protocol.InvalidParams:
+			v.warn("invalid cursor answered with code %d (%s), want %d", resp.Error.Code, resp.Error.Message, protocol.InvalidParams)
+		}
+		return nil
+	}
+}
+
+// checkCall calls a tool given as name or name={json arguments} and checks
+// its result
+func (v *validator) checkCall(call string) error {
+	name, rawArgs, _ := strings.Cut(call, "=")
+	arguments := map[string]interface{}{}
+	if rawArgs != "" {
+		if err := json.Unmarshal([]byte(rawArgs), &arguments); err != nil {
+			return fmt.Errorf("invalid arguments %s: %w", rawArgs, err)
+		}
+	}
+	tool, ok := v.tools[name]
+	if !ok {
+		v.fail("tool %q is not listed", name)
+		return nil
+	}
+
+	var result struct {
+		Content           []map[string]interface{} `json:"content"`
+		StructuredContent json.RawMessage          `json:"structuredContent"`
+		IsError           bool                     `json:"isError"`
+	}
+	if err := v.result("tools/call", map[string]interface{}{"name": name, "arguments": arguments}, &result); err != nil {
+		return err
+	}
+	if result.Content == nil {
+		v.fail("result has no content")
+	}
+	for _, item := range result.Content {
+		v.checkContent("result", item)
+	}
+	switch {
+	case result.IsError:
+		v.warn("tool returned an error result")
+	case tool.OutputSchema != nil && result.StructuredContent == nil:
+		v.fail("tool declares an output schema but returned no structured content")
+	}
+	return nil
+}
+
+// contentFields are the required fields of each type of content
+var contentFields = map[string][]string{
+	"text":          {"text"},
+	"image":         {"data", "mimeType"},
+	"audio":         {"data", "mimeType"},
+	"resource_link": {"uri", "name"},
+	"resource":      {"resource"},
+}
+
+// checkContent checks the type and fields of a content item
+func (v *validator) checkContent(where string, item map[string]interface{}) {
+	typ, _ := item["type"].(string)
+	fields, ok := contentFields[typ]
+	if !ok {
+		v.fail("%s has content of unknown type %q", where, typ)
+		return
+	}
+	for _, field := range fields {
+		if _, ok := item[field]; !ok {
+			v.fail("%s has %s content without %s", where, typ, field)
+		}
+	}
+}
+
+// checkResources checks the listed resources and reads the first one
+func (v *validator) checkResources() error {
+	items, err := v.list("resources/list", "resources")
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, item := range items {
+		var resource protocol.Resource
+		if err := json.Unmarshal(item, &resource); err != nil || resource.URI == "" {
+			v.fail("invalid resource %.200s", item)
+			continue
+		}
+		if seen[resource.URI] {
+			v.fail("resource %q is listed twice", resource.URI)
+		}
+		seen[resource.URI] = true
+		if u, err := url.Parse(resource.URI); err != nil || u.Scheme == "" {
+			v.fail("resource %q does not have an absolute URI", resource.URI)
+		}
+		if resource.Name == "" {
+			v.fail("resource %q has no name", resource.URI)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	var first protocol.Resource
+	json.Unmarshal(items[0], &first)
+	var result struct {
+		Contents []map[string]interface{} `json:"contents"`
+	}
+	if err := v.result("resources/read", map[string]string{"uri": first.URI}, &result); err != nil {
+		return err
+	}
+	if len(result.Contents) == 0 {
+		v.fail("reading %q returned no contents", first.URI)
+	}
+	for _, contents := range result.Contents {
+		_, text := contents["text"].(string)
+		_, blob := contents["blob"].(string)
+		if _, ok := contents["uri"].(string); !ok || text == blob {
+			v.fail("reading %q returned contents without a URI and either text or blob", first.URI)
+		}
+	}
+	return nil
+}
+
+// checkResourceTemplates checks the listed resource templates
+func (v *validator) checkResourceTemplates() error {
+	items, err := v.list("resources/templates/list", "resourceTemplates")
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		var template protocol.ResourceTemplate
+		if err := json.Unmarshal(item, &template); err != nil || template.URITemplate == "" || template.Name == "" {
+			v.fail("invalid resource template %.200s", item)
+		}
+	}
+	return nil
+}
+
+// checkUnknownResource checks the error for reads of unknown resources
+func (v *validator) checkUnknownResource() error {
+	resp, err := v.call("resources/read", map[string]string{"uri": "validate://unknown"})
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.Error == nil:
+		v.fail("unknown resource was read successfully")
+	case resp.Error.Code != protocol.ResourceNotFound:
+		v.warn("unknown resource answered with code %d (%s), want %d", resp.Error.Code, resp.Error.Message, protocol.ResourceNotFound)
+	}
+	return nil
+}
+
+// checkPrompts checks the listed prompts and gets the first one without
+// required arguments
+func (v *validator) checkPrompts() error {
+	items, err := v.list("prompts/list", "prompts")
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	var simple string
+	for _, item := range items {
+		var prompt protocol.Prompt
+		if err := json.Unmarshal(item, &prompt); err != nil || prompt.Name == "" {
+			v.fail("invalid prompt %.200s", item)
+			continue
+		}
+		if seen[prompt.Name] {
+			v.fail("prompt %q is listed twice", prompt.Name)
+		}
+		seen[prompt.Name] = true
+
+		required := false
+		for _, argument := range prompt.Arguments {
+			if argument.Name == "" {
+				v.fail("prompt %q has an argument without a name", prompt.Name)
+			}
+			required = required || isTrue(argument.Required)
+		}
+		if !required && simple == "" {
+			simple = prompt.Name
+		}
+	}
+	if simple == "" {
+		return nil
+	}
+
+	var result struct {
+		Messages []struct {
+			Role    string                 `json:"role"`
+			Content map[string]interface{} `json:"content"`
+		} `json:"messages"`
+	}
+	if err := v.result("prompts/get", map[string]string{"name": simple}, &result); err != nil {
+		return err
+	}
+	for _, msg := range result.Messages {
+		if msg.Role != string(protocol.RoleUser) && msg.Role != string(protocol.RoleAssistant) {
+			v.fail("prompt %q has a message with role %q", simple, msg.Role)
+		}
+		v.checkContent(fmt.Sprintf("prompt %q", simple), msg.Content)
+	}
+	return nil
+}
+
+// checkUnknownPrompt checks the error for unknown prompts
+func (v *validator) checkUnknownPrompt() error {
+	return v.expectError("prompts/get", map[string]string{"name": "validate_unknown_prompt"}, protocol.InvalidParams)
+}
+
+// checkSetLevel checks that servers advertising logging accept a level
+func (v *validator) checkSetLevel() error {
+	return v.result("logging/setLevel", map[string]string{"level": "info"}, nil)
+}
+
+// checkCapabilityGating checks that the list methods of capabilities the
+// server does not advertise are unknown
+func (v *validator) checkCapabilityGating() error {
+	capabilities := []struct {
+		name, method string
+		advertised   bool
+	}{
+		{"tools", "tools/list", v.capabilities.Tools != nil},
+		{"resources", "resources/list", v.capabilities.Resources != nil},
+		{"prompts", "prompts/list", v.capabilities.Prompts != nil},
+	}
+	for _, capability := range capabilities {
+		if capability.advertised {
+			continue
+		}
+		resp, err := v.call(capability.method, nil)
+		if err != nil {
+			return err
+		}
+		if resp.Error == nil || resp.Error.Code != protocol.MethodNotFound {
+			v.warn("%s is answered without the %s capability", capability.method, capability.name)
+		}
+	}
+	return nil
+}
+
+// checkQuiet handles the messages the server sends after the last check
+func (v *validator) checkQuiet() error {
+	quiet := time.NewTimer(validateQuietPeriod)
+	defer quiet.Stop()
+	for {
+		select {
+		case data, ok := <-v.messages:
+			if !ok {
+				return nil
+			}
+			if msg := v.handle(data); msg != nil {
+				v.record("jsonrpc", levelFail, "server answered unknown request ID %s", idString(msg.ID))
+			}
+		case <-quiet.C:
+			return nil
+		}
+	}
+}
+
+// idString returns an ID in a comparable form, "null" for a missing ID
+func idString(id json.RawMessage) string {
+	if len(id) == 0 {
+		return "null"
+	}
+	return string(id)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/transport"
+)
+
+func TestValidateServer(t *testing.T) {
+	srv := server.NewServer("validate")
+	srv.AddTool("greet", func(name string) string { return "Hello, " + name + "!" }, "Greets a person")
+	srv.AddResource("test://static", func() string { return "static" }, "A static resource")
+	srv.AddPrompt("hello", func() string { return "Say hello" }, "Says hello")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := transport.NewTCPTransport(srv)
+	go tr.(*transport.TCPTransport).Serve(listener)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		tr.Stop(ctx)
+	})
+
+	conn, err := client.DialTCP(context.Background(), listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	findings := newValidator(conn, 5*time.Second).run([]string{`greet={"arg0": "Ann"}`})
+	for _, f := range findings {
+		if f.Level != levelPass {
+			t.Errorf("%s: %s %s", f.Check, f.Level, f.Message)
+		}
+	}
+	if len(findings) < 10 {
+		t.Errorf("expected the checks of every capability, got %+v", findings)
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	conn := newScriptedConn(func(method string) (interface{}, *protocol.ErrorData, []interface{}) {
+		switch method {
+		case "initialize":
+			return protocol.InitializeResult{
+				ProtocolVersion: protocol.LatestProtocolVersion,
+				ServerInfo:      protocol.Implementation{Name: "scripted"},
+				Capabilities:    protocol.ServerCapabilities{Tools: &protocol.ToolsCapability{}},
+			}, nil, nil
+		case "ping":
+			return struct{}{}, nil, nil
+		case "tools/list":
+			// Log without the logging capability along the way
+			log := map[string]interface{}{"jsonrpc": "2.0", "method": "notifications/message", "params": map[string]string{"level": "info", "data": "listing"}}
+			return map[string]interface{}{"tools": []interface{}{map[string]string{"name": "bad"}}}, nil, []interface{}{log}
+		default:
+			return nil, protocol.NewError(protocol.InternalError, "no such method"), nil
+		}
+	})
+	defer conn.Close()
+
+	findings := newValidator(conn, 5*time.Second).run(nil)
+	for _, want := range []finding{
+		{Check: "errors/method-not-found", Level: levelFail, Message: "validate/unknown failed with code -32603 (no such method), want -32601"},
+		{Check: "tools/list", Level: levelFail, Message: `tool "bad" has no input schema`},
+		{Check: "tools/list", Level: levelWarn, Message: `tool "bad" has no description`},
+		{Check: "notifications", Level: levelFail, Message: "server sent a log message without advertising the logging capability"},
+	} {
+		found := false
+		for _, f := range findings {
+			found = found || f == want
+		}
+		if !found {
+			t.Errorf("missing finding %+v in %+v", want, findings)
+		}
+	}
+}
+
+// scriptedConn is a connection to a server answering requests with a
+// script, which returns the result or error of a method and the messages
+// to send before the response
+type scriptedConn struct {
+	script   func(method string) (interface{}, *protocol.ErrorData, []interface{})
+	messages chan []byte
+	done     chan struct{}
+}
+
+func newScriptedConn(script func(method string) (interface{}, *protocol.ErrorData, []interface{})) *scriptedConn {
+	return &scriptedConn{script: script, messages: make(chan []byte, 64), done: make(chan struct{})}
+}
+
+func (c *scriptedConn) Read() ([]byte, error) {
+	select {
+	case msg := <-c.messages:
+		return msg, nil
+	case <-c.done:
+		return nil, io.EOF
+	}
+}
+
+func (c *scriptedConn) Write(ctx context.Context, data []byte) error {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		c.send(map[string]interface{}{"jsonrpc": "2.0", "id": nil, "error": protocol.NewError(protocol.ParseError, "parse error")})
+		return nil
+	}
+	if req.ID == nil {
+		return nil
+	}
+
+	result, errData, before := c.script(req.Method)
+	for _, msg := range before {
+		c.send(msg)
+	}
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if errData != nil {
+		resp["error"] = errData
+	} else {
+		resp["result"] = result
+	}
+	c.send(resp)
+	return nil
+}
+
+func (c *scriptedConn) send(msg interface{}) {
+	data, _ := json.Marshal(msg)
+	c.messages <- data
+}
+
+func (c *scriptedConn) Close() error {
+	close(c.done)
+	return nil
+}
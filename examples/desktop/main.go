@@ -82,9 +82,9 @@ func main() {
 		}
 	}, "Prompt for file operations")
 
-	// Run the server with stdio transport
+	// Run the server on the transport chosen by -transport or MCP_TRANSPORT
 	log.Println("Starting Desktop Integration Demo...")
-	if err := app.RunStdio(); err != nil {
+	if err := app.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -108,9 +108,9 @@ func main() {
 		}
 	}, "Prompt for data processing")
 
-	// Run the server with stdio transport
+	// Run the server on the transport chosen by -transport or MCP_TRANSPORT
 	log.Println("Starting Complex Inputs Demo...")
-	if err := app.RunStdio(); err != nil {
+	if err := app.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -59,9 +59,9 @@ func main() {
 		}
 	}, "A confirmation prompt")
 
-	// Run the server with stdio transport
+	// Run the server on the transport chosen by -transport or MCP_TRANSPORT
 	log.Println("Starting Echo Server...")
-	if err := app.RunStdio(); err != nil {
+	if err := app.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -67,19 +67,9 @@ func (fs *FileServer) registerTools() error {
 	return nil
 }
 
-// registerResources registers file system resources
+// registerResources serves the root directory as file resources
 func (fs *FileServer) registerResources() error {
-	// File content resource
-	if err := fs.srv.AddResource("file://{path}", fs.readFile, "Read file contents"); err != nil {
-		return err
-	}
-
-	// Directory listing resource
-	if err := fs.srv.AddResource("dir://{path}", fs.listDir, "List directory contents"); err != nil {
-		return err
-	}
-
-	return nil
+	return fs.srv.AddFSResources("file://", os.DirFS(fs.rootDir))
 }
 
 // registerPrompts registers file operation prompts
@@ -109,31 +99,6 @@ func (fs *FileServer) createDir(path string) error {
 	return os.MkdirAll(fullPath, 0755)
 }
 
-// Resource implementations
-
-func (fs *FileServer) readFile(path string) (string, error) {
-	fullPath := filepath.Join(fs.rootDir, path)
-	content, err := ioutil.ReadFile(fullPath)
-	if err != nil {
-		return "", err
-	}
-	return string(content), nil
-}
-
-func (fs *FileServer) listDir(path string) ([]string, error) {
-	fullPath := filepath.Join(fs.rootDir, path)
-	entries, err := ioutil.ReadDir(fullPath)
-	if err != nil {
-		return nil, err
-	}
-
-	var files []string
-	for _, entry := range entries {
-		files = append(files, entry.Name())
-	}
-	return files, nil
-}
-
 // Prompt implementations
 
 func (fs *FileServer) fileOpPrompt(path string, operation string) []protocol.PromptMessage {
@@ -168,18 +133,15 @@ func main() {
 		log.Fatalf("Failed to create file server: %v", err)
 	}
 
-	// Create a session
-	session := server.NewSession(context.Background(), fs.srv)
-
 	// Create the transport
 	var t transport.Transport
 	switch *transportType {
 	case "stdio":
-		t = transport.NewStdioTransport(session)
+		t = transport.NewStdioTransport(server.NewSession(context.Background(), fs.srv))
 	case "sse":
-		t = transport.NewSSETransport(session, transport.WithAddress(*addr))
+		t = transport.NewSSETransport(fs.srv, transport.WithAddress(*addr))
 	case "websocket":
-		t = transport.NewWebSocketTransport(session, transport.WithAddress(*addr))
+		t = transport.NewWebSocketTransport(fs.srv, transport.WithAddress(*addr))
 	default:
 		log.Fatalf("Unknown transport type: %s", *transportType)
 	}
@@ -0,0 +1,439 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// ErrClosed is returned for requests made on, or pending when, the
+// connection to the server closed
+var ErrClosed = errors.New("client closed")
+
+// NotificationHandler receives notifications sent by the server, other
+// than progress notifications for the client's own requests
+type NotificationHandler func(method string, params json.RawMessage)
+
+// RequestHandler answers a request sent by the server, such as a sampling
+// or roots request. A returned *protocol.ErrorData is sent as is, other
+// errors as internal errors.
+type RequestHandler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// ProgressHandler receives the progress notifications of a request
+type ProgressHandler func(params protocol.ProgressNotificationParams)
+
+// Client is a connection to an MCP server. Requests may be made
+// concurrently once Initialize has completed.
+type Client struct {
+	conn          Conn
+	info          protocol.Implementation
+	capabilities  protocol.ClientCapabilities
+	logger        *slog.Logger
+	notifications []NotificationHandler
+	handlers      map[string]RequestHandler
+	initialized   *protocol.InitializeResult
+	pending       map[protocol.RequestID]chan *protocol.JSONRPCResponse
+	progress      map[string]ProgressHandler
+	inFlight      map[protocol.RequestID]context.CancelFunc
+	nextID        int64
+	done          chan struct{}
+	err           error
+	closeOnce     sync.Once
+	mu            sync.Mutex
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithClientInfo sets the name and version sent to the server in
+// initialize
+func WithClientInfo(info protocol.Implementation) Option {
+	return func(c *Client) {
+		c.info = info
+	}
+}
+
+// WithCapabilities sets the capabilities declared to the server in
+// initialize
+func WithCapabilities(capabilities protocol.ClientCapabilities) Option {
+	return func(c *Client) {
+		c.capabilities = capabilities
+	}
+}
+
+// WithLogger sets the logger for connection errors. By default a text
+// logger on stderr is used.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithNotificationHandler adds a handler for server notifications
+func WithNotificationHandler(handler NotificationHandler) Option {
+	return func(c *Client) {
+		c.notifications = append(c.notifications, handler)
+	}
+}
+
+// WithRequestHandler sets the handler answering server requests for a
+// method. Pings are answered without a handler, and requests for other
+// methods get a method not found error.
+func WithRequestHandler(method string, handler RequestHandler) Option {
+	return func(c *Client) {
+		c.handlers[method] = handler
+	}
+}
+
+// New creates a client communicating over conn and starts reading the
+// server's messages. Initialize must be called before other requests.
+func New(conn Conn, options ...Option) *Client {
+	c := &Client{
+		conn: conn,
+		info: protocol.Implementation{
+			Name:    "mcp-go-sdk",
+			Version: protocol.LatestProtocolVersion,
+		},
+		logger:   slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		handlers: make(map[string]RequestHandler),
+		pending:  make(map[protocol.RequestID]chan *protocol.JSONRPCResponse),
+		progress: make(map[string]ProgressHandler),
+		inFlight: make(map[protocol.RequestID]context.CancelFunc),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	go c.read()
+	return c
+}
+
+// Connect creates a client communicating over conn and initializes the
+// session
+func Connect(ctx context.Context, conn Conn, options ...Option) (*Client, error) {
+	c := New(conn, options...)
+	if _, err := c.Initialize(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Initialize performs the initialization handshake, offering the latest
+// protocol revision
+func (c *Client) Initialize(ctx context.Context) (*protocol.InitializeResult, error) {
+	params := protocol.InitializeRequestParams{
+		ProtocolVersion: protocol.LatestProtocolVersion,
+		Capabilities:    c.capabilities,
+		ClientInfo:      c.info,
+	}
+
+	var result protocol.InitializeResult
+	if err := c.Request(ctx, "initialize", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	if !protocol.IsSupportedProtocolVersion(result.ProtocolVersion) {
+		return nil, fmt.Errorf("unsupported protocol version: %s", result.ProtocolVersion)
+	}
+
+	c.mu.Lock()
+	c.initialized = &result
+	c.mu.Unlock()
+
+	if err := c.Notify(ctx, "notifications/initialized", nil); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// InitializeResult returns the server's reply to initialize, or nil before
+// the session is initialized
+func (c *Client) InitializeResult() *protocol.InitializeResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.initialized
+}
+
+// Request sends a request to the server and decodes the result of its
+// response into result. It blocks until the server responds, ctx is done
+// or the connection closes. Requests given up on are cancelled with a
+// notifications/cancelled message to the server. Error responses are
+// returned as *protocol.ErrorData.
+func (c *Client) Request(ctx context.Context, method string, params interface{}, result interface{}) error {
+	c.mu.Lock()
+	c.nextID++
+	id := protocol.NewIntID(c.nextID)
+	responses := make(chan *protocol.JSONRPCResponse, 1)
+	c.pending[id] = responses
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	err := c.write(ctx, &protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case resp := <-responses:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		data, ok := resp.Result.(json.RawMessage)
+		if !ok {
+			return fmt.Errorf("invalid %s result", method)
+		}
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("invalid %s result: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		c.Notify(context.Background(), "notifications/cancelled", protocol.CancelledNotificationParams{
+			RequestID: id,
+			Reason:    ctx.Err().Error(),
+		})
+		return ctx.Err()
+	case <-c.done:
+		return c.err
+	}
+}
+
+// Notify sends a notification to the server
+func (c *Client) Notify(ctx context.Context, method string, params interface{}) error {
+	err := c.write(ctx, &protocol.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", method, err)
+	}
+	return nil
+}
+
+// write encodes and sends a message
+func (c *Client) write(ctx context.Context, msg interface{}) error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return c.conn.Write(ctx, data)
+}
+
+// Done returns a channel that is closed when the connection to the server
+// closes
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close closes the connection to the server, failing pending requests
+// with ErrClosed
+func (c *Client) Close() error {
+	c.shutdown(ErrClosed)
+	return c.conn.Close()
+}
+
+// shutdown marks the client closed with the given error and cancels the
+// server requests being handled
+func (c *Client) shutdown(err error) {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.err = err
+		for _, cancel := range c.inFlight {
+			cancel()
+		}
+		c.mu.Unlock()
+		close(c.done)
+	})
+}
+
+// read handles messages from the server until the connection closes
+func (c *Client) read() {
+	for {
+		data, err := c.conn.Read()
+		if err != nil {
+			select {
+			case <-c.done:
+				// Closed by Close
+				return
+			default:
+			}
+			if !errors.Is(err, io.EOF) {
+				c.logger.Error("failed to read message", "error", err)
+				err = fmt.Errorf("%w: %v", ErrClosed, err)
+			} else {
+				err = ErrClosed
+			}
+			c.shutdown(err)
+			return
+		}
+
+		var msg struct {
+			JSONRPC string              `json:"jsonrpc"`
+			ID      *protocol.RequestID `json:"id,omitempty"`
+			Method  string              `json:"method"`
+			Params  json.RawMessage     `json:"params,omitempty"`
+			Result  json.RawMessage     `json:"result,omitempty"`
+			Error   *protocol.ErrorData `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.logger.Error("failed to parse message", "error", err)
+			continue
+		}
+
+		switch {
+		case msg.ID != nil && msg.Method == "":
+			c.handleResponse(&protocol.JSONRPCResponse{
+				JSONRPC: msg.JSONRPC,
+				ID:      *msg.ID,
+				Result:  msg.Result,
+				Error:   msg.Error,
+			})
+		case msg.ID != nil:
+			go c.handleRequest(*msg.ID, msg.Method, msg.Params)
+		default:
+			c.handleNotification(msg.Method, msg.Params)
+		}
+	}
+}
+
+// handleResponse delivers a response to the request it answers. Responses
+// to unknown requests are ignored.
+func (c *Client) handleResponse(resp *protocol.JSONRPCResponse) {
+	c.mu.Lock()
+	responses, ok := c.pending[resp.ID]
+	c.mu.Unlock()
+
+	if ok {
+		select {
+		case responses <- resp:
+		default:
+			// A response was already delivered for this request
+		}
+	}
+}
+
+// handleRequest answers a request from the server
+func (c *Client) handleRequest(id protocol.RequestID, method string, params json.RawMessage) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.mu.Lock()
+	c.inFlight[id] = cancel
+	handler, ok := c.handlers[method]
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inFlight, id)
+		c.mu.Unlock()
+		cancel()
+	}()
+
+	resp := &protocol.JSONRPCResponse{JSONRPC: "2.0", ID: id}
+	switch {
+	case ok:
+		result, err := handler(ctx, params)
+		var errData *protocol.ErrorData
+		switch {
+		case errors.As(err, &errData):
+			resp.Error = errData
+		case err != nil:
+			resp.Error = protocol.NewError(protocol.InternalError, err.Error())
+		default:
+			resp.Result = result
+		}
+	case method == "ping":
+		resp.Result = struct{}{}
+	default:
+		resp.Error = protocol.NewError(protocol.MethodNotFound, fmt.Sprintf("unknown method: %s", method))
+	}
+
+	// Requests cancelled by the server receive no response
+	if ctx.Err() != nil {
+		return
+	}
+	if err := c.write(context.Background(), resp); err != nil {
+		c.logger.Error("failed to send response", "method", method, "error", err)
+	}
+}
+
+// handleNotification routes progress notifications to their request and
+// cancellations to the server request they cancel, and passes all other
+// notifications to the notification handlers
+func (c *Client) handleNotification(method string, params json.RawMessage) {
+	switch method {
+	case "notifications/progress":
+		var progress protocol.ProgressNotificationParams
+		if err := json.Unmarshal(params, &progress); err != nil {
+			c.logger.Error("invalid progress notification", "error", err)
+			return
+		}
+		c.mu.Lock()
+		handler, ok := c.progress[fmt.Sprint(progress.ProgressToken)]
+		c.mu.Unlock()
+		if ok {
+			handler(progress)
+			return
+		}
+	case "notifications/cancelled":
+		var cancelled protocol.CancelledNotificationParams
+		if err := json.Unmarshal(params, &cancelled); err != nil {
+			c.logger.Error("invalid cancellation", "error", err)
+			return
+		}
+		c.mu.Lock()
+		cancel, ok := c.inFlight[cancelled.RequestID]
+		c.mu.Unlock()
+		if ok {
+			cancel()
+		}
+		return
+	}
+
+	for _, handler := range c.notifications {
+		handler(method, params)
+	}
+}
+
+// progressToken registers a progress handler under a new token. The
+// returned function unregisters it.
+func (c *Client) progressToken(handler ProgressHandler) (protocol.ProgressToken, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	token := "progress-" + strconv.FormatInt(c.nextID, 10)
+	c.progress[token] = handler
+	return token, func() {
+		c.mu.Lock()
+		delete(c.progress, token)
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Conn carries JSON-RPC messages between a client and a server.
+// Implementations must allow Write to be called concurrently with Read
+// and with other writes.
+type Conn interface {
+	// Read returns the next message from the server, or io.EOF once the
+	// connection is closed
+	Read() ([]byte, error)
+
+	// Write sends a message to the server
+	Write(ctx context.Context, msg []byte) error
+
+	// Close closes the connection
+	Close() error
+}
+
+// commandExitTimeout is how long closing a command connection waits for
+// the command to exit before killing it
+const commandExitTimeout = 5 * time.Second
+
+// streamConn exchanges newline-delimited messages over a stream, the
+// framing of the stdio and TCP transports
+type streamConn struct {
+	rwc    io.ReadWriteCloser
+	reader *bufio.Reader
+	mu     sync.Mutex
+}
+
+// NewStreamConn creates a connection exchanging newline-delimited messages
+// over rwc, such as a TCP connection or one end of a net.Pipe
+func NewStreamConn(rwc io.ReadWriteCloser) Conn {
+	return &streamConn{
+		rwc:    rwc,
+		reader: bufio.NewReader(rwc),
+	}
+}
+
+// DialTCP connects to a server's TCP transport
+func DialTCP(ctx context.Context, addr string) (Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	return NewStreamConn(conn), nil
+}
+
+// Read returns the next non-empty line
+func (c *streamConn) Read() ([]byte, error) {
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return line, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Write writes a message as a line
+func (c *streamConn) Write(ctx context.Context, msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.rwc.Write(append(msg, '\n'))
+	return err
+}
+
+// Close closes the stream
+func (c *streamConn) Close() error {
+	return c.rwc.Close()
+}
+
+// commandStream is the stdin and stdout of a running command
+type commandStream struct {
+	io.Reader
+	io.WriteCloser
+	cmd *exec.Cmd
+}
+
+// Close closes the command's stdin and waits for it to exit, killing it if
+// it does not exit in time
+func (s *commandStream) Close() error {
+	s.WriteCloser.Close()
+
+	exited := make(chan error, 1)
+	go func() { exited <- s.cmd.Wait() }()
+	select {
+	case err := <-exited:
+		return err
+	case <-time.After(commandExitTimeout):
+		s.cmd.Process.Kill()
+		return <-exited
+	}
+}
+
+// NewCommandConn starts cmd and connects to the server it runs over its
+// stdin and stdout, the stdio transport. The command's stderr is left as
+// configured. Closing the connection closes stdin and waits for the
+// command to exit.
+func NewCommandConn(cmd *exec.Cmd) (Conn, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", cmd.Path, err)
+	}
+
+	return NewStreamConn(&commandStream{Reader: stdout, WriteCloser: stdin, cmd: cmd}), nil
+}
@@ -0,0 +1,51 @@
+// Package client provides a client for connecting to MCP servers.
+//
+// A Client speaks JSON-RPC over a Conn, which carries the messages of one
+// of the server transports:
+//
+//	// A server command, over its stdin and stdout
+//	conn, err := client.NewCommandConn(exec.Command("my-mcp-server"))
+//
+//	// The TCP, WebSocket and streamable HTTP transports
+//	conn, err := client.DialTCP(ctx, "localhost:9000")
+//	conn, err := client.DialWebSocket(ctx, "ws://localhost:8080/ws", nil)
+//	conn := client.NewStreamableHTTPConn("http://localhost:8080/mcp", nil, nil)
+//
+// Connect performs the initialization handshake, after which requests may
+// be made concurrently:
+//
+//	c, err := client.Connect(ctx, conn,
+//	    client.WithClientInfo(protocol.Implementation{Name: "my-host", Version: "1.0.0"}),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer c.Close()
+//
+//	tools, err := c.Tools(ctx)
+//	result, err := c.CallTool(ctx, "greet", map[string]interface{}{"arg0": "Ann"})
+//
+// Errors returned by the server are *protocol.ErrorData. Cancelling a
+// request's context sends notifications/cancelled to the server.
+//
+// Progress:
+//
+//	result, err := c.CallToolWithProgress(ctx, "index", args, func(p protocol.ProgressNotificationParams) {
+//	    log.Printf("indexed %v", p.Progress)
+//	})
+//
+// Server Notifications and Requests:
+//
+// Notification handlers run on the goroutine reading the connection, so
+// they must not make requests themselves; start a goroutine for that.
+// Server requests are answered by the handler registered for their method,
+// and pings are answered automatically.
+//
+//	c, err := client.Connect(ctx, conn,
+//	    client.WithNotificationHandler(func(method string, params json.RawMessage) {
+//	        log.Printf("server sent %s", method)
+//	    }),
+//	    client.WithCapabilities(protocol.ClientCapabilities{Sampling: &protocol.SamplingCapability{}}),
+//	    client.WithRequestHandler("sampling/createMessage", sample),
+//	)
+package client
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// Ping checks that the server is responsive
+func (c *Client) Ping(ctx context.Context) error {
+	return c.Request(ctx, "ping", nil, nil)
+}
+
+// listParams returns the parameters of a list request for a page
+func listParams(cursor protocol.Cursor) interface{} {
+	if cursor == "" {
+		return nil
+	}
+	return protocol.PaginatedRequestParams{Cursor: &cursor}
+}
+
+// listAll collects the items of every page of a list, starting from the
+// first
+func listAll[T any](ctx context.Context, list func(ctx context.Context, cursor protocol.Cursor) ([]T, *protocol.Cursor, error)) ([]T, error) {
+	var all []T
+	var cursor protocol.Cursor
+	for {
+		items, next, err := list(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if next == nil || *next == "" {
+			return all, nil
+		}
+		cursor = *next
+	}
+}
+
+// ListTools returns a page of the server's tools, starting at cursor
+func (c *Client) ListTools(ctx context.Context, cursor protocol.Cursor) (*protocol.ListToolsResult, error) {
+	var result protocol.ListToolsResult
+	if err := c.Request(ctx, "tools/list", listParams(cursor), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Tools returns all of the server's tools
+func (c *Client) Tools(ctx context.Context) ([]protocol.Tool, error) {
+	return listAll(ctx, func(ctx context.Context, cursor protocol.Cursor) ([]protocol.Tool, *protocol.Cursor, error) {
+		result, err := c.ListTools(ctx, cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Tools, result.NextCursor, nil
+	})
+}
+
+// CallTool calls a tool with the given arguments. Tool failures are
+// reported in the result with IsError set.
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*protocol.CallToolResult, error) {
+	return c.CallToolWithProgress(ctx, name, arguments, nil)
+}
+
+// CallToolWithProgress is like CallTool, passing the progress the server
+// reports for the call to onProgress when it is not nil
+func (c *Client) CallToolWithProgress(ctx context.Context, name string, arguments map[string]interface{}, onProgress ProgressHandler) (*protocol.CallToolResult, error) {
+	params := protocol.CallToolRequestParams{
+		Name:      name,
+		Arguments: arguments,
+	}
+	if onProgress != nil {
+		token, unregister := c.progressToken(onProgress)
+		defer unregister()
+		params.Meta = &protocol.Meta{ProgressToken: token}
+	}
+
+	var result protocol.CallToolResult
+	if err := c.Request(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResources returns a page of the server's resources, starting at
+// cursor
+func (c *Client) ListResources(ctx context.Context, cursor protocol.Cursor) (*protocol.ListResourcesResult, error) {
+	var result protocol.ListResourcesResult
+	if err := c.Request(ctx, "resources/list", listParams(cursor), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Resources returns all of the server's resources
+func (c *Client) Resources(ctx context.Context) ([]protocol.Resource, error) {
+	return listAll(ctx, func(ctx context.Context, cursor protocol.Cursor) ([]protocol.Resource, *protocol.Cursor, error) {
+		result, err := c.ListResources(ctx, cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Resources, result.NextCursor, nil
+	})
+}
+
+// ListResourceTemplates returns a page of the server's resource templates,
+// starting at cursor
+func (c *Client) ListResourceTemplates(ctx context.Context, cursor protocol.Cursor) (*protocol.ListResourceTemplatesResult, error) {
+	var result protocol.ListResourceTemplatesResult
+	if err := c.Request(ctx, "resources/templates/list", listParams(cursor), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ResourceTemplates returns all of the server's resource templates
+func (c *Client) ResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error) {
+	return listAll(ctx, func(ctx context.Context, cursor protocol.Cursor) ([]protocol.ResourceTemplate, *protocol.Cursor, error) {
+		result, err := c.ListResourceTemplates(ctx, cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.ResourceTemplates, result.NextCursor, nil
+	})
+}
+
+// ReadResource reads the contents of a resource
+func (c *Client) ReadResource(ctx context.Context, uri string) (*protocol.ReadResourceResult, error) {
+	var result protocol.ReadResourceResult
+	if err := c.Request(ctx, "resources/read", protocol.ReadResourceRequestParams{URI: uri}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Subscribe asks the server for notifications/resources/updated messages
+// when a resource changes
+func (c *Client) Subscribe(ctx context.Context, uri string) error {
+	return c.Request(ctx, "resources/subscribe", protocol.SubscribeRequestParams{URI: uri}, nil)
+}
+
+// Unsubscribe stops updates of a resource
+func (c *Client) Unsubscribe(ctx context.Context, uri string) error {
+	return c.Request(ctx, "resources/unsubscribe", protocol.UnsubscribeRequestParams{URI: uri}, nil)
+}
+
+// ListPrompts returns a page of the server's prompts, starting at cursor
+func (c *Client) ListPrompts(ctx context.Context, cursor protocol.Cursor) (*protocol.ListPromptsResult, error) {
+	var result protocol.ListPromptsResult
+	if err := c.Request(ctx, "prompts/list", listParams(cursor), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Prompts returns all of the server's prompts
+func (c *Client) Prompts(ctx context.Context) ([]protocol.Prompt, error) {
+	return listAll(ctx, func(ctx context.Context, cursor protocol.Cursor) ([]protocol.Prompt, *protocol.Cursor, error) {
+		result, err := c.ListPrompts(ctx, cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Prompts, result.NextCursor, nil
+	})
+}
+
+// GetPrompt renders a prompt with the given arguments
+func (c *Client) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*protocol.GetPromptResult, error) {
+	params := protocol.GetPromptRequestParams{
+		Name:      name,
+		Arguments: arguments,
+	}
+
+	var result protocol.GetPromptResult
+	if err := c.Request(ctx, "prompts/get", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SetLogLevel sets the minimum level of the log messages the server sends
+func (c *Client) SetLogLevel(ctx context.Context, level protocol.LoggingLevel) error {
+	return c.Request(ctx, "logging/setLevel", protocol.SetLevelRequestParams{Level: level}, nil)
+}
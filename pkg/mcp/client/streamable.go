@@ -0,0 +1,208 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// sessionIDHeader carries the session ID of the streamable HTTP transport
+const sessionIDHeader = "Mcp-Session-Id"
+
+// streamableConn exchanges messages with a server's streamable HTTP
+// endpoint. Messages are POSTed, and the responses in the replies and on
+// the event stream opened once the session exists are queued for Read.
+type streamableConn struct {
+	url       string
+	client    *http.Client
+	header    http.Header
+	messages  chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	cancel    context.CancelFunc
+	mu        sync.Mutex
+	sessionID string
+}
+
+// NewStreamableHTTPConn creates a connection to a server's streamable HTTP
+// endpoint at url, such as http://localhost:8080/mcp, sending header with
+// every request. A nil httpClient uses http.DefaultClient.
+func NewStreamableHTTPConn(url string, httpClient *http.Client, header http.Header) Conn {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &streamableConn{
+		url:      url,
+		client:   httpClient,
+		header:   header,
+		messages: make(chan []byte, 16),
+		done:     make(chan struct{}),
+	}
+}
+
+// Read returns the next message received from the server
+func (c *streamableConn) Read() ([]byte, error) {
+	select {
+	case msg := <-c.messages:
+		return msg, nil
+	case <-c.done:
+		return nil, io.EOF
+	}
+}
+
+// Write POSTs a message and queues the messages in the reply. The session
+// ID the server assigns in its reply to initialize is sent from then on,
+// and the event stream for server-initiated messages is opened.
+func (c *streamableConn) Write(ctx context.Context, msg []byte) error {
+	req, err := c.newRequest(ctx, http.MethodPost, bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if sessionID := resp.Header.Get(sessionIDHeader); sessionID != "" {
+		c.mu.Lock()
+		opened := c.sessionID == ""
+		c.sessionID = sessionID
+		c.mu.Unlock()
+		if opened {
+			c.openStream()
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/json":
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		c.queue(data)
+		return nil
+	case mediaType == "text/event-stream":
+		return c.readEvents(resp.Body)
+	case resp.StatusCode >= http.StatusBadRequest:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("server returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	default:
+		return nil
+	}
+}
+
+// newRequest creates a request to the endpoint with the configured headers
+// and the session ID
+func (c *streamableConn) newRequest(ctx context.Context, method string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+
+	c.mu.Lock()
+	sessionID := c.sessionID
+	c.mu.Unlock()
+	if sessionID != "" {
+		req.Header.Set(sessionIDHeader, sessionID)
+	}
+	return req, nil
+}
+
+// openStream opens the event stream for server-initiated messages in the
+// background. Servers that do not offer one are only heard from in replies.
+func (c *streamableConn) openStream() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.mu.Lock()
+	c.cancel = cancel
+	c.mu.Unlock()
+
+	go func() {
+		req, err := c.newRequest(ctx, http.MethodGet, nil)
+		if err != nil {
+			return
+		}
+		req.Header.Set("Accept", "text/event-stream")
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			c.readEvents(resp.Body)
+		}
+	}()
+}
+
+// readEvents queues the data of each event in an event stream until it ends
+func (c *streamableConn) readEvents(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	var data []byte
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				c.queue(data)
+				data = nil
+			}
+		case strings.HasPrefix(line, "data:"):
+			if len(data) > 0 {
+				data = append(data, '\n')
+			}
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")...)
+		}
+	}
+	if len(data) > 0 {
+		c.queue(data)
+	}
+	return scanner.Err()
+}
+
+// queue hands a message to Read, unless the connection is closed
+func (c *streamableConn) queue(msg []byte) {
+	select {
+	case c.messages <- msg:
+	case <-c.done:
+	}
+}
+
+// Close ends the session on the server and closes the event stream
+func (c *streamableConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+
+		c.mu.Lock()
+		cancel := c.cancel
+		sessionID := c.sessionID
+		c.mu.Unlock()
+		if cancel != nil {
+			cancel()
+		}
+
+		if sessionID != "" {
+			if req, err := c.newRequest(context.Background(), http.MethodDelete, nil); err == nil {
+				if resp, err := c.client.Do(req); err == nil {
+					resp.Body.Close()
+				}
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsConn exchanges messages over a WebSocket connection
+type wsConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+// DialWebSocket connects to a server's WebSocket transport at url, such as
+// ws://localhost:8080/ws, sending header with the handshake
+func DialWebSocket(ctx context.Context, url string, header http.Header) (Conn, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+	}
+	return &wsConn{conn: conn}, nil
+}
+
+// Read returns the next message
+func (c *wsConn) Read() ([]byte, error) {
+	_, data, err := c.conn.ReadMessage()
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		return nil, io.EOF
+	}
+	return data, err
+}
+
+// Write sends a message
+func (c *wsConn) Write(ctx context.Context, msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetWriteDeadline(deadline)
+		defer c.conn.SetWriteDeadline(time.Time{})
+	}
+	return c.conn.WriteMessage(websocket.TextMessage, msg)
+}
+
+// Close closes the connection
+func (c *wsConn) Close() error {
+	c.mu.Lock()
+	c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	c.mu.Unlock()
+
+	return c.conn.Close()
+}
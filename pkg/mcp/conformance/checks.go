@@ -0,0 +1,336 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// checks lists the conformance checks in the order they run
+var checks = []check{
+	{"lifecycle/initialize", checkInitialize},
+	{"lifecycle/version-negotiation", checkVersionNegotiation},
+	{"lifecycle/request-before-initialize", checkRequestBeforeInitialize},
+	{"lifecycle/ping-before-initialize", checkPingBeforeInitialize},
+	{"lifecycle/ping", checkPing},
+	{"errors/parse-error", checkParseError},
+	{"errors/method-not-found", checkMethodNotFound},
+	{"errors/invalid-params", checkInvalidParams},
+	{"errors/unknown-names", checkUnknownNames},
+	{"errors/tool-error-result", checkToolErrorResult},
+	{"capabilities/gating", checkCapabilityGating},
+	{"pagination/tools", checkPagination("tools/list", "tools")},
+	{"pagination/resources", checkPagination("resources/list", "resources")},
+	{"pagination/prompts", checkPagination("prompts/list", "prompts")},
+	{"pagination/invalid-cursor", checkInvalidCursor},
+	{"cancellation/in-flight", checkCancelInFlight},
+	{"cancellation/unknown-request", checkCancelUnknown},
+}
+
+// checkInitialize checks that the server agrees on the latest revision and
+// describes itself
+func checkInitialize(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	result := p.initialize()
+	if result.ProtocolVersion != protocol.LatestProtocolVersion {
+		t.Errorf("server answered protocol version %q to %q", result.ProtocolVersion, protocol.LatestProtocolVersion)
+	}
+	if result.ServerInfo.Name == "" {
+		t.Errorf("server info has no name")
+	}
+}
+
+// checkVersionNegotiation checks that the server agrees on every supported
+// revision it is offered, and offers one it supports in place of an
+// unknown revision
+func checkVersionNegotiation(t *testing.T, s *suite) {
+	for _, version := range append([]string{"1999-01-01"}, protocol.SupportedProtocolVersions...) {
+		p, _ := s.connect(t)
+		var result protocol.InitializeResult
+		p.expectResult("initialize", initializeParams(version), &result)
+
+		switch {
+		case protocol.IsSupportedProtocolVersion(version) && result.ProtocolVersion != version:
+			t.Errorf("server answered protocol version %q to supported %q", result.ProtocolVersion, version)
+		case !protocol.IsSupportedProtocolVersion(result.ProtocolVersion):
+			t.Errorf("server answered unsupported protocol version %q to %q", result.ProtocolVersion, version)
+		}
+	}
+}
+
+// checkRequestBeforeInitialize checks that requests other than pings are
+// refused before initialization. Transports may refuse them before they
+// reach the server.
+func checkRequestBeforeInitialize(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	id := p.newID()
+	if err := p.write(p.request(id, "tools/list", nil)); err != nil {
+		return
+	}
+	if resp := p.await(id); resp.Error == nil {
+		t.Errorf("tools/list before initialize succeeded with %s", resp.Result)
+	}
+}
+
+// checkPingBeforeInitialize checks that pings are answered before
+// initialization
+func checkPingBeforeInitialize(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	id := p.newID()
+	if err := p.write(p.request(id, "ping", nil)); err != nil {
+		t.Skipf("transport refuses messages before initialize: %v", err)
+	}
+	if resp := p.await(id); resp.Error != nil {
+		t.Errorf("ping before initialize failed: %d %s", resp.Error.Code, resp.Error.Message)
+	}
+}
+
+// checkPing checks that pings are answered with an empty result
+func checkPing(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	p.initialize()
+
+	var result map[string]interface{}
+	p.expectResult("ping", nil, &result)
+	if len(result) != 0 {
+		t.Errorf("ping answered %v, want an empty result", result)
+	}
+}
+
+// checkParseError checks that invalid JSON is answered with a parse error
+func checkParseError(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	p.initialize()
+
+	if err := p.writeRaw([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "ping"`)); err != nil {
+		t.Skipf("transport refuses invalid JSON: %v", err)
+	}
+	resp := p.await(nil)
+	if resp.Error == nil || resp.Error.Code != protocol.ParseError {
+		t.Errorf("invalid JSON answered with %+v, want error code %d", resp.Error, protocol.ParseError)
+	}
+}
+
+// checkMethodNotFound checks that unknown methods are answered with a
+// method not found error
+func checkMethodNotFound(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	p.initialize()
+	p.expectError("conformance/unknown", nil, protocol.MethodNotFound)
+}
+
+// checkInvalidParams checks that params of the wrong shape are answered
+// with an invalid params error
+func checkInvalidParams(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	p.initialize()
+	p.expectError("tools/call", map[string]interface{}{"name": 42}, protocol.InvalidParams)
+	p.expectError("resources/read", map[string]interface{}{"uri": []int{1}}, protocol.InvalidParams)
+	p.expectError("prompts/get", "greeting", protocol.InvalidParams)
+}
+
+// checkUnknownNames checks the errors for unknown tools, prompts and
+// resources
+func checkUnknownNames(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	p.initialize()
+	p.expectError("tools/call", map[string]interface{}{"name": "conformance_unknown"}, protocol.InvalidParams)
+	p.expectError("prompts/get", map[string]interface{}{"name": "conformance_unknown"}, protocol.InvalidParams)
+	p.expectError("resources/read", map[string]interface{}{"uri": "test://unknown"}, protocol.ResourceNotFound)
+}
+
+// checkToolErrorResult checks that tool failures are results flagged as
+// errors rather than protocol errors
+func checkToolErrorResult(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	p.initialize()
+
+	var result protocol.CallToolResult
+	p.expectResult("tools/call", map[string]interface{}{"name": "fail"}, &result)
+	if !result.IsError {
+		t.Errorf("failing tool answered %+v, want an error result", result)
+	}
+
+	p.expectResult("tools/call", map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"arg0": "hi"}}, &result)
+	if result.IsError {
+		t.Errorf("echo tool answered %+v, want a successful result", result)
+	}
+}
+
+// checkCapabilityGating checks that the methods of capabilities the server
+// does not advertise are unknown
+func checkCapabilityGating(t *testing.T, s *suite) {
+	p, _ := s.connect(t, server.WithCapabilities(protocol.ServerCapabilities{
+		Tools: &protocol.ToolsCapability{},
+	}))
+	result := p.initialize()
+	if result.Capabilities.Tools == nil {
+		t.Fatalf("server does not advertise the tools capability it was given")
+	}
+
+	p.expectResult("tools/list", nil, nil)
+	p.expectError("resources/list", nil, protocol.MethodNotFound)
+	p.expectError("resources/read", map[string]interface{}{"uri": "test://static"}, protocol.MethodNotFound)
+	p.expectError("prompts/list", nil, protocol.MethodNotFound)
+	p.expectError("prompts/get", map[string]interface{}{"name": "greeting"}, protocol.MethodNotFound)
+	p.expectError("logging/setLevel", map[string]interface{}{"level": "info"}, protocol.MethodNotFound)
+}
+
+// pageItems is the number of items of each kind the pagination checks
+// register, with pages of two
+const pageItems = 5
+
+// checkPagination returns a check that lists every page of a list method
+// and expects each item exactly once, over more than one page
+func checkPagination(method, field string) func(t *testing.T, s *suite) {
+	return func(t *testing.T, s *suite) {
+		srv, _ := s.newServer(server.WithPageSize(2))
+		for i := 0; i < pageItems; i++ {
+			name := fmt.Sprintf("page_%d", i)
+			srv.AddTool(name, func() string { return name }, "A tool")
+			srv.AddResource("test://"+name, func() string { return name }, "A resource")
+			srv.AddPrompt(name, func() string { return name }, "A prompt")
+		}
+		p := s.dial(t, srv)
+		p.initialize()
+
+		seen := make(map[string]bool)
+		pages := 0
+		var cursor *string
+		for {
+			var params interface{}
+			if cursor != nil {
+				params = map[string]interface{}{"cursor": *cursor}
+			}
+			var page map[string]json.RawMessage
+			p.expectResult(method, params, &page)
+			pages++
+
+			var items []struct {
+				Name string `json:"name"`
+				URI  string `json:"uri"`
+			}
+			if err := json.Unmarshal(page[field], &items); err != nil {
+				t.Fatalf("invalid %s page %s: %v", method, page[field], err)
+			}
+			for _, item := range items {
+				key := item.Name
+				if item.URI != "" {
+					key = item.URI
+				}
+				if seen[key] {
+					t.Errorf("%s listed %s twice", method, key)
+				}
+				seen[key] = true
+			}
+
+			cursor = nil
+			if next, ok := page["nextCursor"]; ok && string(next) != "null" {
+				if err := json.Unmarshal(next, &cursor); err != nil {
+					t.Fatalf("invalid %s cursor %s: %v", method, next, err)
+				}
+			}
+			if cursor == nil {
+				break
+			}
+			if pages > 100 {
+				t.Fatalf("%s did not end after %d pages", method, pages)
+			}
+		}
+
+		if pages < 2 {
+			t.Errorf("%s returned one page, want pages of 2 items", method)
+		}
+		for i := 0; i < pageItems; i++ {
+			key := fmt.Sprintf("page_%d", i)
+			if field == "resources" {
+				key = "test://" + key
+			}
+			if !seen[key] {
+				t.Errorf("%s did not list %s", method, key)
+			}
+		}
+	}
+}
+
+// checkInvalidCursor checks that unknown cursors are answered with an
+// invalid params error
+func checkInvalidCursor(t *testing.T, s *suite) {
+	p, _ := s.connect(t, server.WithPageSize(2))
+	p.initialize()
+	p.expectError("tools/list", map[string]interface{}{"cursor": "!not a cursor!"}, protocol.InvalidParams)
+}
+
+// checkCancelInFlight checks that cancelling a request stops its handler,
+// that it receives no response and that the session keeps working
+func checkCancelInFlight(t *testing.T, s *suite) {
+	p, f := s.connect(t)
+	p.initialize()
+
+	// Transports answering requests in the reply to their message, such as
+	// streamable HTTP, block the write until the request is done
+	id := p.newID()
+	written := make(chan error, 1)
+	go func() {
+		written <- p.write(p.request(id, "tools/call", map[string]interface{}{"name": "block"}))
+	}()
+	waitFor(t, f.started, "tool call")
+
+	p.notify("notifications/cancelled", protocol.CancelledNotificationParams{
+		RequestID: requestID(t, id),
+		Reason:    "conformance check",
+	})
+	waitFor(t, f.cancelled, "cancellation of the tool call")
+	if err := <-written; err != nil {
+		t.Fatalf("failed to send tools/call: %v", err)
+	}
+
+	ping := p.send("ping", nil)
+	deadline := time.After(Timeout)
+	quiet := (<-chan time.Time)(nil)
+	for {
+		select {
+		case msg, ok := <-p.messages:
+			if !ok {
+				t.Fatalf("connection closed after cancelling a request")
+			}
+			switch {
+			case !msg.isResponse():
+			case idString(msg.ID) == idString(id):
+				t.Errorf("cancelled request received a response: %s %+v", msg.Result, msg.Error)
+			case idString(msg.ID) == idString(ping):
+				quiet = time.After(quietPeriod)
+			}
+		case <-quiet:
+			return
+		case <-deadline:
+			t.Fatalf("no response to ping within %s after cancelling a request", Timeout)
+		}
+	}
+}
+
+// checkCancelUnknown checks that cancelling an unknown request is ignored
+func checkCancelUnknown(t *testing.T, s *suite) {
+	p, _ := s.connect(t)
+	p.initialize()
+
+	p.notify("notifications/cancelled", protocol.CancelledNotificationParams{
+		RequestID: protocol.NewIntID(1 << 30),
+		Reason:    "conformance check",
+	})
+	p.expectResult("ping", nil, nil)
+}
+
+// requestID decodes the ID of a request sent by a peer
+func requestID(t *testing.T, id json.RawMessage) protocol.RequestID {
+	t.Helper()
+	var requestID protocol.RequestID
+	if err := json.Unmarshal(id, &requestID); err != nil {
+		t.Fatalf("invalid request ID %s: %v", id, err)
+	}
+	return requestID
+}
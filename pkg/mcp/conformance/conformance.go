@@ -0,0 +1,168 @@
+package conformance
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// Timeout bounds the wait for each response of the server under test
+var Timeout = 10 * time.Second
+
+// quietPeriod is how long a check waits to be sure a message is not sent
+const quietPeriod = 200 * time.Millisecond
+
+// Serve serves srv over the transport under test and returns a connection
+// to it. It is called once per check with a new server, and should stop
+// the transport when the test ends.
+type Serve func(t *testing.T, srv *server.Server) client.Conn
+
+// Option configures a conformance run
+type Option func(*config)
+
+// config holds the settings of a conformance run
+type config struct {
+	serverOptions []server.ServerOption
+	setup         []func(*server.Server)
+	skip          []string
+}
+
+// WithServerOptions passes options to every server the checks create,
+// before the options of the checks themselves
+func WithServerOptions(options ...server.ServerOption) Option {
+	return func(c *config) {
+		c.serverOptions = append(c.serverOptions, options...)
+	}
+}
+
+// WithSetup calls setup on every server the checks create, before its
+// fixtures are registered, to install the middleware, interceptors or
+// registrations under test
+func WithSetup(setup func(srv *server.Server)) Option {
+	return func(c *config) {
+		c.setup = append(c.setup, setup)
+	}
+}
+
+// Skip leaves out checks by name, such as "cancellation/in-flight", or by
+// group, such as "pagination"
+func Skip(names ...string) Option {
+	return func(c *config) {
+		c.skip = append(c.skip, names...)
+	}
+}
+
+// skipped reports whether a check is left out
+func (c *config) skipped(name string) bool {
+	for _, skip := range c.skip {
+		if name == skip || strings.HasPrefix(name, skip+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// check is a conformance check, named after the part of the specification
+// it covers
+type check struct {
+	name string
+	run  func(t *testing.T, s *suite)
+}
+
+// Run runs the conformance checks against servers served by serve, each
+// as a subtest named after the check
+func Run(t *testing.T, serve Serve, options ...Option) {
+	c := &config{}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			if c.skipped(check.name) {
+				t.Skip("skipped by option")
+			}
+			check.run(t, &suite{config: c, serve: serve})
+		})
+	}
+}
+
+// suite connects the checks to servers with the fixtures they rely on
+type suite struct {
+	config *config
+	serve  Serve
+}
+
+// fixture exposes the state of a server's fixtures to a check
+type fixture struct {
+	started   chan struct{}
+	cancelled chan struct{}
+}
+
+// connect creates a server with the fixtures and the given options, serves
+// it and returns a peer connected to it and the fixture state
+func (s *suite) connect(t *testing.T, options ...server.ServerOption) (*peer, *fixture) {
+	t.Helper()
+	srv, f := s.newServer(options...)
+	return s.dial(t, srv), f
+}
+
+// newServer creates a server with the fixtures and the given options
+func (s *suite) newServer(options ...server.ServerOption) (*server.Server, *fixture) {
+	srv := server.NewServer("conformance", append(append([]server.ServerOption(nil), s.config.serverOptions...), options...)...)
+	for _, setup := range s.config.setup {
+		setup(srv)
+	}
+
+	f := &fixture{started: make(chan struct{}, 1), cancelled: make(chan struct{}, 1)}
+	srv.AddTool("echo", func(text string) string { return text }, "Echoes the text")
+	srv.AddTool("fail", server.ToolHandlerFunc(func(ctx context.Context, args map[string]interface{}) (protocol.CallToolResult, error) {
+		return protocol.CallToolResult{
+			Content: []interface{}{protocol.NewTextContent("the tool failed")},
+			IsError: true,
+		}, nil
+	}), "Fails")
+	srv.AddTool("block", server.ToolHandlerFunc(func(ctx context.Context, args map[string]interface{}) (protocol.CallToolResult, error) {
+		signal(f.started)
+		select {
+		case <-ctx.Done():
+			signal(f.cancelled)
+			return protocol.CallToolResult{}, ctx.Err()
+		case <-time.After(Timeout):
+			return protocol.CallToolResult{Content: []interface{}{protocol.NewTextContent("not cancelled")}}, nil
+		}
+	}), "Blocks until cancelled")
+	srv.AddResource("test://static", func() string { return "static" }, "A static resource")
+	srv.AddPrompt("greeting", func(name string) string { return "Hello, " + name + "!" }, "Greets a person")
+	return srv, f
+}
+
+// dial serves srv over the transport under test and returns a peer
+// connected to it
+func (s *suite) dial(t *testing.T, srv *server.Server) *peer {
+	t.Helper()
+	return newPeer(t, s.serve(t, srv))
+}
+
+// signal signals a fixture event without blocking
+func signal(events chan struct{}) {
+	select {
+	case events <- struct{}{}:
+	default:
+	}
+}
+
+// waitFor waits for a fixture event
+func waitFor(t *testing.T, events <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-events:
+	case <-time.After(Timeout):
+		t.Fatalf("no %s within %s", what, Timeout)
+	}
+}
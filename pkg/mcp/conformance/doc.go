@@ -0,0 +1,43 @@
+// Package conformance checks that a server and transport follow the MCP
+// specification.
+//
+// Run serves a server with known tools, resources and prompts for each
+// check over the transport under test, and speaks raw JSON-RPC to it. The
+// checks cover:
+//
+//   - lifecycle: version negotiation, and pings but no other requests
+//     before initialize
+//   - error codes: parse errors, unknown methods, invalid params, unknown
+//     tools, prompts and resources, and tool failures as error results
+//   - capability gating: the methods of capabilities the server does not
+//     advertise are unknown
+//   - pagination: list cursors cover every item once, and invalid cursors
+//     are refused
+//   - cancellation: a cancelled request stops its handler and gets no
+//     response, and the session keeps working
+//
+// A Serve function connects the checks to a transport:
+//
+//	func TestConformance(t *testing.T) {
+//	    conformance.Run(t, func(t *testing.T, srv *server.Server) client.Conn {
+//	        ts := httptest.NewServer(transport.NewStreamableHTTPTransport(srv).(http.Handler))
+//	        t.Cleanup(ts.Close)
+//	        return client.NewStreamableHTTPConn(ts.URL, nil, nil)
+//	    })
+//	}
+//
+// Extensions are validated by installing them on every server with
+// WithSetup or WithServerOptions, so the checks run through them:
+//
+//	conformance.Run(t, serve,
+//	    conformance.WithSetup(func(srv *server.Server) {
+//	        srv.Use(rateLimit)
+//	        srv.UseToolInterceptor(audit)
+//	    }),
+//	    conformance.Skip("cancellation"),
+//	)
+//
+// Checks a transport cannot take part in, such as pings before initialize
+// on streamable HTTP, which requires a session for every message but
+// initialize, are skipped.
+package conformance
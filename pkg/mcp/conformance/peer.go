@@ -0,0 +1,212 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// message is a JSON-RPC message received from the server
+type message struct {
+	ID     json.RawMessage     `json:"id,omitempty"`
+	Method string              `json:"method,omitempty"`
+	Params json.RawMessage     `json:"params,omitempty"`
+	Result json.RawMessage     `json:"result,omitempty"`
+	Error  *protocol.ErrorData `json:"error,omitempty"`
+
+	// invalid holds data the server sent that is not JSON
+	invalid []byte
+}
+
+// isResponse reports whether the message answers a request
+func (m *message) isResponse() bool {
+	return m.Method == "" && (m.Result != nil || m.Error != nil)
+}
+
+// peer speaks raw JSON-RPC to the server under test, so checks control
+// exactly which messages are sent and in what order
+type peer struct {
+	t        *testing.T
+	conn     client.Conn
+	nextID   int
+	messages chan *message
+	done     chan struct{}
+}
+
+// newPeer starts reading the messages of conn. The connection is closed
+// when the test ends.
+func newPeer(t *testing.T, conn client.Conn) *peer {
+	p := &peer{t: t, conn: conn, messages: make(chan *message, 64), done: make(chan struct{})}
+	go p.read()
+	t.Cleanup(func() {
+		close(p.done)
+		conn.Close()
+	})
+	return p
+}
+
+// read queues the messages of the connection until it closes
+func (p *peer) read() {
+	defer close(p.messages)
+	for {
+		data, err := p.conn.Read()
+		if err != nil {
+			return
+		}
+		msg := new(message)
+		if err := json.Unmarshal(data, msg); err != nil {
+			msg.invalid = data
+		}
+		select {
+		case p.messages <- msg:
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// write sends a message, returning the error of the transport
+func (p *peer) write(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		p.t.Fatalf("failed to marshal message: %v", err)
+	}
+	return p.writeRaw(data)
+}
+
+// writeRaw sends data as is, returning the error of the transport
+func (p *peer) writeRaw(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	return p.conn.Write(ctx, data)
+}
+
+// send sends a request and returns its ID
+func (p *peer) send(method string, params interface{}) json.RawMessage {
+	p.t.Helper()
+	id := p.newID()
+	if err := p.write(p.request(id, method, params)); err != nil {
+		p.t.Fatalf("failed to send %s: %v", method, err)
+	}
+	return id
+}
+
+// newID returns the ID of a new request
+func (p *peer) newID() json.RawMessage {
+	p.nextID++
+	return json.RawMessage(strconv.Itoa(p.nextID))
+}
+
+// request returns a request message
+func (p *peer) request(id json.RawMessage, method string, params interface{}) map[string]interface{} {
+	req := map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method}
+	if params != nil {
+		req["params"] = params
+	}
+	return req
+}
+
+// notify sends a notification
+func (p *peer) notify(method string, params interface{}) {
+	p.t.Helper()
+	notif := map[string]interface{}{"jsonrpc": "2.0", "method": method}
+	if params != nil {
+		notif["params"] = params
+	}
+	if err := p.write(notif); err != nil {
+		p.t.Fatalf("failed to send %s: %v", method, err)
+	}
+}
+
+// await returns the response with the given ID, skipping other messages.
+// A nil ID matches responses without an ID, such as parse errors.
+func (p *peer) await(id json.RawMessage) *message {
+	p.t.Helper()
+	timeout := time.NewTimer(Timeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case msg, ok := <-p.messages:
+			if !ok {
+				p.t.Fatalf("connection closed waiting for the response to %s", idString(id))
+			}
+			if msg.invalid != nil {
+				p.t.Errorf("server sent invalid JSON %q", msg.invalid)
+				continue
+			}
+			if msg.isResponse() && idString(msg.ID) == idString(id) {
+				return msg
+			}
+		case <-timeout.C:
+			p.t.Fatalf("no response to %s within %s", idString(id), Timeout)
+		}
+	}
+}
+
+// call sends a request and returns its response
+func (p *peer) call(method string, params interface{}) *message {
+	p.t.Helper()
+	return p.await(p.send(method, params))
+}
+
+// expectResult sends a request and decodes its result into result,
+// failing the check on an error response
+func (p *peer) expectResult(method string, params interface{}, result interface{}) {
+	p.t.Helper()
+	resp := p.call(method, params)
+	if resp.Error != nil {
+		p.t.Fatalf("%s failed: %d %s", method, resp.Error.Code, resp.Error.Message)
+	}
+	if result == nil {
+		return
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		p.t.Fatalf("invalid %s result %s: %v", method, resp.Result, err)
+	}
+}
+
+// expectError sends a request and fails the check unless it is answered
+// with an error of the given code
+func (p *peer) expectError(method string, params interface{}, code int) {
+	p.t.Helper()
+	resp := p.call(method, params)
+	switch {
+	case resp.Error == nil:
+		p.t.Errorf("%s succeeded with %s, want error code %d", method, resp.Result, code)
+	case resp.Error.Code != code:
+		p.t.Errorf("%s failed with code %d (%s), want %d", method, resp.Error.Code, resp.Error.Message, code)
+	}
+}
+
+// initialize performs the initialization handshake and returns the
+// server's result
+func (p *peer) initialize() *protocol.InitializeResult {
+	p.t.Helper()
+	var result protocol.InitializeResult
+	p.expectResult("initialize", initializeParams(protocol.LatestProtocolVersion), &result)
+	p.notify("notifications/initialized", nil)
+	return &result
+}
+
+// initializeParams returns the params of an initialize request offering
+// the given protocol revision
+func initializeParams(version string) protocol.InitializeRequestParams {
+	return protocol.InitializeRequestParams{
+		ProtocolVersion: version,
+		ClientInfo:      protocol.Implementation{Name: "conformance", Version: "1.0.0"},
+	}
+}
+
+// idString returns an ID in a comparable form, "null" for a missing ID
+func idString(id json.RawMessage) string {
+	if len(id) == 0 {
+		return "null"
+	}
+	return string(id)
+}
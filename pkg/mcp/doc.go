@@ -9,6 +9,10 @@
 //   - protocol: Core protocol types and message definitions
 //   - server: Server implementation with session management
 //   - transport: Transport layer implementations (stdio, SSE, WebSocket)
+//   - client: Client for connecting to MCP servers
+//   - proxy: Aggregation of backend servers behind one server
+//   - openapi: Tools generated from OpenAPI 3 documents
+//   - grpcimport: Tools generated from gRPC services
 //
 // Basic usage example:
 //
@@ -8,6 +8,9 @@
 //
 //	app := fastmcp.New("My App")
 //
+//	// Tell hosts how to use the server
+//	app.Instructions("Greet people by name.")
+//
 //	// Add tools
 //	app.Tool("greet", func(name string) string {
 //	    return "Hello, " + name + "!"
@@ -37,11 +40,115 @@
 //	    }
 //	}, "Confirmation prompt")
 //
-//	// Run with stdio transport
-//	if err := app.RunStdio(); err != nil {
+//	// Run on the transport chosen by -transport or MCP_TRANSPORT
+//	if err := app.Run(); err != nil {
 //	    log.Fatal(err)
 //	}
 //
+// Tool Options:
+//
+//	// Declare the schema, behavior and time limit of a tool
+//	app.Tool("search", search, "Search the index",
+//	    fastmcp.WithSchema(searchSchema),
+//	    fastmcp.WithAnnotations(protocol.ToolAnnotations{ReadOnlyHint: &readOnly}),
+//	    fastmcp.WithTimeout(10*time.Second),
+//	)
+//
+//	// Options of the core server apply too
+//	app.Tool("weather", weather, "Current weather",
+//	    fastmcp.WithToolOptions(server.WithOutputSchema(weatherSchema)),
+//	)
+//
+// Typed Tools:
+//
+// AddTool registers a handler taking its arguments as a struct, checked at
+// compile time, with the input and output schemas derived from its types:
+//
+//	type SearchArgs struct {
+//	    Query string `json:"query" description:"Search text"`
+//	    Limit int    `json:"limit,omitempty"`
+//	}
+//
+//	fastmcp.AddTool(app, "search", func(ctx context.Context, args SearchArgs) (Results, error) {
+//	    return index.Search(ctx, args.Query, args.Limit)
+//	}, "Search the index")
+//
+// Tool Sets:
+//
+// ToolSet registers every exported method of a service as a tool named
+// prefix_method, described by struct tags or a Describe method:
+//
+//	type Math struct {
+//	    _ struct{} `tool:"Add" description:"Adds two numbers"`
+//	    _ struct{} `tool:"Sqrt" description:"Square root of a number"`
+//	}
+//
+//	func (m *Math) Add(a, b float64) float64 { return a + b }
+//	func (m *Math) Sqrt(x float64) (float64, error) { ... }
+//
+//	// Registers math_add and math_sqrt
+//	app.ToolSet("math", &Math{})
+//
+// Composing Apps:
+//
+// Mount combines independently built apps into one server, namespacing
+// their tools, resources and prompts:
+//
+//	app := fastmcp.New("Platform").
+//	    Mount("billing", billing.New()).
+//	    Mount("crm", crm.New())
+//
+//	// billing's "invoice" tool is served as billing_invoice
+//
+// Middleware:
+//
+// Use adds tool interceptors to every tool of the app, and UseMiddleware
+// wraps every JSON-RPC request on all sessions:
+//
+//	app.Use(func(ctx context.Context, name string, args map[string]interface{}, next server.ToolInvoker) (protocol.CallToolResult, error) {
+//	    start := time.Now()
+//	    defer func() { toolLatency.WithLabelValues(name).Observe(time.Since(start).Seconds()) }()
+//	    return next(ctx, name, args)
+//	})
+//
+//	app.UseMiddleware(func(next server.Handler) server.Handler {
+//	    return func(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+//	        if server.AuthInfoFromContext(ctx) == nil {
+//	            return nil, protocol.NewError(protocol.InvalidRequest, "unauthenticated")
+//	        }
+//	        return next(ctx, req)
+//	    }
+//	})
+//
+// Groups:
+//
+// Group scopes registrations under a shared prefix, description and
+// interceptors, like router groups in web frameworks:
+//
+//	files := app.Group("files").
+//	    Describe("Operates on the files of the workspace.").
+//	    Use(requireRole("editor"))
+//
+//	// Registers files_read and files_write, both requiring the role
+//	files.Tool("read", readFile, "Reads a file")
+//	files.Tool("write", writeFile, "Writes a file")
+//
+//	// Registers file://files/{path}
+//	files.Resource("file://{path}", fileContents, "A workspace file")
+//
+// Testing:
+//
+// fastmcptest.NewClient connects a client to the app in memory, with the
+// handshake done, for table-driven tests without goroutines, pipes or ports:
+//
+//	func TestEcho(t *testing.T) {
+//	    tc := fastmcptest.NewClient(t, newApp())
+//	    tc.ExpectToolResult("echo", map[string]interface{}{"arg0": "hi"}, "hi")
+//	}
+//
+// The client is an mcptest.Client; see package mcptest for its assertion
+// helpers.
+//
 // Server Capabilities:
 //
 // FastMCP automatically configures default server capabilities:
@@ -62,6 +169,59 @@
 //	    },
 //	}))
 //
+// Running:
+//
+// Run picks the transport from the -transport and -addr flags, or the
+// MCP_TRANSPORT and MCP_ADDR environment variables, defaulting to stdio. It
+// handles SIGINT and SIGTERM by shutting the server down gracefully:
+//
+//	// go run . -transport websocket -addr :8080
+//	// MCP_TRANSPORT=http MCP_ADDR=:9000 go run .
+//	if err := app.Run(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// The transports are stdio, sse, websocket, tcp, streamable (streamable
+// HTTP alone) and http (WebSocket, SSE and streamable HTTP on one port, at
+// /ws, /sse and /mcp, as with RunHTTP).
+// Run parses its flags into a flag set of its own; RunArgs takes the
+// arguments to parse instead of the command line.
+//
+// RunWithContext serves any transport until ctx is cancelled, then stops it
+// and drains in-flight requests and sessions, so apps can be stopped
+// programmatically:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	t := transport.NewWebSocketTransport(app.Server(), transport.WithAddress(":8080"))
+//	go func() {
+//	    if err := app.RunWithContext(ctx, t); err != nil {
+//	        log.Print(err)
+//	    }
+//	}()
+//
+// Configuration from Environment:
+//
+// NewFromEnv configures an app from environment variables, for
+// containerized deployments:
+//
+//	// MCP_NAME=billing MCP_TRANSPORT=http MCP_ADDR=:8443
+//	// MCP_TLS_CERT=/certs/tls.crt MCP_TLS_KEY=/certs/tls.key
+//	// MCP_WIRE_TAP=/tmp/mcp-wire.log (every frame, for debugging)
+//	// MCP_RECORD=/tmp/session.jsonl (frames for transport.Replay)
+//	// MCP_LOG_LEVEL=debug MCP_RESOURCES_SUBSCRIBE=false
+//	app, err := fastmcp.NewFromEnv()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	app.Tool("invoice", invoice, "Look up an invoice")
+//	log.Fatal(app.Run())
+//
+// EnvServerOptions and EnvTransportOptions return the same settings as
+// options, for apps created with New or core servers. EnvTransportOptions
+// also returns a function closing the MCP_WIRE_TAP and MCP_RECORD files,
+// which Run calls for apps created with NewFromEnv.
+//
 // Transport Options:
 //
 //	// Run with stdio (for CLI apps)
@@ -73,6 +233,13 @@
 //	// Run with SSE (for web browsers)
 //	app.RunSSE(":8080")
 //
+//	// Run with TCP (for embedded deployments)
+//	app.RunTCP(":9000")
+//
+//	// Run WebSocket, SSE and streamable HTTP on one port, at /ws,
+//	// /sse and /mcp
+//	app.RunHTTP(":8080")
+//
 // The FastMCP API is designed to be chainable:
 //
 //	fastmcp.New("My App").
@@ -89,6 +256,21 @@
 //	func(string) string
 //	func(int) (int, error)
 //
+//	// Tools without a return value
+//	func(path string) error
+//	func()
+//
+//	// Tools returning multiple content blocks
+//	func(query string) ([]interface{}, error)
+//	func(query string) (protocol.CallToolResult, error)
+//
+//	// Tools returning images (encoded as base64 image content)
+//	func(path string) (*mcp.Image, error)
+//	func(width, height int) image.Image
+//
+//	// Tools producing resources (returned as embedded resources)
+//	func(name string) (protocol.TextResourceContents, error)
+//
 //	// Complex tools with structs
 //	func(User) (*User, error)
 //	func(SearchParams) ([]Result, error)
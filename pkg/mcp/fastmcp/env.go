@@ -0,0 +1,193 @@
+package fastmcp
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/transport"
+)
+
+// DefaultName is the name of apps created by NewFromEnv without MCP_NAME
+const DefaultName = "MCP Server"
+
+// Environment variables configuring apps
+const (
+	EnvName         = "MCP_NAME"
+	EnvInstructions = "MCP_INSTRUCTIONS"
+	EnvTransport    = "MCP_TRANSPORT"
+	EnvAddr         = "MCP_ADDR"
+	EnvLogLevel     = "MCP_LOG_LEVEL"
+	EnvTLSCert      = "MCP_TLS_CERT"
+	EnvTLSKey       = "MCP_TLS_KEY"
+	EnvWireTap      = "MCP_WIRE_TAP"
+	EnvRecord       = "MCP_RECORD"
+
+	// Capability toggles, all enabled unless set to false
+	EnvToolsListChanged     = "MCP_TOOLS_LIST_CHANGED"
+	EnvResourcesSubscribe   = "MCP_RESOURCES_SUBSCRIBE"
+	EnvResourcesListChanged = "MCP_RESOURCES_LIST_CHANGED"
+	EnvPromptsListChanged   = "MCP_PROMPTS_LIST_CHANGED"
+	EnvLogging              = "MCP_LOGGING"
+)
+
+// NewFromEnv creates an app configured by environment variables, for
+// containerized deployments: MCP_NAME, MCP_INSTRUCTIONS, MCP_LOG_LEVEL,
+// the capability toggles and, for the transports started by Run,
+// MCP_TLS_CERT, MCP_TLS_KEY, MCP_WIRE_TAP and MCP_RECORD. Run also reads
+// MCP_TRANSPORT and MCP_ADDR, and closes the MCP_WIRE_TAP and MCP_RECORD
+// files when it returns.
+// Options override the environment.
+func NewFromEnv(options ...server.ServerOption) (*FastMCP, error) {
+	serverOptions, err := EnvServerOptions()
+	if err != nil {
+		return nil, err
+	}
+	transportOptions, closeFiles, err := EnvTransportOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	name := os.Getenv(EnvName)
+	if name == "" {
+		name = DefaultName
+	}
+	app := New(name, append(serverOptions, options...)...)
+	app.transportOptions = transportOptions
+	app.closeEnv = closeFiles
+	return app, nil
+}
+
+// EnvServerOptions returns the server options set by MCP_INSTRUCTIONS,
+// MCP_LOG_LEVEL and the capability toggles
+func EnvServerOptions() ([]server.ServerOption, error) {
+	var options []server.ServerOption
+	if instructions := os.Getenv(EnvInstructions); instructions != "" {
+		options = append(options, server.WithInstructions(instructions))
+	}
+
+	level, ok, err := envLogLevel()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+		options = append(options, server.WithLogger(logger))
+	}
+
+	toggles := make(map[string]bool)
+	for _, env := range []string{EnvToolsListChanged, EnvResourcesSubscribe, EnvResourcesListChanged, EnvPromptsListChanged, EnvLogging} {
+		if toggles[env], err = envBool(env, true); err != nil {
+			return nil, err
+		}
+	}
+	capabilities := protocol.ServerCapabilities{
+		Tools: &protocol.ToolsCapability{
+			ListChanged: boolPtr(toggles[EnvToolsListChanged]),
+		},
+		Resources: &protocol.ResourcesCapability{
+			Subscribe:   boolPtr(toggles[EnvResourcesSubscribe]),
+			ListChanged: boolPtr(toggles[EnvResourcesListChanged]),
+		},
+		Prompts: &protocol.PromptsCapability{
+			ListChanged: boolPtr(toggles[EnvPromptsListChanged]),
+		},
+	}
+	if toggles[EnvLogging] {
+		capabilities.Logging = &protocol.LoggingCapability{}
+	}
+	return append(options, server.WithCapabilities(capabilities)), nil
+}
+
+// EnvTransportOptions returns the transport options set by MCP_LOG_LEVEL,
+// the TLS certificate and key files named by MCP_TLS_CERT and MCP_TLS_KEY,
+// MCP_WIRE_TAP, the file every frame is appended to for debugging, and
+// MCP_RECORD, the file frames are recorded to for replaying with
+// transport.Replay. The returned function closes those files once the
+// transports using the options have stopped.
+func EnvTransportOptions() ([]transport.Option, func() error, error) {
+	var options []transport.Option
+	level, ok, err := envLogLevel()
+	if err != nil {
+		return nil, nil, err
+	}
+	if ok {
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+		options = append(options, transport.WithLogger(logger))
+	}
+
+	certFile, keyFile := os.Getenv(EnvTLSCert), os.Getenv(EnvTLSKey)
+	switch {
+	case certFile == "" && keyFile == "":
+	case certFile == "" || keyFile == "":
+		return nil, nil, fmt.Errorf("%s and %s must be set together", EnvTLSCert, EnvTLSKey)
+	default:
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		options = append(options, transport.WithTLSConfig(&tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}))
+	}
+
+	var files []*os.File
+	closeFiles := func() error {
+		var errs []error
+		for _, f := range files {
+			errs = append(errs, f.Close())
+		}
+		return errors.Join(errs...)
+	}
+	if path := os.Getenv(EnvWireTap); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open wire tap: %w", err)
+		}
+		files = append(files, f)
+		options = append(options, transport.WithWireTap(f))
+	}
+	if path := os.Getenv(EnvRecord); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			closeFiles()
+			return nil, nil, fmt.Errorf("failed to open recording: %w", err)
+		}
+		files = append(files, f)
+		options = append(options, transport.WithRecorder(transport.NewRecorder(f)))
+	}
+	return options, closeFiles, nil
+}
+
+// envLogLevel returns the level set by MCP_LOG_LEVEL, if any
+func envLogLevel() (slog.Level, bool, error) {
+	value := os.Getenv(EnvLogLevel)
+	if value == "" {
+		return 0, false, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return 0, false, fmt.Errorf("invalid %s: %w", EnvLogLevel, err)
+	}
+	return level, true, nil
+}
+
+// envBool returns the boolean value of an environment variable, or
+// fallback when it isn't set
+func envBool(env string, fallback bool) (bool, error) {
+	value := os.Getenv(env)
+	if value == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %q is not a boolean", env, value)
+	}
+	return b, nil
+}
@@ -0,0 +1,149 @@
+package fastmcp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/transport"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv(EnvName, "billing")
+	t.Setenv(EnvInstructions, "Look up invoices")
+	t.Setenv(EnvLogLevel, "warn")
+	t.Setenv(EnvResourcesSubscribe, "false")
+	t.Setenv(EnvLogging, "false")
+
+	app, err := NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Tool("invoice", func(id string) string { return "invoice " + id }, "Looks up an invoice")
+
+	result := mcptest.NewClient(t, app.Server()).Client.InitializeResult()
+	if result.ServerInfo.Name != "billing" || result.Instructions == nil || *result.Instructions != "Look up invoices" {
+		t.Errorf("expected the name and instructions from the environment, got %+v", result)
+	}
+	caps := result.Capabilities
+	if caps.Resources == nil || caps.Resources.Subscribe == nil || *caps.Resources.Subscribe {
+		t.Errorf("expected resource subscriptions to be disabled, got %+v", caps.Resources)
+	}
+	if caps.Resources.ListChanged == nil || !*caps.Resources.ListChanged {
+		t.Errorf("expected resource list changes to stay enabled, got %+v", caps.Resources)
+	}
+	if caps.Logging != nil {
+		t.Error("expected logging to be disabled")
+	}
+}
+
+func TestNewFromEnvDefaults(t *testing.T) {
+	for _, env := range []string{EnvName, EnvLogLevel, EnvTLSCert, EnvTLSKey, EnvWireTap, EnvRecord} {
+		t.Setenv(env, "")
+	}
+	app, err := NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := mcptest.NewClient(t, app.Server()).Client.InitializeResult().ServerInfo.Name; name != DefaultName {
+		t.Errorf("expected the default name, got %q", name)
+	}
+}
+
+func TestNewFromEnvErrors(t *testing.T) {
+	tests := map[string]struct {
+		env  map[string]string
+		want string
+	}{
+		"invalid log level": {map[string]string{EnvLogLevel: "loud"}, EnvLogLevel},
+		"invalid toggle":    {map[string]string{EnvLogging: "maybe"}, EnvLogging},
+		"lone certificate":  {map[string]string{EnvTLSCert: "tls.crt"}, "must be set together"},
+		"missing key pair":  {map[string]string{EnvTLSCert: "missing.crt", EnvTLSKey: "missing.key"}, "TLS certificate"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for env, value := range tt.env {
+				t.Setenv(env, value)
+			}
+			if _, err := NewFromEnv(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNewFromEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	wirePath, recordPath := filepath.Join(dir, "wire.log"), filepath.Join(dir, "session.jsonl")
+	t.Setenv(EnvWireTap, wirePath)
+	t.Setenv(EnvRecord, recordPath)
+
+	app, err := NewFromEnv(server.WithLogger(discardLogger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.TransportOptions(transport.WithLogger(discardLogger))
+	app.Tool("echo", func(s string) string { return s }, "Echoes")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	tr, err := app.newTransport("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- app.RunWithContext(ctx, tr) }()
+
+	// Ping the server so both files record frames
+	var c *transport.TCPClient
+	for deadline := time.Now().Add(5 * time.Second); c == nil; {
+		if c, err = transport.DialTCP(ctx, addr); err != nil {
+			if time.Now().After(deadline) {
+				t.Fatal(err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	defer c.Close()
+	if err := c.Send(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(1), Method: "ping"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Receive(); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+
+	// Run closes the files once the transport has stopped
+	if err := app.closeEnv(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected the files to be closed, got %v", err)
+	}
+	if wire, err := os.ReadFile(wirePath); err != nil || !strings.Contains(string(wire), `"method": "ping"`) {
+		t.Errorf("expected the wire tap to record the ping, got %q (%v)", wire, err)
+	}
+	f, err := os.Open(recordPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if frames, err := transport.ReadFrames(f); err != nil || len(frames) != 2 {
+		t.Errorf("expected the request and response to be recorded, got %+v (%v)", frames, err)
+	}
+}
@@ -3,7 +3,6 @@ package fastmcp
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
@@ -12,9 +11,13 @@ import (
 
 // FastMCP provides a simplified interface for creating MCP servers
 type FastMCP struct {
-	name    string
-	server  *server.Server
-	options []server.ServerOption
+	name             string
+	server           *server.Server
+	options          []server.ServerOption
+	transportOptions []transport.Option
+
+	// closeEnv closes the files opened by NewFromEnv, if any
+	closeEnv func() error
 }
 
 // New creates a new FastMCP instance with default capabilities
@@ -45,57 +48,117 @@ func New(name string, options ...server.ServerOption) *FastMCP {
 	}
 }
 
-// Tool registers a synchronous tool with the server
-func (f *FastMCP) Tool(name string, handler interface{}, description string) *FastMCP {
+// ensureServer creates the server on first registration
+func (f *FastMCP) ensureServer() {
 	if f.server == nil {
 		f.server = server.NewServer(f.name, f.options...)
 	}
-	if err := f.server.AddTool(name, handler, description); err != nil {
-		log.Printf("Warning: Failed to add tool %s: %v", name, err)
+}
+
+// Tool registers a synchronous tool with the server
+func (f *FastMCP) Tool(name string, handler interface{}, description string, opts ...ToolOption) *FastMCP {
+	f.ensureServer()
+	config := newToolConfig(name, opts)
+	if err := f.server.AddTool(config.name, handler, description, config.options...); err != nil {
+		f.server.Logger().Warn("failed to add tool", "tool", config.name, "error", err)
 	}
 	return f
 }
 
 // AsyncTool registers an asynchronous tool with the server
-func (f *FastMCP) AsyncTool(name string, handler interface{}, description string) *FastMCP {
-	if f.server == nil {
-		f.server = server.NewServer(f.name, f.options...)
-	}
-	if err := f.server.AddAsyncTool(name, handler, description); err != nil {
-		log.Printf("Warning: Failed to add async tool %s: %v", name, err)
+func (f *FastMCP) AsyncTool(name string, handler interface{}, description string, opts ...ToolOption) *FastMCP {
+	f.ensureServer()
+	config := newToolConfig(name, opts)
+	if err := f.server.AddAsyncTool(config.name, handler, description, config.options...); err != nil {
+		f.server.Logger().Warn("failed to add async tool", "tool", config.name, "error", err)
 	}
 	return f
 }
 
 // Resource registers a resource with the server
-func (f *FastMCP) Resource(pattern string, handler interface{}, description string) *FastMCP {
-	if f.server == nil {
-		f.server = server.NewServer(f.name, f.options...)
-	}
-	if err := f.server.AddResource(pattern, handler, description); err != nil {
-		log.Printf("Warning: Failed to add resource %s: %v", pattern, err)
+func (f *FastMCP) Resource(pattern string, handler interface{}, description string, opts ...server.ResourceOption) *FastMCP {
+	f.ensureServer()
+	if err := f.server.AddResource(pattern, handler, description, opts...); err != nil {
+		f.server.Logger().Warn("failed to add resource", "pattern", pattern, "error", err)
 	}
 	return f
 }
 
 // Prompt registers a prompt with the server
-func (f *FastMCP) Prompt(name string, handler interface{}, description string) *FastMCP {
-	if f.server == nil {
-		f.server = server.NewServer(f.name, f.options...)
+func (f *FastMCP) Prompt(name string, handler interface{}, description string, opts ...server.PromptOption) *FastMCP {
+	f.ensureServer()
+	if err := f.server.AddPrompt(name, handler, description, opts...); err != nil {
+		f.server.Logger().Warn("failed to add prompt", "prompt", name, "error", err)
+	}
+	return f
+}
+
+// Mount adds the tools, resources and prompts of another app under a
+// prefix, so independently built apps can be served by one process. Tools
+// and prompts are named prefix_name and resource URIs get the prefix as
+// their first path segment, as with server.Mount. What other registers
+// afterwards is not mounted, and its calls run through this app's
+// interceptors and middleware rather than other's.
+func (f *FastMCP) Mount(prefix string, other *FastMCP) *FastMCP {
+	f.ensureServer()
+	if other.server == nil {
+		return f
+	}
+	if err := f.server.Mount(prefix, other.server); err != nil {
+		f.server.Logger().Warn("failed to mount app", "prefix", prefix, "app", other.name, "error", err)
 	}
-	if err := f.server.AddPrompt(name, handler, description); err != nil {
-		log.Printf("Warning: Failed to add prompt %s: %v", name, err)
+	return f
+}
+
+// Instructions sets the instructions sent to clients when they initialize,
+// describing how to use the server. It must be called before the server is
+// run.
+func (f *FastMCP) Instructions(instructions string) *FastMCP {
+	opt := server.WithInstructions(instructions)
+	if f.server == nil {
+		f.options = append(f.options, opt)
+	} else {
+		opt(f.server)
 	}
 	return f
 }
 
+// Use adds interceptors wrapping the calls of every tool registered on the
+// app, such as authorization, logging or metrics. Interceptors run in the
+// order they are added, so the first interceptor is the outermost.
+func (f *FastMCP) Use(interceptors ...server.ToolInterceptor) *FastMCP {
+	f.ensureServer()
+	f.server.UseToolInterceptor(interceptors...)
+	return f
+}
+
+// UseMiddleware adds middleware wrapping every JSON-RPC request the app
+// handles, on all transports and sessions
+func (f *FastMCP) UseMiddleware(middleware ...server.Middleware) *FastMCP {
+	f.ensureServer()
+	f.server.Use(middleware...)
+	return f
+}
+
+// TransportOptions sets options applied to the transports the app's Run
+// methods create, such as TLS or message size limits
+func (f *FastMCP) TransportOptions(options ...transport.Option) *FastMCP {
+	f.transportOptions = append(f.transportOptions, options...)
+	return f
+}
+
+// transportOpts returns the app's transport options followed by extra
+func (f *FastMCP) transportOpts(extra ...transport.Option) []transport.Option {
+	return append(append([]transport.Option(nil), f.transportOptions...), extra...)
+}
+
 // RunStdio starts the server with stdio transport
 func (f *FastMCP) RunStdio() error {
 	if f.server == nil {
 		return fmt.Errorf("no server configured")
 	}
 	session := server.NewSession(context.Background(), f.server)
-	t := transport.NewStdioTransport(session)
+	t := transport.NewStdioTransport(session, f.transportOpts()...)
 	return t.Start()
 }
 
@@ -104,8 +167,7 @@ func (f *FastMCP) RunWebSocket(addr string) error {
 	if f.server == nil {
 		return fmt.Errorf("no server configured")
 	}
-	session := server.NewSession(context.Background(), f.server)
-	t := transport.NewWebSocketTransport(session, transport.WithAddress(addr))
+	t := transport.NewWebSocketTransport(f.server, f.transportOpts(transport.WithAddress(addr))...)
 	return t.Start()
 }
 
@@ -114,13 +176,34 @@ func (f *FastMCP) RunSSE(addr string) error {
 	if f.server == nil {
 		return fmt.Errorf("no server configured")
 	}
-	session := server.NewSession(context.Background(), f.server)
-	t := transport.NewSSETransport(session, transport.WithAddress(addr))
+	t := transport.NewSSETransport(f.server, f.transportOpts(transport.WithAddress(addr))...)
+	return t.Start()
+}
+
+// RunTCP starts the server with TCP transport
+func (f *FastMCP) RunTCP(addr string) error {
+	if f.server == nil {
+		return fmt.Errorf("no server configured")
+	}
+	t := transport.NewTCPTransport(f.server, f.transportOpts(transport.WithAddress(addr))...)
+	return t.Start()
+}
+
+// RunHTTP starts the server with the WebSocket, SSE and streamable HTTP
+// transports on one address, at /ws, /sse and /mcp. SSE clients post their
+// messages to /message, and /events is kept as an alias of /sse.
+func (f *FastMCP) RunHTTP(addr string) error {
+	if f.server == nil {
+		return fmt.Errorf("no server configured")
+	}
+	t := transport.NewMultiHTTPTransport(f.server, f.transportOpts(transport.WithAddress(addr))...)
 	return t.Start()
 }
 
-// Server returns the underlying server instance
+// Server returns the underlying server instance, creating it if nothing
+// has been registered yet
 func (f *FastMCP) Server() *server.Server {
+	f.ensureServer()
 	return f.server
 }
 
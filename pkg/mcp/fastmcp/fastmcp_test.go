@@ -0,0 +1,37 @@
+package fastmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+)
+
+func TestMount(t *testing.T) {
+	billing := New("billing")
+	billing.Tool("invoice", func(id string) string { return "invoice " + id }, "Looks up an invoice")
+	billing.Resource("docs://terms", func() string { return "terms" }, "Payment terms")
+	billing.Prompt("remind", func() string { return "Send a reminder" }, "Reminds a customer")
+
+	app := New("gateway")
+	app.Tool("status", func() string { return "ok" }, "Reports status")
+	app.Mount("billing", billing)
+	app.Mount("empty", New("empty"))
+
+	tc := mcptest.NewClient(t, app.Server())
+	if got, want := strings.Join(toolNames(tc.Tools()), ","), "billing_invoice,status"; got != want {
+		t.Errorf("got tools %s, want %s", got, want)
+	}
+	tc.ExpectToolResult("billing_invoice", map[string]interface{}{"arg0": "42"}, "invoice 42")
+	if len(tc.ReadResource("docs://billing/terms").Contents) != 1 {
+		t.Error("expected the mounted resource under the prefix")
+	}
+	prompts, err := tc.Client.Prompts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 1 || prompts[0].Name != "billing_remind" {
+		t.Errorf("expected the mounted prompt under the prefix, got %+v", prompts)
+	}
+}
@@ -0,0 +1,31 @@
+// Package fastmcptest provides helpers for end-to-end tests of fastmcp
+// apps. It is separate from package fastmcp so that apps don't link the
+// testing package.
+//
+// NewClient connects a client to an app in memory, with the handshake
+// done, for table-driven tests without goroutines, pipes or ports:
+//
+//	func TestEcho(t *testing.T) {
+//	    tc := fastmcptest.NewClient(t, newApp())
+//	    tc.ExpectToolResult("echo", map[string]interface{}{"arg0": "hi"}, "hi")
+//	}
+//
+// The client is an mcptest.Client; see package mcptest for its assertion
+// helpers.
+package fastmcptest
+
+import (
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/fastmcp"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+)
+
+// NewClient connects a client to app over an in-memory connection and
+// performs the initialization handshake. The connection is closed when the
+// test ends.
+func NewClient(t testing.TB, app *fastmcp.FastMCP, options ...client.Option) *mcptest.Client {
+	t.Helper()
+	return mcptest.NewClient(t, app.Server(), options...)
+}
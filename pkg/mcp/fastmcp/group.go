@@ -0,0 +1,168 @@
+package fastmcp
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// Group registers tools, resources and prompts under a shared prefix, like
+// a router group in a web framework. Tools and prompts are named
+// prefix_name, and resource URIs get the prefix as their first path
+// segment, as with server.Mount. The group's description is prepended to
+// the descriptions of everything it registers, and its interceptors wrap
+// the calls of its tools.
+type Group struct {
+	app          *FastMCP
+	parent       *Group
+	prefix       string
+	description  string
+	tools        map[string]bool
+	interceptors []server.ToolInterceptor
+	installed    bool
+	mu           sync.RWMutex
+}
+
+// Group returns a group registering on the app under prefix
+func (f *FastMCP) Group(prefix string) *Group {
+	return &Group{app: f, prefix: prefix, tools: make(map[string]bool)}
+}
+
+// Group returns a nested group, whose prefix and description extend this
+// group's and whose tools are wrapped by this group's interceptors too
+func (g *Group) Group(prefix string) *Group {
+	return &Group{
+		app:         g.app,
+		parent:      g,
+		prefix:      prefixedName(g.prefix, prefix),
+		description: g.description,
+		tools:       make(map[string]bool),
+	}
+}
+
+// Describe sets the context prepended to the descriptions of what the
+// group registers afterwards, such as the resources its tools work on
+func (g *Group) Describe(description string) *Group {
+	g.description = joinDescriptions(g.description, description)
+	return g
+}
+
+// Use adds interceptors wrapping the calls of the group's tools, including
+// those registered before. The group's interceptors join the app's chain
+// when Use is first called, so interceptors added to the app or enclosing
+// groups before then run first.
+func (g *Group) Use(interceptors ...server.ToolInterceptor) *Group {
+	g.mu.Lock()
+	g.interceptors = append(g.interceptors, interceptors...)
+	install := !g.installed
+	g.installed = true
+	g.mu.Unlock()
+
+	if install {
+		g.app.ensureServer()
+		g.app.server.UseToolInterceptor(g.intercept)
+	}
+	return g
+}
+
+// intercept runs the group's interceptors around the calls of its tools
+func (g *Group) intercept(ctx context.Context, name string, args map[string]interface{}, next server.ToolInvoker) (protocol.CallToolResult, error) {
+	g.mu.RLock()
+	member := g.tools[name]
+	interceptors := g.interceptors
+	g.mu.RUnlock()
+
+	if !member {
+		return next(ctx, name, args)
+	}
+	invoke := next
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, name string, args map[string]interface{}) (protocol.CallToolResult, error) {
+			return interceptor(ctx, name, args, next)
+		}
+	}
+	return invoke(ctx, name, args)
+}
+
+// addTool records a tool as a member of the group and its enclosing groups
+func (g *Group) addTool(name string) {
+	for group := g; group != nil; group = group.parent {
+		group.mu.Lock()
+		group.tools[name] = true
+		group.mu.Unlock()
+	}
+}
+
+// Tool registers a synchronous tool named prefix_name. A name given with
+// WithName is prefixed too.
+func (g *Group) Tool(name string, handler interface{}, description string, opts ...ToolOption) *Group {
+	opts = g.toolOptions(name, opts)
+	g.app.Tool(name, handler, joinDescriptions(g.description, description), opts...)
+	return g
+}
+
+// AsyncTool registers an asynchronous tool named prefix_name. A name given
+// with WithName is prefixed too.
+func (g *Group) AsyncTool(name string, handler interface{}, description string, opts ...ToolOption) *Group {
+	opts = g.toolOptions(name, opts)
+	g.app.AsyncTool(name, handler, joinDescriptions(g.description, description), opts...)
+	return g
+}
+
+// toolOptions records a tool as a member of the group and returns its
+// options followed by one prefixing the name they settle on
+func (g *Group) toolOptions(name string, opts []ToolOption) []ToolOption {
+	name = prefixedName(g.prefix, newToolConfig(name, opts).name)
+	g.addTool(name)
+	return append(opts[:len(opts):len(opts)], WithName(name))
+}
+
+// ToolSet registers the methods of service as tools named
+// prefix_set_method, as FastMCP.ToolSet does
+func (g *Group) ToolSet(prefix string, service interface{}, opts ...ToolOption) *Group {
+	eachToolSetMethod(service, func(method string, handler interface{}, description string) {
+		g.Tool(prefixedName(prefix, lowerCamel(method)), handler, description, opts...)
+	})
+	return g
+}
+
+// Resource registers a resource whose URI has the group's prefix as its
+// first path segment
+func (g *Group) Resource(pattern string, handler interface{}, description string, opts ...server.ResourceOption) *Group {
+	g.app.Resource(prefixedURI(g.prefix, pattern), handler, joinDescriptions(g.description, description), opts...)
+	return g
+}
+
+// Prompt registers a prompt named prefix_name
+func (g *Group) Prompt(name string, handler interface{}, description string, opts ...server.PromptOption) *Group {
+	g.app.Prompt(prefixedName(g.prefix, name), handler, joinDescriptions(g.description, description), opts...)
+	return g
+}
+
+// prefixedURI returns a resource pattern with prefix as its first path
+// segment, after the scheme if it has one
+func prefixedURI(prefix, pattern string) string {
+	if prefix == "" {
+		return pattern
+	}
+	if scheme, rest, ok := strings.Cut(pattern, "://"); ok {
+		return scheme + "://" + prefix + "/" + rest
+	}
+	return prefix + "/" + pattern
+}
+
+// joinDescriptions joins a group's description and an item's as paragraphs
+func joinDescriptions(context, description string) string {
+	switch {
+	case context == "":
+		return description
+	case description == "":
+		return context
+	default:
+		return context + "\n\n" + description
+	}
+}
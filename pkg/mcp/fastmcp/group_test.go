@@ -0,0 +1,112 @@
+package fastmcp
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// toolNames returns the sorted names of tools
+func toolNames(tools []protocol.Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recordCalls returns an interceptor appending the names of the tools it
+// wraps to calls
+func recordCalls(calls *[]string) server.ToolInterceptor {
+	return func(ctx context.Context, name string, args map[string]interface{}, next server.ToolInvoker) (protocol.CallToolResult, error) {
+		*calls = append(*calls, name)
+		return next(ctx, name, args)
+	}
+}
+
+func TestGroupPrefixes(t *testing.T) {
+	app := New("group")
+	files := app.Group("files").Describe("Workspace files.")
+	files.Tool("read", func(path string) string { return "read " + path }, "Reads a file")
+	files.Resource("file://{path}", func(path string) string { return path }, "A file")
+	files.Prompt("review", func() string { return "Review the file" }, "Reviews a file")
+	files.Group("dir").Tool("list", func() string { return "listed" }, "Lists a directory")
+	app.Tool("plain", func() string { return "plain" }, "Not grouped")
+
+	tc := mcptest.NewClient(t, app.Server())
+	tools := tc.Tools()
+	if got, want := strings.Join(toolNames(tools), ","), "files_dir_list,files_read,plain"; got != want {
+		t.Errorf("got tools %s, want %s", got, want)
+	}
+	for _, tool := range tools {
+		if strings.HasPrefix(tool.Name, "files_") && !strings.HasPrefix(tool.Description, "Workspace files.\n\n") {
+			t.Errorf("expected the group description on %s, got %q", tool.Name, tool.Description)
+		}
+	}
+	tc.ExpectToolResult("files_read", map[string]interface{}{"arg0": "go.mod"}, "read go.mod")
+	tc.ExpectToolResult("files_dir_list", nil, "listed")
+
+	templates, err := tc.Client.ResourceTemplates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].URITemplate != "file://files/{path}" {
+		t.Errorf("expected the resource under the group prefix, got %+v", templates)
+	}
+	prompts, err := tc.Client.Prompts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 1 || prompts[0].Name != "files_review" {
+		t.Errorf("expected the prompt under the group prefix, got %+v", prompts)
+	}
+}
+
+func TestGroupInterceptors(t *testing.T) {
+	var outer, inner []string
+	app := New("group")
+	files := app.Group("files")
+	files.Tool("read", func() string { return "read" }, "Reads a file")
+	files.Use(recordCalls(&outer))
+	dir := files.Group("dir").Use(recordCalls(&inner))
+	dir.Tool("list", func() string { return "listed" }, "Lists a directory")
+	app.Tool("plain", func() string { return "plain" }, "Not grouped")
+
+	tc := mcptest.NewClient(t, app.Server())
+	tc.ExpectToolResult("files_read", nil, "read")
+	tc.ExpectToolResult("files_dir_list", nil, "listed")
+	tc.ExpectToolResult("plain", nil, "plain")
+
+	// A group's interceptors wrap its tools and those of nested groups,
+	// including tools registered before Use
+	if got := strings.Join(outer, ","); got != "files_read,files_dir_list" {
+		t.Errorf("outer group intercepted %s", got)
+	}
+	if got := strings.Join(inner, ","); got != "files_dir_list" {
+		t.Errorf("inner group intercepted %s", got)
+	}
+}
+
+func TestGroupToolRenamed(t *testing.T) {
+	var calls []string
+	app := New("group")
+	files := app.Group("files").Use(recordCalls(&calls))
+	files.Tool("read", func() string { return "read" }, "Reads a file", WithName("cat"))
+	files.Group("dir").AsyncTool("list", func() string { return "listed" }, "Lists a directory", WithName("ls"))
+
+	// Renamed tools keep the group prefix and interceptors
+	tc := mcptest.NewClient(t, app.Server())
+	if got, want := strings.Join(toolNames(tc.Tools()), ","), "files_cat,files_dir_ls"; got != want {
+		t.Errorf("got tools %s, want %s", got, want)
+	}
+	tc.ExpectToolResult("files_cat", nil, "read")
+	if got := strings.Join(calls, ","); got != "files_cat" {
+		t.Errorf("expected the renamed tool to be intercepted, got %s", got)
+	}
+}
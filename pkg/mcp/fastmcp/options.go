@@ -0,0 +1,59 @@
+package fastmcp
+
+import (
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// ToolOption configures a tool registered with Tool or AsyncTool
+type ToolOption func(*toolConfig)
+
+// toolConfig holds the registration settings of a tool
+type toolConfig struct {
+	name    string
+	options []server.ToolOption
+}
+
+// newToolConfig applies tool options to the settings of a tool
+func newToolConfig(name string, opts []ToolOption) *toolConfig {
+	config := &toolConfig{name: name}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// WithName registers the tool under a different name
+func WithName(name string) ToolOption {
+	return func(c *toolConfig) {
+		c.name = name
+	}
+}
+
+// WithSchema declares the JSON schema of the tool's arguments, replacing
+// the one derived from the handler
+func WithSchema(schema map[string]interface{}) ToolOption {
+	return WithToolOptions(server.WithInputSchema(schema))
+}
+
+// WithAnnotations describes the tool's behavior to clients, such as
+// whether it only reads data
+func WithAnnotations(annotations protocol.ToolAnnotations) ToolOption {
+	return WithToolOptions(server.WithToolAnnotations(annotations))
+}
+
+// WithTimeout bounds each call of the tool; calls past it are reported as
+// error results
+func WithTimeout(timeout time.Duration) ToolOption {
+	return WithToolOptions(server.WithToolTimeout(timeout))
+}
+
+// WithToolOptions applies options of the core server to the tool, giving
+// access to tool features without a FastMCP equivalent
+func WithToolOptions(opts ...server.ToolOption) ToolOption {
+	return func(c *toolConfig) {
+		c.options = append(c.options, opts...)
+	}
+}
@@ -0,0 +1,174 @@
+package fastmcp
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/transport"
+)
+
+const (
+	// DefaultTransport is the transport Run serves when none is configured
+	DefaultTransport = "stdio"
+
+	// DefaultAddr is the address network transports listen on when none is
+	// configured
+	DefaultAddr = ":8080"
+
+	// shutdownTimeout bounds how long Run waits for in-flight work after a
+	// shutdown signal
+	shutdownTimeout = 10 * time.Second
+)
+
+// Run serves the app on the transport chosen by the -transport and -addr
+// flags, falling back to the MCP_TRANSPORT and MCP_ADDR environment
+// variables, then to stdio on :8080. The flags are parsed from the command
+// line into a flag set of Run's own. Programs parsing their own flags with
+// package flag may define -transport and -addr on flag.CommandLine instead;
+// Run then reads them there and leaves the command line alone. Run blocks
+// until the transport ends or SIGINT or SIGTERM is received, then shuts the
+// server down gracefully.
+//
+// Supported transports are stdio, sse, websocket, tcp, streamable, the
+// streamable HTTP transport alone, and http, which serves WebSocket at /ws,
+// SSE at /sse and streamable HTTP at /mcp on one address, as RunHTTP does.
+func (f *FastMCP) Run() error {
+	var args []string
+	if !flag.Parsed() {
+		args = os.Args[1:]
+	}
+	return f.RunArgs(args)
+}
+
+// RunArgs is like Run but parses the -transport and -addr flags from args
+// rather than the command line
+func (f *FastMCP) RunArgs(args []string) error {
+	if f.server == nil {
+		return fmt.Errorf("no server configured")
+	}
+	name, addr, err := runSettings(args)
+	if err != nil {
+		return err
+	}
+	t, err := f.newTransport(name, addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return f.RunWithContext(ctx, t)
+}
+
+// RunWithContext serves the app on t until the transport ends or ctx is
+// done. Then it shuts the server down, stopping t and draining in-flight
+// requests and sessions for up to 10 seconds, and closes the files opened
+// by NewFromEnv. It returns nil after a clean shutdown, so cancelling ctx
+// stops the app programmatically.
+func (f *FastMCP) RunWithContext(ctx context.Context, t transport.Transport) (err error) {
+	if f.server == nil {
+		return fmt.Errorf("no server configured")
+	}
+	if f.closeEnv != nil {
+		defer func() {
+			if closeErr := f.closeEnv(); closeErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to close files: %w", closeErr))
+			}
+		}()
+	}
+	f.server.RegisterTransport(t)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- t.Start()
+	}()
+
+	returned := false
+	select {
+	case err := <-errChan:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("transport error: %w", err)
+		}
+		returned = true
+	case <-ctx.Done():
+		f.server.Logger().Info("shutting down")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err = f.server.Shutdown(shutdownCtx)
+	if !returned {
+		// Wait for the transport to return after being stopped
+		if startErr := <-errChan; startErr != nil && !errors.Is(startErr, http.ErrServerClosed) {
+			err = errors.Join(err, fmt.Errorf("transport error: %w", startErr))
+		}
+	}
+	return err
+}
+
+// runSettings returns the transport name and address Run serves, parsing
+// the flags from args
+func runSettings(args []string) (string, string, error) {
+	flags := flag.NewFlagSet("fastmcp", flag.ContinueOnError)
+	transportFlag := flags.String("transport", "", "Transport type (stdio, sse, websocket, tcp, streamable or http)")
+	addrFlag := flags.String("addr", "", "Address to listen on for network transports")
+	if err := flags.Parse(args); err != nil {
+		return "", "", err
+	}
+	return setting(*transportFlag, "transport", EnvTransport, DefaultTransport), setting(*addrFlag, "addr", EnvAddr, DefaultAddr), nil
+}
+
+// setting returns value if a flag was given in args, then the named flag
+// of flag.CommandLine if the program set it, then the environment
+// variable, then the program's default for the flag and finally fallback
+func setting(value, name, env, fallback string) string {
+	if value != "" {
+		return value
+	}
+	program := flag.Lookup(name)
+	set := false
+	flag.Visit(func(visited *flag.Flag) {
+		if visited == program {
+			set = true
+		}
+	})
+	if set {
+		return program.Value.String()
+	}
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+	if program != nil && program.DefValue != "" {
+		return program.DefValue
+	}
+	return fallback
+}
+
+// newTransport creates the named transport serving the app
+func (f *FastMCP) newTransport(name, addr string) (transport.Transport, error) {
+	options := f.transportOpts(transport.WithAddress(addr))
+	switch name {
+	case "stdio":
+		return transport.NewStdioTransport(server.NewSession(context.Background(), f.server), options...), nil
+	case "sse":
+		return transport.NewSSETransport(f.server, options...), nil
+	case "websocket":
+		return transport.NewWebSocketTransport(f.server, options...), nil
+	case "tcp":
+		return transport.NewTCPTransport(f.server, options...), nil
+	case "streamable":
+		return transport.NewStreamableHTTPTransport(f.server, options...), nil
+	case "http":
+		return transport.NewMultiHTTPTransport(f.server, options...), nil
+	default:
+		return nil, fmt.Errorf("unknown transport: %s", name)
+	}
+}
@@ -0,0 +1,244 @@
+package fastmcp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/transport"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// withCommandLine replaces flag.CommandLine for the test with a flag set
+// defined by define and parsed from args, or left unparsed when args is nil
+func withCommandLine(t *testing.T, define func(*flag.FlagSet), args []string) {
+	t.Helper()
+	saved := flag.CommandLine
+	t.Cleanup(func() { flag.CommandLine = saved })
+
+	flag.CommandLine = flag.NewFlagSet("program", flag.ContinueOnError)
+	if define != nil {
+		define(flag.CommandLine)
+	}
+	if args != nil {
+		if err := flag.CommandLine.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRunSettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		env           map[string]string
+		define        func(*flag.FlagSet)
+		commandLine   []string
+		wantTransport string
+		wantAddr      string
+	}{
+		{
+			name:          "defaults",
+			wantTransport: DefaultTransport,
+			wantAddr:      DefaultAddr,
+		},
+		{
+			name:          "environment",
+			env:           map[string]string{EnvTransport: "sse", EnvAddr: ":9000"},
+			wantTransport: "sse",
+			wantAddr:      ":9000",
+		},
+		{
+			name:          "flags override the environment",
+			args:          []string{"-transport", "tcp", "-addr", ":9001"},
+			env:           map[string]string{EnvTransport: "sse", EnvAddr: ":9000"},
+			wantTransport: "tcp",
+			wantAddr:      ":9001",
+		},
+		{
+			name:          "flags set by the program",
+			define:        defineRunFlags("stdio", ":7000"),
+			commandLine:   []string{"-transport", "websocket"},
+			env:           map[string]string{EnvTransport: "sse"},
+			wantTransport: "websocket",
+			wantAddr:      ":7000",
+		},
+		{
+			name:          "the environment overrides the program's defaults",
+			define:        defineRunFlags("stdio", ":7000"),
+			commandLine:   []string{},
+			env:           map[string]string{EnvTransport: "sse"},
+			wantTransport: "sse",
+			wantAddr:      ":7000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvTransport, "")
+			t.Setenv(EnvAddr, "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			withCommandLine(t, tt.define, tt.commandLine)
+
+			name, addr, err := runSettings(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != tt.wantTransport || addr != tt.wantAddr {
+				t.Errorf("got %s on %s, want %s on %s", name, addr, tt.wantTransport, tt.wantAddr)
+			}
+		})
+	}
+}
+
+// defineRunFlags returns a function defining -transport and -addr with the
+// given defaults, as programs parsing their own flags do
+func defineRunFlags(transport, addr string) func(*flag.FlagSet) {
+	return func(fs *flag.FlagSet) {
+		fs.String("transport", transport, "")
+		fs.String("addr", addr, "")
+	}
+}
+
+func TestRunSettingsLeavesCommandLineAlone(t *testing.T) {
+	withCommandLine(t, nil, nil)
+	if _, _, err := runSettings([]string{"-transport", "sse"}); err != nil {
+		t.Fatal(err)
+	}
+	if flag.Parsed() || flag.Lookup("transport") != nil {
+		t.Error("expected flag.CommandLine not to be defined or parsed")
+	}
+
+	if _, _, err := runSettings([]string{"-unknown"}); err == nil {
+		t.Error("expected an error for an unknown flag")
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	app := New("run")
+	app.Tool("echo", func(s string) string { return s }, "Echoes")
+
+	tests := map[string]interface{}{
+		"stdio":      &transport.StdioTransport{},
+		"sse":        &transport.SSETransport{},
+		"websocket":  &transport.WebSocketTransport{},
+		"tcp":        &transport.TCPTransport{},
+		"streamable": &transport.StreamableHTTPTransport{},
+		"http":       &transport.MultiHTTPTransport{},
+	}
+	for name, want := range tests {
+		tr, err := app.newTransport(name, "127.0.0.1:0")
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if got, want := fmt.Sprintf("%T", tr), fmt.Sprintf("%T", want); got != want {
+			t.Errorf("%s: got a %s, want a %s", name, got, want)
+		}
+	}
+
+	if _, err := app.newTransport("carrier-pigeon", ""); err == nil {
+		t.Error("expected an error for an unknown transport")
+	}
+}
+
+func TestRunWithContext(t *testing.T) {
+	app := New("run", server.WithLogger(discardLogger)).TransportOptions(transport.WithLogger(discardLogger))
+	app.Tool("echo", func(s string) string { return s }, "Echoes")
+	tr, err := app.newTransport("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancelling the context shuts the app down cleanly
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.RunWithContext(ctx, tr) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected RunWithContext to return once cancelled")
+	}
+
+	if err := New("empty").RunArgs(nil); err == nil {
+		t.Error("expected an error running an app without a server")
+	}
+}
+
+func TestRunHTTPTransport(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	app := New("run", server.WithLogger(discardLogger)).TransportOptions(transport.WithLogger(discardLogger))
+	app.Tool("echo", func(s string) string { return s }, "Echoes")
+	tr, err := app.newTransport("http", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.RunWithContext(ctx, tr) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// The http transport serves streamable HTTP, WebSocket and SSE clients
+	// on one address
+	dialCtx, dialCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer dialCancel()
+	var ws client.Conn
+	for ws == nil {
+		if ws, err = client.DialWebSocket(dialCtx, "ws://"+addr+"/ws", nil); err != nil {
+			if dialCtx.Err() != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	for name, conn := range map[string]client.Conn{
+		"websocket":  ws,
+		"streamable": client.NewStreamableHTTPConn("http://"+addr+"/mcp", nil, nil),
+	} {
+		c, err := client.Connect(dialCtx, conn)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		result, err := c.CallTool(dialCtx, "echo", map[string]interface{}{"arg0": name})
+		if err != nil || result.IsError {
+			t.Errorf("%s: failed to call echo: %v %+v", name, err, result)
+		}
+		c.Close()
+	}
+
+	// SSE streams open at /sse, and at /events for existing clients
+	for _, path := range []string{"/sse", "/events"} {
+		resp, err := http.Get("http://" + addr + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "text/event-stream" {
+			t.Errorf("%s: expected an SSE event stream, got %s %s", path, resp.Status, ct)
+		}
+	}
+}
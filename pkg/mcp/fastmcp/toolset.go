@@ -0,0 +1,99 @@
+package fastmcp
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Describer is implemented by tool sets describing their methods. Describe
+// returns the descriptions of the tools keyed by method name.
+type Describer interface {
+	Describe() map[string]string
+}
+
+// ToolSet registers every exported method of service as a tool, so a
+// service-style type needs a single registration. Tools are named after
+// their method in lower camel case, as prefix_name when prefix isn't empty:
+// the method Add of a set registered with prefix "math" becomes the tool
+// math_add. Methods take the same signatures as Tool handlers.
+//
+// Descriptions come from the set's Describe method, if it implements
+// Describer, and otherwise from struct fields tagged with the method they
+// describe:
+//
+//	type Math struct {
+//	    _ struct{} `tool:"Add" description:"Adds two numbers"`
+//	}
+func (f *FastMCP) ToolSet(prefix string, service interface{}, opts ...ToolOption) *FastMCP {
+	eachToolSetMethod(service, func(method string, handler interface{}, description string) {
+		f.Tool(prefixedName(prefix, lowerCamel(method)), handler, description, opts...)
+	})
+	return f
+}
+
+// eachToolSetMethod calls register with the name, bound handler and
+// description of every tool method of a tool set
+func eachToolSetMethod(service interface{}, register func(method string, handler interface{}, description string)) {
+	value := reflect.ValueOf(service)
+	descriptions := toolSetDescriptions(service)
+	for i := 0; i < value.NumMethod(); i++ {
+		method := value.Type().Method(i)
+		if method.Name == "Describe" {
+			if _, ok := service.(Describer); ok {
+				continue
+			}
+		}
+
+		description := descriptions[method.Name]
+		if description == "" {
+			description = method.Name
+		}
+		register(method.Name, value.Method(i).Interface(), description)
+	}
+}
+
+// prefixedName returns name as prefix_name, or unchanged without a prefix
+func prefixedName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// toolSetDescriptions returns the method descriptions of a tool set
+func toolSetDescriptions(service interface{}) map[string]string {
+	if describer, ok := service.(Describer); ok {
+		return describer.Describe()
+	}
+
+	descriptions := make(map[string]string)
+	t := reflect.TypeOf(service)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return descriptions
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if method := field.Tag.Get("tool"); method != "" {
+			descriptions[method] = field.Tag.Get("description")
+		}
+	}
+	return descriptions
+}
+
+// lowerCamel lowercases the leading capitals of a method name, keeping the
+// last one of an initialism before a word: HTTPGet becomes httpGet
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		upper--
+	}
+	return strings.ToLower(string(runes[:upper])) + string(runes[upper:])
+}
@@ -0,0 +1,70 @@
+package fastmcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+)
+
+// mathSet is a tool set described by struct tags
+type mathSet struct {
+	_ struct{} `tool:"Add" description:"Adds two numbers"`
+}
+
+func (mathSet) Add(a, b int) int { return a + b }
+
+func (mathSet) HTTPStatus(code int) string { return http.StatusText(code) }
+
+// textSet is a tool set described by its Describe method
+type textSet struct{}
+
+func (textSet) Upper(s string) string { return strings.ToUpper(s) }
+
+func (textSet) Describe() map[string]string {
+	return map[string]string{"Upper": "Uppercases text"}
+}
+
+func TestToolSet(t *testing.T) {
+	app := New("toolset")
+	app.ToolSet("math", mathSet{})
+	app.ToolSet("", textSet{})
+	app.Group("svc").ToolSet("text", textSet{})
+
+	tc := mcptest.NewClient(t, app.Server())
+	tools := tc.Tools()
+	if got, want := strings.Join(toolNames(tools), ","), "math_add,math_httpStatus,svc_text_upper,upper"; got != want {
+		t.Fatalf("got tools %s, want %s", got, want)
+	}
+
+	descriptions := make(map[string]string)
+	for _, tool := range tools {
+		descriptions[tool.Name] = tool.Description
+	}
+	for name, want := range map[string]string{
+		"math_add":        "Adds two numbers",
+		"math_httpStatus": "HTTPStatus",
+		"upper":           "Uppercases text",
+	} {
+		if descriptions[name] != want {
+			t.Errorf("%s: got description %q, want %q", name, descriptions[name], want)
+		}
+	}
+
+	tc.ExpectToolResult("math_add", map[string]interface{}{"arg0": 2, "arg1": 3}, "5")
+	tc.ExpectToolResult("svc_text_upper", map[string]interface{}{"arg0": "hi"}, "HI")
+}
+
+func TestLowerCamel(t *testing.T) {
+	for name, want := range map[string]string{
+		"Add":     "add",
+		"HTTPGet": "httpGet",
+		"ID":      "id",
+		"GetURL":  "getURL",
+	} {
+		if got := lowerCamel(name); got != want {
+			t.Errorf("lowerCamel(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
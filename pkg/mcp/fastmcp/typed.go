@@ -0,0 +1,29 @@
+package fastmcp
+
+import (
+	"context"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// AddTool registers a tool with a compile-time checked handler, whose
+// arguments are the fields of In and whose result is Out. Schemas are
+// derived from both types, and struct results are returned as structured
+// content:
+//
+//	type SearchArgs struct {
+//	    Query string `json:"query" description:"Search text"`
+//	    Limit int    `json:"limit,omitempty"`
+//	}
+//
+//	fastmcp.AddTool(app, "search", func(ctx context.Context, args SearchArgs) (Results, error) {
+//	    ...
+//	}, "Search the index")
+func AddTool[In, Out any](f *FastMCP, name string, handler func(context.Context, In) (Out, error), description string, opts ...ToolOption) *FastMCP {
+	f.ensureServer()
+	config := newToolConfig(name, opts)
+	if err := server.AddTypedTool(f.server, config.name, handler, description, config.options...); err != nil {
+		f.server.Logger().Warn("failed to add tool", "tool", config.name, "error", err)
+	}
+	return f
+}
@@ -0,0 +1,59 @@
+package fastmcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+type searchArgs struct {
+	Query string `json:"query" description:"Search text"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+type searchResults struct {
+	Hits []string `json:"hits"`
+}
+
+func TestAddTool(t *testing.T) {
+	readOnly := true
+	app := New("typed")
+	AddTool(app, "search", func(ctx context.Context, args searchArgs) (searchResults, error) {
+		if args.Query == "" {
+			return searchResults{}, errors.New("query is required")
+		}
+		hits := []string{args.Query + " 1", args.Query + " 2"}
+		if args.Limit > 0 && args.Limit < len(hits) {
+			hits = hits[:args.Limit]
+		}
+		return searchResults{Hits: hits}, nil
+	}, "Searches the index",
+		WithName("find"),
+		WithAnnotations(protocol.ToolAnnotations{ReadOnlyHint: &readOnly}),
+		WithTimeout(time.Second),
+	)
+
+	tc := mcptest.NewClient(t, app.Server())
+	tools := tc.Tools()
+	if len(tools) != 1 || tools[0].Name != "find" {
+		t.Fatalf("expected the tool to be renamed, got %+v", tools)
+	}
+	tool := tools[0]
+	properties, _ := tool.InputSchema["properties"].(map[string]interface{})
+	if _, ok := properties["query"]; !ok {
+		t.Errorf("expected the input schema to be derived from the arguments, got %v", tool.InputSchema)
+	}
+	if tool.OutputSchema == nil {
+		t.Error("expected an output schema derived from the result")
+	}
+	if tool.Annotations == nil || tool.Annotations.ReadOnlyHint == nil || !*tool.Annotations.ReadOnlyHint {
+		t.Errorf("expected the annotations, got %+v", tool.Annotations)
+	}
+
+	tc.ExpectToolResult("find", map[string]interface{}{"query": "go", "limit": 1}, map[string]interface{}{"hits": []interface{}{"go 1"}})
+	tc.ExpectToolError("find", map[string]interface{}{}, "query is required")
+}
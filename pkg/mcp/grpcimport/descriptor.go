@@ -0,0 +1,431 @@
+package grpcimport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Field types of FieldDescriptorProto
+const (
+	typeDouble   = 1
+	typeFloat    = 2
+	typeInt64    = 3
+	typeUint64   = 4
+	typeInt32    = 5
+	typeFixed64  = 6
+	typeFixed32  = 7
+	typeBool     = 8
+	typeString   = 9
+	typeGroup    = 10
+	typeMessage  = 11
+	typeBytes    = 12
+	typeUint32   = 13
+	typeEnum     = 14
+	typeSfixed32 = 15
+	typeSfixed64 = 16
+	typeSint32   = 17
+	typeSint64   = 18
+)
+
+// labelRepeated is the label of repeated fields
+const labelRepeated = 3
+
+// Files holds the protobuf descriptors of services and the messages they
+// exchange, parsed from compiled FileDescriptorProtos
+type Files struct {
+	files    map[string]bool
+	messages map[string]*message
+	enums    map[string]*enum
+	services []*service
+}
+
+// message describes a message type
+type message struct {
+	fullName    string
+	description string
+	fields      []*field
+	mapEntry    bool
+}
+
+// field returns the field with the given number, or nil
+func (m *message) field(number int32) *field {
+	for _, fd := range m.fields {
+		if fd.number == number {
+			return fd
+		}
+	}
+	return nil
+}
+
+// field describes a message field
+type field struct {
+	name        string
+	jsonName    string
+	number      int32
+	repeated    bool
+	typ         int32
+	typeName    string
+	description string
+}
+
+// enum describes an enum type
+type enum struct {
+	fullName string
+	values   []enumValue
+}
+
+// enumValue is a named value of an enum
+type enumValue struct {
+	name   string
+	number int32
+}
+
+// service describes a service
+type service struct {
+	fullName    string
+	description string
+	methods     []*method
+}
+
+// method describes an RPC method
+type method struct {
+	name            string
+	description     string
+	inputType       string
+	outputType      string
+	clientStreaming bool
+	serverStreaming bool
+}
+
+// NewFiles creates an empty set of descriptors
+func NewFiles() *Files {
+	return &Files{
+		files:    make(map[string]bool),
+		messages: make(map[string]*message),
+		enums:    make(map[string]*enum),
+	}
+}
+
+// ParseFileDescriptorSet parses a serialized FileDescriptorSet, such as
+// written by protoc --descriptor_set_out --include_imports
+// --include_source_info
+func ParseFileDescriptorSet(data []byte) (*Files, error) {
+	files := NewFiles()
+	if err := files.AddFileDescriptorSet(data); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// AddFileDescriptorSet adds the files of a serialized FileDescriptorSet
+func (f *Files) AddFileDescriptorSet(data []byte) error {
+	return parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		if num == 1 && typ == wireBytes {
+			return f.AddFile(data)
+		}
+		return nil
+	})
+}
+
+// AddFile adds a serialized FileDescriptorProto. Files already added,
+// identified by name, are skipped.
+func (f *Files) AddFile(data []byte) error {
+	var name, pkg string
+	var messages, enums, services [][]byte
+	var sourceInfo []byte
+	err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		if typ != wireBytes {
+			return nil
+		}
+		switch num {
+		case 1:
+			name = string(data)
+		case 2:
+			pkg = string(data)
+		case 4:
+			messages = append(messages, data)
+		case 5:
+			enums = append(enums, data)
+		case 6:
+			services = append(services, data)
+		case 9:
+			sourceInfo = data
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("invalid file descriptor: %w", err)
+	}
+	if f.files[name] {
+		return nil
+	}
+
+	comments, err := parseComments(sourceInfo)
+	if err != nil {
+		return fmt.Errorf("invalid source info of %s: %w", name, err)
+	}
+
+	prefix := ""
+	if pkg != "" {
+		prefix = pkg + "."
+	}
+	for i, data := range messages {
+		if err := f.addMessage(prefix, data, comments, pathKey(4, i)); err != nil {
+			return fmt.Errorf("invalid message in %s: %w", name, err)
+		}
+	}
+	for _, data := range enums {
+		if err := f.addEnum(prefix, data); err != nil {
+			return fmt.Errorf("invalid enum in %s: %w", name, err)
+		}
+	}
+	for i, data := range services {
+		if err := f.addService(prefix, data, comments, pathKey(6, i)); err != nil {
+			return fmt.Errorf("invalid service in %s: %w", name, err)
+		}
+	}
+	f.files[name] = true
+	return nil
+}
+
+// addMessage adds a DescriptorProto and its nested types
+func (f *Files) addMessage(prefix string, data []byte, comments map[string]string, path string) error {
+	m := &message{description: comments[path]}
+	var fields, nested, enums [][]byte
+	err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		if typ != wireBytes {
+			return nil
+		}
+		switch num {
+		case 1:
+			m.fullName = prefix + string(data)
+		case 2:
+			fields = append(fields, data)
+		case 3:
+			nested = append(nested, data)
+		case 4:
+			enums = append(enums, data)
+		case 7:
+			return parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+				if num == 7 && typ == wireVarint {
+					m.mapEntry = v != 0
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, data := range fields {
+		fd, err := parseField(data)
+		if err != nil {
+			return err
+		}
+		fd.description = comments[path+pathKey(2, i)]
+		m.fields = append(m.fields, fd)
+	}
+	for i, data := range nested {
+		if err := f.addMessage(m.fullName+".", data, comments, path+pathKey(3, i)); err != nil {
+			return err
+		}
+	}
+	for _, data := range enums {
+		if err := f.addEnum(m.fullName+".", data); err != nil {
+			return err
+		}
+	}
+	f.messages[m.fullName] = m
+	return nil
+}
+
+// parseField parses a FieldDescriptorProto
+func parseField(data []byte) (*field, error) {
+	fd := &field{}
+	err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		switch {
+		case num == 1 && typ == wireBytes:
+			fd.name = string(data)
+		case num == 3 && typ == wireVarint:
+			fd.number = int32(v)
+		case num == 4 && typ == wireVarint:
+			fd.repeated = v == labelRepeated
+		case num == 5 && typ == wireVarint:
+			fd.typ = int32(v)
+		case num == 6 && typ == wireBytes:
+			fd.typeName = strings.TrimPrefix(string(data), ".")
+		case num == 10 && typ == wireBytes:
+			fd.jsonName = string(data)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fd.typ == typeGroup {
+		return nil, fmt.Errorf("field %s: groups are not supported", fd.name)
+	}
+	if fd.jsonName == "" {
+		fd.jsonName = jsonName(fd.name)
+	}
+	return fd, nil
+}
+
+// jsonName returns the lowerCamelCase JSON name protoc derives from a
+// field name
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper && 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// addEnum adds an EnumDescriptorProto
+func (f *Files) addEnum(prefix string, data []byte) error {
+	e := &enum{}
+	err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		if typ != wireBytes {
+			return nil
+		}
+		switch num {
+		case 1:
+			e.fullName = prefix + string(data)
+		case 2:
+			var value enumValue
+			err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+				switch {
+				case num == 1 && typ == wireBytes:
+					value.name = string(data)
+				case num == 2 && typ == wireVarint:
+					value.number = int32(v)
+				}
+				return nil
+			})
+			e.values = append(e.values, value)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.enums[e.fullName] = e
+	return nil
+}
+
+// addService adds a ServiceDescriptorProto
+func (f *Files) addService(prefix string, data []byte, comments map[string]string, path string) error {
+	s := &service{description: comments[path]}
+	var methods [][]byte
+	err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		if typ != wireBytes {
+			return nil
+		}
+		switch num {
+		case 1:
+			s.fullName = prefix + string(data)
+		case 2:
+			methods = append(methods, data)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, data := range methods {
+		m := &method{description: comments[path+pathKey(2, i)]}
+		err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+			switch {
+			case num == 1 && typ == wireBytes:
+				m.name = string(data)
+			case num == 2 && typ == wireBytes:
+				m.inputType = strings.TrimPrefix(string(data), ".")
+			case num == 3 && typ == wireBytes:
+				m.outputType = strings.TrimPrefix(string(data), ".")
+			case num == 5 && typ == wireVarint:
+				m.clientStreaming = v != 0
+			case num == 6 && typ == wireVarint:
+				m.serverStreaming = v != 0
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		s.methods = append(s.methods, m)
+	}
+	f.services = append(f.services, s)
+	return nil
+}
+
+// parseComments returns the leading comments, or else the trailing ones,
+// of a SourceCodeInfo by element path
+func parseComments(data []byte) (map[string]string, error) {
+	comments := make(map[string]string)
+	err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		if num != 1 || typ != wireBytes {
+			return nil
+		}
+
+		var path strings.Builder
+		var leading, trailing string
+		err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+			switch {
+			case num == 1 && typ == wireVarint:
+				path.WriteString(pathElement(int(v)))
+			case num == 1 && typ == wireBytes:
+				for len(data) > 0 {
+					v, n, err := consumeVarint(data)
+					if err != nil {
+						return err
+					}
+					path.WriteString(pathElement(int(v)))
+					data = data[n:]
+				}
+			case num == 3 && typ == wireBytes:
+				leading = string(data)
+			case num == 4 && typ == wireBytes:
+				trailing = string(data)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		comment := strings.TrimSpace(leading)
+		if comment == "" {
+			comment = strings.TrimSpace(trailing)
+		}
+		if comment != "" {
+			comments[path.String()] = comment
+		}
+		return nil
+	})
+	return comments, err
+}
+
+// pathKey returns the key of a source location path segment: a field
+// number and an index into it
+func pathKey(fieldNumber, index int) string {
+	return pathElement(fieldNumber) + pathElement(index)
+}
+
+// pathElement returns the key of one element of a source location path
+func pathElement(v int) string {
+	return "/" + strconv.Itoa(v)
+}
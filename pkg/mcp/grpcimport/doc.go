@@ -0,0 +1,42 @@
+// Package grpcimport exposes gRPC services as MCP tools.
+//
+// Every unary method of a service becomes a tool named Service_Method.
+// Its input schema is derived from the request message, and calls are
+// transcoded between the protobuf JSON mapping and the binary encoding
+// using the services' descriptors, so no generated code is needed. Leading
+// comments in the descriptors become tool and argument descriptions.
+//
+// Descriptors come from a compiled FileDescriptorSet:
+//
+//	// protoc --descriptor_set_out=greeter.pb --include_imports --include_source_info greeter.proto
+//	data, err := os.ReadFile("greeter.pb")
+//	files, err := grpcimport.ParseFileDescriptorSet(data)
+//	err = grpcimport.Register(srv, conn, files)
+//
+// or from the server reflection API:
+//
+//	err = grpcimport.RegisterReflection(ctx, srv, conn, reflector)
+//
+// The package does not depend on grpc-go. Conn and Reflector are small
+// interfaces that adapt a grpc.ClientConn and the reflection client in a
+// few lines. Conn passes serialized messages through, for example with a
+// codec that leaves []byte values as they are:
+//
+//	type rawCodec struct{}
+//
+//	func (rawCodec) Marshal(v any) ([]byte, error)      { return *v.(*[]byte), nil }
+//	func (rawCodec) Unmarshal(data []byte, v any) error { *v.(*[]byte) = data; return nil }
+//	func (rawCodec) Name() string                       { return "proto" }
+//
+//	type grpcConn struct{ cc *grpc.ClientConn }
+//
+//	func (c grpcConn) Invoke(ctx context.Context, method string, request []byte) ([]byte, error) {
+//	    var response []byte
+//	    err := c.cc.Invoke(ctx, method, &request, &response, grpc.ForceCodec(rawCodec{}))
+//	    return response, err
+//	}
+//
+// Streaming methods are skipped. Of the well-known types, Timestamp and
+// Duration use their JSON string forms; the others are mapped like any
+// other message.
+package grpcimport
@@ -0,0 +1,218 @@
+package grpcimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// reflectionServicePrefix is the package of the reflection services, which
+// are not exposed as tools
+const reflectionServicePrefix = "grpc.reflection."
+
+// maxToolNameLength is the longest tool name generated for a method
+const maxToolNameLength = 64
+
+// invalidNameChars matches the characters not allowed in tool names
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// Conn invokes unary RPCs with serialized protobuf messages. It is small
+// enough to adapt a grpc.ClientConn in a few lines, using a codec that
+// passes []byte messages through.
+type Conn interface {
+	// Invoke calls a method, named /package.Service/Method, and returns
+	// the serialized response
+	Invoke(ctx context.Context, method string, request []byte) ([]byte, error)
+}
+
+// Reflector is the subset of the gRPC server reflection API used by
+// RegisterReflection
+type Reflector interface {
+	// ListServices returns the fully-qualified names of the server's
+	// services
+	ListServices(ctx context.Context) ([]string, error)
+
+	// FileContainingSymbol returns the serialized FileDescriptorProto
+	// defining a symbol, along with the files it depends on
+	FileContainingSymbol(ctx context.Context, symbol string) ([][]byte, error)
+}
+
+// Option configures how methods are imported
+type Option func(*importer)
+
+// importer holds the import settings
+type importer struct {
+	files  *Files
+	conn   Conn
+	filter func(service, method string) bool
+}
+
+// WithFilter imports only the methods for which filter returns true.
+// Services are fully-qualified, such as "helloworld.Greeter".
+func WithFilter(filter func(service, method string) bool) Option {
+	return func(i *importer) {
+		i.filter = filter
+	}
+}
+
+// Register adds a tool to srv for every unary method of the services in
+// files, calling it over conn. Tools are named Service_Method after the
+// unqualified service name. Their arguments are the fields of the request
+// message in the protobuf JSON mapping, and results hold the response
+// message as JSON text and structured content. Streaming methods are
+// skipped.
+func Register(srv *server.Server, conn Conn, files *Files, options ...Option) error {
+	i := &importer{files: files, conn: conn}
+	for _, option := range options {
+		option(i)
+	}
+
+	for _, s := range files.services {
+		if err := i.registerService(srv, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterReflection is like Register, with the services and their
+// descriptors discovered through the server reflection API
+func RegisterReflection(ctx context.Context, srv *server.Server, conn Conn, reflector Reflector, options ...Option) error {
+	names, err := reflector.ListServices(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	files := NewFiles()
+	var services []string
+	for _, name := range names {
+		if strings.HasPrefix(name, reflectionServicePrefix) {
+			continue
+		}
+		descriptors, err := reflector.FileContainingSymbol(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to get descriptors of %s: %w", name, err)
+		}
+		for _, data := range descriptors {
+			if err := files.AddFile(data); err != nil {
+				return fmt.Errorf("descriptors of %s: %w", name, err)
+			}
+		}
+		services = append(services, name)
+	}
+
+	i := &importer{files: files, conn: conn}
+	for _, option := range options {
+		option(i)
+	}
+	for _, name := range services {
+		s := files.service(name)
+		if s == nil {
+			return fmt.Errorf("descriptors of %s don't define the service", name)
+		}
+		if err := i.registerService(srv, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// service returns the service with a fully-qualified name, or nil
+func (f *Files) service(name string) *service {
+	for _, s := range f.services {
+		if s.fullName == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// registerService adds the tools for the methods of a service
+func (i *importer) registerService(srv *server.Server, s *service) error {
+	for _, m := range s.methods {
+		if m.clientStreaming || m.serverStreaming {
+			continue
+		}
+		if i.filter != nil && !i.filter(s.fullName, m.name) {
+			continue
+		}
+		if err := i.register(srv, s, m); err != nil {
+			return fmt.Errorf("%s/%s: %w", s.fullName, m.name, err)
+		}
+	}
+	return nil
+}
+
+// register adds the tool for a method
+func (i *importer) register(srv *server.Server, s *service, m *method) error {
+	inputSchema, err := i.files.messageSchema(m.inputType, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+	if inputSchema["type"] != "object" {
+		return fmt.Errorf("request type %s is not an object in JSON", m.inputType)
+	}
+	delete(inputSchema, "description")
+	outputSchema, err := i.files.messageSchema(m.outputType, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+
+	description := m.description
+	if description == "" {
+		description = "Calls " + s.fullName + "/" + m.name
+	}
+
+	opts := []server.ToolOption{server.WithInputSchema(inputSchema)}
+	if outputSchema["type"] == "object" {
+		opts = append(opts, server.WithOutputSchema(outputSchema))
+	}
+	return srv.AddTool(toolName(s, m), server.ToolHandlerFunc(i.call(s, m)), description, opts...)
+}
+
+// toolName returns the name of a method's tool
+func toolName(s *service, m *method) string {
+	serviceName := s.fullName[strings.LastIndex(s.fullName, ".")+1:]
+	name := invalidNameChars.ReplaceAllString(serviceName+"_"+m.name, "_")
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+	return name
+}
+
+// call returns the handler calling a method
+func (i *importer) call(s *service, m *method) server.ToolHandlerFunc {
+	fullMethod := "/" + s.fullName + "/" + m.name
+	return func(ctx context.Context, arguments map[string]interface{}) (protocol.CallToolResult, error) {
+		if arguments == nil {
+			arguments = map[string]interface{}{}
+		}
+		request, err := i.files.encodeMessage(m.inputType, arguments)
+		if err != nil {
+			return protocol.CallToolResult{}, fmt.Errorf("invalid arguments: %w", err)
+		}
+
+		response, err := i.conn.Invoke(ctx, fullMethod, request)
+		if err != nil {
+			return protocol.CallToolResult{}, err
+		}
+
+		value, err := i.files.decodeMessage(m.outputType, response)
+		if err != nil {
+			return protocol.CallToolResult{}, fmt.Errorf("invalid response: %w", err)
+		}
+		text, err := json.Marshal(value)
+		if err != nil {
+			return protocol.CallToolResult{}, err
+		}
+		return protocol.CallToolResult{
+			Content:           []interface{}{protocol.NewTextContent(string(text))},
+			StructuredContent: value,
+		}, nil
+	}
+}
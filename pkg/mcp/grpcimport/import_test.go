@@ -0,0 +1,325 @@
+package grpcimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// Builders of serialized descriptor protos
+
+func bytesField(num int32, data string) []byte {
+	return appendBytesField(nil, num, []byte(data))
+}
+
+func varintField(num int32, v uint64) []byte {
+	return appendVarint(appendTag(nil, num, wireVarint), v)
+}
+
+func concat(parts ...[]byte) string {
+	var b []byte
+	for _, part := range parts {
+		b = append(b, part...)
+	}
+	return string(b)
+}
+
+func fieldProto(name string, number, typ int32, repeated bool, typeName string) []byte {
+	label := uint64(1)
+	if repeated {
+		label = labelRepeated
+	}
+	b := concat(bytesField(1, name), varintField(3, uint64(number)), varintField(4, label), varintField(5, uint64(typ)))
+	if typeName != "" {
+		b += string(bytesField(6, "."+typeName))
+	}
+	return bytesField(2, b)
+}
+
+func methodProto(name, input, output string, streaming bool) []byte {
+	b := concat(bytesField(1, name), bytesField(2, "."+input), bytesField(3, "."+output))
+	if streaming {
+		b += string(varintField(5, 1)) + string(varintField(6, 1))
+	}
+	return bytesField(2, b)
+}
+
+func commentProto(comment string, path ...uint64) []byte {
+	var packed []byte
+	for _, element := range path {
+		packed = appendVarint(packed, element)
+	}
+	return bytesField(1, concat(appendBytesField(nil, 1, packed), bytesField(3, " "+comment+"\n")))
+}
+
+// greeterFile is the FileDescriptorProto of:
+//
+//	package greet;
+//
+//	enum Mood { MOOD_UNKNOWN = 0; HAPPY = 1; }
+//
+//	message HelloRequest {
+//	  // Name to greet
+//	  string name = 1;
+//	  int64 count = 2;
+//	  repeated string tags = 3;
+//	  map<string, int32> scores = 4;
+//	  google.protobuf.Timestamp sent_at = 5;
+//	  Mood mood = 6;
+//	}
+//
+//	message HelloReply {
+//	  string message = 1;
+//	  repeated int32 lengths = 2;
+//	  Mood mood = 3;
+//	  google.protobuf.Timestamp sent_at = 4;
+//	}
+//
+//	// Greets people
+//	service Greeter {
+//	  // Sends a greeting
+//	  rpc SayHello(HelloRequest) returns (HelloReply);
+//	  rpc Chat(stream HelloRequest) returns (stream HelloReply);
+//	}
+var greeterFile = []byte(concat(
+	bytesField(1, "greet.proto"),
+	bytesField(2, "greet"),
+	bytesField(4, concat(
+		bytesField(1, "HelloRequest"),
+		fieldProto("name", 1, typeString, false, ""),
+		fieldProto("count", 2, typeInt64, false, ""),
+		fieldProto("tags", 3, typeString, true, ""),
+		fieldProto("scores", 4, typeMessage, true, "greet.HelloRequest.ScoresEntry"),
+		fieldProto("sent_at", 5, typeMessage, false, timestampType),
+		fieldProto("mood", 6, typeEnum, false, "greet.Mood"),
+		bytesField(3, concat(
+			bytesField(1, "ScoresEntry"),
+			fieldProto("key", 1, typeString, false, ""),
+			fieldProto("value", 2, typeInt32, false, ""),
+			bytesField(7, string(varintField(7, 1))),
+		)),
+	)),
+	bytesField(4, concat(
+		bytesField(1, "HelloReply"),
+		fieldProto("message", 1, typeString, false, ""),
+		fieldProto("lengths", 2, typeInt32, true, ""),
+		fieldProto("mood", 3, typeEnum, false, "greet.Mood"),
+		fieldProto("sent_at", 4, typeMessage, false, timestampType),
+	)),
+	bytesField(5, concat(
+		bytesField(1, "Mood"),
+		bytesField(2, concat(bytesField(1, "MOOD_UNKNOWN"), varintField(2, 0))),
+		bytesField(2, concat(bytesField(1, "HAPPY"), varintField(2, 1))),
+	)),
+	bytesField(6, concat(
+		bytesField(1, "Greeter"),
+		methodProto("SayHello", "greet.HelloRequest", "greet.HelloReply", false),
+		methodProto("Chat", "greet.HelloRequest", "greet.HelloReply", true),
+	)),
+	bytesField(9, concat(
+		commentProto("Greets people", 6, 0),
+		commentProto("Sends a greeting", 6, 0, 2, 0),
+		commentProto("Name to greet", 4, 0, 2, 0),
+	)),
+))
+
+// greeterConn serves the Greeter service in process, working on the wire
+// format directly rather than through the descriptors under test
+type greeterConn struct {
+	methods []string
+}
+
+func (c *greeterConn) Invoke(ctx context.Context, method string, request []byte) ([]byte, error) {
+	c.methods = append(c.methods, method)
+	if method != "/greet.Greeter/SayHello" {
+		return nil, fmt.Errorf("unimplemented method %s", method)
+	}
+
+	var name string
+	var count, mood uint64
+	var tags []string
+	var sentAt []byte
+	scores := map[string]uint64{}
+	err := parseFields(request, func(num int32, typ wireType, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			name = string(data)
+		case 2:
+			count = v
+		case 3:
+			tags = append(tags, string(data))
+		case 4:
+			var key string
+			var value uint64
+			parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+				if num == 1 {
+					key = string(data)
+				} else {
+					value = v
+				}
+				return nil
+			})
+			scores[key] = value
+		case 5:
+			sentAt = data
+		case 6:
+			mood = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	keys := make([]string, 0, len(scores))
+	for key := range scores {
+		keys = append(keys, fmt.Sprintf("%s=%d", key, scores[key]))
+	}
+	sort.Strings(keys)
+	message := fmt.Sprintf("Hello, %s x%d %s", name, count, strings.Join(keys, ","))
+
+	var lengths []byte
+	for _, tag := range tags {
+		lengths = appendVarint(lengths, uint64(len(tag)))
+	}
+	reply := appendBytesField(nil, 1, []byte(message))
+	reply = appendBytesField(reply, 2, lengths)
+	reply = appendVarint(appendTag(reply, 3, wireVarint), mood)
+	if sentAt != nil {
+		reply = appendBytesField(reply, 4, sentAt)
+	}
+	return reply, nil
+}
+
+// greeterReflector serves the descriptors of the Greeter service
+type greeterReflector struct{}
+
+func (greeterReflector) ListServices(ctx context.Context) ([]string, error) {
+	return []string{"grpc.reflection.v1.ServerReflection", "greet.Greeter"}, nil
+}
+
+func (greeterReflector) FileContainingSymbol(ctx context.Context, symbol string) ([][]byte, error) {
+	if symbol != "greet.Greeter" {
+		return nil, fmt.Errorf("unknown symbol %s", symbol)
+	}
+	return [][]byte{greeterFile}, nil
+}
+
+func TestRegister(t *testing.T) {
+	files, err := ParseFileDescriptorSet(appendBytesField(nil, 1, greeterFile))
+	if err != nil {
+		t.Fatalf("failed to parse descriptors: %v", err)
+	}
+	srv := server.NewServer("grpc")
+	conn := &greeterConn{}
+	if err := Register(srv, conn, files); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	tc := mcptest.NewClient(t, srv)
+
+	// Streaming methods are skipped
+	tools := tc.Tools()
+	if len(tools) != 1 || tools[0].Name != "Greeter_SayHello" || tools[0].Description != "Sends a greeting" {
+		t.Fatalf("expected the SayHello tool, got %+v", tools)
+	}
+	wantInput := `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "description": "Name to greet"},
+			"count": {"type": ["integer", "string"]},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"scores": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"sentAt": {"type": "string", "format": "date-time"},
+			"mood": {"type": "string", "enum": ["MOOD_UNKNOWN", "HAPPY"]}
+		}
+	}`
+	if !sameJSON(t, tools[0].InputSchema, wantInput) {
+		got, _ := json.Marshal(tools[0].InputSchema)
+		t.Errorf("got input schema %s", got)
+	}
+	wantOutput := `{
+		"type": "object",
+		"properties": {
+			"message": {"type": "string"},
+			"lengths": {"type": "array", "items": {"type": "integer"}},
+			"mood": {"type": "string", "enum": ["MOOD_UNKNOWN", "HAPPY"]},
+			"sentAt": {"type": "string", "format": "date-time"}
+		}
+	}`
+	if !sameJSON(t, tools[0].OutputSchema, wantOutput) {
+		got, _ := json.Marshal(tools[0].OutputSchema)
+		t.Errorf("got output schema %s", got)
+	}
+
+	// Arguments in the JSON mapping, under JSON or proto field names, make
+	// the round trip through the binary encoding
+	tc.ExpectToolResult("Greeter_SayHello", map[string]interface{}{
+		"name":    "Ann",
+		"count":   "3",
+		"tags":    []interface{}{"go", "grpc"},
+		"scores":  map[string]interface{}{"b": 2, "a": 1},
+		"sent_at": "2024-01-02T03:04:05.5Z",
+		"mood":    "HAPPY",
+	}, map[string]interface{}{
+		"message": "Hello, Ann x3 a=1,b=2",
+		"lengths": []interface{}{2, 4},
+		"mood":    "HAPPY",
+		"sentAt":  "2024-01-02T03:04:05.5Z",
+	})
+	if len(conn.methods) != 1 || conn.methods[0] != "/greet.Greeter/SayHello" {
+		t.Errorf("expected one call of SayHello, got %v", conn.methods)
+	}
+
+	// Invalid arguments never reach the server, and its errors are results
+	tc.ExpectToolError("Greeter_SayHello", map[string]interface{}{"name": "Ann", "nickname": "A"}, `unknown field "nickname"`)
+	tc.ExpectToolError("Greeter_SayHello", map[string]interface{}{"name": "Ann", "mood": "SAD"}, `unknown greet.Mood value "SAD"`)
+	tc.ExpectToolError("Greeter_SayHello", map[string]interface{}{"count": 1.5}, "1.5 is not an integer")
+	if len(conn.methods) != 1 {
+		t.Errorf("expected invalid arguments not to be sent, got calls %v", conn.methods)
+	}
+	tc.ExpectToolError("Greeter_SayHello", nil, "name is required")
+}
+
+func TestRegisterReflection(t *testing.T) {
+	srv := server.NewServer("grpc")
+	err := RegisterReflection(context.Background(), srv, &greeterConn{}, greeterReflector{}, WithFilter(func(service, method string) bool {
+		return service == "greet.Greeter"
+	}))
+	if err != nil {
+		t.Fatalf("RegisterReflection failed: %v", err)
+	}
+	tc := mcptest.NewClient(t, srv)
+
+	if tools := tc.Tools(); len(tools) != 1 || tools[0].Name != "Greeter_SayHello" {
+		t.Fatalf("expected only the SayHello tool, got %+v", tools)
+	}
+	tc.ExpectToolResult("Greeter_SayHello", map[string]interface{}{"name": "Bob"}, `{"message":"Hello, Bob x0 ","mood":"MOOD_UNKNOWN"}`)
+}
+
+// sameJSON reports whether value marshals to the same JSON as want
+func sameJSON(t *testing.T, value interface{}, want string) bool {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, wanted interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &wanted); err != nil {
+		t.Fatal(err)
+	}
+	return reflect.DeepEqual(got, wanted)
+}
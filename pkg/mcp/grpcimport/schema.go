@@ -0,0 +1,105 @@
+package grpcimport
+
+import "fmt"
+
+// Well-known types with a JSON representation of their own
+const (
+	timestampType = "google.protobuf.Timestamp"
+	durationType  = "google.protobuf.Duration"
+)
+
+// messageSchema returns the JSON schema of a message. Recursive messages
+// are cut off with an empty schema, which accepts any value.
+func (f *Files) messageSchema(name string, expanding map[string]bool) (map[string]interface{}, error) {
+	switch name {
+	case timestampType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case durationType:
+		return map[string]interface{}{"type": "string", "description": `Duration in seconds with an "s" suffix, such as "1.5s"`}, nil
+	}
+	if expanding[name] {
+		return map[string]interface{}{}, nil
+	}
+
+	m, ok := f.messages[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown message type %s", name)
+	}
+	expanding[name] = true
+	defer delete(expanding, name)
+
+	properties := make(map[string]interface{}, len(m.fields))
+	for _, fd := range m.fields {
+		schema, err := f.fieldSchema(fd, expanding)
+		if err != nil {
+			return nil, err
+		}
+		if fd.description != "" {
+			schema["description"] = fd.description
+		}
+		properties[fd.jsonName] = schema
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if m.description != "" {
+		schema["description"] = m.description
+	}
+	return schema, nil
+}
+
+// fieldSchema returns the JSON schema of a field's values
+func (f *Files) fieldSchema(fd *field, expanding map[string]bool) (map[string]interface{}, error) {
+	if entry := f.mapEntry(fd); entry != nil {
+		value, err := f.valueSchema(entry.field(2), expanding)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": value}, nil
+	}
+
+	schema, err := f.valueSchema(fd, expanding)
+	if err != nil {
+		return nil, err
+	}
+	if fd.repeated {
+		return map[string]interface{}{"type": "array", "items": schema}, nil
+	}
+	return schema, nil
+}
+
+// valueSchema returns the JSON schema of a single value of a field
+func (f *Files) valueSchema(fd *field, expanding map[string]bool) (map[string]interface{}, error) {
+	switch fd.typ {
+	case typeDouble, typeFloat:
+		return map[string]interface{}{"type": "number"}, nil
+	case typeInt32, typeSint32, typeSfixed32, typeUint32, typeFixed32:
+		return map[string]interface{}{"type": "integer"}, nil
+	case typeInt64, typeSint64, typeSfixed64, typeUint64, typeFixed64:
+		// 64-bit integers are strings in the protobuf JSON mapping, as they
+		// don't fit a double; numbers are accepted too
+		return map[string]interface{}{"type": []string{"integer", "string"}}, nil
+	case typeBool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case typeString:
+		return map[string]interface{}{"type": "string"}, nil
+	case typeBytes:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+	case typeEnum:
+		e, ok := f.enums[fd.typeName]
+		if !ok {
+			return nil, fmt.Errorf("unknown enum type %s", fd.typeName)
+		}
+		names := make([]string, len(e.values))
+		for i, value := range e.values {
+			names[i] = value.name
+		}
+		return map[string]interface{}{"type": "string", "enum": names}, nil
+	case typeMessage:
+		return f.messageSchema(fd.typeName, expanding)
+	default:
+		return nil, fmt.Errorf("field %s has unsupported type %d", fd.name, fd.typ)
+	}
+}
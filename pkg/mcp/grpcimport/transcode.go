@@ -0,0 +1,595 @@
+package grpcimport
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mapEntry returns the entry message of a map field, or nil for other
+// fields
+func (f *Files) mapEntry(fd *field) *message {
+	if fd.typ != typeMessage || !fd.repeated {
+		return nil
+	}
+	entry, ok := f.messages[fd.typeName]
+	if !ok || !entry.mapEntry || entry.field(1) == nil || entry.field(2) == nil {
+		return nil
+	}
+	return entry
+}
+
+// encodeMessage serializes a value in the protobuf JSON mapping as a
+// message of the named type
+func (f *Files) encodeMessage(name string, value interface{}) ([]byte, error) {
+	switch name {
+	case timestampType:
+		return encodeTimestamp(value)
+	case durationType:
+		return encodeDuration(value)
+	}
+
+	m, ok := f.messages[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown message type %s", name)
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object, got %T", name, value)
+	}
+
+	known := make(map[string]bool, 2*len(m.fields))
+	var b []byte
+	for _, fd := range m.fields {
+		known[fd.jsonName], known[fd.name] = true, true
+		v, ok := object[fd.jsonName]
+		if !ok {
+			v, ok = object[fd.name]
+		}
+		if !ok || v == nil {
+			continue
+		}
+
+		var err error
+		if b, err = f.encodeField(b, fd, v); err != nil {
+			return nil, fmt.Errorf("field %s: %w", fd.jsonName, err)
+		}
+	}
+	for key := range object {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown field %q of %s", key, name)
+		}
+	}
+	return b, nil
+}
+
+// encodeField appends the encoding of a field's value
+func (f *Files) encodeField(b []byte, fd *field, value interface{}) ([]byte, error) {
+	if entry := f.mapEntry(fd); entry != nil {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("must be an object, got %T", value)
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			e, err := f.encodeValue(nil, entry.field(1), key)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			if e, err = f.encodeValue(e, entry.field(2), object[key]); err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			b = appendBytesField(b, fd.number, e)
+		}
+		return b, nil
+	}
+
+	if fd.repeated {
+		array, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("must be an array, got %T", value)
+		}
+		for i, v := range array {
+			var err error
+			if b, err = f.encodeValue(b, fd, v); err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		return b, nil
+	}
+	return f.encodeValue(b, fd, value)
+}
+
+// encodeValue appends the encoding of a single value of a field
+func (f *Files) encodeValue(b []byte, fd *field, value interface{}) ([]byte, error) {
+	switch fd.typ {
+	case typeInt32, typeInt64, typeUint32, typeUint64, typeSint32, typeSint64, typeBool, typeEnum:
+		v, err := f.varintValue(fd, value)
+		if err != nil {
+			return nil, err
+		}
+		return appendVarint(appendTag(b, fd.number, wireVarint), v), nil
+	case typeFixed32, typeSfixed32, typeFloat:
+		var v uint32
+		switch fd.typ {
+		case typeFixed32:
+			n, err := toUint(value, 32)
+			if err != nil {
+				return nil, err
+			}
+			v = uint32(n)
+		case typeSfixed32:
+			n, err := toInt(value, 32)
+			if err != nil {
+				return nil, err
+			}
+			v = uint32(int32(n))
+		default:
+			n, err := toFloat(value, 32)
+			if err != nil {
+				return nil, err
+			}
+			v = math.Float32bits(float32(n))
+		}
+		return binary.LittleEndian.AppendUint32(appendTag(b, fd.number, wireFixed32), v), nil
+	case typeFixed64, typeSfixed64, typeDouble:
+		var v uint64
+		switch fd.typ {
+		case typeFixed64:
+			n, err := toUint(value, 64)
+			if err != nil {
+				return nil, err
+			}
+			v = n
+		case typeSfixed64:
+			n, err := toInt(value, 64)
+			if err != nil {
+				return nil, err
+			}
+			v = uint64(n)
+		default:
+			n, err := toFloat(value, 64)
+			if err != nil {
+				return nil, err
+			}
+			v = math.Float64bits(n)
+		}
+		return binary.LittleEndian.AppendUint64(appendTag(b, fd.number, wireFixed64), v), nil
+	case typeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string, got %T", value)
+		}
+		return appendBytesField(b, fd.number, []byte(s)), nil
+	case typeBytes:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a base64 string, got %T", value)
+		}
+		data, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			if data, err = base64.URLEncoding.DecodeString(s); err != nil {
+				return nil, fmt.Errorf("invalid base64: %w", err)
+			}
+		}
+		return appendBytesField(b, fd.number, data), nil
+	case typeMessage:
+		data, err := f.encodeMessage(fd.typeName, value)
+		if err != nil {
+			return nil, err
+		}
+		return appendBytesField(b, fd.number, data), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %d", fd.typ)
+	}
+}
+
+// varintValue converts a value of a varint-encoded field
+func (f *Files) varintValue(fd *field, value interface{}) (uint64, error) {
+	switch fd.typ {
+	case typeInt32, typeInt64:
+		bits := 64
+		if fd.typ == typeInt32 {
+			bits = 32
+		}
+		n, err := toInt(value, bits)
+		return uint64(n), err
+	case typeSint32, typeSint64:
+		bits := 64
+		if fd.typ == typeSint32 {
+			bits = 32
+		}
+		n, err := toInt(value, bits)
+		return zigzag(n), err
+	case typeUint32:
+		return toUint(value, 32)
+	case typeUint64:
+		return toUint(value, 64)
+	case typeBool:
+		switch v := value.(type) {
+		case bool:
+			if v {
+				return 1, nil
+			}
+			return 0, nil
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return 0, fmt.Errorf("invalid boolean %q", v)
+			}
+			if parsed {
+				return 1, nil
+			}
+			return 0, nil
+		}
+		return 0, fmt.Errorf("must be a boolean, got %T", value)
+	default:
+		e, ok := f.enums[fd.typeName]
+		if !ok {
+			return 0, fmt.Errorf("unknown enum type %s", fd.typeName)
+		}
+		if name, ok := value.(string); ok {
+			for _, v := range e.values {
+				if v.name == name {
+					return uint64(int64(v.number)), nil
+				}
+			}
+			return 0, fmt.Errorf("unknown %s value %q", e.fullName, name)
+		}
+		n, err := toInt(value, 32)
+		return uint64(n), err
+	}
+}
+
+// toInt converts a JSON number or numeric string to a signed integer of
+// the given size
+func toInt(value interface{}, bits int) (int64, error) {
+	switch v := value.(type) {
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return strconv.ParseInt(strconv.FormatFloat(v, 'f', -1, 64), 10, bits)
+	case json.Number:
+		return strconv.ParseInt(v.String(), 10, bits)
+	case string:
+		return strconv.ParseInt(v, 10, bits)
+	}
+	return 0, fmt.Errorf("must be an integer, got %T", value)
+}
+
+// toUint converts a JSON number or numeric string to an unsigned integer
+// of the given size
+func toUint(value interface{}, bits int) (uint64, error) {
+	switch v := value.(type) {
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return strconv.ParseUint(strconv.FormatFloat(v, 'f', -1, 64), 10, bits)
+	case json.Number:
+		return strconv.ParseUint(v.String(), 10, bits)
+	case string:
+		return strconv.ParseUint(v, 10, bits)
+	}
+	return 0, fmt.Errorf("must be an unsigned integer, got %T", value)
+}
+
+// toFloat converts a JSON number, numeric string, "NaN", "Infinity" or
+// "-Infinity" to a float
+func toFloat(value interface{}, bits int) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case json.Number:
+		return strconv.ParseFloat(v.String(), bits)
+	case string:
+		switch v {
+		case "NaN":
+			return math.NaN(), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		}
+		return strconv.ParseFloat(v, bits)
+	}
+	return 0, fmt.Errorf("must be a number, got %T", value)
+}
+
+// encodeTimestamp encodes an RFC 3339 time as a google.protobuf.Timestamp
+func encodeTimestamp(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("timestamp must be an RFC 3339 string, got %T", value)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp: %w", err)
+	}
+	return encodeSecondsNanos(t.Unix(), int32(t.Nanosecond())), nil
+}
+
+// encodeDuration encodes a duration such as "1.5s" as a
+// google.protobuf.Duration
+func encodeDuration(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok || !strings.HasSuffix(s, "s") {
+		return nil, fmt.Errorf(`duration must be a string of seconds with an "s" suffix, got %v`, value)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration: %w", err)
+	}
+	return encodeSecondsNanos(int64(d/time.Second), int32(d%time.Second)), nil
+}
+
+// encodeSecondsNanos encodes the seconds and nanos fields shared by
+// timestamps and durations
+func encodeSecondsNanos(seconds int64, nanos int32) []byte {
+	var b []byte
+	if seconds != 0 {
+		b = appendVarint(appendTag(b, 1, wireVarint), uint64(seconds))
+	}
+	if nanos != 0 {
+		b = appendVarint(appendTag(b, 2, wireVarint), uint64(int64(nanos)))
+	}
+	return b
+}
+
+// decodeMessage converts a serialized message of the named type to its
+// protobuf JSON mapping. Unknown fields are dropped.
+func (f *Files) decodeMessage(name string, data []byte) (interface{}, error) {
+	switch name {
+	case timestampType, durationType:
+		seconds, nanos, err := decodeSecondsNanos(data)
+		if err != nil {
+			return nil, err
+		}
+		if name == timestampType {
+			return time.Unix(seconds, int64(nanos)).UTC().Format(time.RFC3339Nano), nil
+		}
+		return formatDuration(seconds, nanos), nil
+	}
+
+	m, ok := f.messages[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown message type %s", name)
+	}
+
+	object := make(map[string]interface{})
+	err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		fd := m.field(num)
+		if fd == nil {
+			return nil
+		}
+
+		if entry := f.mapEntry(fd); entry != nil {
+			key, value, err := f.decodeMapEntry(entry, data)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", fd.jsonName, err)
+			}
+			entries, _ := object[fd.jsonName].(map[string]interface{})
+			if entries == nil {
+				entries = make(map[string]interface{})
+				object[fd.jsonName] = entries
+			}
+			entries[key] = value
+			return nil
+		}
+
+		// Repeated numeric fields may be packed into one length-delimited
+		// field
+		if fd.repeated && typ == wireBytes && packable(fd.typ) {
+			values, err := f.decodePacked(fd, data)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", fd.jsonName, err)
+			}
+			if len(values) == 0 {
+				return nil
+			}
+			array, _ := object[fd.jsonName].([]interface{})
+			object[fd.jsonName] = append(array, values...)
+			return nil
+		}
+
+		value, err := f.decodeValue(fd, typ, v, data)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fd.jsonName, err)
+		}
+		if fd.repeated {
+			array, _ := object[fd.jsonName].([]interface{})
+			object[fd.jsonName] = append(array, value)
+		} else {
+			object[fd.jsonName] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// decodeMapEntry decodes the key and value of a map entry
+func (f *Files) decodeMapEntry(entry *message, data []byte) (string, interface{}, error) {
+	keyField, valueField := entry.field(1), entry.field(2)
+	var key, value interface{}
+	err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		var err error
+		switch num {
+		case 1:
+			key, err = f.decodeValue(keyField, typ, v, data)
+		case 2:
+			value, err = f.decodeValue(valueField, typ, v, data)
+		}
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if value == nil && valueField.typ == typeMessage {
+		value = map[string]interface{}{}
+	}
+	if key == nil {
+		key = ""
+		if keyField.typ != typeString {
+			key = "0"
+		}
+		if keyField.typ == typeBool {
+			key = false
+		}
+	}
+	return fmt.Sprint(key), value, nil
+}
+
+// packable reports whether repeated fields of a type may be packed
+func packable(typ int32) bool {
+	switch typ {
+	case typeString, typeBytes, typeMessage, typeGroup:
+		return false
+	}
+	return true
+}
+
+// decodePacked decodes the values of a packed repeated field
+func (f *Files) decodePacked(fd *field, data []byte) ([]interface{}, error) {
+	var values []interface{}
+	for len(data) > 0 {
+		var v uint64
+		var n int
+		var typ wireType
+		switch fd.typ {
+		case typeFixed32, typeSfixed32, typeFloat:
+			if len(data) < 4 {
+				return nil, errTruncated
+			}
+			v, n, typ = uint64(binary.LittleEndian.Uint32(data)), 4, wireFixed32
+		case typeFixed64, typeSfixed64, typeDouble:
+			if len(data) < 8 {
+				return nil, errTruncated
+			}
+			v, n, typ = binary.LittleEndian.Uint64(data), 8, wireFixed64
+		default:
+			var err error
+			if v, n, err = consumeVarint(data); err != nil {
+				return nil, err
+			}
+			typ = wireVarint
+		}
+		data = data[n:]
+
+		value, err := f.decodeValue(fd, typ, v, nil)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// decodeValue converts a single field value to its JSON mapping. 64-bit
+// integers become strings, as in the protobuf JSON mapping.
+func (f *Files) decodeValue(fd *field, typ wireType, v uint64, data []byte) (interface{}, error) {
+	switch fd.typ {
+	case typeInt32:
+		return int32(v), nil
+	case typeSint32:
+		return int32(unzigzag(v)), nil
+	case typeUint32, typeFixed32:
+		return uint32(v), nil
+	case typeSfixed32:
+		return int32(uint32(v)), nil
+	case typeInt64, typeSfixed64:
+		return strconv.FormatInt(int64(v), 10), nil
+	case typeSint64:
+		return strconv.FormatInt(unzigzag(v), 10), nil
+	case typeUint64, typeFixed64:
+		return strconv.FormatUint(v, 10), nil
+	case typeBool:
+		return v != 0, nil
+	case typeFloat:
+		return floatValue(float64(math.Float32frombits(uint32(v)))), nil
+	case typeDouble:
+		return floatValue(math.Float64frombits(v)), nil
+	case typeEnum:
+		if e, ok := f.enums[fd.typeName]; ok {
+			for _, value := range e.values {
+				if value.number == int32(v) {
+					return value.name, nil
+				}
+			}
+		}
+		return int32(v), nil
+	case typeString:
+		if typ != wireBytes {
+			return nil, fmt.Errorf("unexpected wire type %d", typ)
+		}
+		return string(data), nil
+	case typeBytes:
+		if typ != wireBytes {
+			return nil, fmt.Errorf("unexpected wire type %d", typ)
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	case typeMessage:
+		if typ != wireBytes {
+			return nil, fmt.Errorf("unexpected wire type %d", typ)
+		}
+		return f.decodeMessage(fd.typeName, data)
+	default:
+		return nil, fmt.Errorf("unsupported type %d", fd.typ)
+	}
+}
+
+// floatValue maps a float to JSON, where NaN and infinities are strings
+func floatValue(v float64) interface{} {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
+	}
+	return v
+}
+
+// formatDuration formats a duration as seconds with an "s" suffix
+func formatDuration(seconds int64, nanos int32) string {
+	sign := ""
+	if seconds < 0 || nanos < 0 {
+		sign, seconds, nanos = "-", -seconds, -nanos
+	}
+	s := sign + strconv.FormatInt(seconds, 10)
+	if nanos != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%09d", nanos), "0")
+	}
+	return s + "s"
+}
+
+// decodeSecondsNanos decodes the fields of a timestamp or duration
+func decodeSecondsNanos(data []byte) (int64, int32, error) {
+	var seconds int64
+	var nanos int32
+	err := parseFields(data, func(num int32, typ wireType, v uint64, data []byte) error {
+		switch {
+		case num == 1 && typ == wireVarint:
+			seconds = int64(v)
+		case num == 2 && typ == wireVarint:
+			nanos = int32(v)
+		}
+		return nil
+	})
+	return seconds, nanos, err
+}
@@ -0,0 +1,112 @@
+package grpcimport
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// wireType is the encoding of a protobuf field on the wire
+type wireType int
+
+const (
+	wireVarint  wireType = 0
+	wireFixed64 wireType = 1
+	wireBytes   wireType = 2
+	wireFixed32 wireType = 5
+)
+
+// errTruncated is returned for messages ending in the middle of a field
+var errTruncated = errors.New("truncated protobuf message")
+
+// consumeVarint decodes a varint, returning it and its length
+func consumeVarint(b []byte) (uint64, int, error) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, i + 1, nil
+		}
+	}
+	return 0, 0, errTruncated
+}
+
+// parseFields calls fn for every field of a serialized message. Varint and
+// fixed fields are passed in v, length-delimited fields in data.
+func parseFields(b []byte, fn func(num int32, typ wireType, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, n, err := consumeVarint(b)
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+		num, typ := int32(tag>>3), wireType(tag&7)
+
+		var v uint64
+		var data []byte
+		switch typ {
+		case wireVarint:
+			if v, n, err = consumeVarint(b); err != nil {
+				return err
+			}
+		case wireFixed64:
+			if len(b) < 8 {
+				return errTruncated
+			}
+			v, n = binary.LittleEndian.Uint64(b), 8
+		case wireFixed32:
+			if len(b) < 4 {
+				return errTruncated
+			}
+			v, n = uint64(binary.LittleEndian.Uint32(b)), 4
+		case wireBytes:
+			length, m, err := consumeVarint(b)
+			if err != nil {
+				return err
+			}
+			if uint64(len(b)-m) < length {
+				return errTruncated
+			}
+			data, n = b[m:m+int(length)], m+int(length)
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", typ)
+		}
+		b = b[n:]
+
+		if err := fn(num, typ, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendVarint appends a varint
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// appendTag appends a field tag
+func appendTag(b []byte, num int32, typ wireType) []byte {
+	return appendVarint(b, uint64(num)<<3|uint64(typ))
+}
+
+// appendBytesField appends a length-delimited field
+func appendBytesField(b []byte, num int32, data []byte) []byte {
+	b = appendTag(b, num, wireBytes)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// zigzag encodes a signed integer for sint32 and sint64 fields
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// unzigzag decodes a zigzag-encoded integer
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
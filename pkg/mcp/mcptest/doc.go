@@ -0,0 +1,38 @@
+// Package mcptest provides helpers for end-to-end tests of MCP servers.
+//
+// NewClient serves a Server over an in-memory connection and returns a
+// client that has completed the initialization handshake, so tests need no
+// goroutines, pipes or ports:
+//
+//	func TestGreet(t *testing.T) {
+//	    srv := server.NewServer("Example")
+//	    srv.AddTool("greet", func(name string) string {
+//	        return "Hello, " + name + "!"
+//	    }, "Greet a person")
+//
+//	    tc := mcptest.NewClient(t, srv)
+//	    tc.ExpectToolResult("greet", map[string]interface{}{"arg0": "Ann"}, "Hello, Ann!")
+//	}
+//
+// The Expect methods fail the test when the server answers differently:
+//
+//	// Structured content is compared as JSON
+//	tc.ExpectToolResult("stats", nil, map[string]interface{}{"count": 3})
+//
+//	// An error result whose text contains the given string
+//	tc.ExpectToolError("divide", map[string]interface{}{"arg0": 1, "arg1": 0}, "division by zero")
+//
+//	// A JSON-RPC error code
+//	tc.ExpectError("tools/call", map[string]interface{}{"name": "missing"}, protocol.InvalidParams)
+//
+// Notifications sent by the server are recorded, and ExpectNotification
+// waits for one of a method:
+//
+//	tc.Client.Subscribe(ctx, "file:///notes.txt")
+//	srv.NotifyResourceUpdated("file:///notes.txt")
+//	tc.ExpectNotification("notifications/resources/updated")
+//
+// The methods of Client fail the test on protocol errors and return the
+// results for further checks. Client.Client gives access to the full
+// client API. Package fastmcptest creates a Client for fastmcp apps.
+package mcptest
@@ -0,0 +1,258 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/transport"
+)
+
+// Timeout bounds each request of a Client and the wait for an expected
+// notification
+var Timeout = 10 * time.Second
+
+// Client is a client connected to a server in memory, for tests. Its
+// methods fail the test on protocol errors, so a tool returning an error
+// result is not a failure. Client gives access to the full client API.
+type Client struct {
+	Client *client.Client
+	t      testing.TB
+
+	mu            sync.Mutex
+	notifications []Notification
+	received      chan struct{}
+}
+
+// Notification is a notification the server sent to a Client
+type Notification struct {
+	Method string
+	Params json.RawMessage
+}
+
+// NewClient connects a client to srv over an in-memory connection and
+// performs the initialization handshake. The connection is closed when the
+// test ends. Notifications from the server are recorded for
+// ExpectNotification, in addition to being passed to the handlers given
+// in options.
+func NewClient(t testing.TB, srv *server.Server, options ...client.Option) *Client {
+	t.Helper()
+
+	tc := &Client{t: t, received: make(chan struct{})}
+	options = append([]client.Option{client.WithNotificationHandler(tc.record)}, options...)
+
+	listener := newPipeListener()
+	tr := transport.NewTCPTransport(srv)
+	go tr.(*transport.TCPTransport).Serve(listener)
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	c, err := client.Connect(ctx, client.NewStreamConn(listener.dial()), options...)
+	if err != nil {
+		t.Fatalf("failed to connect test client: %v", err)
+	}
+	t.Cleanup(func() {
+		c.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+		defer cancel()
+		tr.Stop(ctx)
+	})
+	tc.Client = c
+	return tc
+}
+
+// context returns the context of a request
+func (tc *Client) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), Timeout)
+}
+
+// CallTool calls a tool and returns its result
+func (tc *Client) CallTool(name string, arguments map[string]interface{}) *protocol.CallToolResult {
+	tc.t.Helper()
+	ctx, cancel := tc.context()
+	defer cancel()
+	result, err := tc.Client.CallTool(ctx, name, arguments)
+	if err != nil {
+		tc.t.Fatalf("failed to call tool %s: %v", name, err)
+	}
+	return result
+}
+
+// Tools lists the server's tools
+func (tc *Client) Tools() []protocol.Tool {
+	tc.t.Helper()
+	ctx, cancel := tc.context()
+	defer cancel()
+	tools, err := tc.Client.Tools(ctx)
+	if err != nil {
+		tc.t.Fatalf("failed to list tools: %v", err)
+	}
+	return tools
+}
+
+// ReadResource reads a resource
+func (tc *Client) ReadResource(uri string) *protocol.ReadResourceResult {
+	tc.t.Helper()
+	ctx, cancel := tc.context()
+	defer cancel()
+	result, err := tc.Client.ReadResource(ctx, uri)
+	if err != nil {
+		tc.t.Fatalf("failed to read resource %s: %v", uri, err)
+	}
+	return result
+}
+
+// GetPrompt gets a prompt with the given arguments
+func (tc *Client) GetPrompt(name string, arguments map[string]string) *protocol.GetPromptResult {
+	tc.t.Helper()
+	ctx, cancel := tc.context()
+	defer cancel()
+	result, err := tc.Client.GetPrompt(ctx, name, arguments)
+	if err != nil {
+		tc.t.Fatalf("failed to get prompt %s: %v", name, err)
+	}
+	return result
+}
+
+// ExpectToolResult calls a tool and fails the test unless it succeeds with
+// the wanted result. A string is compared with the text content of the
+// result, and any other value with its structured content, as JSON.
+func (tc *Client) ExpectToolResult(name string, arguments map[string]interface{}, want interface{}) *protocol.CallToolResult {
+	tc.t.Helper()
+	result := tc.CallTool(name, arguments)
+	if result.IsError {
+		tc.t.Fatalf("tool %s failed: %s", name, Text(result))
+	}
+
+	if text, ok := want.(string); ok {
+		if got := Text(result); got != text {
+			tc.t.Errorf("tool %s returned %q, want %q", name, got, text)
+		}
+		return result
+	}
+	if !jsonEqual(result.StructuredContent, want) {
+		got, _ := json.Marshal(result.StructuredContent)
+		wanted, _ := json.Marshal(want)
+		tc.t.Errorf("tool %s returned structured content %s, want %s", name, got, wanted)
+	}
+	return result
+}
+
+// ExpectToolError calls a tool and fails the test unless it returns an
+// error result whose text contains want
+func (tc *Client) ExpectToolError(name string, arguments map[string]interface{}, want string) *protocol.CallToolResult {
+	tc.t.Helper()
+	result := tc.CallTool(name, arguments)
+	if !result.IsError {
+		tc.t.Fatalf("tool %s succeeded with %q, want an error", name, Text(result))
+	}
+	if got := Text(result); !strings.Contains(got, want) {
+		tc.t.Errorf("tool %s failed with %q, want an error containing %q", name, got, want)
+	}
+	return result
+}
+
+// ExpectError sends a request and fails the test unless the server answers
+// with a JSON-RPC error of the given code, such as protocol.InvalidParams
+func (tc *Client) ExpectError(method string, params interface{}, code int) *protocol.ErrorData {
+	tc.t.Helper()
+	ctx, cancel := tc.context()
+	defer cancel()
+
+	var result json.RawMessage
+	err := tc.Client.Request(ctx, method, params, &result)
+	if err == nil {
+		tc.t.Fatalf("%s succeeded with %s, want error code %d", method, result, code)
+	}
+	var rpcErr *protocol.ErrorData
+	if !errors.As(err, &rpcErr) {
+		tc.t.Fatalf("%s failed: %v, want error code %d", method, err, code)
+	}
+	if rpcErr.Code != code {
+		tc.t.Errorf("%s failed with code %d (%s), want %d", method, rpcErr.Code, rpcErr.Message, code)
+	}
+	return rpcErr
+}
+
+// ExpectNotification waits for a notification of the given method and
+// returns its params, failing the test if none arrives within Timeout.
+// Each notification is returned once, oldest first.
+func (tc *Client) ExpectNotification(method string) json.RawMessage {
+	tc.t.Helper()
+	deadline := time.NewTimer(Timeout)
+	defer deadline.Stop()
+
+	for {
+		tc.mu.Lock()
+		for i, n := range tc.notifications {
+			if n.Method == method {
+				tc.notifications = append(tc.notifications[:i], tc.notifications[i+1:]...)
+				tc.mu.Unlock()
+				return n.Params
+			}
+		}
+		received := tc.received
+		tc.mu.Unlock()
+
+		select {
+		case <-received:
+		case <-deadline.C:
+			tc.t.Fatalf("no %s notification within %s", method, Timeout)
+			return nil
+		}
+	}
+}
+
+// Notifications returns the recorded notifications not yet returned by
+// ExpectNotification
+func (tc *Client) Notifications() []Notification {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	return append([]Notification(nil), tc.notifications...)
+}
+
+// record stores a notification and wakes up ExpectNotification
+func (tc *Client) record(method string, params json.RawMessage) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.notifications = append(tc.notifications, Notification{Method: method, Params: params})
+	close(tc.received)
+	tc.received = make(chan struct{})
+}
+
+// Text returns the text content of a tool result
+func Text(result *protocol.CallToolResult) string {
+	var text strings.Builder
+	for _, content := range result.Content {
+		if t, ok := content.(protocol.TextContent); ok {
+			text.WriteString(t.Text)
+		}
+	}
+	return text.String()
+}
+
+// jsonEqual reports whether two values have the same JSON encoding,
+// ignoring the order of object keys
+func jsonEqual(a, b interface{}) bool {
+	var decoded [2]interface{}
+	for i, v := range []interface{}{a, b} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return false
+		}
+		if err := json.Unmarshal(data, &decoded[i]); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(decoded[0], decoded[1])
+}
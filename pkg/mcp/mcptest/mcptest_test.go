@@ -0,0 +1,49 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+func TestClient(t *testing.T) {
+	type stats struct {
+		Count int `json:"count"`
+	}
+
+	srv := server.NewServer("test")
+	srv.AddTool("greet", func(name string) string { return "Hello, " + name + "!" }, "Greet a person")
+	srv.AddTool("divide", func(a, b int) (int, error) {
+		if b == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return a / b, nil
+	}, "Divide two numbers")
+	server.AddTypedTool(srv, "stats", func(ctx context.Context, args struct{}) (stats, error) {
+		return stats{Count: 3}, nil
+	}, "Count things")
+
+	tc := NewClient(t, srv)
+	tc.ExpectToolResult("greet", map[string]interface{}{"arg0": "Ann"}, "Hello, Ann!")
+	tc.ExpectToolResult("stats", nil, map[string]interface{}{"count": 3})
+	tc.ExpectToolError("divide", map[string]interface{}{"arg0": 1, "arg1": 0}, "division by zero")
+	tc.ExpectError("tools/call", map[string]interface{}{"name": "missing"}, protocol.InvalidParams)
+
+	if err := tc.Client.Subscribe(context.Background(), "file:///notes.txt"); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	go srv.NotifyResourceUpdated("file:///notes.txt")
+	params := tc.ExpectNotification("notifications/resources/updated")
+
+	var updated protocol.ResourceUpdatedNotificationParams
+	if err := json.Unmarshal(params, &updated); err != nil || updated.URI != "file:///notes.txt" {
+		t.Errorf("unexpected update params %s: %v", params, err)
+	}
+	if n := tc.Notifications(); len(n) != 0 {
+		t.Errorf("expected the notification to be consumed, got %v", n)
+	}
+}
@@ -0,0 +1,56 @@
+package mcptest
+
+import (
+	"net"
+	"sync"
+)
+
+// pipeListener is a net.Listener accepting in-memory connections
+type pipeListener struct {
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newPipeListener creates a listener for in-memory connections
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+// dial returns the client end of a new connection to the listener
+func (l *pipeListener) dial() net.Conn {
+	clientConn, serverConn := net.Pipe()
+	select {
+	case l.conns <- serverConn:
+	case <-l.done:
+		serverConn.Close()
+	}
+	return clientConn
+}
+
+// Accept waits for the next connection
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close stops accepting connections
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() { close(l.done) })
+	return nil
+}
+
+// Addr returns the listener's address
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// pipeAddr is the address of in-memory connections
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
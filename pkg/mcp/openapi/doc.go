@@ -0,0 +1,36 @@
+// Package openapi exposes REST APIs described by OpenAPI 3 documents as
+// MCP tools.
+//
+// Every operation of the document becomes a tool. Its arguments are the
+// operation's path, query, header and cookie parameters, plus a "body"
+// argument holding the request body, with schemas taken from the document.
+// Calling the tool makes the HTTP request and returns the response body as
+// text; responses with a 4xx or 5xx status are reported as error results.
+//
+//	data, err := os.ReadFile("petstore.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	doc, err := openapi.Parse(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	srv := server.NewServer("Petstore")
+//	err = openapi.Register(srv, doc,
+//	    openapi.WithBaseURL("https://petstore.example.com/v3"),
+//	    openapi.WithHeader("Authorization", "Bearer "+token),
+//	)
+//
+// Tools are named after operation IDs, falling back to the method and path
+// (get_pets_petId). GET, HEAD and OPTIONS operations are annotated as
+// read-only. Only JSON documents are parsed; convert YAML documents to JSON
+// first. Local $ref pointers are resolved, while references to other
+// documents are not supported.
+//
+// Register imports a subset of the operations with WithFilter:
+//
+//	openapi.Register(srv, doc, openapi.WithFilter(func(method, path string, op *openapi.Operation) bool {
+//	    return method == http.MethodGet
+//	}))
+package openapi
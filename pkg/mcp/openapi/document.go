@@ -0,0 +1,222 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Document is the part of an OpenAPI 3 document describing operations
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Servers []Server            `json:"servers,omitempty"`
+	Paths   map[string]PathItem `json:"paths"`
+
+	// raw is the whole document, for resolving $ref pointers
+	raw map[string]interface{}
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL of the API, whose {variables} take their defaults
+type Server struct {
+	URL       string                    `json:"url"`
+	Variables map[string]ServerVariable `json:"variables,omitempty"`
+}
+
+// ServerVariable is a variable of a server URL
+type ServerVariable struct {
+	Default string `json:"default"`
+}
+
+// PathItem holds the operations of a path
+type PathItem struct {
+	Ref        string      `json:"$ref,omitempty"`
+	Parameters []Parameter `json:"parameters,omitempty"`
+	Get        *Operation  `json:"get,omitempty"`
+	Put        *Operation  `json:"put,omitempty"`
+	Post       *Operation  `json:"post,omitempty"`
+	Delete     *Operation  `json:"delete,omitempty"`
+	Options    *Operation  `json:"options,omitempty"`
+	Head       *Operation  `json:"head,omitempty"`
+	Patch      *Operation  `json:"patch,omitempty"`
+	Trace      *Operation  `json:"trace,omitempty"`
+}
+
+// operations returns the operations of the path by HTTP method
+func (p PathItem) operations() map[string]*Operation {
+	return map[string]*Operation{
+		"GET":     p.Get,
+		"PUT":     p.Put,
+		"POST":    p.Post,
+		"DELETE":  p.Delete,
+		"OPTIONS": p.Options,
+		"HEAD":    p.Head,
+		"PATCH":   p.Patch,
+		"TRACE":   p.Trace,
+	}
+}
+
+// Operation is an API operation
+type Operation struct {
+	OperationID string       `json:"operationId,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Deprecated  bool         `json:"deprecated,omitempty"`
+	Parameters  []Parameter  `json:"parameters,omitempty"`
+	RequestBody *RequestBody `json:"requestBody,omitempty"`
+}
+
+// Parameter is a path, query, header or cookie parameter of an operation
+type Parameter struct {
+	Ref         string                 `json:"$ref,omitempty"`
+	Name        string                 `json:"name"`
+	In          string                 `json:"in"`
+	Description string                 `json:"description,omitempty"`
+	Required    bool                   `json:"required,omitempty"`
+	Schema      map[string]interface{} `json:"schema,omitempty"`
+}
+
+// RequestBody is the body of an operation
+type RequestBody struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType describes a body of one content type
+type MediaType struct {
+	Schema map[string]interface{} `json:"schema,omitempty"`
+}
+
+// Parse parses an OpenAPI 3 document in JSON
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", doc.OpenAPI)
+	}
+	if err := json.Unmarshal(data, &doc.raw); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	return &doc, nil
+}
+
+// BaseURL returns the URL of the document's first server, with its
+// variables set to their defaults
+func (d *Document) BaseURL() string {
+	if len(d.Servers) == 0 {
+		return ""
+	}
+	server := d.Servers[0]
+	u := server.URL
+	for name, variable := range server.Variables {
+		u = strings.ReplaceAll(u, "{"+name+"}", variable.Default)
+	}
+	return u
+}
+
+// lookup returns the value a local $ref pointer, such as
+// #/components/schemas/Pet, refers to
+func (d *Document) lookup(ref string) (interface{}, error) {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q: only local references are supported", ref)
+	}
+
+	var value interface{} = d.raw
+	for _, token := range strings.Split(pointer, "/") {
+		token, err := url.PathUnescape(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid $ref %q: %w", ref, err)
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		if value, ok = object[token]; !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+	return value, nil
+}
+
+// resolve decodes the value a $ref pointer refers to into v
+func (d *Document) resolve(ref string, v interface{}) error {
+	value, err := d.lookup(ref)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// inlineSchema returns a copy of a schema with its $ref pointers replaced
+// by what they refer to. Recursive references are cut off with an empty
+// schema, which accepts any value.
+func (d *Document) inlineSchema(schema map[string]interface{}) (map[string]interface{}, error) {
+	inlined, err := d.inline(schema, nil)
+	if err != nil {
+		return nil, err
+	}
+	object, _ := inlined.(map[string]interface{})
+	return object, nil
+}
+
+// inline replaces the $ref pointers in a schema value, tracking the
+// references being expanded
+func (d *Document) inline(value interface{}, expanding []string) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok {
+			for _, r := range expanding {
+				if r == ref {
+					return map[string]interface{}{}, nil
+				}
+			}
+			target, err := d.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			return d.inline(target, append(expanding, ref))
+		}
+
+		inlined := make(map[string]interface{}, len(value))
+		for key, v := range value {
+			v, err := d.inline(v, expanding)
+			if err != nil {
+				return nil, err
+			}
+			inlined[key] = v
+		}
+		return inlined, nil
+	case []interface{}:
+		inlined := make([]interface{}, len(value))
+		for i, v := range value {
+			v, err := d.inline(v, expanding)
+			if err != nil {
+				return nil, err
+			}
+			inlined[i] = v
+		}
+		return inlined, nil
+	default:
+		return value, nil
+	}
+}
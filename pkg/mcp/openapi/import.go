@@ -0,0 +1,380 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// bodyArgument is the tool argument holding an operation's request body
+const bodyArgument = "body"
+
+// maxToolNameLength is the longest tool name generated for an operation
+const maxToolNameLength = 64
+
+// invalidNameChars matches the characters not allowed in tool names
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// Option configures how operations are imported
+type Option func(*importer)
+
+// importer holds the import settings
+type importer struct {
+	doc     *Document
+	baseURL string
+	client  *http.Client
+	header  http.Header
+	filter  func(method, path string, op *Operation) bool
+}
+
+// WithBaseURL sets the URL operations are called on, overriding the
+// document's servers
+func WithBaseURL(baseURL string) Option {
+	return func(i *importer) {
+		i.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the client making the API calls. The default is
+// http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(i *importer) {
+		i.client = client
+	}
+}
+
+// WithHeader adds a header to every API call, such as an Authorization
+// header
+func WithHeader(key, value string) Option {
+	return func(i *importer) {
+		i.header.Add(key, value)
+	}
+}
+
+// WithFilter imports only the operations for which filter returns true
+func WithFilter(filter func(method, path string, op *Operation) bool) Option {
+	return func(i *importer) {
+		i.filter = filter
+	}
+}
+
+// Register adds a tool to srv for every operation of doc. Tools are named
+// after the operation ID, or the method and path of operations without one.
+// Their arguments are the operation's parameters, plus a "body" argument
+// for operations taking a request body. A call makes the HTTP request and
+// returns the response body; responses with an error status become error
+// results.
+func Register(srv *server.Server, doc *Document, options ...Option) error {
+	i := &importer{
+		doc:     doc,
+		baseURL: doc.BaseURL(),
+		client:  http.DefaultClient,
+		header:  make(http.Header),
+	}
+	for _, option := range options {
+		option(i)
+	}
+
+	base, err := url.Parse(i.baseURL)
+	if err != nil || !base.IsAbs() {
+		return fmt.Errorf("invalid base URL %q: use WithBaseURL to set an absolute URL", i.baseURL)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := doc.Paths[path]
+		if item.Ref != "" {
+			if err := doc.resolve(item.Ref, &item); err != nil {
+				return fmt.Errorf("path %s: %w", path, err)
+			}
+		}
+
+		operations := item.operations()
+		for _, method := range []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"} {
+			op := operations[method]
+			if op == nil || (i.filter != nil && !i.filter(method, path, op)) {
+				continue
+			}
+			if err := i.register(srv, method, path, item.Parameters, op); err != nil {
+				return fmt.Errorf("%s %s: %w", method, path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// operation is an operation resolved for calling
+type operation struct {
+	method      string
+	path        string
+	parameters  []Parameter
+	contentType string
+}
+
+// register adds the tool for an operation
+func (i *importer) register(srv *server.Server, method, path string, pathParameters []Parameter, op *Operation) error {
+	resolved := operation{method: method, path: path}
+
+	// Operation parameters override path item parameters with the same
+	// name and location
+	parameters := make(map[string]Parameter)
+	var order []string
+	for _, p := range append(append([]Parameter{}, pathParameters...), op.Parameters...) {
+		if p.Ref != "" {
+			if err := i.doc.resolve(p.Ref, &p); err != nil {
+				return err
+			}
+		}
+		key := p.In + ":" + p.Name
+		if _, exists := parameters[key]; !exists {
+			order = append(order, key)
+		}
+		parameters[key] = p
+	}
+
+	properties := make(map[string]interface{})
+	required := []string{}
+	for _, key := range order {
+		p := parameters[key]
+		schema, err := i.doc.inlineSchema(p.Schema)
+		if err != nil {
+			return err
+		}
+		if schema == nil {
+			schema = map[string]interface{}{"type": "string"}
+		}
+		if p.Description != "" {
+			schema["description"] = p.Description
+		}
+		properties[p.Name] = schema
+		if p.Required || p.In == "path" {
+			required = append(required, p.Name)
+		}
+		resolved.parameters = append(resolved.parameters, p)
+	}
+
+	if op.RequestBody != nil {
+		body := *op.RequestBody
+		if body.Ref != "" {
+			if err := i.doc.resolve(body.Ref, &body); err != nil {
+				return err
+			}
+		}
+		contentType, media := bodyMediaType(body.Content)
+		schema, err := i.doc.inlineSchema(media.Schema)
+		if err != nil {
+			return err
+		}
+		if schema == nil {
+			schema = map[string]interface{}{}
+		}
+		if body.Description != "" {
+			schema["description"] = body.Description
+		}
+		properties[bodyArgument] = schema
+		if body.Required {
+			required = append(required, bodyArgument)
+		}
+		resolved.contentType = contentType
+	}
+
+	description := op.Summary
+	if op.Description != "" {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += op.Description
+	}
+
+	readOnly := method == "GET" || method == "HEAD" || method == "OPTIONS"
+	idempotent := readOnly || method == "PUT" || method == "DELETE"
+	openWorld := true
+	annotations := protocol.ToolAnnotations{
+		ReadOnlyHint:   &readOnly,
+		IdempotentHint: &idempotent,
+		OpenWorldHint:  &openWorld,
+	}
+	if !readOnly {
+		destructive := method == "DELETE" || method == "PUT" || method == "PATCH"
+		annotations.DestructiveHint = &destructive
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	return srv.AddTool(toolName(method, path, op), server.ToolHandlerFunc(i.call(resolved)), description,
+		server.WithInputSchema(schema),
+		server.WithToolAnnotations(annotations),
+	)
+}
+
+// bodyMediaType picks the content type a request body is sent as,
+// preferring JSON
+func bodyMediaType(content map[string]MediaType) (string, MediaType) {
+	if media, ok := content["application/json"]; ok {
+		return "application/json", media
+	}
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+	sort.Strings(types)
+	for _, contentType := range types {
+		if strings.HasSuffix(contentType, "+json") {
+			return contentType, content[contentType]
+		}
+	}
+	if len(types) > 0 {
+		return types[0], content[types[0]]
+	}
+	return "application/json", MediaType{}
+}
+
+// toolName returns the name of an operation's tool
+func toolName(method, path string, op *Operation) string {
+	name := op.OperationID
+	if name == "" {
+		name = strings.ToLower(method) + path
+	}
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+	return name
+}
+
+// call returns the handler calling an operation
+func (i *importer) call(op operation) server.ToolHandlerFunc {
+	return func(ctx context.Context, arguments map[string]interface{}) (protocol.CallToolResult, error) {
+		req, err := i.request(ctx, op, arguments)
+		if err != nil {
+			return protocol.CallToolResult{}, err
+		}
+
+		resp, err := i.client.Do(req)
+		if err != nil {
+			return protocol.CallToolResult{}, fmt.Errorf("request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return protocol.CallToolResult{}, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		text := string(data)
+		if resp.StatusCode >= 400 {
+			return protocol.CallToolResult{
+				Content: []interface{}{protocol.NewTextContent(fmt.Sprintf("%s\n%s", resp.Status, text))},
+				IsError: true,
+			}, nil
+		}
+		return protocol.CallToolResult{
+			Content: []interface{}{protocol.NewTextContent(text)},
+		}, nil
+	}
+}
+
+// request builds the HTTP request of an operation call
+func (i *importer) request(ctx context.Context, op operation, arguments map[string]interface{}) (*http.Request, error) {
+	path := op.path
+	query := make(url.Values)
+	header := i.header.Clone()
+	var cookies []*http.Cookie
+
+	for _, p := range op.parameters {
+		value, ok := arguments[p.Name]
+		if !ok {
+			if p.Required || p.In == "path" {
+				return nil, fmt.Errorf("missing required argument %q", p.Name)
+			}
+			continue
+		}
+
+		values := parameterValues(value)
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(strings.Join(values, ",")))
+		case "query":
+			query[p.Name] = values
+		case "header":
+			header.Set(p.Name, strings.Join(values, ","))
+		case "cookie":
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: strings.Join(values, ",")})
+		}
+	}
+
+	u := strings.TrimSuffix(i.baseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if value, ok := arguments[bodyArgument]; ok && op.contentType != "" {
+		if s, isString := value.(string); isString && !strings.Contains(op.contentType, "json") {
+			body = strings.NewReader(s)
+		} else {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode request body: %w", err)
+			}
+			body = bytes.NewReader(data)
+		}
+		header.Set("Content-Type", op.contentType)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, op.method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	return req, nil
+}
+
+// parameterValues formats an argument as parameter values, one for each
+// element of an array
+func parameterValues(value interface{}) []string {
+	if array, ok := value.([]interface{}); ok {
+		values := make([]string, len(array))
+		for i, v := range array {
+			values[i] = parameterValue(v)
+		}
+		return values
+	}
+	return []string{parameterValue(value)}
+}
+
+// parameterValue formats a single argument value
+func parameterValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		data, _ := json.Marshal(value)
+		return string(data)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,250 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+const petstore = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Petstore", "version": "1.0"},
+	"servers": [{"url": "https://{host}/v1", "variables": {"host": {"default": "pets.example.com"}}}],
+	"paths": {
+		"/pets": {
+			"get": {
+				"operationId": "listPets",
+				"summary": "Lists pets",
+				"parameters": [
+					{"name": "limit", "in": "query", "schema": {"type": "integer"}},
+					{"name": "tags", "in": "query", "schema": {"type": "array", "items": {"type": "string"}}},
+					{"$ref": "#/components/parameters/RequestID"}
+				]
+			},
+			"post": {
+				"operationId": "createPet",
+				"requestBody": {"$ref": "#/components/requestBodies/Pet"}
+			}
+		},
+		"/pets/{petId}": {
+			"parameters": [
+				{"name": "petId", "in": "path", "description": "ID of the pet", "schema": {"type": "string"}}
+			],
+			"get": {
+				"operationId": "getPet",
+				"description": "Returns a pet.",
+				"parameters": [
+					{"name": "session", "in": "cookie", "schema": {"type": "string"}}
+				]
+			},
+			"delete": {}
+		}
+	},
+	"components": {
+		"parameters": {
+			"RequestID": {"name": "X-Request-ID", "in": "header", "required": true, "schema": {"type": "string"}}
+		},
+		"requestBodies": {
+			"Pet": {
+				"description": "The pet to add",
+				"required": true,
+				"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+			}
+		},
+		"schemas": {
+			"Pet": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"tag": {"$ref": "#/components/schemas/Tag"},
+					"parent": {"$ref": "#/components/schemas/Pet"}
+				}
+			},
+			"Tag": {"type": "string", "enum": ["cat", "dog"]}
+		}
+	}
+}`
+
+func TestRegisterSchemas(t *testing.T) {
+	doc, err := Parse([]byte(petstore))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if base := doc.BaseURL(); base != "https://pets.example.com/v1" {
+		t.Errorf("got base URL %q", base)
+	}
+	srv := server.NewServer("openapi")
+	if err := Register(srv, doc); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	tools := make(map[string]map[string]interface{})
+	for _, tool := range mcptest.NewClient(t, srv).Tools() {
+		data, _ := json.Marshal(tool)
+		var decoded map[string]interface{}
+		json.Unmarshal(data, &decoded)
+		tools[tool.Name] = decoded
+	}
+
+	for name, want := range map[string]string{
+		"listPets": `{
+			"type": "object",
+			"properties": {
+				"limit": {"type": "integer"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"X-Request-ID": {"type": "string"}
+			},
+			"required": ["X-Request-ID"]
+		}`,
+		"createPet": `{
+			"type": "object",
+			"properties": {
+				"body": {
+					"type": "object",
+					"description": "The pet to add",
+					"required": ["name"],
+					"properties": {
+						"name": {"type": "string"},
+						"tag": {"type": "string", "enum": ["cat", "dog"]},
+						"parent": {}
+					}
+				}
+			},
+			"required": ["body"]
+		}`,
+		"getPet": `{
+			"type": "object",
+			"properties": {
+				"petId": {"type": "string", "description": "ID of the pet"},
+				"session": {"type": "string"}
+			},
+			"required": ["petId"]
+		}`,
+		"delete_pets_petId": `{
+			"type": "object",
+			"properties": {"petId": {"type": "string", "description": "ID of the pet"}},
+			"required": ["petId"]
+		}`,
+	} {
+		tool, ok := tools[name]
+		if !ok {
+			t.Errorf("missing tool %s in %v", name, tools)
+			continue
+		}
+		var schema interface{}
+		json.Unmarshal([]byte(want), &schema)
+		if !reflect.DeepEqual(tool["inputSchema"], schema) {
+			got, _ := json.Marshal(tool["inputSchema"])
+			t.Errorf("tool %s has input schema %s", name, got)
+		}
+	}
+	if len(tools) != 4 {
+		t.Errorf("expected 4 tools, got %d", len(tools))
+	}
+
+	annotations := tools["listPets"]["annotations"].(map[string]interface{})
+	if annotations["readOnlyHint"] != true || tools["listPets"]["description"] != "Lists pets" {
+		t.Errorf("unexpected listPets tool %v", tools["listPets"])
+	}
+	annotations = tools["delete_pets_petId"]["annotations"].(map[string]interface{})
+	if annotations["readOnlyHint"] != false || annotations["destructiveHint"] != true || annotations["idempotentHint"] != true {
+		t.Errorf("unexpected delete annotations %v", annotations)
+	}
+}
+
+// recordedRequest is what the test API received
+type recordedRequest struct {
+	Method      string
+	Path        string
+	Query       string
+	RequestID   string
+	APIKey      string
+	Session     string
+	ContentType string
+	Body        string
+}
+
+func TestRegisterRequests(t *testing.T) {
+	var requests []recordedRequest
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		recorded := recordedRequest{
+			Method:      r.Method,
+			Path:        r.URL.EscapedPath(),
+			Query:       r.URL.RawQuery,
+			RequestID:   r.Header.Get("X-Request-ID"),
+			APIKey:      r.Header.Get("X-API-Key"),
+			ContentType: r.Header.Get("Content-Type"),
+			Body:        string(body),
+		}
+		if cookie, err := r.Cookie("session"); err == nil {
+			recorded.Session = cookie.Value
+		}
+		requests = append(requests, recorded)
+
+		if r.URL.Path == "/v1/pets/missing" {
+			http.Error(w, `{"error":"no such pet"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer api.Close()
+
+	doc, err := Parse([]byte(petstore))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	srv := server.NewServer("openapi")
+	err = Register(srv, doc,
+		WithBaseURL(api.URL+"/v1/"),
+		WithHeader("X-API-Key", "key"),
+		WithFilter(func(method, path string, op *Operation) bool { return method != http.MethodDelete }),
+	)
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	tc := mcptest.NewClient(t, srv)
+	if n := len(tc.Tools()); n != 3 {
+		t.Errorf("expected the filter to leave 3 tools, got %d", n)
+	}
+
+	tc.ExpectToolResult("listPets", map[string]interface{}{"limit": 2, "tags": []interface{}{"cat", "dog"}, "X-Request-ID": "r1"}, `{"ok":true}`)
+	tc.ExpectToolResult("getPet", map[string]interface{}{"petId": "a b/c", "session": "s1"}, `{"ok":true}`)
+	tc.ExpectToolResult("createPet", map[string]interface{}{"body": map[string]interface{}{"name": "Rex", "tag": "dog"}}, `{"ok":true}`)
+	tc.ExpectToolError("getPet", map[string]interface{}{"petId": "missing"}, "404 Not Found\n{\"error\":\"no such pet\"}")
+
+	want := []recordedRequest{
+		{Method: "GET", Path: "/v1/pets", Query: "limit=2&tags=cat&tags=dog", RequestID: "r1", APIKey: "key"},
+		{Method: "GET", Path: "/v1/pets/a%20b%2Fc", APIKey: "key", Session: "s1"},
+		{Method: "POST", Path: "/v1/pets", APIKey: "key", ContentType: "application/json", Body: `{"name":"Rex","tag":"dog"}`},
+		{Method: "GET", Path: "/v1/pets/missing", APIKey: "key"},
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("got requests\n%+v\nwant\n%+v", requests, want)
+	}
+
+	// Missing required arguments fail before any request is made
+	tc.ExpectToolError("listPets", map[string]interface{}{}, `missing required argument "X-Request-ID"`)
+	if len(requests) != len(want) {
+		t.Errorf("expected no request for invalid arguments, got %+v", requests[len(want):])
+	}
+}
+
+func TestRegisterRequiresBaseURL(t *testing.T) {
+	doc, err := Parse([]byte(`{"openapi": "3.1.0", "paths": {}}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if err := Register(server.NewServer("openapi"), doc); err == nil {
+		t.Error("expected an error without a base URL")
+	}
+	if _, err := Parse([]byte(`{"swagger": "2.0"}`)); err == nil {
+		t.Error("expected Swagger 2 documents to be rejected")
+	}
+}
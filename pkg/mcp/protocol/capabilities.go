@@ -3,6 +3,7 @@ package protocol
 // ServerCapabilities defines the capabilities of an MCP server
 type ServerCapabilities struct {
 	Experimental map[string]map[string]interface{} `json:"experimental,omitempty"`
+	Completions  *CompletionsCapability            `json:"completions,omitempty"`
 	Logging      *LoggingCapability                `json:"logging,omitempty"`
 	Prompts      *PromptsCapability                `json:"prompts,omitempty"`
 	Resources    *ResourcesCapability              `json:"resources,omitempty"`
@@ -27,3 +28,6 @@ type ToolsCapability struct {
 
 // LoggingCapability defines logging-related capabilities
 type LoggingCapability struct{}
+
+// CompletionsCapability defines argument completion capabilities
+type CompletionsCapability struct{}
@@ -0,0 +1,202 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// UnmarshalContent decodes a content block into the concrete type named by
+// its "type" field: TextContent, ImageContent, AudioContent, ResourceLink or
+// EmbeddedResource
+func UnmarshalContent(data []byte) (interface{}, error) {
+	var discriminator struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &discriminator); err != nil {
+		return nil, fmt.Errorf("invalid content: %w", err)
+	}
+
+	var content interface{}
+	switch discriminator.Type {
+	case "text":
+		content = &TextContent{}
+	case "image":
+		content = &ImageContent{}
+	case "audio":
+		content = &AudioContent{}
+	case "resource_link":
+		content = &ResourceLink{}
+	case "resource":
+		content = &EmbeddedResource{}
+	default:
+		return nil, fmt.Errorf("unknown content type: %q", discriminator.Type)
+	}
+
+	if err := json.Unmarshal(data, content); err != nil {
+		return nil, fmt.Errorf("invalid %s content: %w", discriminator.Type, err)
+	}
+	// Return values rather than pointers, matching the constructors
+	return reflect.ValueOf(content).Elem().Interface(), nil
+}
+
+// UnmarshalJSON decodes the embedded resource contents into
+// TextResourceContents or BlobResourceContents
+func (e *EmbeddedResource) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type        string          `json:"type"`
+		Resource    json.RawMessage `json:"resource"`
+		Annotations *Annotations    `json:"annotations,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	contents, err := unmarshalResourceContents(raw.Resource)
+	if err != nil {
+		return err
+	}
+	e.Type = raw.Type
+	e.Resource = contents
+	e.Annotations = raw.Annotations
+	return nil
+}
+
+// unmarshalResourceContents decodes resource contents into
+// BlobResourceContents when they carry a blob and TextResourceContents
+// otherwise
+func unmarshalResourceContents(data []byte) (interface{}, error) {
+	var blob struct {
+		Blob *string `json:"blob"`
+	}
+	if err := json.Unmarshal(data, &blob); err != nil {
+		return nil, fmt.Errorf("invalid resource contents: %w", err)
+	}
+	if blob.Blob != nil {
+		var contents BlobResourceContents
+		if err := json.Unmarshal(data, &contents); err != nil {
+			return nil, fmt.Errorf("invalid resource contents: %w", err)
+		}
+		return contents, nil
+	}
+
+	var contents TextResourceContents
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return nil, fmt.Errorf("invalid resource contents: %w", err)
+	}
+	return contents, nil
+}
+
+// UnmarshalJSON decodes the result content into concrete content types
+func (r *CallToolResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Meta              map[string]interface{} `json:"_meta"`
+		Content           []json.RawMessage      `json:"content"`
+		StructuredContent interface{}            `json:"structuredContent"`
+		IsError           bool                   `json:"isError"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	content := make([]interface{}, len(raw.Content))
+	for i, item := range raw.Content {
+		c, err := UnmarshalContent(item)
+		if err != nil {
+			return err
+		}
+		content[i] = c
+	}
+	r.Meta = raw.Meta
+	r.Content = content
+	r.StructuredContent = raw.StructuredContent
+	r.IsError = raw.IsError
+	return nil
+}
+
+// UnmarshalJSON decodes the resource contents into TextResourceContents or
+// BlobResourceContents
+func (r *ReadResourceResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Meta     map[string]interface{} `json:"_meta"`
+		Contents []json.RawMessage      `json:"contents"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	contents := make([]interface{}, len(raw.Contents))
+	for i, item := range raw.Contents {
+		c, err := unmarshalResourceContents(item)
+		if err != nil {
+			return err
+		}
+		contents[i] = c
+	}
+	r.Meta = raw.Meta
+	r.Contents = contents
+	return nil
+}
+
+// UnmarshalJSON decodes the message content into its concrete type
+func (m *PromptMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role    Role            `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	content, err := UnmarshalContent(raw.Content)
+	if err != nil {
+		return err
+	}
+	m.Role = raw.Role
+	m.Content = content
+	return nil
+}
+
+// UnmarshalJSON decodes the message content into its concrete type
+func (m *SamplingMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role    Role            `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	content, err := UnmarshalContent(raw.Content)
+	if err != nil {
+		return err
+	}
+	m.Role = raw.Role
+	m.Content = content
+	return nil
+}
+
+// UnmarshalJSON decodes the sampled content into its concrete type
+func (r *CreateMessageResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Meta       map[string]interface{} `json:"_meta"`
+		Role       Role                   `json:"role"`
+		Content    json.RawMessage        `json:"content"`
+		Model      string                 `json:"model"`
+		StopReason string                 `json:"stopReason"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	content, err := UnmarshalContent(raw.Content)
+	if err != nil {
+		return err
+	}
+	r.Meta = raw.Meta
+	r.Role = raw.Role
+	r.Content = content
+	r.Model = raw.Model
+	r.StopReason = raw.StopReason
+	return nil
+}
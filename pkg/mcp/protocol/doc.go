@@ -10,18 +10,24 @@
 //
 //	type JSONRPCRequest struct {
 //	    JSONRPC string      `json:"jsonrpc"`
-//	    ID      interface{} `json:"id"`
+//	    ID      RequestID   `json:"id"`
 //	    Method  string      `json:"method"`
 //	    Params  interface{} `json:"params,omitempty"`
 //	}
 //
 //	type JSONRPCResponse struct {
 //	    JSONRPC string      `json:"jsonrpc"`
-//	    ID      interface{} `json:"id"`
+//	    ID      RequestID   `json:"id"`
 //	    Result  interface{} `json:"result,omitempty"`
 //	    Error   *Error      `json:"error,omitempty"`
 //	}
 //
+// Request IDs are either strings or integers and keep their JSON type when
+// decoded, so a response with ID "1" does not answer the request with ID 1:
+//
+//	id := protocol.NewIntID(1)
+//	id == protocol.NewStringID("1") // false
+//
 // MCP Types:
 //
 //	// Tool definition
@@ -57,6 +63,15 @@
 //	    Source string `json:"source"`
 //	}
 //
+// Content in prompt and sampling messages decodes into the concrete type
+// named by its "type" field, and UnmarshalContent does the same for a
+// single content block:
+//
+//	content, err := protocol.UnmarshalContent(data)
+//	if text, ok := content.(protocol.TextContent); ok {
+//	    fmt.Println(text.Text)
+//	}
+//
 // Message Roles:
 //
 //	const (
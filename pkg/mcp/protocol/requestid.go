@@ -0,0 +1,93 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// RequestID identifies a JSON-RPC request. It holds either a string or an
+// integer and marshals back to the JSON type it was decoded from, so IDs
+// can be compared and used as map keys. The zero value is the null ID used
+// in responses to messages whose ID could not be read.
+type RequestID struct {
+	str   string
+	num   int64
+	isStr bool
+	valid bool
+}
+
+// NewStringID creates a request ID from a string
+func NewStringID(id string) RequestID {
+	return RequestID{str: id, isStr: true, valid: true}
+}
+
+// NewIntID creates a request ID from an integer
+func NewIntID(id int64) RequestID {
+	return RequestID{num: id, valid: true}
+}
+
+// IsNull reports whether the ID is the null ID
+func (id RequestID) IsNull() bool {
+	return !id.valid
+}
+
+// IsString reports whether the ID is a string
+func (id RequestID) IsString() bool {
+	return id.isStr
+}
+
+// Int returns the integer value of the ID and whether it is an integer
+func (id RequestID) Int() (int64, bool) {
+	return id.num, id.valid && !id.isStr
+}
+
+// String returns the ID as it would appear in a log message
+func (id RequestID) String() string {
+	switch {
+	case !id.valid:
+		return "null"
+	case id.isStr:
+		return id.str
+	default:
+		return strconv.FormatInt(id.num, 10)
+	}
+}
+
+// MarshalJSON encodes the ID as a JSON string, number or null
+func (id RequestID) MarshalJSON() ([]byte, error) {
+	switch {
+	case !id.valid:
+		return []byte("null"), nil
+	case id.isStr:
+		return json.Marshal(id.str)
+	default:
+		return []byte(strconv.FormatInt(id.num, 10)), nil
+	}
+}
+
+// UnmarshalJSON decodes a JSON string, integer or null into the ID
+func (id *RequestID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*id = RequestID{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("invalid request id: %w", err)
+		}
+		*id = NewStringID(s)
+		return nil
+	}
+
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("request id must be a string or integer: %s", data)
+	}
+	*id = NewIntID(n)
+	return nil
+}
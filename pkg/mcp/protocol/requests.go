@@ -1,12 +1,23 @@
 package protocol
 
-import "net/url"
-
 // Tool represents a tool that can be called by the client
 type Tool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description,omitempty"`
-	InputSchema map[string]interface{} `json:"inputSchema"`
+	Name         string                 `json:"name"`
+	Title        string                 `json:"title,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  map[string]interface{} `json:"inputSchema"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations       `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations describe the behavior of a tool to clients. They are
+// hints and clients must not rely on them for security decisions.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
 }
 
 // CallToolRequestParams represents parameters for calling a tool
@@ -19,8 +30,9 @@ type CallToolRequestParams struct {
 // CallToolResult represents the result of a tool call
 type CallToolResult struct {
 	Result
-	Content []interface{} `json:"content"`
-	IsError bool          `json:"isError"`
+	Content           []interface{} `json:"content"`
+	StructuredContent interface{}   `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError"`
 }
 
 // ListToolsResult represents the result of listing tools
@@ -39,10 +51,25 @@ type Resource struct {
 	Annotations *Annotations `json:"annotations,omitempty"`
 }
 
+// ResourceTemplate represents a template for resources available on the server
+type ResourceTemplate struct {
+	URITemplate string       `json:"uriTemplate"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	MimeType    string       `json:"mimeType,omitempty"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+}
+
+// ListResourceTemplatesResult represents the result of listing resource templates
+type ListResourceTemplatesResult struct {
+	PaginatedResult
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
 // ReadResourceRequestParams represents parameters for reading a resource
 type ReadResourceRequestParams struct {
 	RequestParams
-	URI *url.URL `json:"uri"`
+	URI string `json:"uri"`
 }
 
 // ReadResourceResult represents the result of reading a resource
@@ -57,6 +84,24 @@ type ListResourcesResult struct {
 	Resources []Resource `json:"resources"`
 }
 
+// SubscribeRequestParams represents parameters for subscribing to resource updates
+type SubscribeRequestParams struct {
+	RequestParams
+	URI string `json:"uri"`
+}
+
+// UnsubscribeRequestParams represents parameters for unsubscribing from resource updates
+type UnsubscribeRequestParams struct {
+	RequestParams
+	URI string `json:"uri"`
+}
+
+// ResourceUpdatedNotificationParams represents parameters for resource updated notifications
+type ResourceUpdatedNotificationParams struct {
+	NotificationParams
+	URI string `json:"uri"`
+}
+
 // Prompt represents a prompt template
 type Prompt struct {
 	Name        string           `json:"name"`
@@ -97,3 +142,175 @@ type CancelledNotificationParams struct {
 	RequestID RequestID `json:"requestId"`
 	Reason    string    `json:"reason,omitempty"`
 }
+
+// ProgressNotificationParams represents parameters for progress notifications
+type ProgressNotificationParams struct {
+	NotificationParams
+	ProgressToken ProgressToken `json:"progressToken"`
+	Progress      float64       `json:"progress"`
+	Total         *float64      `json:"total,omitempty"`
+	Message       string        `json:"message,omitempty"`
+}
+
+// Root represents a filesystem root the client exposes to the server
+type Root struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// ListRootsResult represents the result of a roots/list request
+type ListRootsResult struct {
+	Result
+	Roots []Root `json:"roots"`
+}
+
+// LoggingLevel is the severity of a log message, following syslog
+type LoggingLevel string
+
+const (
+	LoggingLevelDebug     LoggingLevel = "debug"
+	LoggingLevelInfo      LoggingLevel = "info"
+	LoggingLevelNotice    LoggingLevel = "notice"
+	LoggingLevelWarning   LoggingLevel = "warning"
+	LoggingLevelError     LoggingLevel = "error"
+	LoggingLevelCritical  LoggingLevel = "critical"
+	LoggingLevelAlert     LoggingLevel = "alert"
+	LoggingLevelEmergency LoggingLevel = "emergency"
+)
+
+// loggingLevels orders the logging levels from least to most severe
+var loggingLevels = []LoggingLevel{
+	LoggingLevelDebug,
+	LoggingLevelInfo,
+	LoggingLevelNotice,
+	LoggingLevelWarning,
+	LoggingLevelError,
+	LoggingLevelCritical,
+	LoggingLevelAlert,
+	LoggingLevelEmergency,
+}
+
+// Severity returns the position of the level from least to most severe,
+// or -1 for unknown levels
+func (l LoggingLevel) Severity() int {
+	for i, level := range loggingLevels {
+		if level == l {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetLevelRequestParams represents parameters for logging/setLevel requests
+type SetLevelRequestParams struct {
+	RequestParams
+	Level LoggingLevel `json:"level"`
+}
+
+// LoggingMessageNotificationParams represents parameters for log message notifications
+type LoggingMessageNotificationParams struct {
+	NotificationParams
+	Level  LoggingLevel `json:"level"`
+	Logger string       `json:"logger,omitempty"`
+	Data   interface{}  `json:"data"`
+}
+
+// CompleteReference identifies the prompt or resource template whose
+// argument is being completed
+type CompleteReference struct {
+	Type string `json:"type"` // "ref/prompt" or "ref/resource"
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// CompleteArgument is the argument being completed and its current value
+type CompleteArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompleteRequestParams represents parameters for completion/complete requests
+type CompleteRequestParams struct {
+	RequestParams
+	Ref      CompleteReference `json:"ref"`
+	Argument CompleteArgument  `json:"argument"`
+}
+
+// Completion represents completion values for an argument
+type Completion struct {
+	Values  []string `json:"values"`
+	Total   *int     `json:"total,omitempty"`
+	HasMore bool     `json:"hasMore,omitempty"`
+}
+
+// CompleteResult represents the result of a completion/complete request
+type CompleteResult struct {
+	Result
+	Completion Completion `json:"completion"`
+}
+
+// Elicitation actions taken by the user
+const (
+	ElicitActionAccept  = "accept"
+	ElicitActionDecline = "decline"
+	ElicitActionCancel  = "cancel"
+)
+
+// ElicitRequestParams represents parameters for elicitation/create requests,
+// asking the user for input matching a flat JSON schema of primitive values
+type ElicitRequestParams struct {
+	RequestParams
+	Message         string                 `json:"message"`
+	RequestedSchema map[string]interface{} `json:"requestedSchema"`
+}
+
+// ElicitResult represents the user's response to an elicitation request
+type ElicitResult struct {
+	Result
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// Reasons a sampling request stopped generating
+const (
+	StopReasonEndTurn      = "endTurn"
+	StopReasonStopSequence = "stopSequence"
+	StopReasonMaxTokens    = "maxTokens"
+)
+
+// ModelHint suggests a model for sampling by name or name fragment
+type ModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+// ModelPreferences express the server's priorities for picking a model to
+// sample with. Priorities range from 0 to 1.
+type ModelPreferences struct {
+	Hints                []ModelHint `json:"hints,omitempty"`
+	CostPriority         *float64    `json:"costPriority,omitempty"`
+	SpeedPriority        *float64    `json:"speedPriority,omitempty"`
+	IntelligencePriority *float64    `json:"intelligencePriority,omitempty"`
+}
+
+// CreateMessageRequestParams represents parameters for
+// sampling/createMessage requests, asking the client to sample an LLM
+type CreateMessageRequestParams struct {
+	RequestParams
+	Messages         []SamplingMessage      `json:"messages"`
+	ModelPreferences *ModelPreferences      `json:"modelPreferences,omitempty"`
+	SystemPrompt     string                 `json:"systemPrompt,omitempty"`
+	IncludeContext   string                 `json:"includeContext,omitempty"`
+	Temperature      *float64               `json:"temperature,omitempty"`
+	MaxTokens        int                    `json:"maxTokens"`
+	StopSequences    []string               `json:"stopSequences,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// CreateMessageResult represents the message sampled by the client
+type CreateMessageResult struct {
+	Result
+	Role       Role        `json:"role"`
+	Content    interface{} `json:"content"` // TextContent, ImageContent, or AudioContent
+	Model      string      `json:"model"`
+	StopReason string      `json:"stopReason,omitempty"`
+}
@@ -1,18 +1,49 @@
 package protocol
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
-	"net/url"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+)
+
+// Protocol revisions supported by this SDK
+const (
+	ProtocolVersion20241105 = "2024-11-05"
+	ProtocolVersion20250326 = "2025-03-26"
+	ProtocolVersion20250618 = "2025-06-18"
 )
 
 // Latest protocol version
-const LatestProtocolVersion = "2024-11-05"
+const LatestProtocolVersion = ProtocolVersion20250618
+
+// SupportedProtocolVersions lists the supported protocol revisions from
+// newest to oldest
+var SupportedProtocolVersions = []string{
+	ProtocolVersion20250618,
+	ProtocolVersion20250326,
+	ProtocolVersion20241105,
+}
+
+// IsSupportedProtocolVersion reports whether the SDK supports a protocol revision
+func IsSupportedProtocolVersion(version string) bool {
+	for _, v := range SupportedProtocolVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
 
 // Common types
 type ProgressToken interface{} // string or int
 type Cursor string
 type Role string
-type RequestID interface{} // string or int
 
 const (
 	RoleUser      Role = "user"
@@ -22,6 +53,33 @@ const (
 // Meta represents metadata for requests and notifications
 type Meta struct {
 	ProgressToken ProgressToken `json:"progressToken,omitempty"`
+
+	// Fields holds every metadata entry, including the progress token
+	Fields map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON encodes the metadata entries with the progress token
+func (m Meta) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{}, len(m.Fields)+1)
+	for key, value := range m.Fields {
+		fields[key] = value
+	}
+	if m.ProgressToken != nil {
+		fields["progressToken"] = m.ProgressToken
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes every metadata entry into Fields, and the progress
+// token into ProgressToken
+func (m *Meta) UnmarshalJSON(data []byte) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	m.Fields = fields
+	m.ProgressToken = fields["progressToken"]
+	return nil
 }
 
 // RequestParams represents base parameters for requests
@@ -29,6 +87,11 @@ type RequestParams struct {
 	Meta *Meta `json:"_meta,omitempty"`
 }
 
+// RequestMeta returns the metadata sent with the request, if any
+func (p RequestParams) RequestMeta() *Meta {
+	return p.Meta
+}
+
 // NotificationParams represents base parameters for notifications
 type NotificationParams struct {
 	Meta *Meta `json:"_meta,omitempty"`
@@ -46,6 +109,13 @@ type PaginatedRequest struct {
 	Cursor *Cursor `json:"cursor,omitempty"`
 }
 
+// PaginatedRequestParams represents parameters for list requests that
+// support pagination
+type PaginatedRequestParams struct {
+	RequestParams
+	Cursor *Cursor `json:"cursor,omitempty"`
+}
+
 // Notification represents a base JSON-RPC notification
 type Notification struct {
 	Method string          `json:"method"`
@@ -82,9 +152,34 @@ type JSONRPCNotification struct {
 type JSONRPCResponse struct {
 	JSONRPC string      `json:"jsonrpc"`
 	ID      RequestID   `json:"id"`
-	Result  interface{} `json:"result"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *ErrorData  `json:"error,omitempty"`
 }
 
+// JSON-RPC error codes
+const (
+	// ParseError means the message was not valid JSON
+	ParseError = -32700
+	// InvalidRequest means the message was not a valid request
+	InvalidRequest = -32600
+	// MethodNotFound means the method does not exist
+	MethodNotFound = -32601
+	// InvalidParams means the method parameters were invalid
+	InvalidParams = -32602
+	// InternalError means the server failed to process a valid request
+	InternalError = -32603
+
+	// ServerNotInitialized means a request other than initialize arrived
+	// before the session was initialized. It uses the JSON-RPC range
+	// reserved for implementation-defined server errors.
+	ServerNotInitialized = -32000
+	// RequestTimeout means the server gave up on a request that ran past
+	// its deadline
+	RequestTimeout = -32001
+	// ResourceNotFound means no resource matched the requested URI
+	ResourceNotFound = -32002
+)
+
 // ErrorData represents error information for JSON-RPC error responses
 type ErrorData struct {
 	Code    int         `json:"code"`
@@ -92,6 +187,19 @@ type ErrorData struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// NewError creates error information with the given code and message
+func NewError(code int, message string) *ErrorData {
+	return &ErrorData{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Error returns the error message, making ErrorData usable as an error
+func (e *ErrorData) Error() string {
+	return e.Message
+}
+
 // JSONRPCError represents a JSON-RPC error response
 type JSONRPCError struct {
 	JSONRPC string    `json:"jsonrpc"`
@@ -113,10 +221,13 @@ type RootsCapability struct {
 
 type SamplingCapability struct{}
 
+type ElicitationCapability struct{}
+
 type ClientCapabilities struct {
 	Experimental map[string]map[string]interface{} `json:"experimental,omitempty"`
 	Sampling     *SamplingCapability               `json:"sampling,omitempty"`
 	Roots        *RootsCapability                  `json:"roots,omitempty"`
+	Elicitation  *ElicitationCapability            `json:"elicitation,omitempty"`
 }
 
 // Initialize types
@@ -161,9 +272,16 @@ type ImageContent struct {
 	Annotations *Annotations `json:"annotations,omitempty"`
 }
 
+type AudioContent struct {
+	Type        string       `json:"type"`
+	Data        string       `json:"data"`
+	MimeType    string       `json:"mimeType"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+}
+
 type ResourceContents struct {
-	URI      *url.URL `json:"uri"`
-	MimeType *string  `json:"mimeType,omitempty"`
+	URI      string  `json:"uri"`
+	MimeType *string `json:"mimeType,omitempty"`
 }
 
 type TextResourceContents struct {
@@ -178,20 +296,26 @@ type BlobResourceContents struct {
 
 type EmbeddedResource struct {
 	Type        string       `json:"type"`
-	Resource    interface{}  // TextResourceContents or BlobResourceContents
+	Resource    interface{}  `json:"resource"` // TextResourceContents or BlobResourceContents
 	Annotations *Annotations `json:"annotations,omitempty"`
 }
 
+// ResourceLink references a resource by URI without embedding its contents
+type ResourceLink struct {
+	Type string `json:"type"`
+	Resource
+}
+
 // Message types
 
 type SamplingMessage struct {
 	Role    Role        `json:"role"`
-	Content interface{} // TextContent or ImageContent
+	Content interface{} `json:"content"` // TextContent, ImageContent, or AudioContent
 }
 
 type PromptMessage struct {
 	Role    Role        `json:"role"`
-	Content interface{} // TextContent, ImageContent, or EmbeddedResource
+	Content interface{} `json:"content"` // TextContent, ImageContent, AudioContent, ResourceLink, or EmbeddedResource
 }
 
 // Helper functions
@@ -211,6 +335,79 @@ func NewImageContent(data, mimeType string) ImageContent {
 	}
 }
 
+func NewAudioContent(data, mimeType string) AudioContent {
+	return AudioContent{
+		Type:     "audio",
+		Data:     data,
+		MimeType: mimeType,
+	}
+}
+
+// NewResourceLink creates a resource_link content block for a resource
+func NewResourceLink(resource Resource) ResourceLink {
+	return ResourceLink{
+		Type:     "resource_link",
+		Resource: resource,
+	}
+}
+
+// NewAudioContentFromBytes returns raw audio data, such as a WAV or MP3
+// file, as base64 audio content
+func NewAudioContentFromBytes(data []byte, mimeType string) AudioContent {
+	return NewAudioContent(base64.StdEncoding.EncodeToString(data), mimeType)
+}
+
+// NewImageContentFromImage encodes an image in the given format (png, jpeg
+// or gif) and returns it as base64 image content with the matching mimeType
+func NewImageContentFromImage(img image.Image, format string) (ImageContent, error) {
+	var buf bytes.Buffer
+	var mimeType string
+
+	switch strings.TrimPrefix(strings.ToLower(format), "image/") {
+	case "", "png":
+		mimeType = "image/png"
+		if err := png.Encode(&buf, img); err != nil {
+			return ImageContent{}, fmt.Errorf("failed to encode png: %w", err)
+		}
+	case "jpeg", "jpg":
+		mimeType = "image/jpeg"
+		if err := jpeg.Encode(&buf, img, nil); err != nil {
+			return ImageContent{}, fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+	case "gif":
+		mimeType = "image/gif"
+		if err := gif.Encode(&buf, img, nil); err != nil {
+			return ImageContent{}, fmt.Errorf("failed to encode gif: %w", err)
+		}
+	default:
+		return ImageContent{}, fmt.Errorf("unsupported image format: %s", format)
+	}
+
+	return NewImageContent(base64.StdEncoding.EncodeToString(buf.Bytes()), mimeType), nil
+}
+
+func NewTextResourceContents(uri, mimeType, text string) TextResourceContents {
+	return TextResourceContents{
+		ResourceContents: newResourceContents(uri, mimeType),
+		Text:             text,
+	}
+}
+
+func NewBlobResourceContents(uri, mimeType string, blob []byte) BlobResourceContents {
+	return BlobResourceContents{
+		ResourceContents: newResourceContents(uri, mimeType),
+		Blob:             base64.StdEncoding.EncodeToString(blob),
+	}
+}
+
+func newResourceContents(uri, mimeType string) ResourceContents {
+	contents := ResourceContents{URI: uri}
+	if mimeType != "" {
+		contents.MimeType = &mimeType
+	}
+	return contents
+}
+
 func NewEmbeddedResource(resource interface{}, annotations *Annotations) EmbeddedResource {
 	return EmbeddedResource{
 		Type:        "resource",
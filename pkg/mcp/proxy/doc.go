@@ -0,0 +1,29 @@
+// Package proxy aggregates backend MCP servers behind a single server.
+//
+// A Proxy connects to each backend as a client and registers its tools,
+// prompts and resources on the server, optionally under a prefix. Tool
+// calls, prompt requests and resource reads are forwarded to the backend,
+// with tool progress relayed to the caller.
+//
+//	srv := server.NewServer("gateway")
+//	p := proxy.New(srv)
+//	defer p.Close()
+//
+//	// Tools become fs_read, fs_write, ...; file://{path} becomes file://fs/{path}
+//	conn, err := client.NewCommandConn(exec.Command("fs-server"))
+//	if err := p.Add(ctx, "fs", conn); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// A remote backend keeping its own names
+//	err = p.Add(ctx, "", client.NewStreamableHTTPConn("https://tools.example.com/mcp", nil, nil))
+//
+//	t := transport.NewStreamableHTTPTransport(srv)
+//
+// Notifications from backends are fanned in: tool and prompt list changes
+// resync the registrations, resource list changes and updates are passed
+// on to the server's clients, and log messages are forwarded through
+// srv.Log. A backend's registrations are removed when its connection
+// closes. Resource templates are not listed, but resources matching them
+// can be read.
+package proxy
@@ -0,0 +1,402 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// Proxy re-exposes the tools, resources and prompts of backend MCP servers
+// from a server, so hosts see a single aggregated endpoint
+type Proxy struct {
+	server   *server.Server
+	backends map[string]*backend
+	mu       sync.Mutex
+}
+
+// backend is a connected backend server and what the proxy registered
+// for it
+type backend struct {
+	prefix  string
+	server  *server.Server
+	client  *client.Client
+	tools   map[string]struct{}
+	prompts map[string]struct{}
+	mu      sync.Mutex
+
+	// ready is closed once the initial registrations are done; earlier
+	// list changes are covered by them
+	ready chan struct{}
+}
+
+// New creates a proxy registering backend capabilities on srv
+func New(srv *server.Server) *Proxy {
+	return &Proxy{
+		server:   srv,
+		backends: make(map[string]*backend),
+	}
+}
+
+// Add connects to a backend server over conn and registers its tools,
+// resources and prompts under prefix: tools and prompts are named
+// prefix_name and resource URIs get prefix as their first path segment,
+// as with server.Mount. An empty prefix keeps the backend's names.
+//
+// The registrations follow the backend's list changes, and are removed
+// when the connection to the backend closes. Resource updates and log
+// messages from the backend are forwarded to the server's clients.
+func (p *Proxy) Add(ctx context.Context, prefix string, conn client.Conn, options ...client.Option) error {
+	p.mu.Lock()
+	if _, exists := p.backends[prefix]; exists {
+		p.mu.Unlock()
+		return fmt.Errorf("backend %q already exists", prefix)
+	}
+	b := &backend{
+		prefix:  prefix,
+		server:  p.server,
+		tools:   make(map[string]struct{}),
+		prompts: make(map[string]struct{}),
+		ready:   make(chan struct{}),
+	}
+	p.backends[prefix] = b
+	p.mu.Unlock()
+
+	options = append(options, client.WithNotificationHandler(b.handleNotification))
+	c, err := client.Connect(ctx, conn, options...)
+	if err != nil {
+		p.remove(prefix)
+		return fmt.Errorf("failed to connect to backend %q: %w", prefix, err)
+	}
+	b.client = c
+
+	caps := c.InitializeResult().Capabilities
+	var syncErr error
+	if caps.Tools != nil {
+		syncErr = b.syncTools(ctx)
+	}
+	if caps.Prompts != nil && syncErr == nil {
+		syncErr = b.syncPrompts(ctx)
+	}
+	if syncErr != nil {
+		c.Close()
+		b.unregister(false)
+		p.remove(prefix)
+		return syncErr
+	}
+	if caps.Resources != nil {
+		p.server.AddResourceProvider(b)
+	}
+	close(b.ready)
+
+	go func() {
+		<-c.Done()
+		b.unregister(caps.Resources != nil)
+		p.remove(prefix)
+	}()
+	return nil
+}
+
+// remove forgets a backend
+func (p *Proxy) remove(prefix string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.backends, prefix)
+}
+
+// Close disconnects from every backend
+func (p *Proxy) Close() error {
+	p.mu.Lock()
+	backends := make([]*backend, 0, len(p.backends))
+	for _, b := range p.backends {
+		backends = append(backends, b)
+	}
+	p.mu.Unlock()
+
+	var errs []error
+	for _, b := range backends {
+		if b.client != nil {
+			if err := b.client.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("backend %q: %w", b.prefix, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// name returns the name a backend tool or prompt is registered under
+func (b *backend) name(name string) string {
+	if b.prefix == "" {
+		return name
+	}
+	return b.prefix + "_" + name
+}
+
+// uri returns the URI a backend resource is served under
+func (b *backend) uri(uri string) string {
+	if b.prefix == "" {
+		return uri
+	}
+	if scheme, rest, ok := strings.Cut(uri, "://"); ok {
+		return scheme + "://" + b.prefix + "/" + rest
+	}
+	return b.prefix + "/" + uri
+}
+
+// backendURI returns the backend URI of a URI served by the proxy, and
+// whether the URI belongs to the backend
+func (b *backend) backendURI(uri string) (string, bool) {
+	if b.prefix == "" {
+		return uri, true
+	}
+	if scheme, rest, ok := strings.Cut(uri, "://"); ok {
+		rest, ok = strings.CutPrefix(rest, b.prefix+"/")
+		return scheme + "://" + rest, ok
+	}
+	return strings.CutPrefix(uri, b.prefix+"/")
+}
+
+// syncTools registers the backend's current tools and removes those it no
+// longer has. Names taken by other tools are skipped.
+func (b *backend) syncTools(ctx context.Context) error {
+	tools, err := b.client.Tools(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tools of backend %q: %w", b.prefix, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := make(map[string]struct{}, len(tools))
+	for _, tool := range tools {
+		name := b.name(tool.Name)
+		opts := []server.ToolOption{
+			server.WithInputSchema(tool.InputSchema),
+			server.WithToolTitle(tool.Title),
+		}
+		if tool.Annotations != nil {
+			opts = append(opts, server.WithToolAnnotations(*tool.Annotations))
+		}
+		if tool.OutputSchema != nil {
+			opts = append(opts, server.WithOutputSchema(tool.OutputSchema))
+		}
+
+		handler := b.callTool(tool.Name)
+		if _, owned := b.tools[name]; owned {
+			err = b.server.ReplaceTool(name, handler, tool.Description, opts...)
+		} else {
+			err = b.server.AddTool(name, handler, tool.Description, opts...)
+		}
+		if err != nil {
+			b.server.Logger().Warn("skipping backend tool", "backend", b.prefix, "tool", name, "error", err)
+			continue
+		}
+		current[name] = struct{}{}
+	}
+
+	for name := range b.tools {
+		if _, ok := current[name]; !ok {
+			b.server.RemoveTool(name)
+		}
+	}
+	b.tools = current
+	return nil
+}
+
+// callTool returns a handler forwarding calls to a backend tool, relaying
+// the progress the backend reports
+func (b *backend) callTool(name string) server.ToolHandlerFunc {
+	return func(ctx context.Context, arguments map[string]interface{}) (protocol.CallToolResult, error) {
+		var onProgress client.ProgressHandler
+		if server.ProgressTokenFromContext(ctx) != nil {
+			onProgress = func(params protocol.ProgressNotificationParams) {
+				var total float64
+				if params.Total != nil {
+					total = *params.Total
+				}
+				server.ReportProgress(ctx, params.Progress, total, params.Message)
+			}
+		}
+
+		result, err := b.client.CallToolWithProgress(ctx, name, arguments, onProgress)
+		if err != nil {
+			return protocol.CallToolResult{}, err
+		}
+		return *result, nil
+	}
+}
+
+// syncPrompts registers the backend's current prompts and removes those it
+// no longer has. Names taken by other prompts are skipped.
+func (b *backend) syncPrompts(ctx context.Context) error {
+	prompts, err := b.client.Prompts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list prompts of backend %q: %w", b.prefix, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := make(map[string]struct{}, len(prompts))
+	for _, prompt := range prompts {
+		name := b.name(prompt.Name)
+		var opts []server.PromptOption
+		for _, arg := range prompt.Arguments {
+			opts = append(opts, server.WithPromptArgument(arg.Name, arg.Description, arg.Required != nil && *arg.Required))
+		}
+
+		handler := b.getPrompt(prompt.Name)
+		if _, owned := b.prompts[name]; owned {
+			err = b.server.ReplacePrompt(name, handler, prompt.Description, opts...)
+		} else {
+			err = b.server.AddPrompt(name, handler, prompt.Description, opts...)
+		}
+		if err != nil {
+			b.server.Logger().Warn("skipping backend prompt", "backend", b.prefix, "prompt", name, "error", err)
+			continue
+		}
+		current[name] = struct{}{}
+	}
+
+	for name := range b.prompts {
+		if _, ok := current[name]; !ok {
+			b.server.RemovePrompt(name)
+		}
+	}
+	b.prompts = current
+	return nil
+}
+
+// getPrompt returns a handler forwarding requests to a backend prompt
+func (b *backend) getPrompt(name string) server.PromptHandlerFunc {
+	return func(ctx context.Context, arguments map[string]string) (protocol.GetPromptResult, error) {
+		result, err := b.client.GetPrompt(ctx, name, arguments)
+		if err != nil {
+			return protocol.GetPromptResult{}, err
+		}
+		return *result, nil
+	}
+}
+
+// closed reports whether the connection to the backend closed
+func (b *backend) closed() bool {
+	select {
+	case <-b.client.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// List returns the backend's resources under the proxy's URIs, or none
+// once the backend disconnected
+func (b *backend) List(ctx context.Context) ([]protocol.Resource, error) {
+	if b.closed() {
+		return nil, nil
+	}
+	resources, err := b.client.Resources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources of backend %q: %w", b.prefix, err)
+	}
+	for i := range resources {
+		resources[i].URI = b.uri(resources[i].URI)
+	}
+	return resources, nil
+}
+
+// Read reads a resource from the backend, including resources of its
+// templates
+func (b *backend) Read(ctx context.Context, uri string) (interface{}, error) {
+	backendURI, ok := b.backendURI(uri)
+	if !ok || b.closed() {
+		return nil, server.ErrResourceNotFound
+	}
+
+	result, err := b.client.ReadResource(ctx, backendURI)
+	var errData *protocol.ErrorData
+	if errors.As(err, &errData) && errData.Code == protocol.ResourceNotFound {
+		return nil, server.ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make([]interface{}, len(result.Contents))
+	for i, c := range result.Contents {
+		switch c := c.(type) {
+		case protocol.TextResourceContents:
+			c.URI = b.uri(c.URI)
+			contents[i] = c
+		case protocol.BlobResourceContents:
+			c.URI = b.uri(c.URI)
+			contents[i] = c
+		}
+	}
+	return contents, nil
+}
+
+// handleNotification forwards the backend's notifications to the server.
+// It runs on the client's reading goroutine, so list changes are synced in
+// the background.
+func (b *backend) handleNotification(method string, params json.RawMessage) {
+	select {
+	case <-b.ready:
+	default:
+		return
+	}
+
+	switch method {
+	case "notifications/tools/list_changed":
+		go b.resync("tools", b.syncTools)
+	case "notifications/prompts/list_changed":
+		go b.resync("prompts", b.syncPrompts)
+	case "notifications/resources/list_changed":
+		b.server.NotifyResourceListChanged()
+	case "notifications/resources/updated":
+		var updated protocol.ResourceUpdatedNotificationParams
+		if err := json.Unmarshal(params, &updated); err == nil {
+			b.server.NotifyResourceUpdated(b.uri(updated.URI))
+		}
+	case "notifications/message":
+		var message protocol.LoggingMessageNotificationParams
+		if err := json.Unmarshal(params, &message); err == nil {
+			if message.Logger == "" {
+				message.Logger = b.prefix
+			}
+			b.server.Log(message.Level, message.Logger, message.Data)
+		}
+	}
+}
+
+// resync runs a sync after a list change, logging failures
+func (b *backend) resync(list string, sync func(ctx context.Context) error) {
+	if err := sync(context.Background()); err != nil {
+		b.server.Logger().Warn("failed to sync backend "+list, "backend", b.prefix, "error", err)
+	}
+}
+
+// unregister removes the backend's tools and prompts from the server and
+// tells clients its resources are gone
+func (b *backend) unregister(resources bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if resources {
+		b.server.NotifyResourceListChanged()
+	}
+	for name := range b.tools {
+		b.server.RemoveTool(name)
+	}
+	for name := range b.prompts {
+		b.server.RemovePrompt(name)
+	}
+	b.tools = nil
+	b.prompts = nil
+}
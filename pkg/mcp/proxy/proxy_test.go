@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/mcptest"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/transport"
+)
+
+// serveBackend serves a backend server over TCP and returns a connection
+// to it, along with a function stopping it
+func serveBackend(t *testing.T, srv *server.Server) (client.Conn, func()) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := transport.NewTCPTransport(srv)
+	go tr.(*transport.TCPTransport).Serve(listener)
+	stop := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		tr.Stop(ctx)
+	}
+	t.Cleanup(stop)
+
+	conn, err := client.DialTCP(context.Background(), listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, stop
+}
+
+// eventually polls cond until it holds or a second passes
+func eventually(t *testing.T, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+func TestProxy(t *testing.T) {
+	cancelled := make(chan struct{})
+	backend := server.NewServer("backend", server.WithDefaultCapabilities())
+	backend.AddTool("echo", func(text string) string { return text }, "Echoes the text")
+	backend.AddTool("fail", func() error { return errors.New("boom") }, "Always fails")
+	backend.AddTool("wait", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}, "Waits until cancelled")
+	backend.AddResource("notes://today", func() string { return "Buy milk" }, "Today's notes")
+	backend.AddPrompt("hello", func(name string) string { return "Say hello to " + name }, "Says hello")
+	conn, stopBackend := serveBackend(t, backend)
+
+	gateway := server.NewServer("gateway")
+	p := New(gateway)
+	defer p.Close()
+	if err := p.Add(context.Background(), "be", conn); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := p.Add(context.Background(), "be", conn); err == nil {
+		t.Error("expected a second backend with the same prefix to be rejected")
+	}
+	tc := mcptest.NewClient(t, gateway)
+
+	// Tools, resources and prompts are forwarded under the prefix
+	tools := tc.Tools()
+	if len(tools) != 3 {
+		t.Fatalf("expected the 3 backend tools, got %+v", tools)
+	}
+	tc.ExpectToolResult("be_echo", map[string]interface{}{"arg0": "hi"}, "hi")
+
+	resources, err := tc.Client.Resources(context.Background())
+	if err != nil || len(resources) != 1 || resources[0].URI != "notes://be/today" {
+		t.Errorf("expected the prefixed backend resource, got %+v (%v)", resources, err)
+	}
+	contents := tc.ReadResource("notes://be/today").Contents
+	if text, ok := contents[0].(protocol.TextResourceContents); !ok || text.Text != "Buy milk" || text.URI != "notes://be/today" {
+		t.Errorf("unexpected resource contents %+v", contents)
+	}
+
+	prompt := tc.GetPrompt("be_hello", map[string]string{"arg0": "Ann"})
+	if len(prompt.Messages) != 1 || prompt.Messages[0].Content.(protocol.TextContent).Text != "Say hello to Ann" {
+		t.Errorf("unexpected prompt %+v", prompt)
+	}
+
+	// Errors of the backend reach the client
+	tc.ExpectToolError("be_fail", nil, "boom")
+	tc.ExpectToolError("be_echo", map[string]interface{}{"arg0": 1}, "invalid argument arg0")
+	tc.ExpectError("resources/read", map[string]interface{}{"uri": "notes://be/missing"}, protocol.ResourceNotFound)
+
+	// Cancelling a call cancels it on the backend
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := tc.Client.CallTool(ctx, "be_wait", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the call to be given up, got %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Error("expected the backend call to be cancelled")
+	}
+
+	// Backend list changes are followed
+	backend.AddTool("added", func() string { return "new" }, "Added later")
+	if !eventually(t, func() bool { return len(tc.Tools()) == 4 }) {
+		t.Errorf("expected the added tool to be registered, got %+v", tc.Tools())
+	}
+	tc.ExpectToolResult("be_added", nil, "new")
+
+	// A disconnected backend's registrations are removed
+	stopBackend()
+	if !eventually(t, func() bool { return len(tc.Tools()) == 0 }) {
+		t.Errorf("expected the backend tools to be removed, got %+v", tc.Tools())
+	}
+	tc.ExpectError("resources/read", map[string]interface{}{"uri": "notes://be/today"}, protocol.ResourceNotFound)
+}
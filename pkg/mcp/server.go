@@ -93,13 +93,15 @@ func validateHandler(handler interface{}) error {
 		return fmt.Errorf("handler must be a function")
 	}
 
-	// Check return types
-	if t.NumOut() < 1 {
-		return fmt.Errorf("handler must return at least one value")
+	// Check return types: func(...), func(...) T, func(...) error and
+	// func(...) (T, error) are all accepted
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if t.NumOut() > 2 {
+		return fmt.Errorf("handler must return at most two values")
 	}
 
-	// For async handlers, check error return
-	if t.NumOut() == 2 && !t.Out(1).Implements(reflect.TypeOf((*error)(nil)).Elem()) {
+	// A second result must be the error
+	if t.NumOut() == 2 && !t.Out(1).Implements(errorType) {
 		return fmt.Errorf("second return value must be error")
 	}
 
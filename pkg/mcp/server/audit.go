@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// AuditRecord describes a completed tools/call
+type AuditRecord struct {
+	Time      time.Time               `json:"time"`
+	Tool      string                  `json:"tool"`
+	Arguments map[string]interface{}  `json:"arguments,omitempty"`
+	Client    protocol.Implementation `json:"client"`
+	Duration  time.Duration           `json:"durationNs"`
+	IsError   bool                    `json:"isError"`
+	Error     string                  `json:"error,omitempty"`
+}
+
+// AuditSink records audited tool calls
+type AuditSink interface {
+	Audit(ctx context.Context, record AuditRecord) error
+}
+
+// AuditFunc adapts a function to an AuditSink
+type AuditFunc func(ctx context.Context, record AuditRecord) error
+
+// Audit calls f(ctx, record)
+func (f AuditFunc) Audit(ctx context.Context, record AuditRecord) error {
+	return f(ctx, record)
+}
+
+// AuditInterceptor returns a tool interceptor that records every tool call
+// to the given sinks. Add it first so the recorded duration covers the
+// whole chain. Arguments are redacted like those of LoggingMiddleware,
+// matching DefaultRedactedFields and the tool's sensitive arguments. Sink
+// errors don't affect the call, and are logged to the server's logger.
+func AuditInterceptor(sinks ...AuditSink) ToolInterceptor {
+	redactor := loggingConfig{patterns: DefaultRedactedFields}
+	return func(ctx context.Context, name string, args map[string]interface{}, next ToolInvoker) (protocol.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, name, args)
+
+		record := AuditRecord{
+			Time:     start,
+			Tool:     name,
+			Duration: time.Since(start),
+			IsError:  err != nil || result.IsError,
+		}
+		logger := slog.Default()
+		var sensitive []string
+		if session := SessionFromContext(ctx); session != nil {
+			record.Client = session.ClientInfo()
+			logger = session.server.logger
+			sensitive = session.server.sensitiveArguments(name)
+		}
+		if args != nil {
+			record.Arguments = redactor.redact(args, sensitive).(map[string]interface{})
+		}
+		if err != nil {
+			record.Error = err.Error()
+		}
+
+		for _, sink := range sinks {
+			if err := sink.Audit(ctx, record); err != nil {
+				logger.Error("failed to audit tool call", "tool", name, "error", err)
+			}
+		}
+		return result, err
+	}
+}
+
+// JSONLinesAuditSink writes audit records as JSON lines
+type JSONLinesAuditSink struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewJSONLinesAuditSink creates a sink writing one JSON record per line to w
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w}
+}
+
+// NewFileAuditSink creates a sink appending JSON lines to the file at path
+func NewFileAuditSink(path string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return NewJSONLinesAuditSink(f), nil
+}
+
+// Audit writes the record as a single JSON line
+func (s *JSONLinesAuditSink) Audit(ctx context.Context, record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.w.Write(data)
+	return err
+}
+
+// Close closes the underlying writer if it is an io.Closer
+func (s *JSONLinesAuditSink) Close() error {
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
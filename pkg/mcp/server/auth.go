@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"slices"
+)
+
+// AuthInfo describes the authenticated client of a session, as established
+// by the transport that accepted its connection
+type AuthInfo struct {
+	// Principal identifies the authenticated user or service
+	Principal string
+
+	// Scopes lists the permissions granted to the principal
+	Scopes []string
+
+	// Extra holds authenticator-specific details, such as token claims
+	Extra map[string]interface{}
+}
+
+// HasScope reports whether the principal was granted the given scope
+func (a *AuthInfo) HasScope(scope string) bool {
+	return a != nil && slices.Contains(a.Scopes, scope)
+}
+
+// SetAuthInfo attaches the authenticated client to the session
+func (s *Session) SetAuthInfo(info *AuthInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.authInfo = info
+}
+
+// AuthInfo returns the authenticated client of the session, or nil when
+// the transport did not authenticate it
+func (s *Session) AuthInfo() *AuthInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.authInfo
+}
+
+// AuthInfoFromContext returns the authenticated client of the session
+// handling the current request, or nil if there is none
+func AuthInfoFromContext(ctx context.Context) *AuthInfo {
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return nil
+	}
+	return session.AuthInfo()
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// benchSession returns an initialized session of a server with an echo
+// tool and a text resource
+func benchSession(b *testing.B) *Session {
+	b.Helper()
+
+	srv := NewServer("bench")
+	srv.AddTool("echo", func(text string) string { return text }, "Echo the text")
+	srv.AddResource("file:///notes.txt", func() string { return "notes" }, "Notes")
+	session := NewSession(context.Background(), srv)
+	resp, err := session.HandleRequest(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(1),
+		Method:  "initialize",
+		Params:  json.RawMessage(`{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"bench","version":"1.0.0"}}`),
+	})
+	if err != nil || resp.Error != nil {
+		b.Fatalf("initialize failed: %v %+v", err, resp)
+	}
+	return session
+}
+
+// benchRequest handles the same request b.N times
+func benchRequest(b *testing.B, session *Session, method, params string) {
+	req := &protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(2),
+		Method:  method,
+		Params:  json.RawMessage(params),
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, err := session.HandleRequest(req)
+		if err != nil || resp.Error != nil {
+			b.Fatalf("%s failed: %v %+v", method, err, resp.Error)
+		}
+	}
+}
+
+func BenchmarkToolCall(b *testing.B) {
+	benchRequest(b, benchSession(b), "tools/call", `{"name":"echo","arguments":{"arg0":"hello"}}`)
+}
+
+func BenchmarkToolCallHandlerFunc(b *testing.B) {
+	session := benchSession(b)
+	session.server.AddTool("raw", ToolHandlerFunc(func(ctx context.Context, args map[string]interface{}) (protocol.CallToolResult, error) {
+		text, _ := args["text"].(string)
+		return protocol.CallToolResult{Content: []interface{}{protocol.NewTextContent(text)}}, nil
+	}), "Echo the text")
+	benchRequest(b, session, "tools/call", `{"name":"raw","arguments":{"text":"hello"}}`)
+}
+
+func BenchmarkTypedToolCall(b *testing.B) {
+	type echoArgs struct {
+		Text string `json:"text"`
+	}
+	session := benchSession(b)
+	AddTypedTool(session.server, "typed", func(ctx context.Context, args echoArgs) (string, error) {
+		return args.Text, nil
+	}, "Echo the text")
+	benchRequest(b, session, "tools/call", `{"name":"typed","arguments":{"text":"hello"}}`)
+}
+
+func BenchmarkReadResource(b *testing.B) {
+	benchRequest(b, benchSession(b), "resources/read", `{"uri":"file:///notes.txt"}`)
+}
+
+func BenchmarkListTools(b *testing.B) {
+	session := benchSession(b)
+	for i := 0; i < 50; i++ {
+		session.server.AddTool("tool"+strings.Repeat("x", i), func(text string) string { return text }, "")
+	}
+	benchRequest(b, session, "tools/list", `{}`)
+}
+
+func BenchmarkNotifyResourceUpdated(b *testing.B) {
+	srv := NewServer("bench")
+	for i := 0; i < 100; i++ {
+		session := NewSession(context.Background(), srv)
+		session.SetNotificationSender(discardSender{})
+		session.subscriptions["file:///notes.txt"] = struct{}{}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		srv.NotifyResourceUpdated("file:///notes.txt")
+	}
+}
+
+// discardSender drops notifications
+type discardSender struct{}
+
+func (discardSender) SendNotification(method string, params interface{}) error {
+	return nil
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ResourceVersion identifies a version of a resource's contents
+type ResourceVersion struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// matches reports whether two versions identify the same contents,
+// comparing ETags when known and modification times otherwise
+func (v ResourceVersion) matches(other ResourceVersion) bool {
+	if v.ETag != "" || other.ETag != "" {
+		return v.ETag == other.ETag
+	}
+	return !v.LastModified.IsZero() && v.LastModified.Equal(other.LastModified)
+}
+
+// ResourceValidator reports the current version of a resource so expired
+// cache entries can be revalidated without calling the handler
+type ResourceValidator func(uri string) (ResourceVersion, error)
+
+// WithResourceCache caches the contents of a resource by URI for the given
+// TTL, so expensive handlers aren't called on every read
+func WithResourceCache(ttl time.Duration) ResourceOption {
+	return func(r *Resource) {
+		r.CacheTTL = ttl
+	}
+}
+
+// WithResourceValidator revalidates expired cached contents, keeping them
+// while the resource's ETag or modification time is unchanged
+func WithResourceValidator(validator ResourceValidator) ResourceOption {
+	return func(r *Resource) {
+		r.Validator = validator
+	}
+}
+
+// WithResourceCacheSize bounds the number of URIs whose contents are
+// cached for a resource, evicting the least recently read first. It
+// defaults to DefaultResourceCacheSize.
+func WithResourceCacheSize(size int) ResourceOption {
+	return func(r *Resource) {
+		r.CacheSize = size
+	}
+}
+
+// DefaultResourceCacheSize is the number of URIs cached per resource when
+// WithResourceCacheSize isn't set
+const DefaultResourceCacheSize = 1024
+
+// cacheEntry holds the cached contents of a resource URI
+type cacheEntry struct {
+	uri      string
+	contents []interface{}
+	version  ResourceVersion
+	expires  time.Time
+}
+
+// resourceCache caches resource contents by URI, evicting the least
+// recently used entries beyond its size
+type resourceCache struct {
+	ttl       time.Duration
+	size      int
+	validator ResourceValidator
+	entries   map[string]*list.Element
+	lru       *list.List
+	mu        sync.Mutex
+}
+
+// newResourceCache creates a cache for a resource, or nil when caching
+// isn't configured
+func newResourceCache(resource Resource) *resourceCache {
+	if resource.CacheTTL <= 0 && resource.Validator == nil {
+		return nil
+	}
+	size := resource.CacheSize
+	if size <= 0 {
+		size = DefaultResourceCacheSize
+	}
+	return &resourceCache{
+		ttl:       resource.CacheTTL,
+		size:      size,
+		validator: resource.Validator,
+		entries:   make(map[string]*list.Element),
+		lru:       list.New(),
+	}
+}
+
+// get returns the contents of a URI, calling read when the cached contents
+// are missing, expired and not revalidated
+func (c *resourceCache) get(uri string, read func() ([]interface{}, error)) ([]interface{}, error) {
+	now := time.Now()
+
+	entry, ok := c.lookup(uri, now)
+	if ok && now.Before(entry.expires) {
+		return entry.contents, nil
+	}
+
+	var version ResourceVersion
+	if c.validator != nil {
+		var err error
+		version, err = c.validator(uri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate cached resource: %w", err)
+		}
+		if ok && version.matches(entry.version) {
+			entry.expires = now.Add(c.ttl)
+			c.store(entry, now)
+			return entry.contents, nil
+		}
+	}
+
+	contents, err := read()
+	if err != nil {
+		return nil, err
+	}
+	c.store(cacheEntry{
+		uri:      uri,
+		contents: contents,
+		version:  version,
+		expires:  now.Add(c.ttl),
+	}, now)
+	return contents, nil
+}
+
+// lookup returns the cached entry of a URI, marking it recently used.
+// Expired entries are dropped unless a validator may revalidate them.
+func (c *resourceCache) lookup(uri string, now time.Time) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[uri]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	entry := elem.Value.(cacheEntry)
+	if c.validator == nil && !now.Before(entry.expires) {
+		c.remove(elem)
+		return cacheEntry{}, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry, true
+}
+
+// store saves a cache entry, evicting expired entries and then the least
+// recently used ones to stay within the cache size
+func (c *resourceCache) store(entry cacheEntry, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.uri]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.uri] = c.lru.PushFront(entry)
+
+	if c.validator == nil {
+		for elem := c.lru.Back(); elem != nil; {
+			prev := elem.Prev()
+			if !now.Before(elem.Value.(cacheEntry).expires) {
+				c.remove(elem)
+			}
+			elem = prev
+		}
+	}
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove drops an entry; the caller holds c.mu
+func (c *resourceCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(cacheEntry).uri)
+}
+
+// invalidate drops the cached contents of a URI
+func (c *resourceCache) invalidate(uri string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[uri]; ok {
+		c.remove(elem)
+	}
+}
+
+// invalidateResourceCache drops the cached contents of a URI from every resource
+func (s *Server) invalidateResourceCache(uri string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, resource := range s.resources {
+		if resource.cache != nil {
+			resource.cache.invalidate(uri)
+		}
+	}
+}
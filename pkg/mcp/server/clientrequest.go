@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// ErrRequestsNotSupported is returned when a session's transport cannot
+// send requests to the client
+var ErrRequestsNotSupported = errors.New("transport does not support server requests")
+
+// ErrRequestNotDelivered is returned, possibly wrapped, by RequestSenders
+// with no way to reach the client at the moment, such as a streamable HTTP
+// session without an open event stream. Unlike an unanswered request, it
+// says nothing about whether the client is alive.
+var ErrRequestNotDelivered = errors.New("no channel to deliver the request to the client")
+
+// RequestSender delivers server-initiated requests to the client. Transports
+// that support them implement it alongside NotificationSender and pass the
+// client's responses to Session.HandleResponse.
+type RequestSender interface {
+	SendRequest(req *protocol.JSONRPCRequest) error
+}
+
+// WithClientRequestTimeout bounds how long requests sent to clients, such
+// as sampling, roots and ping requests, wait for a response when their
+// context has no deadline of its own
+func WithClientRequestTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.clientRequestTimeout = timeout
+	}
+}
+
+// Request sends a request to the client and decodes the result of its
+// response into result. It blocks until the client responds, ctx is done
+// or the session is closed. Requests given up on are cancelled with a
+// notifications/cancelled message to the client.
+func (s *Session) Request(ctx context.Context, method string, params interface{}, result interface{}) error {
+	if _, ok := ctx.Deadline(); !ok && s.server.clientRequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.server.clientRequestTimeout)
+		defer cancel()
+	}
+
+	s.mu.Lock()
+	sender, ok := s.sender.(RequestSender)
+	if !ok {
+		s.mu.Unlock()
+		return ErrRequestsNotSupported
+	}
+	s.nextRequestID++
+	id := protocol.NewIntID(s.nextRequestID)
+	responses := make(chan *protocol.JSONRPCResponse, 1)
+	s.pending[id] = responses
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+	}()
+
+	if err := sender.SendRequest(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	}); err != nil {
+		return fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case resp := <-responses:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		data, err := json.Marshal(resp.Result)
+		if err != nil {
+			return fmt.Errorf("invalid %s result: %w", method, err)
+		}
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("invalid %s result: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		s.SendNotification("notifications/cancelled", protocol.CancelledNotificationParams{
+			RequestID: id,
+			Reason:    ctx.Err().Error(),
+		})
+		return ctx.Err()
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// Ping checks that the client is responsive
+func (s *Session) Ping(ctx context.Context) error {
+	return s.Request(ctx, "ping", nil, nil)
+}
+
+// HandleResponse delivers a client response to the pending request it
+// answers. Responses to unknown requests are ignored.
+func (s *Session) HandleResponse(resp *protocol.JSONRPCResponse) error {
+	s.mu.RLock()
+	responses, ok := s.pending[resp.ID]
+	s.mu.RUnlock()
+
+	if ok {
+		select {
+		case responses <- resp:
+		default:
+			// A response was already delivered for this request
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// maxCompletionValues is the most completion values returned per request
+const maxCompletionValues = 100
+
+// Completer suggests values for an argument given its current value
+type Completer func(ctx context.Context, value string) ([]string, error)
+
+// WithArgumentCompleter serves completion/complete requests for a prompt
+// argument with the given completer
+func WithArgumentCompleter(argument string, completer Completer) PromptOption {
+	return func(p *Prompt) {
+		if p.completers == nil {
+			p.completers = make(map[string]Completer)
+		}
+		p.completers[argument] = completer
+	}
+}
+
+// WithVariableCompleter serves completion/complete requests for a variable
+// of a resource template with the given completer
+func WithVariableCompleter(variable string, completer Completer) ResourceOption {
+	return func(r *Resource) {
+		if r.completers == nil {
+			r.completers = make(map[string]Completer)
+		}
+		r.completers[variable] = completer
+	}
+}
+
+// handleComplete processes completion/complete requests
+func (s *Session) handleComplete(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.CompleteRequestParams) (*protocol.JSONRPCResponse, error) {
+
+	var completers map[string]Completer
+	s.server.mu.RLock()
+	switch params.Ref.Type {
+	case "ref/prompt":
+		prompt, exists := s.server.prompts[params.Ref.Name]
+		if !exists {
+			s.server.mu.RUnlock()
+			return nil, invalidParams("prompt not found: %s", params.Ref.Name)
+		}
+		completers = prompt.completers
+	case "ref/resource":
+		resource, exists := s.server.resources[params.Ref.URI]
+		if !exists {
+			s.server.mu.RUnlock()
+			return nil, invalidParams("resource template not found: %s", params.Ref.URI)
+		}
+		completers = resource.completers
+	default:
+		s.server.mu.RUnlock()
+		return nil, invalidParams("unknown reference type: %s", params.Ref.Type)
+	}
+	s.server.mu.RUnlock()
+
+	values := []string{}
+	if completer, ok := completers[params.Argument.Name]; ok {
+		suggested, err := completer(ctx, params.Argument.Value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, suggested...)
+	}
+
+	total := len(values)
+	completion := protocol.Completion{
+		Values: values,
+		Total:  &total,
+	}
+	if total > maxCompletionValues {
+		completion.Values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+
+	return &protocol.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  protocol.CompleteResult{Completion: completion},
+	}, nil
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+)
+
+// ConnInfo describes the transport connection a session is served over, as
+// recorded by the transport that accepted it
+type ConnInfo struct {
+	// Transport names the transport: "stdio", "tcp", "websocket", "sse",
+	// "streamable-http" or "nats"
+	Transport string
+
+	// RemoteAddr is the network address of the client, if known
+	RemoteAddr string
+
+	// TLS is the state of the TLS connection, or nil for plain connections
+	TLS *tls.ConnectionState
+
+	// Header holds the headers of the HTTP request that opened the session
+	// which the transport was configured to keep
+	Header http.Header
+}
+
+// SetConnInfo records the connection the session is served over
+func (s *Session) SetConnInfo(info *ConnInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.connInfo = info
+}
+
+// ConnInfo returns the connection the session is served over, or nil when
+// the transport did not record it
+func (s *Session) ConnInfo() *ConnInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.connInfo
+}
+
+// ConnInfoFromContext returns the connection of the session handling the
+// current request, or nil if there is none. The negotiated protocol version
+// is available from SessionFromContext(ctx).ProtocolVersion().
+func ConnInfoFromContext(ctx context.Context) *ConnInfo {
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return nil
+	}
+	return session.ConnInfo()
+}
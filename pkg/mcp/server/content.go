@@ -0,0 +1,211 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// toolResult converts a tool handler return value into a tool result.
+// Handlers may return a protocol.CallToolResult directly, one or more
+// content blocks, or any other value which is serialized as text.
+func toolResult(value interface{}) (protocol.CallToolResult, error) {
+	switch v := value.(type) {
+	case protocol.CallToolResult:
+		if v.Content == nil {
+			v.Content = []interface{}{}
+		}
+		return v, nil
+	case *protocol.CallToolResult:
+		if v == nil {
+			return protocol.CallToolResult{Content: []interface{}{}}, nil
+		}
+		return toolResult(*v)
+	}
+
+	content, err := toolContent(value)
+	if err != nil {
+		return protocol.CallToolResult{}, err
+	}
+	return protocol.CallToolResult{Content: content}, nil
+}
+
+// imageContenter is implemented by image values that know how to encode
+// themselves as image content, such as *mcp.Image
+type imageContenter interface {
+	ImageContent() (protocol.ImageContent, error)
+}
+
+// toolContent converts a tool handler return value into content blocks
+func toolContent(value interface{}) ([]interface{}, error) {
+	if items, ok := value.([]interface{}); ok && isContentList(items) {
+		// Mixed content: strings become text blocks, content blocks pass through
+		content := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			blocks, err := toolContent(item)
+			if err != nil {
+				return nil, err
+			}
+			content = append(content, blocks...)
+		}
+		return content, nil
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return []interface{}{}, nil
+	case imageContenter:
+		content, err := v.ImageContent()
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{content}, nil
+	case image.Image:
+		content, err := protocol.NewImageContentFromImage(v, "png")
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{content}, nil
+	case string:
+		return []interface{}{protocol.NewTextContent(v)}, nil
+	case protocol.TextContent, protocol.ImageContent, protocol.AudioContent, protocol.ResourceLink, protocol.EmbeddedResource:
+		return []interface{}{v}, nil
+	case protocol.Resource:
+		// Resource descriptions are linked rather than serialized as JSON
+		return []interface{}{protocol.NewResourceLink(v)}, nil
+	case []protocol.Resource:
+		content := make([]interface{}, len(v))
+		for i, r := range v {
+			content[i] = protocol.NewResourceLink(r)
+		}
+		return content, nil
+	case protocol.TextResourceContents, protocol.BlobResourceContents:
+		// Resource contents are embedded so the client sees which resource was produced
+		return []interface{}{protocol.NewEmbeddedResource(v, nil)}, nil
+	case *protocol.TextResourceContents:
+		return []interface{}{protocol.NewEmbeddedResource(*v, nil)}, nil
+	case *protocol.BlobResourceContents:
+		return []interface{}{protocol.NewEmbeddedResource(*v, nil)}, nil
+	case []protocol.TextContent:
+		content := make([]interface{}, len(v))
+		for i, c := range v {
+			content[i] = c
+		}
+		return content, nil
+	case []protocol.ImageContent:
+		content := make([]interface{}, len(v))
+		for i, c := range v {
+			content[i] = c
+		}
+		return content, nil
+	case []protocol.AudioContent:
+		content := make([]interface{}, len(v))
+		for i, c := range v {
+			content[i] = c
+		}
+		return content, nil
+	case []protocol.ResourceLink:
+		content := make([]interface{}, len(v))
+		for i, c := range v {
+			content[i] = c
+		}
+		return content, nil
+	case []protocol.EmbeddedResource:
+		content := make([]interface{}, len(v))
+		for i, c := range v {
+			content[i] = c
+		}
+		return content, nil
+	default:
+		// Serialize everything else (numbers, structs, maps, slices) as JSON text
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal result: %w", err)
+		}
+		return []interface{}{protocol.NewTextContent(string(data))}, nil
+	}
+}
+
+// isContentList reports whether every item is a string or a content block,
+// with at least one content block present
+func isContentList(items []interface{}) bool {
+	hasBlock := false
+	for _, item := range items {
+		switch item.(type) {
+		case protocol.TextContent, protocol.ImageContent, protocol.AudioContent, protocol.ResourceLink, protocol.EmbeddedResource,
+			imageContenter, image.Image, protocol.Resource, protocol.TextResourceContents, protocol.BlobResourceContents:
+			hasBlock = true
+		case string:
+		default:
+			return false
+		}
+	}
+	return hasBlock
+}
+
+// resourceContents converts a resource handler return value into resource
+// contents. Strings become text contents and []byte or io.Reader values
+// become base64 blob contents, with MIME types detected by URI extension or
+// content sniffing unless declared at registration. Other values are
+// serialized as JSON.
+func resourceContents(uri, mimeType string, value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case protocol.TextResourceContents, protocol.BlobResourceContents:
+		return []interface{}{v}, nil
+	case []interface{}:
+		if isResourceContentsList(v) {
+			return v, nil
+		}
+	case string:
+		if mimeType == "" {
+			mimeType = detectMimeType(uri, []byte(v))
+		}
+		return []interface{}{protocol.NewTextResourceContents(uri, mimeType, v)}, nil
+	case []byte:
+		return []interface{}{blobContents(uri, mimeType, v)}, nil
+	case io.Reader:
+		if closer, ok := v.(io.Closer); ok {
+			defer closer.Close()
+		}
+		data, err := io.ReadAll(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resource: %w", err)
+		}
+		return []interface{}{blobContents(uri, mimeType, data)}, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	if mimeType == "" {
+		mimeType = "application/json"
+	}
+	return []interface{}{protocol.NewTextResourceContents(uri, mimeType, string(data))}, nil
+}
+
+// blobContents creates base64 blob contents for binary resource data
+func blobContents(uri, mimeType string, data []byte) protocol.BlobResourceContents {
+	if mimeType == "" {
+		mimeType = detectMimeType(uri, data)
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return protocol.NewBlobResourceContents(uri, mimeType, data)
+}
+
+// isResourceContentsList reports whether every item is resource contents
+func isResourceContentsList(items []interface{}) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case protocol.TextResourceContents, protocol.BlobResourceContents:
+		default:
+			return false
+		}
+	}
+	return len(items) > 0
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// contextKey is the type of context keys defined by this package
+type contextKey int
+
+const (
+	sessionContextKey contextKey = iota
+	progressTokenContextKey
+	metaContextKey
+	resultMetaContextKey
+)
+
+// contextType is the reflected type of the context.Context interface
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// SessionFromContext returns the session handling the current request
+func SessionFromContext(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionContextKey).(*Session)
+	return session
+}
+
+// ProgressTokenFromContext returns the progress token sent with the current
+// request, or nil if the client did not ask for progress notifications
+func ProgressTokenFromContext(ctx context.Context) protocol.ProgressToken {
+	return ctx.Value(progressTokenContextKey)
+}
+
+// MetaFromContext returns the _meta object sent with the current request,
+// including the progressToken and any other keys, or nil if there was none
+func MetaFromContext(ctx context.Context) map[string]interface{} {
+	meta, _ := ctx.Value(metaContextKey).(map[string]interface{})
+	return meta
+}
+
+// resultMeta collects the _meta entries handlers attach to their result
+type resultMeta struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+// SetResultMeta attaches a _meta entry to the result of the current request.
+// Entries set on a result returned by the handler take precedence.
+func SetResultMeta(ctx context.Context, key string, value interface{}) error {
+	meta, ok := ctx.Value(resultMetaContextKey).(*resultMeta)
+	if !ok {
+		return fmt.Errorf("no request in context")
+	}
+
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+
+	if meta.values == nil {
+		meta.values = make(map[string]interface{})
+	}
+	meta.values[key] = value
+	return nil
+}
+
+// mergeResultMeta combines the _meta entries attached through the context
+// with those already set on a result
+func mergeResultMeta(ctx context.Context, result map[string]interface{}) map[string]interface{} {
+	meta, ok := ctx.Value(resultMetaContextKey).(*resultMeta)
+	if !ok {
+		return result
+	}
+
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+
+	if len(meta.values) == 0 {
+		return result
+	}
+	merged := make(map[string]interface{}, len(meta.values)+len(result))
+	for key, value := range meta.values {
+		merged[key] = value
+	}
+	for key, value := range result {
+		merged[key] = value
+	}
+	return merged
+}
+
+// ReportProgress sends a notifications/progress message for the current
+// request. A total of zero or less means the total is unknown. It is a no-op
+// when the client did not supply a progress token.
+func ReportProgress(ctx context.Context, progress, total float64, message string) error {
+	token := ProgressTokenFromContext(ctx)
+	if token == nil {
+		return nil
+	}
+
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return fmt.Errorf("no session in context")
+	}
+
+	params := protocol.ProgressNotificationParams{
+		ProgressToken: token,
+		Progress:      progress,
+		Message:       message,
+	}
+	if total > 0 {
+		params.Total = &total
+	}
+
+	return session.SendNotification("notifications/progress", params)
+}
+
+// requestValues is the context of a single request. It has the lifetime of
+// the session's context, carries the request's own values and falls back to
+// the values of the context the request was handled with. Holding them in
+// one value saves a context per value on every request.
+type requestValues struct {
+	context.Context
+	parent        context.Context
+	session       *Session
+	meta          map[string]interface{}
+	progressToken protocol.ProgressToken
+	resultMeta    resultMeta
+}
+
+// Value returns the request's value for key, or else that of the session's
+// context or the parent context
+func (c *requestValues) Value(key interface{}) interface{} {
+	switch key {
+	case sessionContextKey:
+		return c.session
+	case resultMetaContextKey:
+		return &c.resultMeta
+	case metaContextKey:
+		if c.meta != nil {
+			return c.meta
+		}
+	case progressTokenContextKey:
+		if c.progressToken != nil {
+			return c.progressToken
+		}
+	}
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.parent.Value(key)
+}
+
+// requestContext derives the context for handling a single request from the
+// session's context, with the values of parent and the request's _meta
+func (s *Session) requestContext(parent context.Context, meta map[string]interface{}) context.Context {
+	ctx := &requestValues{Context: s.ctx, parent: parent, session: s, meta: meta}
+	if meta != nil {
+		ctx.progressToken = meta["progressToken"]
+	}
+	return ctx
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// methodHandler handles the requests of one method
+type methodHandler func(s *Session, ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error)
+
+// methods maps the methods an initialized session handles to their handlers
+var methods = map[string]methodHandler{
+	"ping":                     typedMethod((*Session).handlePing),
+	"tools/list":               typedMethod((*Session).handleListTools),
+	"tools/call":               typedMethod((*Session).handleCallTool),
+	"resources/list":           typedMethod((*Session).handleListResources),
+	"resources/templates/list": typedMethod((*Session).handleListResourceTemplates),
+	"resources/read":           typedMethod((*Session).handleReadResource),
+	"resources/subscribe":      typedMethod((*Session).handleSubscribe),
+	"resources/unsubscribe":    typedMethod((*Session).handleUnsubscribe),
+	"prompts/list":             typedMethod((*Session).handleListPrompts),
+	"prompts/get":              typedMethod((*Session).handleGetPrompt),
+	"logging/setLevel":         typedMethod((*Session).handleSetLevel),
+	"completion/complete":      typedMethod((*Session).handleComplete),
+}
+
+// advertises reports whether the server advertises the capability a method
+// belongs to. Methods of capabilities the server left out are unknown to
+// its sessions.
+func (s *Server) advertises(method string) bool {
+	group, _, _ := strings.Cut(method, "/")
+	switch group {
+	case "tools":
+		return s.capabilities.Tools != nil
+	case "resources":
+		return s.capabilities.Resources != nil
+	case "prompts":
+		return s.capabilities.Prompts != nil
+	case "logging":
+		return s.capabilities.Logging != nil
+	}
+	return true
+}
+
+// metaParams is implemented by params embedding protocol.RequestParams
+type metaParams interface {
+	RequestMeta() *protocol.Meta
+}
+
+// typedMethod returns the handler of a method whose params are a P. The
+// params are decoded once, with their _meta, before the request is tracked
+// and handled.
+func typedMethod[P any](handle func(s *Session, ctx context.Context, req *protocol.JSONRPCRequest, params *P) (*protocol.JSONRPCResponse, error)) methodHandler {
+	return func(s *Session, ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+		params := new(P)
+		if err := decodeParams(req.Params, params); err != nil {
+			return errorResponse(req.ID, invalidParams("invalid %s params: %v", req.Method, err)), nil
+		}
+
+		var meta map[string]interface{}
+		if m, ok := interface{}(params).(metaParams); ok && m.RequestMeta() != nil {
+			meta = m.RequestMeta().Fields
+		}
+		return s.handleTracked(ctx, req, meta, func(ctx context.Context) (*protocol.JSONRPCResponse, error) {
+			return handle(s, ctx, req, params)
+		})
+	}
+}
+
+// decodeParams decodes the params of a request or notification into v.
+// Params are raw JSON as read by transports, or values passed in process,
+// which are converted through JSON. Missing params leave v unchanged.
+func decodeParams(params interface{}, v interface{}) error {
+	var raw []byte
+	switch p := params.(type) {
+	case nil:
+		return nil
+	case json.RawMessage:
+		raw = p
+	case []byte:
+		raw = p
+	default:
+		var err error
+		if raw, err = json.Marshal(p); err != nil {
+			return err
+		}
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// handleTracked handles a request with a context carrying its _meta,
+// registered as in flight so the client can cancel it, and bounded by the
+// server's request timeout
+func (s *Session) handleTracked(ctx context.Context, req *protocol.JSONRPCRequest, meta map[string]interface{}, handle func(ctx context.Context) (*protocol.JSONRPCResponse, error)) (*protocol.JSONRPCResponse, error) {
+	ctx, cancel := s.trackRequest(ctx, req, meta)
+	defer s.untrackRequest(req.ID, cancel)
+
+	resp, err := handle(ctx)
+
+	// Requests past their deadline get a timeout error, and cancelled
+	// requests must not receive a response
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errorResponse(req.ID, s.server.timeoutError()), nil
+	}
+	if ctx.Err() != nil {
+		return nil, ErrRequestCancelled
+	}
+	if err != nil {
+		return errorResponse(req.ID, err), nil
+	}
+	return resp, nil
+}
+
+// unknownMethodError is the error for requests of unknown methods
+func unknownMethodError(method string) error {
+	return protocol.NewError(protocol.MethodNotFound, fmt.Sprintf("unknown method: %s", method))
+}
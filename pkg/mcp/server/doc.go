@@ -18,6 +18,18 @@
 //	    SupportsAsync: true,
 //	})
 //
+//	// Leave out capabilities the server doesn't offer; their methods, such
+//	// as prompts/list here, are then unknown to sessions
+//	srv := server.NewServer("My Server", server.WithCapabilities(protocol.ServerCapabilities{
+//	    Tools:     &protocol.ToolsCapability{},
+//	    Resources: &protocol.ResourcesCapability{},
+//	}))
+//
+//	// Tell hosts how to use the server; many surface this to the model
+//	srv := server.NewServer("My Server",
+//	    server.WithInstructions("Call search before fetch to find document IDs."),
+//	)
+//
 // Tool Registration:
 //
 //	// Add a synchronous tool
@@ -31,6 +43,197 @@
 //	    return nil
 //	}, "Async tool description")
 //
+// Async tools return immediately with a task ID in the result _meta
+// ("taskId"). Task state and results are kept in a TaskStore, in memory by
+// default, and can be looked up with srv.Task(ctx, id):
+//
+//	// Persist tasks in SQLite so results survive restarts
+//	store, err := server.NewSQLTaskStore(ctx, db, "mcp_tasks")
+//	srv := server.NewServer("My Server", server.WithTaskStore(store))
+//
+// Auditing Tool Calls:
+//
+//	// Record every tool call, with its caller, redacted arguments, duration
+//	// and outcome
+//	auditLog, err := server.NewFileAuditSink("/var/log/mcp-audit.jsonl")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	srv.UseToolInterceptor(server.AuditInterceptor(auditLog))
+//
+// Tools whose parameters are only known at runtime take the arguments as
+// sent, with their schema declared explicitly:
+//
+//	srv.AddTool("query", server.ToolHandlerFunc(func(ctx context.Context, args map[string]interface{}) (protocol.CallToolResult, error) {
+//	    return runQuery(ctx, args)
+//	}), "Run a query", server.WithInputSchema(querySchema))
+//
+// AddTypedTool takes the arguments as a struct instead, deriving the input
+// and output schemas from the handler's types:
+//
+//	server.AddTypedTool(srv, "search", func(ctx context.Context, args SearchArgs) (Results, error) {
+//	    return index.Search(ctx, args.Query)
+//	}, "Search the index")
+//
+// Runtime Tool Changes:
+//
+//	// Replace or remove tools on a live server; clients are notified
+//	srv.ReplaceTool("search", searchV2, "Search the index")
+//	srv.RemoveTool("experimental-export")
+//
+// Bounding Tool Concurrency:
+//
+//	// Run at most 8 tool calls at once, queue 64 more and reject the rest
+//	srv := server.NewServer("My Server",
+//	    server.WithWorkerPool(8, 64, server.QueueFullReject),
+//	)
+//
+//	// Or bound the handlers running at once without a pool of workers,
+//	// counting async tools until their task finishes. Calls wait up to 5
+//	// seconds for a slot and are then rejected with a custom error.
+//	srv := server.NewServer("My Server",
+//	    server.WithToolConcurrencyLimit(4, 32,
+//	        server.WithQueueTimeout(5*time.Second),
+//	        server.WithRejectionError(protocol.NewError(-32000, "server busy, retry later")),
+//	    ),
+//	)
+//
+// Protocol Revisions:
+//
+//	// Sessions negotiate the 2024-11-05, 2025-03-26 or 2025-06-18 revision
+//	// in initialize, and fields newer than the negotiated revision, such as
+//	// tool annotations and structured content, are left out
+//	srv.AddTool("weather", getWeather, "Current weather",
+//	    server.WithToolAnnotations(protocol.ToolAnnotations{ReadOnlyHint: &readOnly}),
+//	    server.WithOutputSchema(weatherSchema),
+//	)
+//
+//	// Ask the user for input on clients that support elicitation
+//	result, err := session.Elicit(ctx, "Which city?", citySchema)
+//
+// Keepalive:
+//
+//	// Ping clients every 30 seconds and close sessions that do not answer
+//	// within 10 seconds, so dead connections behind proxies are dropped
+//	srv := server.NewServer("My Server",
+//	    server.WithKeepAlive(30*time.Second, 10*time.Second),
+//	)
+//
+// Request Timeouts:
+//
+//	// Give every request 30 seconds; handlers see the deadline on their
+//	// context and late requests get a protocol.RequestTimeout error
+//	srv := server.NewServer("My Server",
+//	    server.WithRequestTimeout(30*time.Second),
+//	)
+//
+//	// Bound a single tool; calls past the timeout become error results
+//	srv.AddTool("crawl", crawl, "Crawl a site", server.WithToolTimeout(time.Minute))
+//
+// Progress Notifications:
+//
+//	// Handlers taking a context.Context can report progress to clients
+//	// that sent a progressToken with the request
+//	srv.AddTool("import", func(ctx context.Context, files []string) error {
+//	    for i, f := range files {
+//	        server.ReportProgress(ctx, float64(i+1), float64(len(files)), f)
+//	    }
+//	    return nil
+//	}, "Import files")
+//
+// Request Metadata:
+//
+//	// Handlers taking a context.Context can read the request _meta and
+//	// attach _meta entries to their result, for example for tracing
+//	srv.AddTool("query", func(ctx context.Context, sql string) (string, error) {
+//	    traceID := server.MetaFromContext(ctx)["traceId"]
+//	    server.SetResultMeta(ctx, "traceId", traceID)
+//	    return runQuery(ctx, sql)
+//	}, "Run a query")
+//
+// Authentication:
+//
+//	// Transports that authenticate clients attach an AuthInfo to the
+//	// session, which handlers can use for authorization
+//	srv.AddTool("delete", func(ctx context.Context, path string) error {
+//	    if !server.AuthInfoFromContext(ctx).HasScope("write") {
+//	        return errors.New("write scope required")
+//	    }
+//	    return os.Remove(path)
+//	}, "Delete a file")
+//
+// Connection Info:
+//
+//	// Handlers can see which transport and address a client connected from
+//	srv.AddTool("whoami", func(ctx context.Context) string {
+//	    info := server.ConnInfoFromContext(ctx)
+//	    return info.Transport + " " + info.RemoteAddr
+//	}, "Describe the caller")
+//
+// Session State:
+//
+//	// Keep per-client state for the lifetime of the session
+//	srv.AddTool("next-page", func(ctx context.Context) ([]string, error) {
+//	    cursor, _ := server.StateValue[string](ctx, "cursor")
+//	    page, next, err := fetchPage(ctx, cursor)
+//	    server.StateFromContext(ctx).Set("cursor", next)
+//	    return page, err
+//	}, "Fetch the next page")
+//
+// Tool Interceptors:
+//
+//	// Wrap every tool execution with cross-cutting logic
+//	srv.UseToolInterceptor(func(ctx context.Context, name string, args map[string]interface{}, next server.ToolInvoker) (protocol.CallToolResult, error) {
+//	    start := time.Now()
+//	    result, err := next(ctx, name, args)
+//	    log.Printf("tool %s took %v", name, time.Since(start))
+//	    return result, err
+//	})
+//
+// Lifecycle Hooks:
+//
+//	// Observe and veto protocol events without wrapping handlers
+//	srv := server.NewServer("My Server", server.WithHooks(server.Hooks{
+//	    OnInitialize: func(session *server.Session, client protocol.Implementation, caps protocol.ClientCapabilities) error {
+//	        if !allowedClients[client.Name] {
+//	            return protocol.NewError(protocol.InvalidRequest, "client not allowed")
+//	        }
+//	        return nil
+//	    },
+//	    AfterToolCall: func(ctx context.Context, name string, args map[string]interface{}, result protocol.CallToolResult, err error) {
+//	        toolCalls.WithLabelValues(name).Inc()
+//	    },
+//	}))
+//
+// Request Middleware:
+//
+//	// Wrap every JSON-RPC request on a session, for example to rate limit it
+//	session.Use(func(next server.Handler) server.Handler {
+//	    return func(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+//	        if !limiter.Allow() {
+//	            return nil, protocol.NewError(protocol.InvalidRequest, "rate limit exceeded")
+//	        }
+//	        return next(ctx, req)
+//	    }
+//	})
+//
+//	// Or wrap the requests of every session of the server
+//	srv.Use(requestLogger)
+//
+// Request Logging:
+//
+//	// Log the method, duration and outcome of every request, and the
+//	// arguments of tool calls and prompts; values of arguments named like
+//	// DefaultRedactedFields, such as "password" or "apiToken", are redacted
+//	srv.Use(server.LoggingMiddleware(logger))
+//
+//	// Redact other argument names, and mark secrets of typed tools
+//	srv.Use(server.LoggingMiddleware(logger, server.WithRedactedFields("*ssn*", "*card*")))
+//	type Login struct {
+//	    User string `json:"user"`
+//	    PIN  string `json:"pin" sensitive:"true"`
+//	}
+//
 // Resource Registration:
 //
 //	// Add a resource with pattern matching
@@ -38,6 +241,63 @@
 //	    return ioutil.ReadFile(path)
 //	}, "Access files")
 //
+//	// Resource patterns are RFC 6570 URI templates: {+path} matches
+//	// reserved characters such as "/", and {?q,limit} binds optional
+//	// query parameters. Templated resources are listed through
+//	// resources/templates/list. When several templates match a URI, the
+//	// one with the most literal characters serves it.
+//	srv.AddResource("search://{index}{?q,limit}", func(index, q string, limit int) ([]string, error) {
+//	    return search(index, q, limit)
+//	}, "Search an index")
+//
+//	// Declare the MIME type instead of detecting it from the URI or contents
+//	srv.AddResource("notes://{id}", readNote, "Markdown notes",
+//	    server.WithMimeType("text/markdown"))
+//
+//	// Describe a resource for listings
+//	srv.AddResource("file:///logo.png", readLogo, "Company logo",
+//	    server.WithResourceName("Logo"),
+//	    server.WithResourceSize(2048),
+//	    server.WithResourceAnnotations(protocol.Annotations{
+//	        Audience: []protocol.Role{protocol.RoleUser},
+//	    }))
+//
+// Resource Caching:
+//
+//	// Cache expensive reads for a minute, then revalidate by ETag
+//	srv.AddResource("https://example.com/{path}", fetch, "Remote documents",
+//	    server.WithResourceCache(time.Minute),
+//	    server.WithResourceValidator(func(uri string) (server.ResourceVersion, error) {
+//	        return server.ResourceVersion{ETag: headETag(uri)}, nil
+//	    }))
+//
+// Runtime Resource Changes:
+//
+//	// Replace or remove resources on a live server; clients are notified
+//	srv.ReplaceResource("config", loadConfig, "Current configuration")
+//	srv.RemoveResource("legacy://{id}")
+//
+// Resource Providers:
+//
+//	// Serve resources that aren't known up front; Read returns
+//	// server.ErrResourceNotFound for URIs the provider doesn't serve
+//	srv.AddResourceProvider(rowProvider{db: db})
+//
+// File System Resources:
+//
+//	// Serve a directory tree as file:// resources
+//	srv.AddFSResources("file://", os.DirFS("/srv/docs"))
+//
+// Pagination:
+//
+//	// Return list results in pages of 100 with a cursor to the next page
+//	srv := server.NewServer("catalog", server.WithPageSize(100))
+//
+// Resource Subscriptions:
+//
+//	// Tell subscribed clients that a resource changed
+//	srv.NotifyResourceUpdated("file:///var/log/app.log")
+//
 // Prompt Registration:
 //
 //	// Add a prompt template
@@ -45,14 +305,190 @@
 //	    return fmt.Sprintf("Are you sure you want to %s?", action)
 //	}, "Confirmation prompt")
 //
+//	// Return a full result to set a per-call description
+//	srv.AddPrompt("summarize", func(topic string) (protocol.GetPromptResult, error) {
+//	    return protocol.GetPromptResult{
+//	        Description: "Summary of " + topic,
+//	        Messages:    summaryMessages(topic),
+//	    }, nil
+//	}, "Summary prompt")
+//
+//	// Attach a registered resource to a prompt
+//	srv.AddPrompt("review-file", func(path string) ([]protocol.PromptMessage, error) {
+//	    file, err := srv.ResourcePromptMessage(context.Background(), protocol.RoleUser, "file:///"+path)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return []protocol.PromptMessage{file, reviewInstructions}, nil
+//	}, "Review a file")
+//
+// Prompt Arguments:
+//
+//	// Declare named arguments with a struct; omitempty marks them optional
+//	type ReviewArgs struct {
+//	    Language string `json:"language" description:"Programming language"`
+//	    Focus    string `json:"focus,omitempty" description:"Area to focus on"`
+//	}
+//	srv.AddPrompt("review", func(args ReviewArgs) string {
+//	    return fmt.Sprintf("Review this %s code, focusing on %s", args.Language, args.Focus)
+//	}, "Code review prompt")
+//
+//	// Or name positional parameters explicitly
+//	srv.AddPrompt("greet", greet, "Greeting prompt",
+//	    server.WithPromptArgument("name", "Who to greet", true))
+//
+// Prompt Templates:
+//
+//	// Register a text/template prompt; {{.topic}} becomes an argument
+//	srv.AddPromptTemplate("explain", "Explain {{.topic}} simply.", "Explanation prompt")
+//
+//	// Load every template file in a directory
+//	srv.LoadPromptTemplates(os.DirFS("."), "prompts/*.tmpl")
+//
+// Runtime Prompt Changes:
+//
+//	// Replace or remove prompts on a live server; clients are notified
+//	srv.ReplacePrompt("greet", greetFormally, "Formal greeting prompt")
+//	srv.RemovePrompt("confirm")
+//
+// Prompts can likewise take their arguments as sent with a
+// PromptHandlerFunc, declaring them with WithPromptArgument.
+//
+// Server Composition:
+//
+// Servers built by separate modules can be mounted into one endpoint under
+// a prefix. Tools and prompts are renamed prefix_name and resource URIs get
+// the prefix as their first path segment. Mount copies what the sub-server
+// has registered at that point.
+//
+//	fsServer := server.NewServer("fs")
+//	fsServer.AddTool("read", readFile, "Read a file")
+//	fsServer.AddResource("file://{path}", fileContents, "File contents")
+//
+//	// Serves the tool fs_read and the resource file://fs/{path}
+//	srv.Mount("fs", fsServer)
+//
+// Plugins:
+//
+// Tools, resources and prompts can be added at startup from Go plugins,
+// without recompiling the program. A plugin is a main package exporting
+// Register, built with -buildmode=plugin against the same module version
+// and Go toolchain (plugins need cgo and are supported on Linux, FreeBSD
+// and macOS only):
+//
+//	// go build -buildmode=plugin -o plugins/weather.so ./weather
+//	func Register(srv *server.Server) error {
+//	    return srv.AddTool("forecast", forecast, "Get the forecast")
+//	}
+//
+//	// Load every .so file in the directory
+//	if err := srv.LoadPlugins("plugins"); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Manifest:
+//
+//	// Describe the tools (with their input schemas), resources, resource
+//	// templates and prompts as one JSON document
+//	manifest, err := srv.Manifest(ctx)
+//	data, err := json.MarshalIndent(manifest, "", "  ")
+//
+// Tools without a declared input schema get one derived from their handler
+// parameters, named arg0, arg1, ... Struct parameters become objects whose
+// properties follow the json tags, described by description tags.
+//
+// Argument Completion:
+//
+//	// Suggest values for prompt arguments and resource template variables
+//	srv.AddPrompt("review", review, "Code review prompt",
+//	    server.WithPromptArgument("language", "Programming language", true),
+//	    server.WithArgumentCompleter("language", completeLanguage))
+//	srv.AddResource("docs://{language}", readDocs, "Language docs",
+//	    server.WithVariableCompleter("language", completeLanguage))
+//
+// Logging:
+//
+//	// Send log messages to clients; each client picks its minimum level
+//	// with logging/setLevel, defaulting to info
+//	srv.Log(protocol.LoggingLevelWarning, "indexer", "index is stale")
+//	session.Log(protocol.LoggingLevelDebug, "indexer", map[string]int{"files": 42})
+//
+// Server Logs:
+//
+//	// Server diagnostics go to a JSON logger on stderr by default, so
+//	// stdout stays free for the stdio transport
+//	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//	srv := server.NewServer("My Server", server.WithLogger(logger))
+//
+// Metrics:
+//
+//	// Requests by method, tool call counts, errors and durations, open
+//	// sessions, running async tasks and queued tool calls; requests for
+//	// unknown methods are counted as "unknown"
+//	stats := srv.Stats()
+//	log.Printf("%d sessions, %d searches", stats.ActiveSessions, stats.Tools["search"].Count)
+//
+//	// Or in the Prometheus text format
+//	srv.WritePrometheus(w, "mcp")
+//
+// Client Roots:
+//
+//	// Scope filesystem access to the roots the client exposes
+//	srv.AddTool("list", func(ctx context.Context) ([]string, error) {
+//	    roots, err := server.SessionFromContext(ctx).Roots(ctx)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return rootURIs(roots), nil
+//	}, "List workspace roots")
+//
+// Sampling:
+//
+//	// Ask the client's LLM for a completion on clients that support
+//	// sampling; requests to clients wait at most the client request timeout
+//	srv := server.NewServer("My Server", server.WithClientRequestTimeout(2*time.Minute))
+//	result, err := server.SessionFromContext(ctx).CreateMessage(ctx, protocol.CreateMessageRequestParams{
+//	    Messages:  []protocol.SamplingMessage{{Role: protocol.RoleUser, Content: protocol.NewTextContent(question)}},
+//	    MaxTokens: 500,
+//	})
+//
+// Graceful Shutdown:
+//
+//	// Drain in-flight requests and async tasks, then stop the transports
+//	// and close the sessions
+//	srv.RegisterTransport(t)
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := srv.Shutdown(ctx); err != nil {
+//	    log.Printf("shutdown: %v", err)
+//	}
+//
 // Session Management:
 //
 //	// Create a new session
 //	session := server.NewSession(context.Background(), srv)
 //
-//	// Handle requests through the session
+//	// Handle requests through the session. Failures are returned as
+//	// JSON-RPC error responses, such as protocol.MethodNotFound or
+//	// protocol.InvalidParams, in response.Error.
 //	response, err := session.HandleRequest(request)
 //
+//	// Params are raw JSON as read by transports, or typed values when
+//	// requests are built in process; each method decodes them once
+//	response, err = session.HandleRequest(&protocol.JSONRPCRequest{
+//	    JSONRPC: "2.0",
+//	    ID:      protocol.NewIntID(2),
+//	    Method:  "resources/read",
+//	    Params:  protocol.ReadResourceRequestParams{URI: "file:///notes.txt"},
+//	})
+//
+//	// Sessions get a unique ID and are tracked by the server's session
+//	// manager until closed, so one server can serve many clients
+//	for _, info := range srv.Sessions().Info() {
+//	    log.Printf("%s: %s since %v", info.ID, info.ClientInfo.Name, info.CreatedAt)
+//	}
+//	srv.Sessions().Close(id)
+//
 // The server package uses reflection to dynamically invoke handlers and convert
 // parameters, making it easy to register any Go function as a tool, resource,
 // or prompt handler.
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// ErrElicitationNotSupported is returned by Elicit when the client did not
+// advertise the elicitation capability or negotiated a protocol revision
+// without it
+var ErrElicitationNotSupported = errors.New("client does not support elicitation")
+
+// Elicit asks the user, through the client, for input matching a flat JSON
+// schema of primitive properties. The result's Action reports whether the
+// user accepted, declined or cancelled; Content holds the accepted values.
+func (s *Session) Elicit(ctx context.Context, message string, schema map[string]interface{}) (protocol.ElicitResult, error) {
+	s.mu.RLock()
+	supported := s.capabilities.Elicitation != nil
+	s.mu.RUnlock()
+
+	if !supported || !s.supportsVersion(protocol.ProtocolVersion20250618) {
+		return protocol.ElicitResult{}, ErrElicitationNotSupported
+	}
+
+	var result protocol.ElicitResult
+	if err := s.Request(ctx, "elicitation/create", protocol.ElicitRequestParams{
+		Message:         message,
+		RequestedSchema: schema,
+	}, &result); err != nil {
+		return protocol.ElicitResult{}, err
+	}
+	return result, nil
+}
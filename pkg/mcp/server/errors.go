@@ -0,0 +1,38 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// invalidParams creates an error reported to the client as invalid params
+func invalidParams(format string, args ...interface{}) error {
+	return protocol.NewError(protocol.InvalidParams, fmt.Sprintf(format, args...))
+}
+
+// errorResponse converts a request failure into a JSON-RPC error response.
+// Errors wrapping *protocol.ErrorData keep its code, missing resources use
+// ResourceNotFound, timeouts use RequestTimeout and anything else is an
+// internal error.
+func errorResponse(id protocol.RequestID, err error) *protocol.JSONRPCResponse {
+	errorData := protocol.NewError(protocol.InternalError, err.Error())
+
+	var data *protocol.ErrorData
+	switch {
+	case errors.As(err, &data):
+		errorData.Code = data.Code
+		errorData.Data = data.Data
+	case errors.Is(err, ErrResourceNotFound):
+		errorData.Code = protocol.ResourceNotFound
+	case errors.Is(err, ErrRequestTimeout):
+		errorData.Code = protocol.RequestTimeout
+	}
+
+	return &protocol.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   errorData,
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// fsProvider serves the files of an fs.FS as resources under a URI prefix
+type fsProvider struct {
+	prefix string
+	fsys   fs.FS
+}
+
+// AddFSResources serves the files of fsys as resources whose URIs are the
+// prefix followed by the slash-separated file path. Reading a directory
+// returns the names of its entries. Symbolic links are neither listed nor
+// read, since they can lead out of fsys.
+func (s *Server) AddFSResources(prefix string, fsys fs.FS) error {
+	if prefix == "" {
+		return fmt.Errorf("resource prefix must not be empty")
+	}
+	s.AddResourceProvider(&fsProvider{prefix: prefix, fsys: fsys})
+	return nil
+}
+
+// List returns a resource for every regular file in the file system
+func (p *fsProvider) List(ctx context.Context) ([]protocol.Resource, error) {
+	var resources []protocol.Resource
+	err := fs.WalkDir(p.fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		uri := p.prefix + name
+		resource := protocol.Resource{
+			URI:      uri,
+			Name:     name,
+			MimeType: detectMimeType(uri, nil),
+		}
+		if info, err := entry.Info(); err == nil {
+			size := info.Size()
+			resource.Size = &size
+		}
+		resources = append(resources, resource)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// Read returns the contents of a file, or the entry names of a directory
+func (p *fsProvider) Read(ctx context.Context, uri string) (interface{}, error) {
+	name, ok := strings.CutPrefix(uri, p.prefix)
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+	if name == "" {
+		name = "."
+	}
+
+	// fs.ValidPath rejects "..", absolute and unclean paths, and rejecting
+	// symbolic links stops reads through links leading out of the root of
+	// an os.DirFS
+	if !fs.ValidPath(name) {
+		return nil, invalidParams("invalid resource path: %s", name)
+	}
+	if err := p.checkSymlinks(name); err != nil {
+		return nil, err
+	}
+
+	info, err := fs.Stat(p.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		entries, err := fs.ReadDir(p.fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names, nil
+	}
+
+	data, err := fs.ReadFile(p.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if utf8.Valid(data) {
+		return string(data), nil
+	}
+	return data, nil
+}
+
+// checkSymlinks fails when an element of a valid path is a symbolic link.
+// Directory entries report links without following them, so each element
+// is looked up in the entries of its parent.
+func (p *fsProvider) checkSymlinks(name string) error {
+	if name == "." {
+		return nil
+	}
+	dir := "."
+	for _, elem := range strings.Split(name, "/") {
+		entries, err := fs.ReadDir(p.fsys, dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrResourceNotFound
+		}
+		if err != nil {
+			return err
+		}
+		i, found := slices.BinarySearchFunc(entries, elem, func(entry fs.DirEntry, name string) int {
+			return strings.Compare(entry.Name(), name)
+		})
+		if !found {
+			return ErrResourceNotFound
+		}
+		if entries[i].Type()&fs.ModeSymlink != 0 {
+			return invalidParams("resource path is a symbolic link: %s", name)
+		}
+		dir = path.Join(dir, elem)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// discardLogger drops the logs of fuzzed servers
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// fuzzServer returns a server with tools, resources and prompts taking
+// arguments of several types
+func fuzzServer() *Server {
+	type point struct {
+		X, Y float64
+	}
+	srv := NewServer("fuzz", WithDefaultCapabilities(), WithPageSize(2), WithLogger(discardLogger))
+	srv.AddTool("add", func(a, b int) int { return a + b }, "Add two numbers")
+	srv.AddTool("join", func(parts []string, sep string) string { return sep }, "Join strings")
+	srv.AddTool("move", func(ctx context.Context, p point, by map[string]float64) (point, error) { return p, nil }, "Move a point")
+	AddTypedTool(srv, "typed", func(ctx context.Context, args point) (point, error) { return args, nil }, "Echo a point")
+	srv.AddTool("raw", ToolHandlerFunc(func(ctx context.Context, args map[string]interface{}) (protocol.CallToolResult, error) {
+		return protocol.CallToolResult{}, nil
+	}), "Return nothing")
+	srv.AddResource("file:///notes.txt", func() string { return "notes" }, "Notes")
+	srv.AddResource("users://{id}/posts/{post}", func(id string, post int) string { return id }, "Posts")
+	srv.AddResource("file:///{+path}", func(path string) ([]byte, error) { return nil, errors.New(path) }, "Files")
+	srv.AddResource("search://{index}{?q,limit,exact}", func(index, q string, limit uint, exact bool) ([]string, error) { return nil, nil }, "Search")
+	srv.AddResource("maps://{lat,lng}", func(lat, lng float64) (*point, error) { return nil, nil }, "Places")
+	srv.AddResource("any://{kind}", func(kind string) interface{} { return nil }, "Anything")
+	srv.AddPrompt("greet", func(name string, times int) string { return name }, "Greet a person")
+	return srv
+}
+
+// FuzzHandleRequest checks that no request, whatever its ID, method or
+// params, panics the server. Params are passed both as raw JSON, as
+// transports do, and as decoded values, as in-process callers do.
+func FuzzHandleRequest(f *testing.F) {
+	seeds := []struct{ id, method, params string }{
+		{`1`, "tools/call", `{"name":"add","arguments":{"arg0":1,"arg1":2}}`},
+		{`"a"`, "tools/call", `{"name":"add","arguments":{"arg0":1e400,"arg1":-9223372036854775809}}`},
+		{`2`, "tools/call", `{"name":"join","arguments":{"arg0":[1,null,{}],"arg1":[]}}`},
+		{`3`, "tools/call", `{"name":"move","arguments":{"arg0":"x","arg1":{"a":"b"}}}`},
+		{`4`, "tools/call", `{"name":"typed","arguments":[]}`},
+		{`5`, "tools/call", `{"name":"raw","arguments":null,"_meta":{"progressToken":{}}}`},
+		{`6`, "tools/call", `[]`},
+		{`7`, "tools/list", `{"cursor":"%%%"}`},
+		{`8`, "resources/read", `{"uri":"users://1/posts/99999999999999999999"}`},
+		{`9`, "resources/subscribe", `"file:///notes.txt"`},
+		{`9`, "resources/read", `{"uri":"file:///etc/../%00"}`},
+		{`9`, "resources/read", `{"uri":"search://docs?limit=-1&exact=maybe&q"}`},
+		{`9`, "resources/read", `{"uri":"maps://1e999,NaN"}`},
+		{`9`, "resources/read", `{"uri":"any://x"}`},
+		{`10`, "prompts/get", `{"name":"greet","arguments":{"arg0":1}}`},
+		{`11`, "completion/complete", `{"ref":{"type":"ref/prompt","name":"greet"},"argument":{"name":"arg0","value":"a"}}`},
+		{`12`, "logging/setLevel", `{"level":42}`},
+		{`13`, "initialize", `{"protocolVersion":{}}`},
+		{`null`, "ping", `0`},
+		{`1.5`, "", `null`},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.id, seed.method, seed.params)
+	}
+
+	srv := fuzzServer()
+	f.Fuzz(func(t *testing.T, rawID, method, rawParams string) {
+		var id protocol.RequestID
+		if json.Unmarshal([]byte(rawID), &id) != nil {
+			id = protocol.NewIntID(1)
+		}
+		var decoded interface{}
+		if json.Unmarshal([]byte(rawParams), &decoded) != nil {
+			decoded = rawParams
+		}
+
+		for _, params := range []interface{}{json.RawMessage(rawParams), decoded} {
+			session := newTestSession(t, srv)
+			session.SetNotificationSender(discardSender{})
+			session.HandleRequest(&protocol.JSONRPCRequest{
+				JSONRPC: "2.0",
+				ID:      id,
+				Method:  method,
+				Params:  params,
+			})
+			session.HandleNotification(&protocol.JSONRPCNotification{
+				JSONRPC: "2.0",
+				Method:  method,
+				Params:  params,
+			})
+			session.Close()
+		}
+	})
+}
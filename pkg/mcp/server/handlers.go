@@ -1,29 +1,40 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"reflect"
+	"time"
 
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
 )
 
+// errorType is the reflected type of the error interface
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 // handleListTools processes tools/list requests
-func (s *Session) handleListTools(req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+func (s *Session) handleListTools(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.PaginatedRequestParams) (*protocol.JSONRPCResponse, error) {
+	cursor := params.Cursor
 	s.server.mu.RLock()
+	pageSize := s.server.pageSize
 	tools := make([]protocol.Tool, 0, len(s.server.tools))
 	for name, tool := range s.server.tools {
-		tools = append(tools, protocol.Tool{
-			Name:        name,
-			Description: tool.Description,
-		})
+		tools = append(tools, s.toolDefinition(name, tool))
 	}
 	s.server.mu.RUnlock()
 
+	page, next, err := paginate(tools, func(t protocol.Tool) string { return t.Name }, cursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
 	result := protocol.ListToolsResult{
-		Tools: tools,
+		Tools: page,
 	}
+	result.NextCursor = next
 
 	return &protocol.JSONRPCResponse{
 		JSONRPC: "2.0",
@@ -32,93 +43,326 @@ func (s *Session) handleListTools(req *protocol.JSONRPCRequest) (*protocol.JSONR
 	}, nil
 }
 
-// handleCallTool processes tools/call requests
-func (s *Session) handleCallTool(req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
-	var params protocol.CallToolRequestParams
-	if err := json.Unmarshal(req.Params.(json.RawMessage), &params); err != nil {
-		return nil, fmt.Errorf("invalid tool call params: %w", err)
+// definition describes a tool with the fields of the latest protocol
+// revision
+func (t Tool) definition(name string) protocol.Tool {
+	return protocol.Tool{
+		Name:         name,
+		Title:        t.Title,
+		Description:  t.Description,
+		InputSchema:  t.InputSchema,
+		OutputSchema: t.OutputSchema,
+		Annotations:  t.Annotations,
+	}
+}
+
+// toolDefinition describes a tool, leaving out the fields the negotiated
+// protocol revision does not define
+func (s *Session) toolDefinition(name string, tool Tool) protocol.Tool {
+	definition := tool.definition(name)
+	if !s.supportsVersion(protocol.ProtocolVersion20250326) {
+		definition.Annotations = nil
 	}
-	log.Printf("Received tool call request: %+v", params)
+	if !s.supportsVersion(protocol.ProtocolVersion20250618) {
+		definition.Title = ""
+		definition.OutputSchema = nil
+	}
+	return definition
+}
+
+// handleCallTool processes tools/call requests
+func (s *Session) handleCallTool(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.CallToolRequestParams) (*protocol.JSONRPCResponse, error) {
+	s.logger().LogAttrs(ctx, slog.LevelDebug, "tool call", slog.String("tool", params.Name))
 
 	s.server.mu.RLock()
-	tool, exists := s.server.tools[params.Name]
+	_, exists := s.server.tools[params.Name]
+	invoke := s.server.invoke
 	s.server.mu.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("tool not found: %s", params.Name)
+		return nil, invalidParams("tool not found: %s", params.Name)
+	}
+
+	if err := s.server.beforeToolCall(ctx, params.Name, params.Arguments); err != nil {
+		return nil, err
+	}
+	result, err := invoke(ctx, params.Name, params.Arguments)
+	s.server.afterToolCall(ctx, params.Name, params.Arguments, result, err)
+	if err != nil {
+		return nil, err
+	}
+	result.Meta = mergeResultMeta(ctx, result.Meta)
+	if !s.supportsVersion(protocol.ProtocolVersion20250618) {
+		result.StructuredContent = nil
+	}
+
+	return &protocol.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}, nil
+}
+
+// invokeTool calls a tool handler with the given arguments
+func (s *Server) invokeTool(ctx context.Context, name string, arguments map[string]interface{}) (result protocol.CallToolResult, err error) {
+	s.mu.RLock()
+	tool, exists := s.tools[name]
+	s.mu.RUnlock()
+
+	if !exists {
+		return protocol.CallToolResult{}, invalidParams("tool not found: %s", name)
+	}
+
+	start := time.Now()
+	defer func() {
+		s.metrics.observe(&s.metrics.tools, name, time.Since(start), err != nil || result.IsError)
+	}()
+
+	// Calls queue for the concurrency limit within their request, and async
+	// tools and calls with a timeout hand their slot to the goroutine
+	// running the handler
+	if err := s.acquireTool(ctx); err != nil {
+		return protocol.CallToolResult{}, err
+	}
+	async := false
+	defer func() {
+		if !async {
+			s.releaseTool()
+		}
+	}()
+
+	// Async tools outlive the request, so they must not observe its cancellation
+	if tool.IsAsync {
+		ctx = context.WithoutCancel(ctx)
+	} else if tool.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, tool.Timeout)
+		defer cancel()
+	}
+
+	var args []reflect.Value
+	if _, ok := tool.Handler.(ToolHandlerFunc); !ok {
+		if args, err = toolArgs(ctx, tool.Handler, arguments); err != nil {
+			return protocol.CallToolResult{}, err
+		}
+	}
+
+	if tool.IsAsync {
+		result, err = s.startTask(ctx, name, tool, arguments, args)
+		async = err == nil
+		return result, err
+	}
+
+	if tool.Timeout > 0 {
+		async = true
+		return s.callToolWithTimeout(ctx, name, tool, arguments, args)
 	}
+	return s.callTool(ctx, tool.Handler, tool.OutputSchema != nil, arguments, args)
+}
+
+// callToolWithTimeout calls a synchronous tool with a timeout, racing the
+// handler against its deadline so handlers that ignore their context still
+// time out. Abandoned handlers run on in the background and keep their
+// concurrency slot, which is released when they return.
+func (s *Server) callToolWithTimeout(ctx context.Context, name string, tool Tool, arguments map[string]interface{}, args []reflect.Value) (protocol.CallToolResult, error) {
+	type call struct {
+		result protocol.CallToolResult
+		err    error
+	}
+	done := make(chan call, 1)
+	go func() {
+		defer s.releaseTool()
+		result, err := s.callTool(ctx, tool.Handler, tool.OutputSchema != nil, arguments, args)
+		done <- call{result, err}
+	}()
+
+	select {
+	case c := <-done:
+		if c.err != nil {
+			return protocol.CallToolResult{}, c.err
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errorResult(toolTimeoutError(name, tool.Timeout)), nil
+		}
+		return c.result, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errorResult(toolTimeoutError(name, tool.Timeout)), nil
+		}
+		return protocol.CallToolResult{}, ctx.Err()
+	}
+}
 
-	// Convert arguments to reflect.Values
-	handlerType := reflect.TypeOf(tool.Handler)
+// toolArgs converts arguments to the reflect.Values a handler is called
+// with, passing the request context to handlers that accept a
+// context.Context as their first parameter
+func toolArgs(ctx context.Context, handler interface{}, arguments map[string]interface{}) ([]reflect.Value, error) {
+	handlerType := reflect.TypeOf(handler)
 	args := make([]reflect.Value, handlerType.NumIn())
-	for i := 0; i < handlerType.NumIn(); i++ {
+	offset := 0
+	if handlerType.NumIn() > 0 && handlerType.In(0) == contextType {
+		args[0] = reflect.ValueOf(ctx)
+		offset = 1
+	}
+	for i := offset; i < handlerType.NumIn(); i++ {
 		paramType := handlerType.In(i)
-		paramValue := reflect.New(paramType).Interface()
 
 		// Get argument value from params
-		argName := fmt.Sprintf("arg%d", i)
-		log.Printf("argName: %s", argName)
-		if params.Arguments != nil {
-			if argValue, ok := params.Arguments[argName]; ok {
-				// Directly assign the argument value
-				paramValue = argValue
-			} else {
-				return nil, fmt.Errorf("missing argument: %s", argName)
-			}
-		} else {
-			return nil, fmt.Errorf("arguments map is nil")
+		name := argName(i - offset)
+		if arguments == nil {
+			return nil, invalidParams("arguments map is nil")
+		}
+		argValue, ok := arguments[name]
+		if !ok {
+			return nil, invalidParams("missing argument: %s", name)
+		}
+
+		paramValue, err := convertArgument(argValue, paramType)
+		if err != nil {
+			return nil, invalidParams("invalid argument %s: %v", name, err)
+		}
+		args[i] = paramValue
+	}
+	return args, nil
+}
+
+// callTool calls a synchronous tool handler, on the worker pool if one is
+// configured. Calls without a pool run directly, so they don't allocate a
+// job.
+func (s *Server) callTool(ctx context.Context, handler interface{}, structured bool, arguments map[string]interface{}, args []reflect.Value) (protocol.CallToolResult, error) {
+	if s.pool == nil {
+		return callToolHandler(ctx, handler, structured, arguments, args), nil
+	}
+
+	var result protocol.CallToolResult
+	err := s.runTool(ctx, func() {
+		result = callToolHandler(ctx, handler, structured, arguments, args)
+	})
+	return result, err
+}
+
+// callToolHandler calls a tool handler and converts its return values.
+// ToolHandlerFuncs are called with the arguments as they are, other
+// handlers through reflection with the converted args.
+func callToolHandler(ctx context.Context, handler interface{}, structured bool, arguments map[string]interface{}, args []reflect.Value) protocol.CallToolResult {
+	if handle, ok := handler.(ToolHandlerFunc); ok {
+		result, err := handle(ctx, arguments)
+		if err != nil {
+			return errorResult(err)
+		}
+		if result.Content == nil {
+			result.Content = []interface{}{}
 		}
-		args[i] = reflect.ValueOf(paramValue)
+		return result
 	}
 
-	// Call the handler
-	log.Printf("arguments: %+v", args)
-	results := reflect.ValueOf(tool.Handler).Call(args)
+	handlerType := reflect.TypeOf(handler)
+	results := reflect.ValueOf(handler).Call(args)
 
 	// Process results
-	var content []interface{}
-	var isError bool
+	result := protocol.CallToolResult{Content: []interface{}{}}
 
-	if len(results) == 2 { // Function returns (value, error)
+	switch {
+	case len(results) == 0: // Function returns nothing
+	case len(results) == 1 && handlerType.Out(0) == errorType: // Function returns error
+		if !results[0].IsNil() {
+			result = errorResult(results[0].Interface().(error))
+		}
+	case len(results) == 2: // Function returns (value, error)
 		if !results[1].IsNil() { // Error occurred
-			err := results[1].Interface().(error)
-			content = []interface{}{protocol.NewTextContent(err.Error())}
-			isError = true
+			result = errorResult(results[1].Interface().(error))
 		} else {
-			content = []interface{}{results[0].String()}
+			result = resultValue(results[0], structured)
 		}
-	} else { // Function returns single value
-		content = []interface{}{results[0].String()}
+	default: // Function returns single value
+		result = resultValue(results[0], structured)
+	}
+
+	return result
+}
+
+// convertArgument converts a decoded JSON argument into the parameter type
+func convertArgument(value interface{}, paramType reflect.Type) (reflect.Value, error) {
+	if value != nil && reflect.TypeOf(value).AssignableTo(paramType) {
+		return reflect.ValueOf(value), nil
 	}
 
-	result := protocol.CallToolResult{
-		Content: content,
-		IsError: isError,
+	// Round-trip through JSON to convert numbers, maps and slices into
+	// the concrete types the handler expects
+	data, err := json.Marshal(value)
+	if err != nil {
+		return reflect.Value{}, err
 	}
+	paramValue := reflect.New(paramType)
+	if err := json.Unmarshal(data, paramValue.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return paramValue.Elem(), nil
+}
 
-	return &protocol.JSONRPCResponse{
-		JSONRPC: "2.0",
-		ID:      req.ID,
-		Result:  result,
-	}, nil
+// resultValue converts a handler return value into a tool result,
+// reporting conversion failures as an error result. Structured results
+// also carry the value itself as structured content.
+func resultValue(value reflect.Value, structured bool) protocol.CallToolResult {
+	if (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && value.IsNil() {
+		return protocol.CallToolResult{Content: []interface{}{}}
+	}
+	result, err := toolResult(value.Interface())
+	if err != nil {
+		return errorResult(err)
+	}
+	if structured && result.StructuredContent == nil && !isToolResult(value.Interface()) {
+		result.StructuredContent = value.Interface()
+	}
+	return result
+}
+
+// isToolResult reports whether a handler returned a full tool result
+func isToolResult(value interface{}) bool {
+	switch value.(type) {
+	case protocol.CallToolResult, *protocol.CallToolResult:
+		return true
+	}
+	return false
+}
+
+// errorResult creates a tool result reporting the given error
+func errorResult(err error) protocol.CallToolResult {
+	return protocol.CallToolResult{
+		Content: []interface{}{protocol.NewTextContent(err.Error())},
+		IsError: true,
+	}
 }
 
 // handleListResources processes resources/list requests
-func (s *Session) handleListResources(req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+func (s *Session) handleListResources(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.PaginatedRequestParams) (*protocol.JSONRPCResponse, error) {
+	cursor := params.Cursor
 	s.server.mu.RLock()
+	pageSize := s.server.pageSize
 	resources := make([]protocol.Resource, 0, len(s.server.resources))
 	for _, resource := range s.server.resources {
-		resources = append(resources, protocol.Resource{
-			URI:         resource.Pattern,
-			Name:        resource.Pattern,
-			Description: resource.Description,
-		})
+		if resource.compiled.isTemplate() {
+			continue
+		}
+		resources = append(resources, resource.definition())
 	}
 	s.server.mu.RUnlock()
 
+	provided, err := s.server.listProviderResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, provided...)
+
+	page, next, err := paginate(resources, func(r protocol.Resource) string { return r.URI }, cursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
 	result := protocol.ListResourcesResult{
-		Resources: resources,
+		Resources: page,
 	}
+	result.NextCursor = next
 
 	return &protocol.JSONRPCResponse{
 		JSONRPC: "2.0",
@@ -127,28 +371,48 @@ func (s *Session) handleListResources(req *protocol.JSONRPCRequest) (*protocol.J
 	}, nil
 }
 
-// handleReadResource processes resources/read requests
-func (s *Session) handleReadResource(req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
-	var params protocol.ReadResourceRequestParams
-	if err := json.Unmarshal(req.Params.(json.RawMessage), &params); err != nil {
-		return nil, fmt.Errorf("invalid resource read params: %w", err)
+// handleListResourceTemplates processes resources/templates/list requests
+func (s *Session) handleListResourceTemplates(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.PaginatedRequestParams) (*protocol.JSONRPCResponse, error) {
+	cursor := params.Cursor
+	s.server.mu.RLock()
+	pageSize := s.server.pageSize
+	templates := make([]protocol.ResourceTemplate, 0, len(s.server.resources))
+	for _, resource := range s.server.resources {
+		if !resource.compiled.isTemplate() {
+			continue
+		}
+		templates = append(templates, resource.templateDefinition())
 	}
+	s.server.mu.RUnlock()
 
-	// Find matching resource and extract parameters
-	resource, resourceParams, err := s.server.matchResource(params.URI.String())
+	page, next, err := paginate(templates, func(t protocol.ResourceTemplate) string { return t.URITemplate }, cursor, pageSize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to match resource: %w", err)
+		return nil, err
+	}
+
+	result := protocol.ListResourceTemplatesResult{
+		ResourceTemplates: page,
 	}
+	result.NextCursor = next
+
+	return &protocol.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}, nil
+}
 
-	// Read the resource
-	contents, err := s.server.readResource(resource, resourceParams)
+// handleReadResource processes resources/read requests
+func (s *Session) handleReadResource(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.ReadResourceRequestParams) (*protocol.JSONRPCResponse, error) {
+	contents, err := s.server.ReadResource(ctx, params.URI)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read resource: %w", err)
+		return nil, err
 	}
 
 	result := protocol.ReadResourceResult{
 		Contents: contents,
 	}
+	result.Meta = mergeResultMeta(ctx, nil)
 
 	return &protocol.JSONRPCResponse{
 		JSONRPC: "2.0",
@@ -157,21 +421,52 @@ func (s *Session) handleReadResource(req *protocol.JSONRPCRequest) (*protocol.JS
 	}, nil
 }
 
+// handleSubscribe processes resources/subscribe requests
+func (s *Session) handleSubscribe(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.SubscribeRequestParams) (*protocol.JSONRPCResponse, error) {
+	s.mu.Lock()
+	s.subscriptions[params.URI] = struct{}{}
+	s.mu.Unlock()
+
+	return &protocol.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  struct{}{},
+	}, nil
+}
+
+// handleUnsubscribe processes resources/unsubscribe requests
+func (s *Session) handleUnsubscribe(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.UnsubscribeRequestParams) (*protocol.JSONRPCResponse, error) {
+	s.mu.Lock()
+	delete(s.subscriptions, params.URI)
+	s.mu.Unlock()
+
+	return &protocol.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  struct{}{},
+	}, nil
+}
+
 // handleListPrompts processes prompts/list requests
-func (s *Session) handleListPrompts(req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+func (s *Session) handleListPrompts(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.PaginatedRequestParams) (*protocol.JSONRPCResponse, error) {
+	cursor := params.Cursor
 	s.server.mu.RLock()
+	pageSize := s.server.pageSize
 	prompts := make([]protocol.Prompt, 0, len(s.server.prompts))
 	for name, prompt := range s.server.prompts {
-		prompts = append(prompts, protocol.Prompt{
-			Name:        name,
-			Description: prompt.Description,
-		})
+		prompts = append(prompts, prompt.definition(name))
 	}
 	s.server.mu.RUnlock()
 
+	page, next, err := paginate(prompts, func(p protocol.Prompt) string { return p.Name }, cursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
 	result := protocol.ListPromptsResult{
-		Prompts: prompts,
+		Prompts: page,
 	}
+	result.NextCursor = next
 
 	return &protocol.JSONRPCResponse{
 		JSONRPC: "2.0",
@@ -181,31 +476,21 @@ func (s *Session) handleListPrompts(req *protocol.JSONRPCRequest) (*protocol.JSO
 }
 
 // handleGetPrompt processes prompts/get requests
-func (s *Session) handleGetPrompt(req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
-	var params protocol.GetPromptRequestParams
-	if err := json.Unmarshal(req.Params.(json.RawMessage), &params); err != nil {
-		return nil, fmt.Errorf("invalid prompt get params: %w", err)
-	}
-
+func (s *Session) handleGetPrompt(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.GetPromptRequestParams) (*protocol.JSONRPCResponse, error) {
 	s.server.mu.RLock()
 	prompt, exists := s.server.prompts[params.Name]
 	s.server.mu.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("prompt not found: %s", params.Name)
+		return nil, invalidParams("prompt not found: %s", params.Name)
 	}
 
 	// Render the prompt
-	messages, err := s.server.renderPrompt(prompt, params.Arguments)
+	result, err := s.server.renderPrompt(ctx, prompt, params.Arguments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render prompt: %w", err)
 	}
 
-	result := protocol.GetPromptResult{
-		Description: prompt.Description,
-		Messages:    messages,
-	}
-
 	return &protocol.JSONRPCResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// Hooks observe protocol events of every session of a server. Hooks
+// returning an error veto the event, and their error is sent to the client
+// as a JSON-RPC error. Nil hooks are skipped.
+type Hooks struct {
+	// OnInitialize is called when a client sends initialize, before the
+	// session is initialized. Returning an error rejects initialization.
+	OnInitialize func(session *Session, clientInfo protocol.Implementation, caps protocol.ClientCapabilities) error
+
+	// OnInitialized is called when the client confirms initialization with
+	// the initialized notification
+	OnInitialized func(session *Session)
+
+	// BeforeToolCall is called before a tool is invoked. Returning an error
+	// rejects the call.
+	BeforeToolCall func(ctx context.Context, name string, args map[string]interface{}) error
+
+	// AfterToolCall is called once a tool call completed, with its result or
+	// error
+	AfterToolCall func(ctx context.Context, name string, args map[string]interface{}, result protocol.CallToolResult, err error)
+
+	// OnError is called for every request answered with a JSON-RPC error
+	OnError func(session *Session, req *protocol.JSONRPCRequest, err *protocol.ErrorData)
+}
+
+// WithHooks adds hooks observing the server's protocol events. Hooks added
+// by several options all run, in the order they were added.
+func WithHooks(hooks Hooks) ServerOption {
+	return func(s *Server) {
+		s.hooks = append(s.hooks, hooks)
+	}
+}
+
+// onInitialize runs the OnInitialize hooks, stopping at the first error
+func (s *Server) onInitialize(session *Session, clientInfo protocol.Implementation, caps protocol.ClientCapabilities) error {
+	for _, hooks := range s.hooks {
+		if hooks.OnInitialize != nil {
+			if err := hooks.OnInitialize(session, clientInfo, caps); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// onInitialized runs the OnInitialized hooks
+func (s *Server) onInitialized(session *Session) {
+	for _, hooks := range s.hooks {
+		if hooks.OnInitialized != nil {
+			hooks.OnInitialized(session)
+		}
+	}
+}
+
+// beforeToolCall runs the BeforeToolCall hooks, stopping at the first error
+func (s *Server) beforeToolCall(ctx context.Context, name string, args map[string]interface{}) error {
+	for _, hooks := range s.hooks {
+		if hooks.BeforeToolCall != nil {
+			if err := hooks.BeforeToolCall(ctx, name, args); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// afterToolCall runs the AfterToolCall hooks
+func (s *Server) afterToolCall(ctx context.Context, name string, args map[string]interface{}, result protocol.CallToolResult, err error) {
+	for _, hooks := range s.hooks {
+		if hooks.AfterToolCall != nil {
+			hooks.AfterToolCall(ctx, name, args, result, err)
+		}
+	}
+}
+
+// onError runs the OnError hooks
+func (s *Server) onError(session *Session, req *protocol.JSONRPCRequest, err *protocol.ErrorData) {
+	for _, hooks := range s.hooks {
+		if hooks.OnError != nil {
+			hooks.OnError(session, req, err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// ToolInvoker executes a tool call with the given arguments
+type ToolInvoker func(ctx context.Context, name string, args map[string]interface{}) (protocol.CallToolResult, error)
+
+// ToolInterceptor wraps a tool execution. An interceptor may inspect or
+// rewrite the arguments, call next to continue the chain, and inspect or
+// replace the result. Returning an error without calling next aborts the
+// call and reports the error to the client.
+type ToolInterceptor func(ctx context.Context, name string, args map[string]interface{}, next ToolInvoker) (protocol.CallToolResult, error)
+
+// UseToolInterceptor adds interceptors that wrap every tool execution.
+// Interceptors run in the order they are added, so the first interceptor
+// is the outermost.
+func (s *Server) UseToolInterceptor(interceptors ...ToolInterceptor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.toolInterceptors = append(s.toolInterceptors, interceptors...)
+	s.invoke = s.toolInvoker()
+}
+
+// toolInvoker builds the interceptor chain around invokeTool, which is kept
+// in s.invoke so it isn't rebuilt for every call. The caller must hold s.mu.
+func (s *Server) toolInvoker() ToolInvoker {
+	invoke := ToolInvoker(s.invokeTool)
+	for i := len(s.toolInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := s.toolInterceptors[i], invoke
+		invoke = func(ctx context.Context, name string, args map[string]interface{}) (protocol.CallToolResult, error) {
+			return interceptor(ctx, name, args, next)
+		}
+	}
+	return invoke
+}
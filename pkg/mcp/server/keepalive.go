@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// WithKeepAlive makes sessions ping their client every interval once
+// initialized, closing sessions whose client does not answer within timeout.
+// A timeout of zero waits a full interval. Sessions whose transport cannot
+// send requests are not pinged.
+func WithKeepAlive(interval, timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.keepAliveInterval = interval
+		s.keepAliveTimeout = timeout
+	}
+}
+
+// startKeepAlive starts pinging the client if the server has keepalive enabled
+func (s *Session) startKeepAlive() {
+	interval := s.server.keepAliveInterval
+	if interval <= 0 {
+		return
+	}
+	timeout := s.server.keepAliveTimeout
+	if timeout <= 0 {
+		timeout = interval
+	}
+	go s.keepAlive(interval, timeout)
+}
+
+// keepAlive pings the client every interval until the session is closed,
+// closing it when a ping goes unanswered
+func (s *Session) keepAlive(interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, timeout)
+		err := s.Ping(ctx)
+		cancel()
+
+		// An error response still shows the client is alive
+		var rpcErr *protocol.ErrorData
+		switch {
+		case err == nil, errors.As(err, &rpcErr):
+		case errors.Is(err, ErrRequestNotDelivered):
+			// The client may open a channel before the next ping
+		case errors.Is(err, ErrRequestsNotSupported), s.ctx.Err() != nil:
+			return
+		default:
+			s.logger().Info("closing session after unanswered ping", "error", err)
+			s.Close()
+			return
+		}
+	}
+}
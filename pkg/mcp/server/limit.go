@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrToolConcurrencyLimit is returned when a tool call is rejected because
+// the server's tool concurrency limit is reached and its queue is full
+var ErrToolConcurrencyLimit = errors.New("server busy: too many tool calls in progress")
+
+// LimitOption configures the tool concurrency limit
+type LimitOption func(*toolLimiter)
+
+// WithQueueTimeout bounds how long a tool call waits for a slot before it
+// is rejected. Without a timeout calls wait until their request is
+// cancelled or times out.
+func WithQueueTimeout(timeout time.Duration) LimitOption {
+	return func(l *toolLimiter) {
+		l.timeout = timeout
+	}
+}
+
+// WithRejectionError sets the error rejected tool calls fail with, in place
+// of ErrToolConcurrencyLimit. A *protocol.ErrorData sets the JSON-RPC error
+// code clients receive.
+func WithRejectionError(err error) LimitOption {
+	return func(l *toolLimiter) {
+		l.err = err
+	}
+}
+
+// toolLimiter bounds the number of tool handlers running at once
+type toolLimiter struct {
+	slots   chan struct{}
+	queue   int64
+	waiting atomic.Int64
+	timeout time.Duration
+	err     error
+}
+
+// WithToolConcurrencyLimit bounds the tool handlers running at once, across
+// all sessions, to limit. Up to queueLength further calls wait for a
+// handler to finish, and calls beyond that are rejected. Async tools hold
+// their slot until their task finishes. Unlike a worker pool, handlers run
+// on the goroutine of their request, so the limit adds no goroutines.
+func WithToolConcurrencyLimit(limit, queueLength int, opts ...LimitOption) ServerOption {
+	return func(s *Server) {
+		if limit < 1 {
+			limit = 1
+		}
+		if queueLength < 0 {
+			queueLength = 0
+		}
+		l := &toolLimiter{
+			slots: make(chan struct{}, limit),
+			queue: int64(queueLength),
+			err:   ErrToolConcurrencyLimit,
+		}
+		for _, opt := range opts {
+			opt(l)
+		}
+		s.toolLimit = l
+	}
+}
+
+// acquire takes a slot, waiting in the queue if none is free. It fails with
+// the rejection error when the queue is full or the queue timeout passes,
+// and with the context's error when ctx is done first.
+func (l *toolLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if l.waiting.Add(1) > l.queue {
+		l.waiting.Add(-1)
+		return l.err
+	}
+	defer l.waiting.Add(-1)
+
+	var timeout <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timeout:
+		return l.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (l *toolLimiter) release() {
+	<-l.slots
+}
+
+// acquireTool takes a slot of the tool concurrency limit, if one is set
+func (s *Server) acquireTool(ctx context.Context) error {
+	if s.toolLimit == nil {
+		return nil
+	}
+	return s.toolLimit.acquire(ctx)
+}
+
+// releaseTool frees a slot taken by acquireTool
+func (s *Server) releaseTool() {
+	if s.toolLimit != nil {
+		s.toolLimit.release()
+	}
+}
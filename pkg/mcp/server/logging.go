@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// defaultLogLevel is the minimum level sent to clients that haven't
+// called logging/setLevel
+const defaultLogLevel = protocol.LoggingLevelInfo
+
+// Log sends a notifications/message log message to the client if the level
+// is at or above the level the client chose with logging/setLevel
+func (s *Session) Log(level protocol.LoggingLevel, logger string, data interface{}) error {
+	if !s.logEnabled(level) {
+		return nil
+	}
+	return s.SendNotification("notifications/message", protocol.LoggingMessageNotificationParams{
+		Level:  level,
+		Logger: logger,
+		Data:   data,
+	})
+}
+
+// Log sends a log message to every session whose client accepts the level
+func (s *Server) Log(level protocol.LoggingLevel, logger string, data interface{}) error {
+	params := protocol.LoggingMessageNotificationParams{
+		Level:  level,
+		Logger: logger,
+		Data:   data,
+	}
+	return s.broadcast("notifications/message", params, func(session *Session) bool {
+		return session.logEnabled(level)
+	})
+}
+
+// logEnabled reports whether the client accepts messages at the level
+func (s *Session) logEnabled(level protocol.LoggingLevel) bool {
+	s.mu.RLock()
+	minLevel := s.logLevel
+	s.mu.RUnlock()
+
+	if minLevel == "" {
+		minLevel = defaultLogLevel
+	}
+	return level.Severity() >= minLevel.Severity()
+}
+
+// handleSetLevel processes logging/setLevel requests
+func (s *Session) handleSetLevel(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.SetLevelRequestParams) (*protocol.JSONRPCResponse, error) {
+	if params.Level.Severity() < 0 {
+		return nil, invalidParams("unknown logging level: %s", params.Level)
+	}
+
+	s.mu.Lock()
+	s.logLevel = params.Level
+	s.mu.Unlock()
+
+	return &protocol.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  struct{}{},
+	}, nil
+}
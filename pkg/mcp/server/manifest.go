@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// Manifest describes everything a server offers, as listed to clients
+// negotiating the latest protocol revision. It serializes to JSON for
+// documentation, registry publishing and client-side caching.
+type Manifest struct {
+	ProtocolVersion   string                      `json:"protocolVersion"`
+	ServerInfo        protocol.Implementation     `json:"serverInfo"`
+	Instructions      string                      `json:"instructions,omitempty"`
+	Capabilities      protocol.ServerCapabilities `json:"capabilities"`
+	Tools             []protocol.Tool             `json:"tools"`
+	Resources         []protocol.Resource         `json:"resources"`
+	ResourceTemplates []protocol.ResourceTemplate `json:"resourceTemplates"`
+	Prompts           []protocol.Prompt           `json:"prompts"`
+}
+
+// Manifest returns the server's manifest, with its tools, resources,
+// resource templates and prompts sorted by name or URI. Resource providers
+// are listed too, so the manifest holds the resources they serve now.
+func (s *Server) Manifest(ctx context.Context) (*Manifest, error) {
+	s.mu.RLock()
+	manifest := &Manifest{
+		ProtocolVersion:   protocol.LatestProtocolVersion,
+		ServerInfo:        s.info,
+		Instructions:      s.instructions,
+		Capabilities:      s.capabilities,
+		Tools:             make([]protocol.Tool, 0, len(s.tools)),
+		Resources:         []protocol.Resource{},
+		ResourceTemplates: []protocol.ResourceTemplate{},
+		Prompts:           make([]protocol.Prompt, 0, len(s.prompts)),
+	}
+	for name, tool := range s.tools {
+		manifest.Tools = append(manifest.Tools, tool.definition(name))
+	}
+	for _, resource := range s.resources {
+		if resource.compiled.isTemplate() {
+			manifest.ResourceTemplates = append(manifest.ResourceTemplates, resource.templateDefinition())
+		} else {
+			manifest.Resources = append(manifest.Resources, resource.definition())
+		}
+	}
+	for name, prompt := range s.prompts {
+		manifest.Prompts = append(manifest.Prompts, prompt.definition(name))
+	}
+	s.mu.RUnlock()
+
+	provided, err := s.listProviderResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Resources = append(manifest.Resources, provided...)
+
+	sort.Slice(manifest.Tools, func(i, j int) bool { return manifest.Tools[i].Name < manifest.Tools[j].Name })
+	sort.Slice(manifest.Resources, func(i, j int) bool { return manifest.Resources[i].URI < manifest.Resources[j].URI })
+	sort.Slice(manifest.ResourceTemplates, func(i, j int) bool {
+		return manifest.ResourceTemplates[i].URITemplate < manifest.ResourceTemplates[j].URITemplate
+	})
+	sort.Slice(manifest.Prompts, func(i, j int) bool { return manifest.Prompts[i].Name < manifest.Prompts[j].Name })
+	return manifest, nil
+}
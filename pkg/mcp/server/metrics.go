@@ -0,0 +1,257 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// latencyBuckets are the upper bounds of the duration histograms
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	time.Minute,
+}
+
+// unknownMethod labels the requests for methods the server doesn't
+// implement, so clients can't create unbounded metrics
+const unknownMethod = "unknown"
+
+// Stats is a snapshot of a server's metrics
+type Stats struct {
+	// ActiveSessions is the number of open sessions
+	ActiveSessions int
+
+	// Requests holds the requests handled, by method
+	Requests map[string]CallStats
+
+	// Tools holds the tool calls, by tool name. Async tools are timed
+	// until their task starts.
+	Tools map[string]CallStats
+
+	// RunningTasks is the number of async tool tasks running
+	RunningTasks int64
+
+	// QueuedCalls is the number of tool calls waiting for a worker of the
+	// worker pool or a slot of the tool concurrency limit
+	QueuedCalls int
+}
+
+// CallStats counts the requests or tool calls of one kind
+type CallStats struct {
+	// Count is the number of calls
+	Count uint64
+
+	// Errors counts the calls answered with an error or error result
+	Errors uint64
+
+	// Latency is the distribution of their durations
+	Latency LatencyStats
+}
+
+// LatencyStats is a histogram of durations
+type LatencyStats struct {
+	// Count is the number of durations observed
+	Count uint64
+
+	// Sum is their total
+	Sum time.Duration
+
+	// Buckets count the durations within each upper bound, cumulatively;
+	// longer durations are only in Count
+	Buckets []LatencyBucket
+}
+
+// LatencyBucket counts the durations within UpperBound
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// serverMetrics collects the metrics of a server
+type serverMetrics struct {
+	requests     sync.Map // method -> *callMetrics
+	tools        sync.Map // tool name -> *callMetrics
+	runningTasks atomic.Int64
+}
+
+// callMetrics collects the metrics of one kind of call
+type callMetrics struct {
+	count   atomic.Uint64
+	errors  atomic.Uint64
+	sum     atomic.Int64
+	buckets []atomic.Uint64
+}
+
+// observe records a call of a kind, its duration and whether it failed
+func (m *serverMetrics) observe(calls *sync.Map, kind string, elapsed time.Duration, failed bool) {
+	value, ok := calls.Load(kind)
+	if !ok {
+		value, _ = calls.LoadOrStore(kind, &callMetrics{buckets: make([]atomic.Uint64, len(latencyBuckets))})
+	}
+	c := value.(*callMetrics)
+
+	c.count.Add(1)
+	if failed {
+		c.errors.Add(1)
+	}
+	c.sum.Add(int64(elapsed))
+	for i, bound := range latencyBuckets {
+		if elapsed <= bound {
+			c.buckets[i].Add(1)
+		}
+	}
+}
+
+// observeRequest records a handled request
+func (m *serverMetrics) observeRequest(req *protocol.JSONRPCRequest, resp *protocol.JSONRPCResponse, elapsed time.Duration) {
+	method := req.Method
+	failed := resp != nil && resp.Error != nil
+	if failed && resp.Error.Code == protocol.MethodNotFound {
+		method = unknownMethod
+	}
+	m.observe(&m.requests, method, elapsed, failed)
+}
+
+// snapshot returns the stats of each kind of call
+func snapshot(calls *sync.Map) map[string]CallStats {
+	stats := make(map[string]CallStats)
+	calls.Range(func(key, value interface{}) bool {
+		c := value.(*callMetrics)
+		latency := LatencyStats{
+			Count:   c.count.Load(),
+			Sum:     time.Duration(c.sum.Load()),
+			Buckets: make([]LatencyBucket, len(latencyBuckets)),
+		}
+		for i, bound := range latencyBuckets {
+			latency.Buckets[i] = LatencyBucket{UpperBound: bound, Count: c.buckets[i].Load()}
+		}
+		stats[key.(string)] = CallStats{Count: latency.Count, Errors: c.errors.Load(), Latency: latency}
+		return true
+	})
+	return stats
+}
+
+// Stats returns a snapshot of the server's metrics
+func (s *Server) Stats() Stats {
+	stats := Stats{
+		ActiveSessions: s.sessions.Len(),
+		Requests:       snapshot(&s.metrics.requests),
+		Tools:          snapshot(&s.metrics.tools),
+		RunningTasks:   s.metrics.runningTasks.Load(),
+	}
+	if s.pool != nil {
+		stats.QueuedCalls = len(s.pool.jobs)
+	}
+	if s.toolLimit != nil {
+		stats.QueuedCalls += int(s.toolLimit.waiting.Load())
+	}
+	return stats
+}
+
+// WritePrometheus writes the server's metrics in the Prometheus text
+// exposition format, with metric names starting with prefix, such as "mcp"
+func (s *Server) WritePrometheus(w io.Writer, prefix string) error {
+	stats := s.Stats()
+	gauge := func(name, help string, value interface{}) error {
+		_, err := fmt.Fprintf(w, "# HELP %s_%s %s\n# TYPE %s_%s gauge\n%s_%s %v\n",
+			prefix, name, help, prefix, name, prefix, name, value)
+		return err
+	}
+	if err := gauge("sessions_active", "Open sessions.", stats.ActiveSessions); err != nil {
+		return err
+	}
+	if err := gauge("tasks_running", "Async tool tasks running.", stats.RunningTasks); err != nil {
+		return err
+	}
+	if err := gauge("tool_calls_queued", "Tool calls waiting for a worker or a concurrency limit slot.", stats.QueuedCalls); err != nil {
+		return err
+	}
+	requests := callMetricNames{
+		total:    prefix + "_rpc_requests_total",
+		errors:   prefix + "_rpc_request_errors_total",
+		duration: prefix + "_rpc_request_duration_seconds",
+		label:    "method",
+		help:     [3]string{"Requests handled.", "Requests answered with an error.", "Request handling time."},
+	}
+	if err := writeCalls(w, requests, stats.Requests); err != nil {
+		return err
+	}
+	tools := callMetricNames{
+		total:    prefix + "_tool_calls_total",
+		errors:   prefix + "_tool_call_errors_total",
+		duration: prefix + "_tool_call_duration_seconds",
+		label:    "tool",
+		help:     [3]string{"Tool calls.", "Tool calls that failed.", "Tool call duration."},
+	}
+	return writeCalls(w, tools, stats.Tools)
+}
+
+// callMetricNames names the metrics of one kind of call, and the help text
+// of its counter, error counter and histogram
+type callMetricNames struct {
+	total, errors, duration string
+	label                   string
+	help                    [3]string
+}
+
+// writeCalls writes the counters and duration histograms of calls,
+// labelled by kind
+func writeCalls(w io.Writer, names callMetricNames, calls map[string]CallStats) error {
+	kinds := make([]string, 0, len(calls))
+	for kind := range calls {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	label := names.label
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", names.total, names.help[0], names.total); err != nil {
+		return err
+	}
+	for _, kind := range kinds {
+		if _, err := fmt.Fprintf(w, "%s{%s=%q} %d\n", names.total, label, kind, calls[kind].Count); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", names.errors, names.help[1], names.errors); err != nil {
+		return err
+	}
+	for _, kind := range kinds {
+		if _, err := fmt.Fprintf(w, "%s{%s=%q} %d\n", names.errors, label, kind, calls[kind].Errors); err != nil {
+			return err
+		}
+	}
+
+	duration := names.duration
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", duration, names.help[2], duration); err != nil {
+		return err
+	}
+	for _, kind := range kinds {
+		latency := calls[kind].Latency
+		for _, bucket := range latency.Buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket{%s=%q,le=\"%g\"} %d\n", duration, label, kind, bucket.UpperBound.Seconds(), bucket.Count); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket{%s=%q,le=\"+Inf\"} %d\n%s_sum{%s=%q} %g\n%s_count{%s=%q} %d\n",
+			duration, label, kind, latency.Count,
+			duration, label, kind, latency.Sum.Seconds(),
+			duration, label, kind, latency.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
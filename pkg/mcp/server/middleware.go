@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// Handler handles a JSON-RPC request, with the same contract as
+// Session.HandleRequestContext
+type Handler func(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error)
+
+// Middleware wraps the handling of every request on a session. It may
+// inspect the request, call next to continue the chain, and inspect or
+// replace the response. Returning an error without calling next rejects
+// the request; the error is sent to the client as a JSON-RPC error, with
+// the code of a *protocol.ErrorData or InternalError otherwise.
+// SessionFromContext returns the session handling the request.
+type Middleware func(next Handler) Handler
+
+// Use adds middleware that wraps every request handled by the session,
+// including initialize. Middleware runs in the order it is added, so the
+// first middleware is the outermost.
+func (s *Session) Use(middleware ...Middleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.middleware = append(s.middleware, middleware...)
+}
+
+// Use adds middleware that wraps every request on all sessions of the
+// server, including sessions created before. Server middleware runs before
+// the middleware of each session, in the order it is added.
+func (s *Server) Use(middleware ...Middleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.middleware = append(s.middleware, middleware...)
+}
+
+// handle passes a request through the server and session middleware to
+// handleRequest. Middleware sees the session in the context.
+func (s *Session) handle(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+	s.server.mu.RLock()
+	middleware := s.server.middleware
+	s.server.mu.RUnlock()
+
+	s.mu.RLock()
+	sessionMiddleware := s.middleware
+	s.mu.RUnlock()
+
+	if len(middleware) == 0 && len(sessionMiddleware) == 0 {
+		return s.handleRequest(ctx, req)
+	}
+	middleware = append(append([]Middleware(nil), middleware...), sessionMiddleware...)
+	return s.handler(middleware)(context.WithValue(ctx, sessionContextKey, s), req)
+}
+
+// handler builds a middleware chain around handleRequest
+func (s *Session) handler(middleware []Middleware) Handler {
+	handle := Handler(s.handleRequest)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handle = middleware[i](handle)
+	}
+	return handle
+}
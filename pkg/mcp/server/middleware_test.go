@@ -0,0 +1,212 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+func TestSessionMiddleware(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("echo", func(s string) string { return s }, "")
+	session := newTestSession(t, srv)
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+				order = append(order, name+":"+req.Method)
+				return next(ctx, req)
+			}
+		}
+	}
+	session.Use(trace("outer"), trace("inner"))
+	session.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+			if req.Method == "tools/call" {
+				return nil, protocol.NewError(protocol.InvalidRequest, "rate limited")
+			}
+			return next(ctx, req)
+		}
+	})
+
+	if resp := request(t, session, "ping", `{}`); resp.Error != nil {
+		t.Fatalf("unexpected ping error: %+v", resp.Error)
+	}
+	if err := requestError(t, session, "tools/call", `{"name":"echo","arguments":{"s":"hi"}}`); err.Code != protocol.InvalidRequest || err.Message != "rate limited" {
+		t.Errorf("expected rate limit error, got %+v", err)
+	}
+
+	want := []string{"outer:ping", "inner:ping", "outer:tools/call", "inner:tools/call"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("expected middleware order %v, got %v", want, order)
+	}
+}
+
+func TestServerMiddleware(t *testing.T) {
+	srv := NewServer("test")
+	session := newTestSession(t, srv)
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+				order = append(order, name+":"+req.Method)
+				return next(ctx, req)
+			}
+		}
+	}
+	session.Use(trace("session"))
+	srv.Use(trace("server"))
+
+	if resp := request(t, session, "ping", `{}`); resp.Error != nil {
+		t.Fatalf("unexpected ping error: %+v", resp.Error)
+	}
+
+	want := []string{"server:ping", "session:ping"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("expected middleware order %v, got %v", want, order)
+	}
+}
+
+func TestServerHooks(t *testing.T) {
+	var events []string
+	srv := NewServer("test", WithHooks(Hooks{
+		OnInitialize: func(session *Session, clientInfo protocol.Implementation, caps protocol.ClientCapabilities) error {
+			events = append(events, "initialize:"+clientInfo.Name)
+			return nil
+		},
+		OnInitialized: func(session *Session) {
+			events = append(events, "initialized")
+		},
+		BeforeToolCall: func(ctx context.Context, name string, args map[string]interface{}) error {
+			events = append(events, "before:"+name)
+			if name == "forbidden" {
+				return protocol.NewError(protocol.InvalidRequest, "not allowed")
+			}
+			return nil
+		},
+		AfterToolCall: func(ctx context.Context, name string, args map[string]interface{}, result protocol.CallToolResult, err error) {
+			events = append(events, "after:"+name)
+		},
+		OnError: func(session *Session, req *protocol.JSONRPCRequest, err *protocol.ErrorData) {
+			events = append(events, "error:"+req.Method)
+		},
+	}))
+	srv.AddTool("echo", func(s string) string { return s }, "")
+	srv.AddTool("forbidden", func() string { return "secret" }, "")
+
+	session := newTestSession(t, srv)
+	if err := session.HandleNotification(&protocol.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  "notifications/initialized",
+		Params:  json.RawMessage(`{}`),
+	}); err != nil {
+		t.Fatalf("initialized failed: %v", err)
+	}
+
+	callTool(t, session, `{"name":"echo","arguments":{"arg0":"hi"}}`)
+	if err := requestError(t, session, "tools/call", `{"name":"forbidden","arguments":{}}`); err.Message != "not allowed" {
+		t.Errorf("expected vetoed tool call, got %+v", err)
+	}
+
+	want := []string{"initialize:test", "initialized", "before:echo", "after:echo", "before:forbidden", "error:tools/call"}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("expected events %v, got %v", want, events)
+	}
+
+	// OnInitialize can reject clients
+	srv = NewServer("test", WithHooks(Hooks{
+		OnInitialize: func(session *Session, clientInfo protocol.Implementation, caps protocol.ClientCapabilities) error {
+			return protocol.NewError(protocol.InvalidRequest, "unknown client")
+		},
+	}))
+	session = NewSession(context.Background(), srv)
+	if err := requestError(t, session, "initialize", `{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}`); err.Message != "unknown client" {
+		t.Errorf("expected rejected initialize, got %+v", err)
+	}
+	if session.Info().Initialized {
+		t.Error("expected session to stay uninitialized")
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	type Login struct {
+		User string `json:"user"`
+		PIN  string `json:"pin" sensitive:"true"`
+	}
+	srv := NewServer("test")
+	AddTypedTool(srv, "login", func(ctx context.Context, l Login) (string, error) { return l.User, nil }, "")
+	var buf bytes.Buffer
+	srv.Use(LoggingMiddleware(slog.New(slog.NewJSONHandler(&buf, nil))))
+	session := NewSession(context.Background(), srv)
+	request(t, session, "initialize", `{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}`)
+
+	buf.Reset()
+	callTool(t, session, `{"name":"login","arguments":{"user":"ada","pin":"1234","extra":{"apiToken":"s3cret"}}}`)
+	var entry struct {
+		Level     string                 `json:"level"`
+		Method    string                 `json:"method"`
+		Tool      string                 `json:"tool"`
+		Session   string                 `json:"session"`
+		Outcome   string                 `json:"outcome"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log entry %q: %v", buf.String(), err)
+	}
+	if entry.Method != "tools/call" || entry.Tool != "login" || entry.Outcome != "ok" || entry.Session != session.ID() {
+		t.Errorf("unexpected log entry %s", buf.String())
+	}
+	want := map[string]interface{}{"user": "ada", "pin": Redacted, "extra": map[string]interface{}{"apiToken": Redacted}}
+	if !reflect.DeepEqual(entry.Arguments, want) {
+		t.Errorf("expected arguments %v, got %v", want, entry.Arguments)
+	}
+
+	buf.Reset()
+	session.HandleRequest(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(9), Method: "bogus/method"})
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log entry %q: %v", buf.String(), err)
+	}
+	if entry.Level != "WARN" || entry.Outcome != "error" {
+		t.Errorf("expected a warning for the failed request, got %s", buf.String())
+	}
+
+	// Cancelled requests are logged at info, other errors at error level
+	// with the error
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	tests := []struct {
+		err     error
+		level   string
+		outcome string
+		message string
+	}{
+		{fmt.Errorf("ping: %w", ErrRequestCancelled), "INFO", "cancelled", ""},
+		{errors.New("connection reset"), "ERROR", "failed", "connection reset"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		handler := LoggingMiddleware(logger)(func(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+			return nil, tt.err
+		})
+		handler(context.Background(), &protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(10), Method: "ping"})
+		var entry struct {
+			Level   string `json:"level"`
+			Outcome string `json:"outcome"`
+			Error   string `json:"error"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("failed to decode log entry %q: %v", buf.String(), err)
+		}
+		if entry.Level != tt.level || entry.Outcome != tt.outcome || entry.Error != tt.message {
+			t.Errorf("%v: expected %s %s %q, got %s", tt.err, tt.level, tt.outcome, tt.message, buf.String())
+		}
+	}
+}
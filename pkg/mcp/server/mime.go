@@ -0,0 +1,31 @@
+package server
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// detectMimeType determines the MIME type of resource contents, first by
+// the extension of the URI path and then by sniffing the content itself
+func detectMimeType(uri string, data []byte) string {
+	p := uri
+	if parsed, err := url.Parse(uri); err == nil {
+		p = parsed.Host + parsed.Path
+		if parsed.Opaque != "" {
+			p = parsed.Opaque
+		}
+	}
+
+	if ext := path.Ext(p); ext != "" {
+		if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+			return mimeType
+		}
+	}
+
+	if len(data) == 0 {
+		return ""
+	}
+	return http.DetectContentType(data)
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mount adds the tools, resources and prompts of another server under a
+// prefix, so separately developed modules can be served from one endpoint.
+// Tools and prompts are named prefix_name, and resource URIs get the prefix
+// as the first path segment: file://{path} mounted under "fs" becomes
+// file://fs/{path}. An empty prefix keeps the original names.
+//
+// Mount copies what other has registered when it is called; later changes
+// to other are not reflected. Handlers run with the session of this server,
+// through its interceptors and hooks. Nothing is mounted if any name is
+// already taken.
+func (s *Server) Mount(prefix string, other *Server) error {
+	if other == s {
+		return fmt.Errorf("cannot mount a server on itself")
+	}
+
+	tools, resources, prompts, err := other.mounted(prefix)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for name := range tools {
+		if _, exists := s.tools[name]; exists {
+			s.mu.Unlock()
+			return fmt.Errorf("tool %s already exists", name)
+		}
+	}
+	for pattern := range resources {
+		if _, exists := s.resources[pattern]; exists {
+			s.mu.Unlock()
+			return fmt.Errorf("resource %s already exists", pattern)
+		}
+	}
+	for name := range prompts {
+		if _, exists := s.prompts[name]; exists {
+			s.mu.Unlock()
+			return fmt.Errorf("prompt %s already exists", name)
+		}
+	}
+
+	for name, tool := range tools {
+		s.tools[name] = tool
+	}
+	for pattern, resource := range resources {
+		s.resources[pattern] = resource
+	}
+	s.indexPatterns()
+	for name, prompt := range prompts {
+		s.prompts[name] = prompt
+	}
+	s.mu.Unlock()
+
+	if len(tools) > 0 {
+		s.notifyToolsChanged()
+	}
+	if len(resources) > 0 {
+		s.notifyResourcesChanged()
+	}
+	if len(prompts) > 0 {
+		s.notifyPromptsChanged()
+	}
+	return nil
+}
+
+// mounted returns copies of the server's tools, resources and prompts
+// renamed for mounting under prefix
+func (s *Server) mounted(prefix string) (map[string]Tool, map[string]Resource, map[string]Prompt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tools := make(map[string]Tool, len(s.tools))
+	for name, tool := range s.tools {
+		tools[mountedName(prefix, name)] = tool
+	}
+
+	resources := make(map[string]Resource, len(s.resources))
+	for pattern, resource := range s.resources {
+		pattern = mountedURI(prefix, pattern)
+		compiled, err := parseResourcePattern(pattern, resource.Handler)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to mount resource %s: %w", resource.Pattern, err)
+		}
+		resource.Pattern = pattern
+		resource.compiled = compiled
+		resource.cache = newResourceCache(resource)
+		resources[pattern] = resource
+	}
+
+	prompts := make(map[string]Prompt, len(s.prompts))
+	for name, prompt := range s.prompts {
+		prompts[mountedName(prefix, name)] = prompt
+	}
+	return tools, resources, prompts, nil
+}
+
+// mountedName returns the name of a tool or prompt mounted under prefix
+func mountedName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// mountedURI returns a resource pattern with prefix as its first path
+// segment, after the scheme if it has one
+func mountedURI(prefix, pattern string) string {
+	if prefix == "" {
+		return pattern
+	}
+	if scheme, rest, ok := strings.Cut(pattern, "://"); ok {
+		return scheme + "://" + prefix + "/" + rest
+	}
+	return prefix + "/" + pattern
+}
@@ -0,0 +1,64 @@
+package server
+
+import "github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+
+// broadcast sends a notification to every initialized session accepted by
+// the filter. Sessions without a transport attached are skipped.
+func (s *Server) broadcast(method string, params interface{}, filter func(*Session) bool) error {
+	var lastErr error
+	for _, session := range s.sessions.List() {
+		session.mu.RLock()
+		ready := session.initialized && session.sender != nil
+		session.mu.RUnlock()
+
+		if !ready || (filter != nil && !filter(session)) {
+			continue
+		}
+		if err := session.SendNotification(method, params); err != nil {
+			session.logger().Warn("failed to send notification", "method", method, "error", err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// notifyToolsChanged tells clients that the tool list changed, if the
+// server advertises tools.listChanged
+func (s *Server) notifyToolsChanged() {
+	if caps := s.capabilities.Tools; caps != nil && isTrue(caps.ListChanged) {
+		s.broadcast("notifications/tools/list_changed", struct{}{}, nil)
+	}
+}
+
+// notifyResourcesChanged tells clients that the resource list changed, if
+// the server advertises resources.listChanged
+func (s *Server) notifyResourcesChanged() {
+	if caps := s.capabilities.Resources; caps != nil && isTrue(caps.ListChanged) {
+		s.broadcast("notifications/resources/list_changed", struct{}{}, nil)
+	}
+}
+
+// notifyPromptsChanged tells clients that the prompt list changed, if the
+// server advertises prompts.listChanged
+func (s *Server) notifyPromptsChanged() {
+	if caps := s.capabilities.Prompts; caps != nil && isTrue(caps.ListChanged) {
+		s.broadcast("notifications/prompts/list_changed", struct{}{}, nil)
+	}
+}
+
+// NotifyResourceUpdated drops any cached contents of the given resource URI
+// and sends a notifications/resources/updated message to every session
+// subscribed to it
+func (s *Server) NotifyResourceUpdated(uri string) error {
+	s.invalidateResourceCache(uri)
+
+	params := protocol.ResourceUpdatedNotificationParams{URI: uri}
+	return s.broadcast("notifications/resources/updated", params, func(session *Session) bool {
+		return session.Subscribed(uri)
+	})
+}
+
+// isTrue reports whether an optional bool is set and true
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
@@ -1,10 +1,32 @@
 package server
 
-import "github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+import (
+	"io"
+	"log/slog"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
 
 // ServerOption configures a Server
 type ServerOption func(*Server)
 
+// WithLogger sets the logger for server and session events, which defaults
+// to a JSON logger on stderr so stdout stays free for stdio framing. A nil
+// logger discards them.
+func WithLogger(logger *slog.Logger) ServerOption {
+	return func(s *Server) {
+		if logger == nil {
+			logger = slog.New(slog.NewJSONHandler(io.Discard, nil))
+		}
+		s.logger = logger
+	}
+}
+
+// Logger returns the server's logger
+func (s *Server) Logger() *slog.Logger {
+	return s.logger
+}
+
 // WithCapabilities sets the server capabilities
 func WithCapabilities(caps protocol.ServerCapabilities) ServerOption {
 	return func(s *Server) {
@@ -25,7 +47,8 @@ func WithDefaultCapabilities() ServerOption {
 		Prompts: &protocol.PromptsCapability{
 			ListChanged: boolPtr(true),
 		},
-		Logging: &protocol.LoggingCapability{},
+		Logging:     &protocol.LoggingCapability{},
+		Completions: &protocol.CompletionsCapability{},
 	})
 }
 
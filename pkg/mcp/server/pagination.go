@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/base64"
+	"sort"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// WithPageSize limits list results to pages of at most size items, with a
+// cursor for fetching the next page. Zero disables pagination.
+func WithPageSize(size int) ServerOption {
+	return func(s *Server) {
+		s.pageSize = size
+	}
+}
+
+// encodeCursor creates an opaque cursor pointing after the given key
+func encodeCursor(key string) *protocol.Cursor {
+	cursor := protocol.Cursor(base64.RawURLEncoding.EncodeToString([]byte(key)))
+	return &cursor
+}
+
+// decodeCursor returns the key a cursor points after
+func decodeCursor(cursor protocol.Cursor) (string, error) {
+	key, err := base64.RawURLEncoding.DecodeString(string(cursor))
+	if err != nil {
+		return "", invalidParams("invalid cursor: %v", err)
+	}
+	return string(key), nil
+}
+
+// paginate sorts items by key and returns the page following the cursor,
+// along with the cursor of the next page when more items remain. Cursors
+// record the last key served, so they stay valid as items are added or
+// removed.
+func paginate[T any](items []T, key func(T) string, cursor *protocol.Cursor, pageSize int) ([]T, *protocol.Cursor, error) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return key(items[i]) < key(items[j])
+	})
+
+	start := 0
+	if cursor != nil {
+		after, err := decodeCursor(*cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		start = sort.Search(len(items), func(i int) bool {
+			return key(items[i]) > after
+		})
+	}
+
+	if pageSize <= 0 || len(items)-start <= pageSize {
+		return items[start:], nil, nil
+	}
+	page := items[start : start+pageSize]
+	return page, encodeCursor(key(page[len(page)-1])), nil
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+)
+
+// PluginRegisterSymbol is the function a plugin exports to add its tools,
+// resources and prompts to a server. It must be a
+// func(*server.Server) or a func(*server.Server) error.
+const PluginRegisterSymbol = "Register"
+
+// pluginSymbols looks up the symbols of an opened plugin
+type pluginSymbols interface {
+	Lookup(name string) (plugin.Symbol, error)
+}
+
+// openPlugin opens a Go plugin. Tests replace it to load plugins without
+// building them.
+var openPlugin = func(path string) (pluginSymbols, error) {
+	return plugin.Open(path)
+}
+
+// LoadPlugins opens every Go plugin (.so file) in dir, in name order, and
+// calls its Register function with the server. Plugins must be built with
+// -buildmode=plugin against the same version of this module and Go
+// toolchain as the program loading them, on a platform that supports
+// plugins. Loading stops at the first plugin that fails.
+func (s *Server) LoadPlugins(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".so" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := s.LoadPlugin(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadPlugin opens a Go plugin and calls its Register function with the
+// server
+func (s *Server) LoadPlugin(path string) error {
+	p, err := openPlugin(path)
+	if err != nil {
+		return fmt.Errorf("failed to open plugin %s: %w", path, err)
+	}
+	symbol, err := p.Lookup(PluginRegisterSymbol)
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", path, err)
+	}
+
+	switch register := symbol.(type) {
+	case func(*Server):
+		register(s)
+	case func(*Server) error:
+		if err := register(s); err != nil {
+			return fmt.Errorf("plugin %s failed to register: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("plugin %s: %s has type %T, expected func(*server.Server) error", path, PluginRegisterSymbol, symbol)
+	}
+
+	s.logger.Info("plugin loaded", "plugin", path)
+	return nil
+}
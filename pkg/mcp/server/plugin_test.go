@@ -0,0 +1,104 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"strings"
+	"testing"
+)
+
+// fakePlugin serves the symbols of a plugin without building one
+type fakePlugin map[string]plugin.Symbol
+
+func (p fakePlugin) Lookup(name string) (plugin.Symbol, error) {
+	symbol, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("symbol %s not found", name)
+	}
+	return symbol, nil
+}
+
+// fakePlugins replaces openPlugin for the test with one serving plugins
+// by file name
+func fakePlugins(t *testing.T, plugins map[string]fakePlugin) {
+	t.Helper()
+	saved := openPlugin
+	t.Cleanup(func() { openPlugin = saved })
+	openPlugin = func(path string) (pluginSymbols, error) {
+		p, ok := plugins[filepath.Base(path)]
+		if !ok {
+			return nil, errors.New("not a plugin")
+		}
+		return p, nil
+	}
+}
+
+func TestLoadPlugins(t *testing.T) {
+	var order []string
+	fakePlugins(t, map[string]fakePlugin{
+		"b.so": {PluginRegisterSymbol: func(s *Server) error {
+			order = append(order, "b")
+			return s.AddTool("b_tool", func() string { return "b" }, "From plugin b")
+		}},
+		"a.so": {PluginRegisterSymbol: func(s *Server) {
+			order = append(order, "a")
+			s.AddTool("a_tool", func() string { return "a" }, "From plugin a")
+		}},
+	})
+
+	dir := t.TempDir()
+	for _, name := range []string{"b.so", "a.so", "notes.txt"} {
+		os.WriteFile(filepath.Join(dir, name), nil, 0o644)
+	}
+	os.Mkdir(filepath.Join(dir, "sub.so"), 0o755)
+
+	srv := NewServer("plugins", WithLogger(discardLogger))
+	if err := srv.LoadPlugins(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(order, ","); got != "a,b" {
+		t.Errorf("expected plugins to load in name order, got %s", got)
+	}
+	for _, name := range []string{"a_tool", "b_tool"} {
+		if _, ok := srv.tools[name]; !ok {
+			t.Errorf("expected %s to be registered", name)
+		}
+	}
+
+	if err := srv.LoadPlugins(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+func TestLoadPluginErrors(t *testing.T) {
+	fakePlugins(t, map[string]fakePlugin{
+		"nosymbol.so":  {},
+		"wrongtype.so": {PluginRegisterSymbol: func() {}},
+		"failing.so": {PluginRegisterSymbol: func(s *Server) error {
+			return errors.New("missing credentials")
+		}},
+	})
+
+	srv := NewServer("plugins", WithLogger(discardLogger))
+	tests := map[string]string{
+		"broken.so":    "failed to open plugin",
+		"nosymbol.so":  "symbol Register not found",
+		"wrongtype.so": "expected func(*server.Server) error",
+		"failing.so":   "failed to register: missing credentials",
+	}
+	for name, want := range tests {
+		if err := srv.LoadPlugin(name); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected an error containing %q, got %v", name, want, err)
+		}
+	}
+
+	// Loading stops at the first failing plugin
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "failing.so"), nil, 0o644)
+	if err := srv.LoadPlugins(dir); err == nil {
+		t.Error("expected the failing plugin's error")
+	}
+}
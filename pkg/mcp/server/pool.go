@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// QueueFullPolicy determines what happens when a job is submitted to a
+// worker pool whose queue is full
+type QueueFullPolicy int
+
+const (
+	// QueueFullBlock waits for queue space until the request is cancelled
+	QueueFullBlock QueueFullPolicy = iota
+
+	// QueueFullReject fails the submission immediately with ErrPoolFull
+	QueueFullReject
+)
+
+// ErrPoolFull is returned when a tool call is rejected because the worker
+// pool queue is full
+var ErrPoolFull = errors.New("server busy: tool execution queue is full")
+
+// workerPool runs jobs on a bounded number of goroutines
+type workerPool struct {
+	size   int
+	policy QueueFullPolicy
+	slots  chan struct{}
+	jobs   chan func()
+	once   sync.Once
+}
+
+// WithWorkerPool bounds tool execution to size concurrent workers with a
+// queue of queueLength pending calls. The policy decides whether calls
+// arriving at a full queue block or are rejected. Without a worker pool
+// every tool call runs on its own goroutine.
+func WithWorkerPool(size, queueLength int, policy QueueFullPolicy) ServerOption {
+	return func(s *Server) {
+		if size < 1 {
+			size = 1
+		}
+		if queueLength < 0 {
+			queueLength = 0
+		}
+		s.pool = &workerPool{
+			size:   size,
+			policy: policy,
+			slots:  make(chan struct{}, size+queueLength),
+			jobs:   make(chan func(), size+queueLength),
+		}
+	}
+}
+
+// submit queues a job for execution by the pool. Each job holds a slot
+// from submission until it finishes, so at most size jobs run and
+// queueLength jobs wait at any time.
+func (p *workerPool) submit(ctx context.Context, job func()) error {
+	p.once.Do(p.start)
+
+	if p.policy == QueueFullReject {
+		select {
+		case p.slots <- struct{}{}:
+		default:
+			return ErrPoolFull
+		}
+	} else {
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	p.jobs <- job
+	return nil
+}
+
+// start launches the pool workers
+func (p *workerPool) start() {
+	for i := 0; i < p.size; i++ {
+		go func() {
+			for job := range p.jobs {
+				job()
+				<-p.slots
+			}
+		}()
+	}
+}
+
+// runTool runs a tool job on the worker pool if one is configured and
+// waits for it to finish
+func (s *Server) runTool(ctx context.Context, job func()) error {
+	if s.pool == nil {
+		job()
+		return nil
+	}
+
+	done := make(chan struct{})
+	if err := s.pool.submit(ctx, func() {
+		defer close(done)
+		job()
+	}); err != nil {
+		return err
+	}
+	<-done
+	return nil
+}
+
+// goTool runs a tool job in the background, on the worker pool if one is
+// configured
+func (s *Server) goTool(ctx context.Context, job func()) error {
+	if s.pool == nil {
+		go job()
+		return nil
+	}
+	return s.pool.submit(ctx, job)
+}
@@ -2,8 +2,11 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"text/template"
 
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
@@ -15,93 +18,241 @@ type promptTemplate struct {
 	description string
 	arguments   []protocol.PromptArgument
 	template    *template.Template
+
+	// argsType is the struct parameter type for handlers that take their
+	// arguments as a struct, with fieldIndexes mapping each argument to
+	// its struct field
+	argsType     reflect.Type
+	fieldIndexes []int
+}
+
+// definition describes a prompt
+func (p Prompt) definition(name string) protocol.Prompt {
+	return protocol.Prompt{
+		Name:        name,
+		Description: p.Description,
+		Arguments:   p.compiled.arguments,
+	}
 }
 
-// parsePromptTemplate parses a prompt handler into a template
-func parsePromptTemplate(name string, handler interface{}, description string) (*promptTemplate, error) {
+// parsePromptTemplate parses a prompt handler into a template, naming its
+// leading positional parameters after the declared arguments
+func parsePromptTemplate(name string, handler interface{}, description string, declared []protocol.PromptArgument) (*promptTemplate, error) {
 	// Validate handler
 	handlerType := reflect.TypeOf(handler)
-	if handlerType.Kind() != reflect.Func {
+	if handlerType == nil || handlerType.Kind() != reflect.Func {
 		return nil, fmt.Errorf("handler must be a function")
 	}
 
+	prompt := &promptTemplate{
+		handler:     handler,
+		description: description,
+	}
+
+	// Handlers taking the raw arguments only have the declared ones
+	if _, ok := handler.(PromptHandlerFunc); ok {
+		prompt.arguments = declared
+		return prompt, nil
+	}
+
+	// A single struct parameter declares the arguments through its fields
+	if handlerType.NumIn() == 1 && handlerType.In(0).Kind() == reflect.Struct {
+		if len(declared) > 0 {
+			return nil, fmt.Errorf("prompt %s takes a struct parameter and cannot declare arguments", name)
+		}
+		prompt.argsType = handlerType.In(0)
+		prompt.arguments, prompt.fieldIndexes = structPromptArguments(prompt.argsType)
+		return prompt, nil
+	}
+
+	if len(declared) > handlerType.NumIn() {
+		return nil, fmt.Errorf("prompt %s declares %d arguments but its handler takes %d", name, len(declared), handlerType.NumIn())
+	}
+
 	// Extract argument information
-	var arguments []protocol.PromptArgument
-	for i := 0; i < handlerType.NumIn(); i++ {
+	prompt.arguments = append(prompt.arguments, declared...)
+	for i := len(declared); i < handlerType.NumIn(); i++ {
 		paramType := handlerType.In(i)
 		required := true
 
-		arguments = append(arguments, protocol.PromptArgument{
-			Name:        fmt.Sprintf("arg%d", i),
+		prompt.arguments = append(prompt.arguments, protocol.PromptArgument{
+			Name:        argName(i),
 			Description: fmt.Sprintf("Argument of type %v", paramType),
 			Required:    &required,
 		})
 	}
 
-	return &promptTemplate{
-		handler:     handler,
-		description: description,
-		arguments:   arguments,
-	}, nil
+	return prompt, nil
+}
+
+// structPromptArguments derives prompt arguments from the exported fields
+// of a struct. Names come from the json tag or the field name, descriptions
+// from the description tag, and fields tagged omitempty are optional.
+func structPromptArguments(argsType reflect.Type) ([]protocol.PromptArgument, []int) {
+	var arguments []protocol.PromptArgument
+	var fieldIndexes []int
+	for i := 0; i < argsType.NumField(); i++ {
+		field := argsType.Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		required := !slices.Contains(strings.Split(opts, ","), "omitempty")
+
+		description := field.Tag.Get("description")
+		if description == "" {
+			description = fmt.Sprintf("Argument of type %v", field.Type)
+		}
+
+		arguments = append(arguments, protocol.PromptArgument{
+			Name:        name,
+			Description: description,
+			Required:    &required,
+		})
+		fieldIndexes = append(fieldIndexes, i)
+	}
+	return arguments, fieldIndexes
+}
+
+// structPromptArgs builds the struct parameter of a prompt handler from
+// the named prompt arguments
+func structPromptArgs(prompt *promptTemplate, args map[string]string) (reflect.Value, error) {
+	value := reflect.New(prompt.argsType).Elem()
+	for i, argument := range prompt.arguments {
+		argValue, ok := args[argument.Name]
+		if !ok {
+			if isTrue(argument.Required) {
+				return reflect.Value{}, invalidParams("missing argument: %s", argument.Name)
+			}
+			continue
+		}
+
+		field := value.Field(prompt.fieldIndexes[i])
+		if err := convertValue(argValue, field.Addr().Interface()); err != nil {
+			return reflect.Value{}, invalidParams("invalid argument %s: %v", argument.Name, err)
+		}
+	}
+	return value, nil
 }
 
-// renderPrompt renders a prompt with the given arguments
-func (s *Server) renderPrompt(prompt Prompt, args map[string]string) ([]protocol.PromptMessage, error) {
+// renderPrompt renders a prompt with the given arguments. Handlers return a
+// string, a single content block, a []protocol.PromptMessage or a full
+// protocol.GetPromptResult, optionally followed by an error.
+func (s *Server) renderPrompt(ctx context.Context, prompt Prompt, args map[string]string) (protocol.GetPromptResult, error) {
+	if prompt.compiled.template != nil {
+		return renderTemplatePrompt(prompt, args)
+	}
+	if handler, ok := prompt.Handler.(PromptHandlerFunc); ok {
+		result, err := handler(ctx, args)
+		if err != nil {
+			return protocol.GetPromptResult{}, err
+		}
+		return promptResult(result, prompt.Description), nil
+	}
+
 	// Convert arguments to reflect.Values
 	handlerType := reflect.TypeOf(prompt.Handler)
 	handlerArgs := make([]reflect.Value, handlerType.NumIn())
 
-	for i := 0; i < handlerType.NumIn(); i++ {
-		paramType := handlerType.In(i)
-		argName := fmt.Sprintf("arg%d", i)
+	if prompt.compiled.argsType != nil {
+		argsValue, err := structPromptArgs(prompt.compiled, args)
+		if err != nil {
+			return protocol.GetPromptResult{}, err
+		}
+		handlerArgs[0] = argsValue
+	} else {
+		for i := 0; i < handlerType.NumIn(); i++ {
+			paramType := handlerType.In(i)
+			argument := prompt.compiled.arguments[i]
+			argName := argument.Name
 
-		if argValue, ok := args[argName]; ok {
-			// Create a new value of the parameter type
-			paramValue := reflect.New(paramType).Interface()
-			if err := convertValue(argValue, paramValue); err != nil {
-				return nil, fmt.Errorf("invalid argument %s: %w", argName, err)
+			if argValue, ok := args[argName]; ok {
+				// Create a new value of the parameter type
+				paramValue := reflect.New(paramType).Interface()
+				if err := convertValue(argValue, paramValue); err != nil {
+					return protocol.GetPromptResult{}, invalidParams("invalid argument %s: %v", argName, err)
+				}
+				handlerArgs[i] = reflect.ValueOf(paramValue).Elem()
+			} else if isTrue(argument.Required) {
+				return protocol.GetPromptResult{}, invalidParams("missing argument: %s", argName)
+			} else {
+				handlerArgs[i] = reflect.Zero(paramType)
 			}
-			handlerArgs[i] = reflect.ValueOf(paramValue).Elem()
-		} else {
-			handlerArgs[i] = reflect.Zero(paramType)
 		}
 	}
 
 	// Call the handler
 	results := reflect.ValueOf(prompt.Handler).Call(handlerArgs)
+	if len(results) == 0 {
+		return protocol.GetPromptResult{}, fmt.Errorf("prompt handler returned no value")
+	}
+	if len(results) == 2 && !results[1].IsNil() {
+		return protocol.GetPromptResult{}, results[1].Interface().(error)
+	}
 
 	// Process results
-	var messages []protocol.PromptMessage
+	result := protocol.GetPromptResult{Description: prompt.Description}
 
-	switch result := results[0].Interface().(type) {
+	switch value := results[0].Interface().(type) {
 	case string:
 		// Single message template
-		messages = []protocol.PromptMessage{
+		result.Messages = textPromptMessages(value)
+	case protocol.TextContent, protocol.ImageContent, protocol.AudioContent, protocol.ResourceLink, protocol.EmbeddedResource:
+		// Single message with a content block
+		result.Messages = []protocol.PromptMessage{
 			{
-				Role: protocol.RoleAssistant,
-				Content: protocol.TextContent{
-					Type: "text",
-					Text: result,
-				},
+				Role:    protocol.RoleUser,
+				Content: value,
 			},
 		}
 	case []protocol.PromptMessage:
 		// Multiple messages
-		messages = result
+		result.Messages = value
+	case protocol.GetPromptResult:
+		// Full result, keeping the registered description unless overridden
+		result = promptResult(value, prompt.Description)
+	case *protocol.GetPromptResult:
+		if value == nil {
+			return protocol.GetPromptResult{}, fmt.Errorf("prompt handler returned a nil result")
+		}
+		result = promptResult(*value, prompt.Description)
 	default:
-		return nil, fmt.Errorf("invalid prompt handler return type: %T", result)
+		return protocol.GetPromptResult{}, fmt.Errorf("invalid prompt handler return type: %T", value)
 	}
 
-	return messages, nil
+	return result, nil
 }
 
-// renderTemplate renders a text template with the given arguments
-func renderTemplate(tmpl string, args map[string]interface{}) (string, error) {
-	t, err := template.New("prompt").Parse(tmpl)
-	if err != nil {
-		return "", fmt.Errorf("invalid template: %w", err)
+// textPromptMessages creates the single message of a text prompt
+func textPromptMessages(text string) []protocol.PromptMessage {
+	return []protocol.PromptMessage{
+		{
+			Role: protocol.RoleAssistant,
+			Content: protocol.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
 	}
+}
 
+// promptResult fills in the registered description of a handler-built result
+func promptResult(result protocol.GetPromptResult, description string) protocol.GetPromptResult {
+	if result.Description == "" {
+		result.Description = description
+	}
+	return result
+}
+
+// renderTemplate renders a text template with the given arguments
+func renderTemplate(t *template.Template, args map[string]string) (string, error) {
 	var buf bytes.Buffer
 	if err := t.Execute(&buf, args); err != nil {
 		return "", fmt.Errorf("template execution failed: %w", err)
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+func TestStructPromptArguments(t *testing.T) {
+	type reviewArgs struct {
+		Language string `json:"language" description:"Programming language"`
+		Focus    string `json:"focus,omitempty"`
+		Lines    int
+	}
+	srv := NewServer("test")
+	if err := srv.AddPrompt("review", func(args reviewArgs) string {
+		return fmt.Sprintf("Review %d lines of %s focusing on %q", args.Lines, args.Language, args.Focus)
+	}, "Code review"); err != nil {
+		t.Fatalf("AddPrompt failed: %v", err)
+	}
+	session := newTestSession(t, srv)
+
+	prompts := request(t, session, "prompts/list", `{}`).Result.(protocol.ListPromptsResult).Prompts
+	arguments := prompts[0].Arguments
+	if len(arguments) != 3 {
+		t.Fatalf("expected 3 arguments, got %+v", arguments)
+	}
+	if arguments[0].Name != "language" || arguments[0].Description != "Programming language" || !*arguments[0].Required {
+		t.Errorf("unexpected language argument: %+v", arguments[0])
+	}
+	if arguments[1].Name != "focus" || *arguments[1].Required {
+		t.Errorf("expected optional focus argument, got %+v", arguments[1])
+	}
+	if arguments[2].Name != "Lines" {
+		t.Errorf("expected field name for untagged argument, got %+v", arguments[2])
+	}
+
+	result := request(t, session, "prompts/get", `{"name":"review","arguments":{"language":"Go","Lines":"40"}}`).Result.(protocol.GetPromptResult)
+	text := result.Messages[0].Content.(protocol.TextContent).Text
+	if text != `Review 40 lines of Go focusing on ""` {
+		t.Errorf("unexpected prompt text: %q", text)
+	}
+
+	if rpcErr := requestError(t, session, "prompts/get", `{"name":"review","arguments":{"Lines":"40"}}`); rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("expected invalid params for missing required argument, got %v", rpcErr)
+	}
+}
+
+func TestPromptArgumentOptions(t *testing.T) {
+	srv := NewServer("test")
+	if err := srv.AddPrompt("greet", func(name string, times int) string {
+		return strings.Repeat("Hello "+name+"! ", times)
+	}, "Greeting",
+		WithPromptArgument("name", "Who to greet", true),
+		WithPromptArgument("times", "How many times", false),
+	); err != nil {
+		t.Fatalf("AddPrompt failed: %v", err)
+	}
+	session := newTestSession(t, srv)
+
+	arguments := request(t, session, "prompts/list", `{}`).Result.(protocol.ListPromptsResult).Prompts[0].Arguments
+	if len(arguments) != 2 || arguments[0].Name != "name" || arguments[1].Description != "How many times" {
+		t.Errorf("unexpected arguments: %+v", arguments)
+	}
+
+	result := request(t, session, "prompts/get", `{"name":"greet","arguments":{"name":"Ada","times":"2"}}`).Result.(protocol.GetPromptResult)
+	if text := result.Messages[0].Content.(protocol.TextContent).Text; text != "Hello Ada! Hello Ada! " {
+		t.Errorf("unexpected prompt text: %q", text)
+	}
+
+	if err := srv.AddPrompt("bad", func(name string) string { return name }, "",
+		WithPromptArgument("a", "", true), WithPromptArgument("b", "", true)); err == nil {
+		t.Error("expected error declaring more arguments than parameters")
+	}
+}
+
+func TestPromptFullResult(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddPrompt("summarize", func(topic string) (*protocol.GetPromptResult, error) {
+		if topic == "" {
+			return nil, errors.New("topic is empty")
+		}
+		result := &protocol.GetPromptResult{
+			Description: "Summary of " + topic,
+			Messages: []protocol.PromptMessage{
+				{Role: protocol.RoleUser, Content: protocol.NewTextContent("Summarize " + topic)},
+			},
+		}
+		result.Meta = map[string]interface{}{"topic": topic}
+		return result, nil
+	}, "Summary prompt", WithPromptArgument("topic", "", false))
+	session := newTestSession(t, srv)
+
+	result := request(t, session, "prompts/get", `{"name":"summarize","arguments":{"topic":"Go"}}`).Result.(protocol.GetPromptResult)
+	if result.Description != "Summary of Go" || result.Meta["topic"] != "Go" || len(result.Messages) != 1 {
+		t.Errorf("unexpected prompt result: %+v", result)
+	}
+
+	requestError(t, session, "prompts/get", `{"name":"summarize","arguments":{}}`)
+}
+
+func TestLoadPromptTemplates(t *testing.T) {
+	srv := NewServer("test")
+	fsys := fstest.MapFS{
+		"prompts/review.tmpl": {Data: []byte("{{/* Review code */}}Review this {{.language}} code{{if .focus}} for {{.focus}}{{end}}.")},
+		"prompts/README.md":   {Data: []byte("not a prompt")},
+	}
+	if err := srv.LoadPromptTemplates(fsys, "prompts/*.tmpl"); err != nil {
+		t.Fatalf("LoadPromptTemplates failed: %v", err)
+	}
+	session := newTestSession(t, srv)
+
+	prompts := request(t, session, "prompts/list", `{}`).Result.(protocol.ListPromptsResult).Prompts
+	if len(prompts) != 1 || prompts[0].Name != "review" || prompts[0].Description != "Review code" {
+		t.Fatalf("unexpected prompts: %+v", prompts)
+	}
+	arguments := prompts[0].Arguments
+	if len(arguments) != 2 || arguments[0].Name != "language" || !*arguments[0].Required ||
+		arguments[1].Name != "focus" || *arguments[1].Required {
+		t.Errorf("unexpected arguments: %+v", arguments)
+	}
+
+	result := request(t, session, "prompts/get", `{"name":"review","arguments":{"language":"Go","focus":"errors"}}`).Result.(protocol.GetPromptResult)
+	if text := result.Messages[0].Content.(protocol.TextContent).Text; text != "Review this Go code for errors." {
+		t.Errorf("unexpected prompt text: %q", text)
+	}
+
+	result = request(t, session, "prompts/get", `{"name":"review","arguments":{"language":"Go"}}`).Result.(protocol.GetPromptResult)
+	if text := result.Messages[0].Content.(protocol.TextContent).Text; text != "Review this Go code." {
+		t.Errorf("unexpected prompt text without optional argument: %q", text)
+	}
+}
+
+func TestPromptEmbeddedResource(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddResource("file:///notes.txt", func() string { return "remember the milk" }, "")
+	srv.AddPrompt("recall", func() ([]protocol.PromptMessage, error) {
+		message, err := srv.ResourcePromptMessage(context.Background(), protocol.RoleUser, "file:///notes.txt")
+		if err != nil {
+			return nil, err
+		}
+		return []protocol.PromptMessage{message}, nil
+	}, "")
+	session := newTestSession(t, srv)
+
+	result := request(t, session, "prompts/get", `{"name":"recall"}`).Result.(protocol.GetPromptResult)
+	embedded, ok := result.Messages[0].Content.(protocol.EmbeddedResource)
+	if !ok {
+		t.Fatalf("expected embedded resource content, got %T", result.Messages[0].Content)
+	}
+	if text := embedded.Resource.(protocol.TextResourceContents).Text; text != "remember the milk" {
+		t.Errorf("unexpected embedded contents: %q", text)
+	}
+
+	if _, err := srv.EmbedResource(context.Background(), "file:///missing.txt"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound, got %v", err)
+	}
+}
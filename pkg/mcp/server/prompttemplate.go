@@ -0,0 +1,175 @@
+package server
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// AddPromptTemplate adds a prompt rendered from a text/template. Its
+// arguments are the fields the template references, such as {{.topic}};
+// fields used only within {{if}} actions are optional.
+func (s *Server) AddPromptTemplate(name, text, description string) error {
+	prompt, err := newTemplatePrompt(name, text, description)
+	if err != nil {
+		return err
+	}
+	return s.addPrompt(name, prompt)
+}
+
+// LoadPromptTemplates adds a template prompt for every file in fsys
+// matching the glob pattern, such as "prompts/*.tmpl". Prompts are named
+// after the file without its extension, and a leading {{/* comment */}}
+// becomes the prompt description.
+func (s *Server) LoadPromptTemplates(fsys fs.FS, pattern string) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return fmt.Errorf("invalid prompt template pattern: %w", err)
+	}
+
+	for _, file := range names {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt template %s: %w", file, err)
+		}
+
+		base := path.Base(file)
+		name := strings.TrimSuffix(base, path.Ext(base))
+		text := string(data)
+		if err := s.AddPromptTemplate(name, text, templateDescription(text)); err != nil {
+			return fmt.Errorf("failed to add prompt template %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// newTemplatePrompt parses a template into a prompt
+func newTemplatePrompt(name, text, description string) (Prompt, error) {
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return Prompt{}, fmt.Errorf("invalid template: %w", err)
+	}
+
+	var arguments []protocol.PromptArgument
+	for _, field := range templateFields(t) {
+		required := field.required
+		arguments = append(arguments, protocol.PromptArgument{
+			Name:     field.name,
+			Required: &required,
+		})
+	}
+
+	return Prompt{
+		Description: description,
+		Arguments:   arguments,
+		compiled: &promptTemplate{
+			description: description,
+			arguments:   arguments,
+			template:    t,
+		},
+	}, nil
+}
+
+// renderTemplatePrompt renders a template prompt with the given arguments
+func renderTemplatePrompt(prompt Prompt, args map[string]string) (protocol.GetPromptResult, error) {
+	for _, argument := range prompt.compiled.arguments {
+		if _, ok := args[argument.Name]; !ok && isTrue(argument.Required) {
+			return protocol.GetPromptResult{}, invalidParams("missing argument: %s", argument.Name)
+		}
+	}
+
+	text, err := renderTemplate(prompt.compiled.template, args)
+	if err != nil {
+		return protocol.GetPromptResult{}, err
+	}
+
+	return protocol.GetPromptResult{
+		Description: prompt.Description,
+		Messages:    textPromptMessages(text),
+	}, nil
+}
+
+// templateDescription returns the text of a leading {{/* comment */}}
+func templateDescription(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{{/*") {
+		return ""
+	}
+	comment, _, ok := strings.Cut(text[len("{{/*"):], "*/}}")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(comment)
+}
+
+// templateField is a top-level field referenced by a template
+type templateField struct {
+	name     string
+	required bool
+}
+
+// templateFields returns the top-level fields a template references, in
+// order of first use. Fields referenced only within {{if}} actions are
+// optional.
+func templateFields(t *template.Template) []templateField {
+	var fields []templateField
+	index := make(map[string]int)
+
+	var walk func(node parse.Node, optional bool)
+	walk = func(node parse.Node, optional bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, optional)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, optional)
+		case *parse.IfNode:
+			walk(n.Pipe, true)
+			walk(n.List, true)
+			walk(n.ElseList, true)
+		case *parse.RangeNode:
+			// Dot changes inside range and with bodies, so only their
+			// pipelines and else branches reference top-level fields
+			walk(n.Pipe, optional)
+			walk(n.ElseList, optional)
+		case *parse.WithNode:
+			walk(n.Pipe, optional)
+			walk(n.ElseList, optional)
+		case *parse.TemplateNode:
+			walk(n.Pipe, optional)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd, optional)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, optional)
+			}
+		case *parse.FieldNode:
+			name := n.Ident[0]
+			if i, ok := index[name]; ok {
+				fields[i].required = fields[i].required || !optional
+				return
+			}
+			index[name] = len(fields)
+			fields = append(fields, templateField{name: name, required: !optional})
+		}
+	}
+
+	if t.Tree != nil {
+		walk(t.Tree.Root, false)
+	}
+	return fields
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// ErrResourceNotFound is returned when no resource matches a URI.
+// Resource providers return it from Read for URIs they don't serve.
+var ErrResourceNotFound = errors.New("resource not found")
+
+// ResourceProvider serves a dynamic set of resources, such as database
+// rows or bucket objects, without registering each one up front
+type ResourceProvider interface {
+	// List returns the resources currently available from the provider
+	List(ctx context.Context) ([]protocol.Resource, error)
+
+	// Read returns the contents of a resource. The value is converted like
+	// a resource handler result: strings become text contents, []byte and
+	// io.Reader become blob contents, and other values are serialized as
+	// JSON. Read returns ErrResourceNotFound for URIs it doesn't serve.
+	Read(ctx context.Context, uri string) (interface{}, error)
+}
+
+// AddResourceProvider adds a provider consulted for resources/list and for
+// resources/read URIs that match no registered resource
+func (s *Server) AddResourceProvider(provider ResourceProvider) {
+	s.mu.Lock()
+	s.providers = append(s.providers, provider)
+	s.mu.Unlock()
+
+	s.notifyResourcesChanged()
+}
+
+// NotifyResourceListChanged tells clients that the resources served by a
+// provider changed, if the server advertises resources.listChanged
+func (s *Server) NotifyResourceListChanged() {
+	s.notifyResourcesChanged()
+}
+
+// listProviderResources collects the resources of every provider
+func (s *Server) listProviderResources(ctx context.Context) ([]protocol.Resource, error) {
+	s.mu.RLock()
+	providers := s.providers
+	s.mu.RUnlock()
+
+	var resources []protocol.Resource
+	for _, provider := range providers {
+		listed, err := provider.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list provider resources: %w", err)
+		}
+		resources = append(resources, listed...)
+	}
+	return resources, nil
+}
+
+// readProviderResource reads a resource from the first provider serving it
+func (s *Server) readProviderResource(ctx context.Context, uri string) ([]interface{}, error) {
+	s.mu.RLock()
+	providers := s.providers
+	s.mu.RUnlock()
+
+	for _, provider := range providers {
+		value, err := provider.Read(ctx, uri)
+		if errors.Is(err, ErrResourceNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return resourceContents(uri, "", value)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+}
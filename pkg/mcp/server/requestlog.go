@@ -0,0 +1,236 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// Redacted replaces the values of redacted arguments in request logs
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedFields are the argument name patterns redacted by
+// LoggingMiddleware, unless replaced with WithRedactedFields, and by
+// AuditInterceptor
+var DefaultRedactedFields = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*token*",
+	"*apikey*",
+	"*api_key*",
+	"*credential*",
+	"*private_key*",
+	"authorization",
+}
+
+// LoggingOption configures LoggingMiddleware
+type LoggingOption func(*loggingConfig)
+
+// loggingConfig is the configuration of LoggingMiddleware
+type loggingConfig struct {
+	patterns  []string
+	arguments bool
+}
+
+// WithRedactedFields sets the patterns of argument names whose values are
+// redacted, replacing DefaultRedactedFields. Patterns use path.Match syntax
+// and match case-insensitively, at any depth of the arguments.
+func WithRedactedFields(patterns ...string) LoggingOption {
+	return func(c *loggingConfig) {
+		c.patterns = patterns
+	}
+}
+
+// WithoutArguments stops LoggingMiddleware from logging tool and prompt
+// arguments at all
+func WithoutArguments() LoggingOption {
+	return func(c *loggingConfig) {
+		c.arguments = false
+	}
+}
+
+// WithSensitiveArguments marks arguments of a tool whose values are
+// redacted from request logs, whatever their name. Typed tools mark the
+// fields of their arguments tagged `sensitive:"true"`.
+func WithSensitiveArguments(names ...string) ToolOption {
+	return func(t *Tool) {
+		t.SensitiveArguments = append(t.SensitiveArguments, names...)
+	}
+}
+
+// LoggingMiddleware returns middleware logging every request with its
+// method, duration and outcome, and the name and arguments of tool calls
+// and prompts. Argument values are redacted when their name matches one of
+// the redacted field patterns or the tool marks them as sensitive.
+// Requests are logged at info level, cancelled ones included, those
+// answered with an error at warn level, and those that failed without a
+// response at error level. A nil logger logs to the server's logger.
+func LoggingMiddleware(logger *slog.Logger, opts ...LoggingOption) Middleware {
+	config := loggingConfig{patterns: DefaultRedactedFields, arguments: true}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+
+			session := SessionFromContext(ctx)
+			log := logger
+			if log == nil {
+				log = slog.Default()
+				if session != nil {
+					log = session.server.logger
+				}
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("id", req.ID.String()),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if session != nil {
+				attrs = append(attrs, slog.String("session", session.ID()))
+			}
+			attrs = append(attrs, config.paramAttrs(session, req)...)
+
+			level := slog.LevelInfo
+			switch {
+			case errors.Is(err, ErrRequestCancelled):
+				attrs = append(attrs, slog.String("outcome", "cancelled"))
+			case err != nil:
+				level = slog.LevelError
+				attrs = append(attrs,
+					slog.String("outcome", "failed"),
+					slog.String("error", err.Error()),
+				)
+			case resp != nil && resp.Error != nil:
+				level = slog.LevelWarn
+				attrs = append(attrs,
+					slog.String("outcome", "error"),
+					slog.Int("code", resp.Error.Code),
+					slog.String("error", resp.Error.Message),
+				)
+			default:
+				attrs = append(attrs, slog.String("outcome", "ok"))
+			}
+			log.LogAttrs(ctx, level, "request", attrs...)
+			return resp, err
+		}
+	}
+}
+
+// paramAttrs returns the log attributes of the name and redacted arguments
+// of tool calls and prompts
+func (c loggingConfig) paramAttrs(session *Session, req *protocol.JSONRPCRequest) []slog.Attr {
+	if req.Method != "tools/call" && req.Method != "prompts/get" {
+		return nil
+	}
+	var params struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := decodeParams(req.Params, &params); err != nil {
+		return nil
+	}
+
+	key := "tool"
+	var sensitive []string
+	if req.Method == "prompts/get" {
+		key = "prompt"
+	} else if session != nil {
+		sensitive = session.server.sensitiveArguments(params.Name)
+	}
+
+	attrs := []slog.Attr{slog.String(key, params.Name)}
+	if c.arguments && params.Arguments != nil {
+		redacted := c.redact(params.Arguments, sensitive)
+		attrs = append(attrs, slog.Any("arguments", redacted))
+	}
+	return attrs
+}
+
+// sensitiveArguments returns the arguments a tool marks as sensitive
+func (s *Server) sensitiveArguments(tool string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tools[tool].SensitiveArguments
+}
+
+// redact returns a copy of value with the values of redacted fields
+// replaced, recursing into objects and arrays
+func (c loggingConfig) redact(value interface{}, sensitive []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for name, field := range v {
+			if c.isRedacted(name, sensitive) {
+				redacted[name] = Redacted
+			} else {
+				redacted[name] = c.redact(field, sensitive)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = c.redact(item, sensitive)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// isRedacted reports whether the value of the named field is redacted
+func (c loggingConfig) isRedacted(name string, sensitive []string) bool {
+	for _, s := range sensitive {
+		if s == name {
+			return true
+		}
+	}
+	lower := strings.ToLower(name)
+	for _, pattern := range c.patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), lower); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveFields returns the JSON names of the fields of t, including
+// those promoted from embedded structs, and of the structs it contains,
+// tagged `sensitive:"true"`
+func sensitiveFields(t reflect.Type, visiting map[reflect.Type]bool) []string {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return sensitiveFields(t.Elem(), visiting)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var names []string
+	for _, field := range jsonFields(t) {
+		if field.Tag.Get("sensitive") == "true" {
+			names = append(names, field.name)
+			continue
+		}
+		names = append(names, sensitiveFields(field.Type, visiting)...)
+	}
+	return names
+}
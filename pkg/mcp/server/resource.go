@@ -1,140 +1,247 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"reflect"
-	"regexp"
-	"strings"
+	"sort"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
 )
 
 // resourcePattern represents a parsed resource pattern
 type resourcePattern struct {
 	pattern     string
-	regex       *regexp.Regexp
+	template    *uriTemplate
 	paramNames  []string
 	paramTypes  []reflect.Type
 	handlerType reflect.Type
 }
 
-// parseResourcePattern parses a resource pattern into a regex and parameter info
+// isTemplate reports whether the pattern has template variables, making
+// it a resource template rather than a concrete resource
+func (p *resourcePattern) isTemplate() bool {
+	return len(p.paramNames) > 0
+}
+
+// parseResourcePattern parses an RFC 6570 resource pattern and matches its
+// variables, in order of appearance, to the handler parameters
 func parseResourcePattern(pattern string, handler interface{}) (*resourcePattern, error) {
 	// Validate handler
 	handlerType := reflect.TypeOf(handler)
-	if handlerType.Kind() != reflect.Func {
+	if handlerType == nil || handlerType.Kind() != reflect.Func {
 		return nil, fmt.Errorf("handler must be a function")
 	}
-
-	// Extract parameter names and build regex
-	var paramNames []string
-	var paramTypes []reflect.Type
-	regexStr := pattern
-
-	// Find all {param} in pattern
-	paramRegex := regexp.MustCompile(`\{([^}]+)\}`)
-	matches := paramRegex.FindAllStringSubmatch(pattern, -1)
-
-	for i, match := range matches {
-		paramName := match[1]
-		paramNames = append(paramNames, paramName)
-
-		// Get parameter type from handler
-		if i >= handlerType.NumIn() {
-			return nil, fmt.Errorf("not enough parameters in handler for pattern %s", pattern)
-		}
-		paramTypes = append(paramTypes, handlerType.In(i))
-
-		// Replace {param} with regex capture group
-		regexStr = strings.Replace(regexStr, match[0], `([^/]+)`, 1)
+	switch {
+	case handlerType.NumOut() == 0 || handlerType.NumOut() > 2:
+		return nil, fmt.Errorf("resource handler must return a value, optionally followed by an error")
+	case handlerType.Out(0) == errorType:
+		return nil, fmt.Errorf("resource handler must return a value before its error")
+	case handlerType.NumOut() == 2 && handlerType.Out(1) != errorType:
+		return nil, fmt.Errorf("second return value of a resource handler must be error")
 	}
 
-	// Compile the regex
-	regex, err := regexp.Compile("^" + regexStr + "$")
+	template, err := parseURITemplate(pattern)
 	if err != nil {
-		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		return nil, err
+	}
+
+	// Get parameter types from handler
+	paramNames := template.Variables()
+	if len(paramNames) > handlerType.NumIn() {
+		return nil, fmt.Errorf("not enough parameters in handler for pattern %s", pattern)
+	}
+	paramTypes := make([]reflect.Type, len(paramNames))
+	for i := range paramNames {
+		paramTypes[i] = handlerType.In(i)
 	}
 
 	return &resourcePattern{
 		pattern:     pattern,
-		regex:       regex,
+		template:    template,
 		paramNames:  paramNames,
 		paramTypes:  paramTypes,
 		handlerType: handlerType,
 	}, nil
 }
 
-// matchResource finds a matching resource and extracts parameters
-func (s *Server) matchResource(uri string) (Resource, map[string]interface{}, error) {
+// displayName returns the resource name, defaulting to its pattern
+func (r Resource) displayName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Pattern
+}
+
+// definition describes a resource with a fixed URI
+func (r Resource) definition() protocol.Resource {
+	return protocol.Resource{
+		URI:         r.Pattern,
+		Name:        r.displayName(),
+		Description: r.Description,
+		MimeType:    r.MimeType,
+		Size:        r.Size,
+		Annotations: r.Annotations,
+	}
+}
+
+// templateDefinition describes a resource whose pattern is a template
+func (r Resource) templateDefinition() protocol.ResourceTemplate {
+	return protocol.ResourceTemplate{
+		URITemplate: r.Pattern,
+		Name:        r.displayName(),
+		Description: r.Description,
+		MimeType:    r.MimeType,
+		Annotations: r.Annotations,
+	}
+}
+
+// indexPatterns orders the resource patterns for matching URIs against,
+// most specific first. s.mu must be held for writing.
+func (s *Server) indexPatterns() {
+	s.patterns = s.patterns[:0]
+	for pattern := range s.resources {
+		s.patterns = append(s.patterns, pattern)
+	}
+	sort.Slice(s.patterns, func(i, j int) bool {
+		return s.resources[s.patterns[i]].compiled.template.moreSpecific(s.resources[s.patterns[j]].compiled.template)
+	})
+}
+
+// matchResource finds a matching resource and extracts its handler arguments
+func (s *Server) matchResource(uri string) (Resource, []interface{}, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	parsedURI, err := url.Parse(uri)
-	if err != nil {
-		return Resource{}, nil, fmt.Errorf("invalid URI: %w", err)
+	// Static resources are found by their URI without parsing it or trying
+	// every pattern
+	if resource, ok := s.resources[uri]; ok && !resource.compiled.isTemplate() {
+		return resource, nil, nil
 	}
 
-	// Try to match each resource pattern
-	for pattern, resource := range s.resources {
-		resourcePattern, err := parseResourcePattern(pattern, resource.Handler)
-		if err != nil {
-			continue
-		}
+	if _, err := url.Parse(uri); err != nil {
+		return Resource{}, nil, invalidParams("invalid URI: %v", err)
+	}
 
-		matches := resourcePattern.regex.FindStringSubmatch(parsedURI.Path)
-		if matches == nil {
+	// Try each precompiled pattern, most specific first
+	for _, pattern := range s.patterns {
+		resource := s.resources[pattern]
+		resourcePattern := resource.compiled
+		values, ok := resourcePattern.template.Match(uri)
+		if !ok {
 			continue
 		}
 
-		// Extract parameters
-		params := make(map[string]interface{})
+		// Extract parameters in handler order, leaving absent optional
+		// query parameters at their zero value
+		params := make([]interface{}, len(resourcePattern.paramNames))
 		for i, name := range resourcePattern.paramNames {
-			// Convert parameter value to the correct type
 			paramValue := reflect.New(resourcePattern.paramTypes[i]).Interface()
-			if err := convertValue(matches[i+1], paramValue); err != nil {
-				return Resource{}, nil, fmt.Errorf("invalid parameter %s: %w", name, err)
+			if value, ok := values[name]; ok {
+				// Convert parameter value to the correct type
+				if err := convertValue(value, paramValue); err != nil {
+					return Resource{}, nil, invalidParams("invalid parameter %s: %v", name, err)
+				}
 			}
-			params[name] = reflect.ValueOf(paramValue).Elem().Interface()
+			params[i] = reflect.ValueOf(paramValue).Elem().Interface()
 		}
 
 		return resource, params, nil
 	}
 
-	return Resource{}, nil, fmt.Errorf("no matching resource found for %s", uri)
+	return Resource{}, nil, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+}
+
+// ReadResource reads a resource the way resources/read does, matching
+// registered resources first and then asking resource providers
+func (s *Server) ReadResource(ctx context.Context, uri string) ([]interface{}, error) {
+	var contents []interface{}
+	resource, params, err := s.matchResource(uri)
+	switch {
+	case errors.Is(err, ErrResourceNotFound):
+		contents, err = s.readProviderResource(ctx, uri)
+	case err != nil:
+		return nil, fmt.Errorf("failed to match resource: %w", err)
+	default:
+		contents, err = s.readResource(resource, uri, params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource: %w", err)
+	}
+	return contents, nil
+}
+
+// EmbedResource reads a resource and wraps its contents as an embedded
+// resource, for attaching to prompt messages and tool results
+func (s *Server) EmbedResource(ctx context.Context, uri string) (protocol.EmbeddedResource, error) {
+	contents, err := s.ReadResource(ctx, uri)
+	if err != nil {
+		return protocol.EmbeddedResource{}, err
+	}
+	if len(contents) != 1 {
+		return protocol.EmbeddedResource{}, fmt.Errorf("resource %s has %d contents, expected 1", uri, len(contents))
+	}
+	return protocol.NewEmbeddedResource(contents[0], nil), nil
 }
 
-// readResource reads data from a resource using its handler
-func (s *Server) readResource(resource Resource, params map[string]interface{}) ([]interface{}, error) {
+// ResourcePromptMessage creates a prompt message embedding the contents
+// of a resource
+func (s *Server) ResourcePromptMessage(ctx context.Context, role protocol.Role, uri string) (protocol.PromptMessage, error) {
+	resource, err := s.EmbedResource(ctx, uri)
+	if err != nil {
+		return protocol.PromptMessage{}, err
+	}
+	return protocol.PromptMessage{
+		Role:    role,
+		Content: resource,
+	}, nil
+}
+
+// readResource reads data from a resource, serving cached contents when
+// the resource is cached
+func (s *Server) readResource(resource Resource, uri string, params []interface{}) ([]interface{}, error) {
+	if resource.cache != nil {
+		return resource.cache.get(uri, func() ([]interface{}, error) {
+			return callResourceHandler(resource, uri, params)
+		})
+	}
+	return callResourceHandler(resource, uri, params)
+}
+
+// callResourceHandler reads data from a resource using its handler
+func callResourceHandler(resource Resource, uri string, params []interface{}) ([]interface{}, error) {
 	// Convert parameters to reflect.Values
 	handlerType := reflect.TypeOf(resource.Handler)
 	args := make([]reflect.Value, handlerType.NumIn())
 
 	for i := 0; i < handlerType.NumIn(); i++ {
-		paramType := handlerType.In(i)
-		paramName := fmt.Sprintf("param%d", i)
-
-		if paramValue, ok := params[paramName]; ok {
-			args[i] = reflect.ValueOf(paramValue)
+		if i < len(params) {
+			args[i] = reflect.ValueOf(params[i])
 		} else {
-			args[i] = reflect.Zero(paramType)
+			args[i] = reflect.Zero(handlerType.In(i))
 		}
 	}
 
 	// Call the handler
 	results := reflect.ValueOf(resource.Handler).Call(args)
 
-	// Process results
-	var contents []interface{}
-
-	if len(results) == 2 { // Handler returns (value, error)
-		if !results[1].IsNil() {
-			return nil, results[1].Interface().(error)
-		}
-		contents = []interface{}{results[0].Interface()}
-	} else { // Handler returns single value
-		contents = []interface{}{results[0].Interface()}
+	// Handlers return a value, optionally followed by an error, as
+	// checked by parseResourcePattern
+	if len(results) == 2 && !results[1].IsNil() {
+		return nil, results[1].Interface().(error)
 	}
+	return resourceContents(uri, resource.MimeType, results[0].Interface())
+}
 
-	return contents, nil
+// Subscribed reports whether the client subscribed to updates of a resource
+func (s *Session) Subscribed(uri string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.subscriptions[uri]
+	return ok
 }
 
 // convertValue converts a string value to the target type
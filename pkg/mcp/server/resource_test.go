@@ -0,0 +1,466 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+func TestResourceSubscriptions(t *testing.T) {
+	srv := NewServer("test")
+	subscribed := newTestSession(t, srv)
+	other := newTestSession(t, srv)
+	subscribedSender, otherSender := &fakeClient{}, &fakeClient{}
+	subscribed.SetNotificationSender(subscribedSender)
+	other.SetNotificationSender(otherSender)
+
+	request(t, subscribed, "resources/subscribe", `{"uri":"file:///log.txt"}`)
+	if err := srv.NotifyResourceUpdated("file:///log.txt"); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	if len(subscribedSender.methods) != 1 || subscribedSender.methods[0] != "notifications/resources/updated" {
+		t.Errorf("expected one update notification, got %v", subscribedSender.methods)
+	}
+	if len(otherSender.methods) != 0 {
+		t.Errorf("expected no notifications for unsubscribed session, got %v", otherSender.methods)
+	}
+
+	request(t, subscribed, "resources/unsubscribe", `{"uri":"file:///log.txt"}`)
+	srv.NotifyResourceUpdated("file:///log.txt")
+	if len(subscribedSender.methods) != 1 {
+		t.Errorf("expected no notifications after unsubscribe, got %v", subscribedSender.methods)
+	}
+}
+
+// readResource reads a resource through the session and returns its contents
+func readResource(t *testing.T, session *Session, uri string) []interface{} {
+	t.Helper()
+
+	resp := request(t, session, "resources/read", `{"uri":"`+uri+`"}`)
+	result, ok := resp.Result.(protocol.ReadResourceResult)
+	if !ok {
+		t.Fatalf("expected ReadResourceResult, got %T", resp.Result)
+	}
+	return result.Contents
+}
+
+func TestReadResourceMimeType(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddResource("docs://{name}", func(name string) string { return "hello " + name }, "")
+	srv.AddResource("notes://{id}", func(id int) string { return "# Note" }, "", WithMimeType("text/markdown"))
+	srv.AddResource("config", func() map[string]int { return map[string]int{"port": 8080} }, "")
+	session := newTestSession(t, srv)
+
+	tests := []struct {
+		uri      string
+		mimeType string
+	}{
+		{"docs://data.json", "application/json"},
+		{"docs://plain", "text/plain; charset=utf-8"},
+		{"notes://7", "text/markdown"},
+		{"config", "application/json"},
+	}
+
+	for _, tt := range tests {
+		contents := readResource(t, session, tt.uri)
+		text, ok := contents[0].(protocol.TextResourceContents)
+		if !ok {
+			t.Fatalf("%s: expected TextResourceContents, got %T", tt.uri, contents[0])
+		}
+		if text.URI != tt.uri {
+			t.Errorf("%s: expected uri %q, got %q", tt.uri, tt.uri, text.URI)
+		}
+		if text.MimeType == nil || *text.MimeType != tt.mimeType {
+			t.Errorf("%s: expected mimeType %q, got %v", tt.uri, tt.mimeType, text.MimeType)
+		}
+	}
+}
+
+func TestReadBinaryResource(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	srv := NewServer("test")
+	srv.AddResource("images://{name}", func(name string) ([]byte, error) { return png, nil }, "")
+	srv.AddResource("stream", func() io.Reader { return bytes.NewReader([]byte{0, 1, 2}) }, "")
+	session := newTestSession(t, srv)
+
+	blob, ok := readResource(t, session, "images://logo")[0].(protocol.BlobResourceContents)
+	if !ok {
+		t.Fatal("expected BlobResourceContents")
+	}
+	if *blob.MimeType != "image/png" || blob.Blob != base64.StdEncoding.EncodeToString(png) {
+		t.Errorf("unexpected blob contents: %s %s", *blob.MimeType, blob.Blob)
+	}
+
+	blob, ok = readResource(t, session, "stream")[0].(protocol.BlobResourceContents)
+	if !ok {
+		t.Fatal("expected BlobResourceContents")
+	}
+	if *blob.MimeType != "application/octet-stream" || blob.Blob != "AAEC" {
+		t.Errorf("unexpected blob contents: %s %s", *blob.MimeType, blob.Blob)
+	}
+}
+
+func TestAddResourceCompilesPattern(t *testing.T) {
+	srv := NewServer("test")
+
+	if err := srv.AddResource("users://{id}/posts/{post}", func(id string, post int) string {
+		return fmt.Sprintf("%s/%d", id, post)
+	}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := srv.AddResource("bad://{a}/{b}", func(a string) string { return a }, ""); err == nil {
+		t.Error("expected error for handler with too few parameters")
+	}
+	if err := srv.AddResource("nil", nil, ""); err == nil {
+		t.Error("expected error for nil handler")
+	}
+	for _, handler := range []interface{}{
+		func() {},
+		func() error { return nil },
+		func() (string, string) { return "", "" },
+		func() (string, string, error) { return "", "", nil },
+	} {
+		if err := srv.AddResource("bad://results", handler, ""); err == nil {
+			t.Errorf("expected error for handler %T", handler)
+		}
+	}
+
+	session := newTestSession(t, srv)
+	text := readResource(t, session, "users://ada/posts/3")[0].(protocol.TextResourceContents)
+	if text.Text != "ada/3" {
+		t.Errorf("expected %q, got %q", "ada/3", text.Text)
+	}
+}
+
+func TestOverlappingResourceTemplates(t *testing.T) {
+	srv := NewServer("test")
+	serve := func(prefix string) func(string) string {
+		return func(value string) string { return prefix + " " + value }
+	}
+	for pattern, handler := range map[string]interface{}{
+		"file:///{+path}":     serve("reserved"),
+		"file:///{path}":      serve("simple"),
+		"file:///docs/{name}": serve("docs"),
+		"search://{q}":        serve("plain"),
+		"search://{q}{?limit}": func(q string, limit int) string {
+			return fmt.Sprintf("query %s %d", q, limit)
+		},
+	} {
+		if err := srv.AddResource(pattern, handler, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The most specific matching template serves a URI, every time
+	session := newTestSession(t, srv)
+	for i := 0; i < 20; i++ {
+		for uri, want := range map[string]string{
+			"file:///a.txt":       "simple a.txt",
+			"file:///a/b.txt":     "reserved a/b.txt",
+			"file:///docs/x":      "docs x",
+			"search://go":         "plain go",
+			"search://go?limit=5": "query go 5",
+		} {
+			if text := readResource(t, session, uri)[0].(protocol.TextResourceContents).Text; text != want {
+				t.Fatalf("%s: expected %q, got %q", uri, want, text)
+			}
+		}
+	}
+
+	// Removing a template leaves the others in order
+	if err := srv.RemoveResource("file:///{path}"); err != nil {
+		t.Fatal(err)
+	}
+	if text := readResource(t, session, "file:///a.txt")[0].(protocol.TextResourceContents).Text; text != "reserved a.txt" {
+		t.Errorf("expected the reserved template to serve the URI, got %q", text)
+	}
+}
+
+func TestListResourcesMetadata(t *testing.T) {
+	priority := 0.8
+	srv := NewServer("test")
+	srv.AddResource("file:///logo.png", func() []byte { return nil }, "Company logo",
+		WithResourceName("Logo"),
+		WithMimeType("image/png"),
+		WithResourceSize(2048),
+		WithResourceAnnotations(protocol.Annotations{Audience: []protocol.Role{protocol.RoleUser}, Priority: &priority}),
+	)
+	srv.AddResource("users://{id}", func(id string) string { return id }, "User profile")
+	session := newTestSession(t, srv)
+
+	resp := request(t, session, "resources/list", `{}`)
+	resources := resp.Result.(protocol.ListResourcesResult).Resources
+	if len(resources) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(resources))
+	}
+	r := resources[0]
+	if r.Name != "Logo" || r.URI != "file:///logo.png" || r.MimeType != "image/png" || *r.Size != 2048 || *r.Annotations.Priority != 0.8 {
+		t.Errorf("unexpected resource metadata: %+v", r)
+	}
+
+	resp = request(t, session, "resources/templates/list", `{}`)
+	templates := resp.Result.(protocol.ListResourceTemplatesResult).ResourceTemplates
+	if len(templates) != 1 || templates[0].URITemplate != "users://{id}" || templates[0].Name != "users://{id}" {
+		t.Errorf("unexpected resource templates: %+v", templates)
+	}
+}
+
+func TestReplaceAndRemoveResource(t *testing.T) {
+	srv := NewServer("test", WithDefaultCapabilities())
+	srv.AddResource("config", func() string { return "v1" }, "")
+	session := newTestSession(t, srv)
+	sender := &fakeClient{}
+	session.SetNotificationSender(sender)
+	request(t, session, "resources/subscribe", `{"uri":"config"}`)
+
+	if err := srv.ReplaceResource("config", func() string { return "v2" }, ""); err != nil {
+		t.Fatalf("replace failed: %v", err)
+	}
+	if text := readResource(t, session, "config")[0].(protocol.TextResourceContents).Text; text != "v2" {
+		t.Errorf("expected replaced contents, got %q", text)
+	}
+	want := []string{"notifications/resources/list_changed", "notifications/resources/updated"}
+	if !reflect.DeepEqual(sender.methods, want) {
+		t.Errorf("expected notifications %v, got %v", want, sender.methods)
+	}
+
+	if err := srv.RemoveResource("config"); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if err := srv.RemoveResource("config"); err == nil {
+		t.Error("expected error removing missing resource")
+	}
+	if rpcErr := requestError(t, session, "resources/read", `{"uri":"config"}`); rpcErr.Code != protocol.ResourceNotFound {
+		t.Errorf("expected resource not found reading removed resource, got %v", rpcErr)
+	}
+}
+
+// mapProvider serves resources from a map of URI to contents
+type mapProvider map[string]string
+
+func (p mapProvider) List(ctx context.Context) ([]protocol.Resource, error) {
+	resources := make([]protocol.Resource, 0, len(p))
+	for uri := range p {
+		resources = append(resources, protocol.Resource{URI: uri, Name: uri})
+	}
+	return resources, nil
+}
+
+func (p mapProvider) Read(ctx context.Context, uri string) (interface{}, error) {
+	text, ok := p[uri]
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+	return text, nil
+}
+
+func TestResourceProvider(t *testing.T) {
+	srv := NewServer("test", WithDefaultCapabilities())
+	srv.AddResource("config", func() string { return "static" }, "")
+	srv.AddResourceProvider(mapProvider{"db://rows/1": "row one"})
+	session := newTestSession(t, srv)
+
+	resp := request(t, session, "resources/list", `{}`)
+	if n := len(resp.Result.(protocol.ListResourcesResult).Resources); n != 2 {
+		t.Errorf("expected 2 resources, got %d", n)
+	}
+
+	if text := readResource(t, session, "db://rows/1")[0].(protocol.TextResourceContents).Text; text != "row one" {
+		t.Errorf("expected provider contents, got %q", text)
+	}
+	if text := readResource(t, session, "config")[0].(protocol.TextResourceContents).Text; text != "static" {
+		t.Errorf("expected static contents, got %q", text)
+	}
+
+	if rpcErr := requestError(t, session, "resources/read", `{"uri":"db://rows/2"}`); rpcErr.Code != protocol.ResourceNotFound {
+		t.Errorf("expected resource not found, got %v", rpcErr)
+	}
+}
+
+func TestResourceCache(t *testing.T) {
+	srv := NewServer("test")
+	calls := 0
+	srv.AddResource("remote", func() string {
+		calls++
+		return fmt.Sprintf("v%d", calls)
+	}, "", WithResourceCache(time.Hour))
+	session := newTestSession(t, srv)
+
+	readResource(t, session, "remote")
+	if text := readResource(t, session, "remote")[0].(protocol.TextResourceContents).Text; text != "v1" {
+		t.Errorf("expected cached contents, got %q", text)
+	}
+
+	srv.NotifyResourceUpdated("remote")
+	if text := readResource(t, session, "remote")[0].(protocol.TextResourceContents).Text; text != "v2" {
+		t.Errorf("expected refreshed contents after update, got %q", text)
+	}
+}
+
+func TestResourceCacheEviction(t *testing.T) {
+	reads := map[string]int{}
+	read := func(uri string) func() ([]interface{}, error) {
+		return func() ([]interface{}, error) {
+			reads[uri]++
+			return []interface{}{uri}, nil
+		}
+	}
+
+	// The least recently read URI is evicted beyond the cache size
+	cache := newResourceCache(Resource{CacheTTL: time.Hour, CacheSize: 2})
+	cache.get("a", read("a"))
+	cache.get("b", read("b"))
+	cache.get("a", read("a"))
+	cache.get("c", read("c"))
+	if n := len(cache.entries); n != 2 {
+		t.Errorf("expected 2 cached entries, got %d", n)
+	}
+	cache.get("a", read("a"))
+	cache.get("b", read("b"))
+	if reads["a"] != 1 || reads["b"] != 2 {
+		t.Errorf("expected only b to be evicted, got reads %v", reads)
+	}
+
+	// Expired entries are dropped rather than kept until evicted
+	cache = newResourceCache(Resource{CacheTTL: time.Millisecond})
+	cache.get("a", read("a"))
+	time.Sleep(5 * time.Millisecond)
+	cache.get("b", read("b"))
+	if _, ok := cache.entries["a"]; ok || len(cache.entries) != 1 {
+		t.Errorf("expected the expired entry to be dropped, got %d entries", len(cache.entries))
+	}
+}
+
+func TestResourceCacheValidator(t *testing.T) {
+	srv := NewServer("test")
+	calls := 0
+	etag := "a"
+	srv.AddResource("remote", func() string {
+		calls++
+		return fmt.Sprintf("v%d", calls)
+	}, "", WithResourceValidator(func(uri string) (ResourceVersion, error) {
+		return ResourceVersion{ETag: etag}, nil
+	}))
+	session := newTestSession(t, srv)
+
+	readResource(t, session, "remote")
+	readResource(t, session, "remote")
+	if calls != 1 {
+		t.Errorf("expected unchanged ETag to reuse contents, got %d handler calls", calls)
+	}
+
+	etag = "b"
+	if text := readResource(t, session, "remote")[0].(protocol.TextResourceContents).Text; text != "v2" {
+		t.Errorf("expected changed ETag to reread contents, got %q", text)
+	}
+}
+
+func TestFSResources(t *testing.T) {
+	srv := NewServer("test")
+	fsys := fstest.MapFS{
+		"notes/todo.md": {Data: []byte("# Todo")},
+		"logo.png":      {Data: []byte{0x89, 'P', 'N', 'G', 0xff}},
+	}
+	if err := srv.AddFSResources("files://", fsys); err != nil {
+		t.Fatalf("AddFSResources failed: %v", err)
+	}
+	session := newTestSession(t, srv)
+
+	resp := request(t, session, "resources/list", `{}`)
+	if n := len(resp.Result.(protocol.ListResourcesResult).Resources); n != 2 {
+		t.Errorf("expected 2 resources, got %d", n)
+	}
+
+	if text := readResource(t, session, "files://notes/todo.md")[0].(protocol.TextResourceContents).Text; text != "# Todo" {
+		t.Errorf("expected file contents, got %q", text)
+	}
+	if blob, ok := readResource(t, session, "files://logo.png")[0].(protocol.BlobResourceContents); !ok || *blob.MimeType != "image/png" {
+		t.Errorf("expected PNG blob contents, got %+v", blob)
+	}
+
+	if rpcErr := requestError(t, session, "resources/read", `{"uri":"files://../secret"}`); rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("expected invalid params reading path outside the file system, got %v", rpcErr)
+	}
+}
+
+func TestFSResourcesSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "public"), []byte("public"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret"), filepath.Join(root, "link")); err != nil {
+		t.Skipf("symbolic links unsupported: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "dir")); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := NewServer("test")
+	if err := srv.AddFSResources("files://", os.DirFS(root)); err != nil {
+		t.Fatalf("AddFSResources failed: %v", err)
+	}
+	session := newTestSession(t, srv)
+
+	if text := readResource(t, session, "files://public")[0].(protocol.TextResourceContents).Text; text != "public" {
+		t.Errorf("expected file contents, got %q", text)
+	}
+	for _, uri := range []string{"files://link", "files://dir", "files://dir/secret"} {
+		if rpcErr := requestError(t, session, "resources/read", fmt.Sprintf(`{"uri":%q}`, uri)); rpcErr.Code != protocol.InvalidParams {
+			t.Errorf("expected invalid params reading %s through a symbolic link, got %v", uri, rpcErr)
+		}
+	}
+	if rpcErr := requestError(t, session, "resources/read", `{"uri":"files://missing"}`); rpcErr.Code != protocol.ResourceNotFound {
+		t.Errorf("expected resource not found, got %v", rpcErr)
+	}
+
+	resp := request(t, session, "resources/list", `{}`)
+	if resources := resp.Result.(protocol.ListResourcesResult).Resources; len(resources) != 1 {
+		t.Errorf("expected only the regular file to be listed, got %+v", resources)
+	}
+}
+
+func TestListResourcesPagination(t *testing.T) {
+	srv := NewServer("test", WithPageSize(2))
+	for _, uri := range []string{"res://c", "res://a", "res://e", "res://b", "res://d"} {
+		srv.AddResource(uri, func() string { return "" }, "")
+	}
+	session := newTestSession(t, srv)
+
+	var uris []string
+	params := `{}`
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("pagination did not terminate")
+		}
+		result := request(t, session, "resources/list", params).Result.(protocol.ListResourcesResult)
+		if len(result.Resources) > 2 {
+			t.Errorf("expected at most 2 resources per page, got %d", len(result.Resources))
+		}
+		for _, resource := range result.Resources {
+			uris = append(uris, resource.URI)
+		}
+		if result.NextCursor == nil {
+			break
+		}
+		params = fmt.Sprintf(`{"cursor":%q}`, *result.NextCursor)
+	}
+
+	want := []string{"res://a", "res://b", "res://c", "res://d", "res://e"}
+	if !reflect.DeepEqual(uris, want) {
+		t.Errorf("expected %v, got %v", want, uris)
+	}
+}
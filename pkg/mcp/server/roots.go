@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// ErrRootsNotSupported is returned by Roots when the client did not
+// advertise the roots capability
+var ErrRootsNotSupported = errors.New("client does not support roots")
+
+// Roots returns the filesystem roots the client exposes to the server.
+// The list is requested from the client once and cached until the client
+// reports that it changed.
+func (s *Session) Roots(ctx context.Context) ([]protocol.Root, error) {
+	s.mu.RLock()
+	supported := s.capabilities.Roots != nil
+	roots, cached := s.roots, s.rootsCached
+	s.mu.RUnlock()
+
+	if !supported {
+		return nil, ErrRootsNotSupported
+	}
+	if cached {
+		return roots, nil
+	}
+
+	var result protocol.ListRootsResult
+	if err := s.Request(ctx, "roots/list", struct{}{}, &result); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.roots, s.rootsCached = result.Roots, true
+	s.mu.Unlock()
+
+	return result.Roots, nil
+}
+
+// handleRootsListChanged processes notifications/roots/list_changed by
+// dropping the cached roots
+func (s *Session) handleRootsListChanged(notif *protocol.JSONRPCNotification) error {
+	s.mu.Lock()
+	s.roots, s.rootsCached = nil, false
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// ErrSamplingNotSupported is returned by CreateMessage when the client did
+// not advertise the sampling capability
+var ErrSamplingNotSupported = errors.New("client does not support sampling")
+
+// CreateMessage asks the client to sample its LLM with the given messages,
+// letting tools use the host's model without API keys of their own. The
+// client may ask the user to review the request, so it can take a while.
+func (s *Session) CreateMessage(ctx context.Context, params protocol.CreateMessageRequestParams) (protocol.CreateMessageResult, error) {
+	s.mu.RLock()
+	supported := s.capabilities.Sampling != nil
+	s.mu.RUnlock()
+
+	if !supported {
+		return protocol.CreateMessageResult{}, ErrSamplingNotSupported
+	}
+
+	var result protocol.CreateMessageResult
+	if err := s.Request(ctx, "sampling/createMessage", params, &result); err != nil {
+		return protocol.CreateMessageResult{}, err
+	}
+	return result, nil
+}
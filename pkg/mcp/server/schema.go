@@ -0,0 +1,217 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// argNames are the names of the first positional handler arguments
+var argNames = [...]string{"arg0", "arg1", "arg2", "arg3", "arg4", "arg5", "arg6", "arg7"}
+
+// argName returns the name of the positional handler argument at index i
+func argName(i int) string {
+	if i < len(argNames) {
+		return argNames[i]
+	}
+	return fmt.Sprintf("arg%d", i)
+}
+
+// handlerInputSchema derives the input schema of a tool handler from its
+// positional parameters, arg0, arg1, ..., all of which are required.
+// ToolHandlerFuncs accept any object.
+func handlerInputSchema(handler interface{}) map[string]interface{} {
+	if _, ok := handler.(ToolHandlerFunc); ok {
+		return map[string]interface{}{"type": "object"}
+	}
+	handlerType := reflect.TypeOf(handler)
+	if handlerType == nil || handlerType.Kind() != reflect.Func {
+		return nil
+	}
+
+	offset := 0
+	if handlerType.NumIn() > 0 && handlerType.In(0) == contextType {
+		offset = 1
+	}
+	properties := make(map[string]interface{})
+	required := []string{}
+	for i := offset; i < handlerType.NumIn(); i++ {
+		name := argName(i - offset)
+		properties[name] = typeSchema(handlerType.In(i), make(map[reflect.Type]bool))
+		required = append(required, name)
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// typeSchema returns the JSON schema of the values that decode into a Go
+// type. Types with custom JSON encodings and recursive types accept any
+// value.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawMessageType, t.Implements(jsonMarshalerType), reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), visiting)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return map[string]interface{}{}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		return structSchema(t, visiting)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema returns the schema of a struct from its exported fields,
+// named as encoding/json names them. Fields without omitempty are
+// required.
+func structSchema(t reflect.Type, visiting map[reflect.Type]bool) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	for _, field := range jsonFields(t) {
+		schema := typeSchema(field.Type, visiting)
+		if description := field.Tag.Get("description"); description != "" {
+			schema["description"] = description
+		}
+		properties[field.name] = schema
+		if !strings.Contains(","+field.opts+",", ",omitempty,") {
+			required = append(required, field.name)
+		}
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// jsonField is a struct field as encoding/json encodes it, with its JSON
+// name and tag options. Index is relative to the outer struct.
+type jsonField struct {
+	reflect.StructField
+	name string
+	opts string
+}
+
+// jsonFields returns the fields encoding/json encodes for a struct, in
+// field order. Fields of embedded structs without a JSON name are
+// promoted, and fields sharing a name are resolved as encoding/json
+// resolves them: the shallowest wins, then the only tagged one among
+// equals, and otherwise none does.
+func jsonFields(t reflect.Type) []jsonField {
+	type candidate struct {
+		jsonField
+		depth  int
+		tagged bool
+	}
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var candidates []candidate
+	visited := make(map[reflect.Type]bool)
+	current := []embedded{{typ: t}}
+	for depth := 0; len(current) > 0; depth++ {
+		var next []embedded
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				field := e.typ.Field(i)
+				fieldType := field.Type
+				if field.Anonymous && fieldType.Kind() == reflect.Pointer {
+					fieldType = fieldType.Elem()
+				}
+				if !field.IsExported() && !(field.Anonymous && fieldType.Kind() == reflect.Struct) {
+					continue
+				}
+				name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if name == "-" {
+					continue
+				}
+
+				index := append(slices.Clone(e.index), i)
+				if name == "" && field.Anonymous && fieldType.Kind() == reflect.Struct {
+					next = append(next, embedded{typ: fieldType, index: index})
+					continue
+				}
+				if !field.IsExported() {
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = field.Name
+				}
+				field.Index = index
+				candidates = append(candidates, candidate{jsonField{field, name, opts}, depth, tagged})
+			}
+		}
+		current = next
+	}
+
+	// Keep the dominant field of each name
+	byName := make(map[string][]candidate)
+	for _, c := range candidates {
+		byName[c.name] = append(byName[c.name], c)
+	}
+	var fields []jsonField
+	for _, c := range candidates {
+		dominant := true
+		for _, other := range byName[c.name] {
+			if slices.Equal(other.Index, c.Index) {
+				continue
+			}
+			if other.depth < c.depth || other.depth == c.depth && (other.tagged || !c.tagged) {
+				dominant = false
+			}
+		}
+		if dominant {
+			fields = append(fields, c.jsonField)
+		}
+	}
+	slices.SortFunc(fields, func(a, b jsonField) int {
+		return slices.Compare(a.Index, b.Index)
+	})
+	return fields
+}
@@ -2,41 +2,165 @@ package server
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
 )
 
 // Server represents an MCP server instance
 type Server struct {
-	name         string
-	capabilities protocol.ServerCapabilities
-	info         protocol.Implementation
-	session      *Session
-	tools        map[string]Tool
-	resources    map[string]Resource
-	prompts      map[string]Prompt
-	mu           sync.RWMutex
+	name                 string
+	capabilities         protocol.ServerCapabilities
+	info                 protocol.Implementation
+	instructions         string
+	sessions             *SessionManager
+	tools                map[string]Tool
+	resources            map[string]Resource
+	patterns             []string
+	prompts              map[string]Prompt
+	toolInterceptors     []ToolInterceptor
+	invoke               ToolInvoker
+	toolLimit            *toolLimiter
+	middleware           []Middleware
+	hooks                []Hooks
+	logger               *slog.Logger
+	transports           []Stopper
+	drain                drain
+	taskStore            TaskStore
+	pool                 *workerPool
+	metrics              serverMetrics
+	providers            []ResourceProvider
+	pageSize             int
+	keepAliveInterval    time.Duration
+	keepAliveTimeout     time.Duration
+	requestTimeout       time.Duration
+	clientRequestTimeout time.Duration
+	mu                   sync.RWMutex
 }
 
 // Session represents a connection between client and server
 type Session struct {
-	ctx          context.Context
-	cancel       context.CancelFunc
-	server       *Server
-	initialized  bool
-	capabilities protocol.ClientCapabilities
-	clientInfo   protocol.Implementation
-	mu           sync.RWMutex
+	id            string
+	createdAt     time.Time
+	ctx           context.Context
+	cancel        context.CancelFunc
+	server        *Server
+	log           *slog.Logger
+	initialized   bool
+	capabilities  protocol.ClientCapabilities
+	clientInfo    protocol.Implementation
+	version       string
+	sender        NotificationSender
+	inFlight      map[protocol.RequestID]context.CancelFunc
+	subscriptions map[string]struct{}
+	pending       map[protocol.RequestID]chan *protocol.JSONRPCResponse
+	nextRequestID int64
+	roots         []protocol.Root
+	rootsCached   bool
+	logLevel      protocol.LoggingLevel
+	authInfo      *AuthInfo
+	connInfo      *ConnInfo
+	middleware    []Middleware
+	state         State
+	mu            sync.RWMutex
+}
+
+// ErrRequestCancelled is returned by HandleRequest when the request was
+// cancelled by the client. Transports must not send a response for it.
+var ErrRequestCancelled = errors.New("request cancelled")
+
+// NotificationSender delivers server-initiated notifications to the client
+// connected to a session. Transports register themselves as the sender for
+// the sessions they serve.
+type NotificationSender interface {
+	SendNotification(method string, params interface{}) error
 }
 
 // Tool represents a function that can be called by the LLM
 type Tool struct {
-	Handler     interface{}
-	Description string
-	IsAsync     bool
+	Handler      interface{}
+	Description  string
+	IsAsync      bool
+	Title        string
+	Annotations  *protocol.ToolAnnotations
+	InputSchema  map[string]interface{}
+	OutputSchema map[string]interface{}
+	Timeout      time.Duration
+
+	// SensitiveArguments name the arguments redacted from request logs
+	SensitiveArguments []string
+}
+
+// ToolHandlerFunc is a tool handler that receives the call arguments as the
+// client sent them rather than as positional parameters, for tools whose
+// parameters are only known at runtime. Its parameters are declared with
+// WithInputSchema, and a returned error is reported as an error result.
+type ToolHandlerFunc func(ctx context.Context, arguments map[string]interface{}) (protocol.CallToolResult, error)
+
+// ToolOption configures a Tool at registration
+type ToolOption func(*Tool)
+
+// WithToolTitle sets the human-readable title shown for a tool
+func WithToolTitle(title string) ToolOption {
+	return func(t *Tool) {
+		t.Title = title
+	}
+}
+
+// WithToolAnnotations sets the behavior hints of a tool, such as whether it
+// is read-only or destructive
+func WithToolAnnotations(annotations protocol.ToolAnnotations) ToolOption {
+	return func(t *Tool) {
+		t.Annotations = &annotations
+	}
+}
+
+// WithInputSchema declares the JSON schema of a tool's arguments. Without
+// it, the schema is derived from the handler's parameters.
+func WithInputSchema(schema map[string]interface{}) ToolOption {
+	return func(t *Tool) {
+		t.InputSchema = schema
+	}
+}
+
+// WithToolTimeout bounds each call of a synchronous tool. The handler's
+// context gets the deadline, and calls still running when it passes are
+// reported as error results right away. Handlers ignoring their context
+// run on in the background until they return, holding their slot of the
+// tool concurrency limit.
+func WithToolTimeout(timeout time.Duration) ToolOption {
+	return func(t *Tool) {
+		t.Timeout = timeout
+	}
+}
+
+// WithOutputSchema declares the JSON schema of a tool's result. The value
+// returned by the handler is then also sent as structured content.
+func WithOutputSchema(schema map[string]interface{}) ToolOption {
+	return func(t *Tool) {
+		t.OutputSchema = schema
+	}
+}
+
+// newTool creates a tool and applies its options
+func newTool(handler interface{}, description string, async bool, opts []ToolOption) Tool {
+	tool := Tool{
+		Handler:     handler,
+		Description: description,
+		IsAsync:     async,
+	}
+	for _, opt := range opts {
+		opt(&tool)
+	}
+	if tool.InputSchema == nil {
+		tool.InputSchema = handlerInputSchema(handler)
+	}
+	return tool
 }
 
 // Resource represents a data source that can be accessed by the LLM
@@ -44,12 +168,79 @@ type Resource struct {
 	Handler     interface{}
 	Description string
 	Pattern     string
+	Name        string
+	MimeType    string
+	Size        *int64
+	Annotations *protocol.Annotations
+	CacheTTL    time.Duration
+	CacheSize   int
+	Validator   ResourceValidator
+	compiled    *resourcePattern
+	cache       *resourceCache
+	completers  map[string]Completer
+}
+
+// ResourceOption configures a Resource at registration
+type ResourceOption func(*Resource)
+
+// WithMimeType declares the MIME type of a resource's contents, overriding
+// automatic detection
+func WithMimeType(mimeType string) ResourceOption {
+	return func(r *Resource) {
+		r.MimeType = mimeType
+	}
+}
+
+// WithResourceName sets the human-readable name shown in resource listings
+// instead of the raw pattern
+func WithResourceName(name string) ResourceOption {
+	return func(r *Resource) {
+		r.Name = name
+	}
+}
+
+// WithResourceSize declares the size of a resource's contents in bytes
+func WithResourceSize(size int64) ResourceOption {
+	return func(r *Resource) {
+		r.Size = &size
+	}
+}
+
+// WithResourceAnnotations sets the audience and priority annotations of a resource
+func WithResourceAnnotations(annotations protocol.Annotations) ResourceOption {
+	return func(r *Resource) {
+		r.Annotations = &annotations
+	}
 }
 
 // Prompt represents a template for LLM interactions
 type Prompt struct {
 	Handler     interface{}
 	Description string
+	Arguments   []protocol.PromptArgument
+	compiled    *promptTemplate
+	completers  map[string]Completer
+}
+
+// PromptHandlerFunc is a prompt handler that receives the arguments as the
+// client sent them, for prompts whose arguments are only known at runtime.
+// Its arguments are declared with WithPromptArgument.
+type PromptHandlerFunc func(ctx context.Context, arguments map[string]string) (protocol.GetPromptResult, error)
+
+// PromptOption configures a Prompt at registration
+type PromptOption func(*Prompt)
+
+// WithPromptArgument declares the name, description and required flag of
+// the next positional handler parameter, so prompts/get binds it by name
+// instead of by its argN key
+func WithPromptArgument(name, description string, required bool) PromptOption {
+	return func(p *Prompt) {
+		p.Arguments = append(p.Arguments, protocol.PromptArgument{
+			Name:        name,
+			Description: description,
+			Required:    &required,
+		})
+	}
 }
 
 // NewServer creates a new MCP server instance
@@ -59,6 +250,7 @@ func NewServer(name string, opts ...ServerOption) *Server {
 		tools:     make(map[string]Tool),
 		resources: make(map[string]Resource),
 		prompts:   make(map[string]Prompt),
+		taskStore: NewMemoryTaskStore(),
 		info: protocol.Implementation{
 			Name:    name,
 			Version: protocol.LatestProtocolVersion,
@@ -71,6 +263,10 @@ func NewServer(name string, opts ...ServerOption) *Server {
 		},
 	}
 
+	s.sessions = newSessionManager(s)
+	s.logger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	s.invoke = s.invokeTool
+
 	for _, opt := range opts {
 		opt(s)
 	}
@@ -81,15 +277,62 @@ func NewServer(name string, opts ...ServerOption) *Server {
 // NewSession creates a new session for a client connection
 func NewSession(ctx context.Context, server *Server) *Session {
 	ctx, cancel := context.WithCancel(ctx)
-	return &Session{
-		ctx:    ctx,
-		cancel: cancel,
-		server: server,
+	id := newSessionID()
+	session := &Session{
+		id:            id,
+		createdAt:     time.Now(),
+		ctx:           ctx,
+		cancel:        cancel,
+		server:        server,
+		log:           server.logger.With("session", id),
+		inFlight:      make(map[protocol.RequestID]context.CancelFunc),
+		subscriptions: make(map[string]struct{}),
+		pending:       make(map[protocol.RequestID]chan *protocol.JSONRPCResponse),
 	}
+
+	server.sessions.add(session)
+
+	return session
+}
+
+// NewSession creates a new session for a client connection, letting the
+// server act as the session factory of multi-client transports
+func (s *Server) NewSession(ctx context.Context) *Session {
+	return NewSession(ctx, s)
 }
 
-// HandleRequest processes an incoming JSON-RPC request
+// HandleRequest processes an incoming JSON-RPC request. Failures are
+// returned as JSON-RPC error responses; the only error returned is
+// ErrRequestCancelled, for requests that must not receive a response.
 func (s *Session) HandleRequest(req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+	return s.HandleRequestContext(context.Background(), req)
+}
+
+// HandleRequestContext is like HandleRequest, and makes the values of ctx,
+// such as those a transport attaches for the originating HTTP request,
+// visible to handlers. Handlers are still cancelled only by the client or
+// when the session closes, not when ctx is done. Requests pass through the
+// middleware added with Use.
+func (s *Session) HandleRequestContext(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+	if !s.server.drain.enter() {
+		return errorResponse(req.ID, ErrServerShuttingDown), nil
+	}
+	defer s.server.drain.exit()
+
+	start := time.Now()
+	resp, err := s.handle(ctx, req)
+	if err != nil && !errors.Is(err, ErrRequestCancelled) {
+		resp, err = errorResponse(req.ID, err), nil
+	}
+	s.server.metrics.observeRequest(req, resp, time.Since(start))
+	if resp != nil && resp.Error != nil {
+		s.server.onError(s, req, resp.Error)
+	}
+	return resp, err
+}
+
+// handleRequest processes a request once it passed the middleware
+func (s *Session) handleRequest(ctx context.Context, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
 	s.mu.RLock()
 	initialized := s.initialized
 	s.mu.RUnlock()
@@ -97,35 +340,25 @@ func (s *Session) HandleRequest(req *protocol.JSONRPCRequest) (*protocol.JSONRPC
 	// Handle initialization request
 	if req.Method == "initialize" {
 		if initialized {
-			return nil, fmt.Errorf("server already initialized")
+			return errorResponse(req.ID, protocol.NewError(protocol.InvalidRequest, "server already initialized")), nil
 		}
-		return s.handleInitialize(req)
-	}
-
-	// All other requests require initialization
-	if !initialized {
-		return nil, fmt.Errorf("server not initialized")
-	}
-
-	// Handle other requests based on method
-	switch req.Method {
-	case "ping":
-		return s.handlePing(req)
-	case "tools/list":
-		return s.handleListTools(req)
-	case "tools/call":
-		return s.handleCallTool(req)
-	case "resources/list":
-		return s.handleListResources(req)
-	case "resources/read":
-		return s.handleReadResource(req)
-	case "prompts/list":
-		return s.handleListPrompts(req)
-	case "prompts/get":
-		return s.handleGetPrompt(req)
-	default:
-		return nil, fmt.Errorf("unknown method: %s", req.Method)
+		resp, err := s.handleInitialize(req)
+		if err != nil {
+			return errorResponse(req.ID, err), nil
+		}
+		return resp, nil
 	}
+
+	// All other requests but pings require initialization
+	if !initialized && req.Method != "ping" {
+		return errorResponse(req.ID, protocol.NewError(protocol.ServerNotInitialized, "server not initialized")), nil
+	}
+
+	handle, ok := methods[req.Method]
+	if !ok || !s.server.advertises(req.Method) {
+		return errorResponse(req.ID, unknownMethodError(req.Method)), nil
+	}
+	return handle(s, ctx, req)
 }
 
 // HandleNotification processes an incoming JSON-RPC notification
@@ -143,6 +376,8 @@ func (s *Session) HandleNotification(notif *protocol.JSONRPCNotification) error
 		return s.handleInitialized(notif)
 	case "notifications/cancelled":
 		return s.handleCancelled(notif)
+	case "notifications/roots/list_changed":
+		return s.handleRootsListChanged(notif)
 	default:
 		return fmt.Errorf("unknown notification method: %s", notif.Method)
 	}
@@ -151,21 +386,38 @@ func (s *Session) HandleNotification(notif *protocol.JSONRPCNotification) error
 // handleInitialize processes the initialize request
 func (s *Session) handleInitialize(req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
 	var params protocol.InitializeRequestParams
-	if err := json.Unmarshal(req.Params.(json.RawMessage), &params); err != nil {
-		return nil, fmt.Errorf("invalid initialize params: %w", err)
+	if err := decodeParams(req.Params, &params); err != nil {
+		return nil, invalidParams("invalid initialize params: %v", err)
+	}
+
+	// Agree on the client's revision when supported, and otherwise offer
+	// the latest one for the client to accept or disconnect
+	version := params.ProtocolVersion
+	if !protocol.IsSupportedProtocolVersion(version) {
+		version = protocol.LatestProtocolVersion
+	}
+
+	if err := s.server.onInitialize(s, params.ClientInfo, params.Capabilities); err != nil {
+		return nil, err
 	}
 
 	s.mu.Lock()
 	s.capabilities = params.Capabilities
 	s.clientInfo = params.ClientInfo
+	s.version = version
 	s.initialized = true
 	s.mu.Unlock()
 
+	s.startKeepAlive()
+
 	result := protocol.InitializeResult{
-		ProtocolVersion: protocol.LatestProtocolVersion,
+		ProtocolVersion: version,
 		Capabilities:    s.server.capabilities,
 		ServerInfo:      s.server.info,
 	}
+	if s.server.instructions != "" {
+		result.Instructions = &s.server.instructions
+	}
 
 	return &protocol.JSONRPCResponse{
 		JSONRPC: "2.0",
@@ -176,12 +428,12 @@ func (s *Session) handleInitialize(req *protocol.JSONRPCRequest) (*protocol.JSON
 
 // handleInitialized processes the initialized notification
 func (s *Session) handleInitialized(notif *protocol.JSONRPCNotification) error {
-	// Nothing to do here, but we could trigger any post-initialization tasks
+	s.server.onInitialized(s)
 	return nil
 }
 
 // handlePing processes ping requests
-func (s *Session) handlePing(req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+func (s *Session) handlePing(ctx context.Context, req *protocol.JSONRPCRequest, params *protocol.RequestParams) (*protocol.JSONRPCResponse, error) {
 	return &protocol.JSONRPCResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
@@ -192,16 +444,102 @@ func (s *Session) handlePing(req *protocol.JSONRPCRequest) (*protocol.JSONRPCRes
 // handleCancelled processes cancellation notifications
 func (s *Session) handleCancelled(notif *protocol.JSONRPCNotification) error {
 	var params protocol.CancelledNotificationParams
-	if err := json.Unmarshal(notif.Params.(json.RawMessage), &params); err != nil {
+	if err := decodeParams(notif.Params, &params); err != nil {
 		return fmt.Errorf("invalid cancellation params: %w", err)
 	}
 
-	// TODO: Implement request cancellation
+	s.mu.RLock()
+	cancel, exists := s.inFlight[params.RequestID]
+	s.mu.RUnlock()
+
+	// Unknown or already completed requests are ignored, as the
+	// cancellation may race with the response
+	if exists {
+		cancel()
+	}
 	return nil
 }
 
+// trackRequest derives a cancellable context for a request with its _meta,
+// with the server's request timeout if one is set, and registers it as in
+// flight. untrackRequest must be called once the request has been handled.
+func (s *Session) trackRequest(parent context.Context, req *protocol.JSONRPCRequest, meta map[string]interface{}) (context.Context, context.CancelFunc) {
+	ctx := s.requestContext(parent, meta)
+	var cancel context.CancelFunc
+	if timeout := s.server.requestTimeout; timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	s.mu.Lock()
+	s.inFlight[req.ID] = cancel
+	s.mu.Unlock()
+
+	return ctx, cancel
+}
+
+// untrackRequest removes a handled request from the requests in flight and
+// releases its context
+func (s *Session) untrackRequest(id protocol.RequestID, cancel context.CancelFunc) {
+	s.mu.Lock()
+	delete(s.inFlight, id)
+	s.mu.Unlock()
+	cancel()
+}
+
+// ProtocolVersion returns the protocol revision negotiated in initialize
+func (s *Session) ProtocolVersion() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.version
+}
+
+// supportsVersion reports whether the negotiated protocol revision is the
+// given revision or a newer one
+func (s *Session) supportsVersion(version string) bool {
+	return s.ProtocolVersion() >= version
+}
+
+// ClientInfo returns the name and version the client sent in initialize
+func (s *Session) ClientInfo() protocol.Implementation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.clientInfo
+}
+
+// SetNotificationSender sets the sender used to deliver notifications
+func (s *Session) SetNotificationSender(sender NotificationSender) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sender = sender
+}
+
+// SendNotification sends a notification to the client through the
+// transport attached to the session
+func (s *Session) SendNotification(method string, params interface{}) error {
+	s.mu.RLock()
+	sender := s.sender
+	s.mu.RUnlock()
+
+	if sender == nil {
+		return fmt.Errorf("session has no notification sender")
+	}
+	return sender.SendNotification(method, params)
+}
+
+// Done returns a channel that is closed when the session is closed, for
+// example after its client stopped answering keepalive pings
+func (s *Session) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
 // Close ends the session
 func (s *Session) Close() error {
+	s.server.sessions.remove(s)
+
 	s.cancel()
 	return nil
 }
@@ -213,69 +551,214 @@ func WithImplementation(impl protocol.Implementation) ServerOption {
 	}
 }
 
+// WithInstructions sets the instructions sent to clients in the initialize
+// result, describing how to use the server. Hosts may add them to the
+// model's context.
+func WithInstructions(instructions string) ServerOption {
+	return func(s *Server) {
+		s.instructions = instructions
+	}
+}
+
 // AddTool adds a tool to the server
-func (s *Server) AddTool(name string, handler interface{}, description string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *Server) AddTool(name string, handler interface{}, description string, opts ...ToolOption) error {
+	return s.addTool(name, newTool(handler, description, false, opts))
+}
+
+// AddAsyncTool adds an asynchronous tool to the server
+func (s *Server) AddAsyncTool(name string, handler interface{}, description string, opts ...ToolOption) error {
+	return s.addTool(name, newTool(handler, description, true, opts))
+}
 
+// addTool registers a tool under a name that must not be taken
+func (s *Server) addTool(name string, tool Tool) error {
+	s.mu.Lock()
 	if _, exists := s.tools[name]; exists {
+		s.mu.Unlock()
 		return fmt.Errorf("tool %s already exists", name)
 	}
 
-	s.tools[name] = Tool{
-		Handler:     handler,
-		Description: description,
-		IsAsync:     false,
-	}
+	s.tools[name] = tool
+	s.mu.Unlock()
+
+	s.notifyToolsChanged()
 	return nil
 }
 
-// AddAsyncTool adds an asynchronous tool to the server
-func (s *Server) AddAsyncTool(name string, handler interface{}, description string) error {
+// ReplaceTool adds a tool or replaces an existing one with the same name.
+// A replaced async tool stays async.
+func (s *Server) ReplaceTool(name string, handler interface{}, description string, opts ...ToolOption) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	async := s.tools[name].IsAsync
+	s.tools[name] = newTool(handler, description, async, opts)
+	s.mu.Unlock()
 
-	if _, exists := s.tools[name]; exists {
-		return fmt.Errorf("tool %s already exists", name)
-	}
+	s.notifyToolsChanged()
+	return nil
+}
 
-	s.tools[name] = Tool{
-		Handler:     handler,
-		Description: description,
-		IsAsync:     true,
+// RemoveTool removes a tool from the server
+func (s *Server) RemoveTool(name string) error {
+	s.mu.Lock()
+	if _, exists := s.tools[name]; !exists {
+		s.mu.Unlock()
+		return fmt.Errorf("tool %s not found", name)
 	}
+
+	delete(s.tools, name)
+	s.mu.Unlock()
+
+	s.notifyToolsChanged()
 	return nil
 }
 
 // AddResource adds a resource to the server
-func (s *Server) AddResource(pattern string, handler interface{}, description string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *Server) AddResource(pattern string, handler interface{}, description string, opts ...ResourceOption) error {
+	resource, err := newResource(pattern, handler, description, opts)
+	if err != nil {
+		return err
+	}
 
+	s.mu.Lock()
 	if _, exists := s.resources[pattern]; exists {
+		s.mu.Unlock()
 		return fmt.Errorf("resource %s already exists", pattern)
 	}
 
-	s.resources[pattern] = Resource{
+	s.resources[pattern] = resource
+	s.indexPatterns()
+	s.mu.Unlock()
+
+	s.notifyResourcesChanged()
+	return nil
+}
+
+// ReplaceResource adds a resource or replaces an existing one with the same
+// pattern. Clients subscribed to a replaced resource are told it was updated.
+func (s *Server) ReplaceResource(pattern string, handler interface{}, description string, opts ...ResourceOption) error {
+	resource, err := newResource(pattern, handler, description, opts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	_, replaced := s.resources[pattern]
+	s.resources[pattern] = resource
+	s.indexPatterns()
+	s.mu.Unlock()
+
+	s.notifyResourcesChanged()
+	if replaced && !resource.compiled.isTemplate() {
+		s.NotifyResourceUpdated(pattern)
+	}
+	return nil
+}
+
+// RemoveResource removes a resource from the server
+func (s *Server) RemoveResource(pattern string) error {
+	s.mu.Lock()
+	if _, exists := s.resources[pattern]; !exists {
+		s.mu.Unlock()
+		return fmt.Errorf("resource %s not found", pattern)
+	}
+
+	delete(s.resources, pattern)
+	s.indexPatterns()
+	s.mu.Unlock()
+
+	s.notifyResourcesChanged()
+	return nil
+}
+
+// newResource builds a resource, compiling its pattern once so reads
+// don't re-parse it
+func newResource(pattern string, handler interface{}, description string, opts []ResourceOption) (Resource, error) {
+	compiled, err := parseResourcePattern(pattern, handler)
+	if err != nil {
+		return Resource{}, err
+	}
+
+	resource := Resource{
 		Handler:     handler,
 		Description: description,
 		Pattern:     pattern,
+		compiled:    compiled,
 	}
-	return nil
+	for _, opt := range opts {
+		opt(&resource)
+	}
+	resource.cache = newResourceCache(resource)
+	return resource, nil
 }
 
 // AddPrompt adds a prompt to the server
-func (s *Server) AddPrompt(name string, handler interface{}, description string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *Server) AddPrompt(name string, handler interface{}, description string, opts ...PromptOption) error {
+	prompt, err := newPrompt(name, handler, description, opts)
+	if err != nil {
+		return err
+	}
+	return s.addPrompt(name, prompt)
+}
 
+// addPrompt registers a prompt under a name that must not be taken
+func (s *Server) addPrompt(name string, prompt Prompt) error {
+	s.mu.Lock()
 	if _, exists := s.prompts[name]; exists {
+		s.mu.Unlock()
 		return fmt.Errorf("prompt %s already exists", name)
 	}
 
-	s.prompts[name] = Prompt{
+	s.prompts[name] = prompt
+	s.mu.Unlock()
+
+	s.notifyPromptsChanged()
+	return nil
+}
+
+// ReplacePrompt adds a prompt or replaces an existing one with the same name
+func (s *Server) ReplacePrompt(name string, handler interface{}, description string, opts ...PromptOption) error {
+	prompt, err := newPrompt(name, handler, description, opts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.prompts[name] = prompt
+	s.mu.Unlock()
+
+	s.notifyPromptsChanged()
+	return nil
+}
+
+// RemovePrompt removes a prompt from the server
+func (s *Server) RemovePrompt(name string) error {
+	s.mu.Lock()
+	if _, exists := s.prompts[name]; !exists {
+		s.mu.Unlock()
+		return fmt.Errorf("prompt %s not found", name)
+	}
+
+	delete(s.prompts, name)
+	s.mu.Unlock()
+
+	s.notifyPromptsChanged()
+	return nil
+}
+
+// newPrompt builds a prompt, applying its options and parsing its handler
+func newPrompt(name string, handler interface{}, description string, opts []PromptOption) (Prompt, error) {
+	prompt := Prompt{
 		Handler:     handler,
 		Description: description,
 	}
-	return nil
+	for _, opt := range opts {
+		opt(&prompt)
+	}
+
+	compiled, err := parsePromptTemplate(name, handler, description, prompt.Arguments)
+	if err != nil {
+		return Prompt{}, err
+	}
+	prompt.compiled = compiled
+	return prompt, nil
 }
@@ -0,0 +1,626 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// newTestSession creates an initialized session for the given server
+func newTestSession(t *testing.T, srv *Server) *Session {
+	t.Helper()
+
+	session := NewSession(context.Background(), srv)
+	_, err := session.HandleRequest(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(1),
+		Method:  "initialize",
+		Params:  json.RawMessage(`{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}`),
+	})
+	if err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	return session
+}
+
+// fakeClient stands in for the client of a session. It records the
+// notifications sent to it and answers requests with the JSON results set
+// by answer, leaving requests for other methods, and all requests while
+// silent, unanswered.
+type fakeClient struct {
+	session *Session
+	silent  atomic.Bool
+
+	methods []string
+	params  []interface{}
+
+	mu       sync.Mutex
+	results  map[string]string
+	requests map[string]int
+}
+
+// newFakeClient creates a fake client receiving the messages session sends
+func newFakeClient(session *Session) *fakeClient {
+	c := &fakeClient{session: session}
+	session.SetNotificationSender(c)
+	return c
+}
+
+// answer makes the client answer requests for method with result
+func (c *fakeClient) answer(method, result string) *fakeClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.results == nil {
+		c.results = make(map[string]string)
+	}
+	c.results[method] = result
+	return c
+}
+
+// requestCount returns the number of requests for method sent to the client
+func (c *fakeClient) requestCount(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.requests[method]
+}
+
+func (c *fakeClient) SendNotification(method string, params interface{}) error {
+	c.methods = append(c.methods, method)
+	c.params = append(c.params, params)
+	return nil
+}
+
+func (c *fakeClient) SendRequest(req *protocol.JSONRPCRequest) error {
+	c.mu.Lock()
+	if c.requests == nil {
+		c.requests = make(map[string]int)
+	}
+	c.requests[req.Method]++
+	result, ok := c.results[req.Method]
+	c.mu.Unlock()
+
+	if ok && !c.silent.Load() {
+		go c.session.HandleResponse(&protocol.JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  json.RawMessage(result),
+		})
+	}
+	return nil
+}
+
+// request sends a request through the session and fails the test on error
+func request(t *testing.T, session *Session, method, params string) *protocol.JSONRPCResponse {
+	t.Helper()
+
+	resp, err := session.HandleRequest(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(4),
+		Method:  method,
+		Params:  json.RawMessage(params),
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %v", method, err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("%s failed: %v", method, resp.Error)
+	}
+	return resp
+}
+
+// requestError sends a request through the session that must fail and
+// returns its JSON-RPC error
+func requestError(t *testing.T, session *Session, method, params string) *protocol.ErrorData {
+	t.Helper()
+
+	resp, err := session.HandleRequest(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(5),
+		Method:  method,
+		Params:  json.RawMessage(params),
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %v", method, err)
+	}
+	if resp.Error == nil {
+		t.Fatalf("expected %s to fail, got %+v", method, resp.Result)
+	}
+	return resp.Error
+}
+
+func TestListChangedNotifications(t *testing.T) {
+	srv := NewServer("test", WithDefaultCapabilities())
+	session := newTestSession(t, srv)
+	sender := &fakeClient{}
+	session.SetNotificationSender(sender)
+
+	srv.AddTool("echo", func(s string) string { return s }, "")
+	srv.ReplaceTool("echo", func(s string) string { return s + s }, "")
+	if err := srv.RemoveTool("echo"); err != nil {
+		t.Fatalf("RemoveTool failed: %v", err)
+	}
+	srv.AddResource("config", func() string { return "" }, "")
+	srv.AddPrompt("greet", func(name string) string { return name }, "")
+	srv.ReplacePrompt("greet", func(name string) string { return "Hi " + name }, "")
+	if err := srv.RemovePrompt("greet"); err != nil {
+		t.Fatalf("RemovePrompt failed: %v", err)
+	}
+
+	want := []string{
+		"notifications/tools/list_changed",
+		"notifications/tools/list_changed",
+		"notifications/tools/list_changed",
+		"notifications/resources/list_changed",
+		"notifications/prompts/list_changed",
+		"notifications/prompts/list_changed",
+		"notifications/prompts/list_changed",
+	}
+	if len(sender.methods) != len(want) {
+		t.Fatalf("expected notifications %v, got %v", want, sender.methods)
+	}
+	for i := range want {
+		if sender.methods[i] != want[i] {
+			t.Errorf("expected notification %s, got %s", want[i], sender.methods[i])
+		}
+	}
+
+	// Servers that don't advertise listChanged send nothing
+	quiet := NewServer("quiet")
+	quietSession := newTestSession(t, quiet)
+	quietSender := &fakeClient{}
+	quietSession.SetNotificationSender(quietSender)
+	quiet.AddTool("echo", func(s string) string { return s }, "")
+	if len(quietSender.methods) != 0 {
+		t.Errorf("expected no notifications, got %v", quietSender.methods)
+	}
+}
+
+func TestListToolsAndPromptsPagination(t *testing.T) {
+	srv := NewServer("test", WithPageSize(2))
+	for _, name := range []string{"c", "a", "b"} {
+		srv.AddTool(name, func() string { return "" }, "")
+		srv.AddPrompt(name, func() string { return "" }, "")
+	}
+	session := newTestSession(t, srv)
+
+	tools := request(t, session, "tools/list", `{}`).Result.(protocol.ListToolsResult)
+	if len(tools.Tools) != 2 || tools.Tools[0].Name != "a" || tools.Tools[1].Name != "b" || tools.NextCursor == nil {
+		t.Fatalf("unexpected first tools page: %+v", tools)
+	}
+	tools = request(t, session, "tools/list", fmt.Sprintf(`{"cursor":%q}`, *tools.NextCursor)).Result.(protocol.ListToolsResult)
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "c" || tools.NextCursor != nil {
+		t.Errorf("unexpected last tools page: %+v", tools)
+	}
+
+	prompts := request(t, session, "prompts/list", `{}`).Result.(protocol.ListPromptsResult)
+	if len(prompts.Prompts) != 2 || prompts.Prompts[0].Name != "a" || prompts.NextCursor == nil {
+		t.Errorf("unexpected first prompts page: %+v", prompts)
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("add", func(a, b int) int { return a + b }, "")
+
+	uninitialized := NewSession(context.Background(), srv)
+	resp, err := uninitialized.HandleRequest(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(1),
+		Method:  "tools/list",
+	})
+	if err != nil || resp.Error == nil || resp.Error.Code != protocol.ServerNotInitialized {
+		t.Errorf("expected server not initialized error, got %+v, %v", resp, err)
+	}
+
+	session := newTestSession(t, srv)
+	tests := []struct {
+		method, params string
+		code           int
+	}{
+		{"initialize", `{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}`, protocol.InvalidRequest},
+		{"tools/missing", `{}`, protocol.MethodNotFound},
+		{"tools/call", `{"name":"add","arguments":{"arg0":1}}`, protocol.InvalidParams},
+		{"tools/call", `{"name":"subtract","arguments":{}}`, protocol.InvalidParams},
+		{"tools/call", `[]`, protocol.InvalidParams},
+		{"resources/read", `{"uri":"missing://x"}`, protocol.ResourceNotFound},
+	}
+	for _, tt := range tests {
+		if rpcErr := requestError(t, session, tt.method, tt.params); rpcErr.Code != tt.code {
+			t.Errorf("%s %s: expected code %d, got %d (%s)", tt.method, tt.params, tt.code, rpcErr.Code, rpcErr.Message)
+		}
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	languages := func(ctx context.Context, value string) ([]string, error) {
+		var matches []string
+		for _, lang := range []string{"go", "gleam", "python"} {
+			if strings.HasPrefix(lang, value) {
+				matches = append(matches, lang)
+			}
+		}
+		return matches, nil
+	}
+	srv := NewServer("test", WithDefaultCapabilities())
+	srv.AddPrompt("review", func(language string) string { return language }, "",
+		WithPromptArgument("language", "", true),
+		WithArgumentCompleter("language", languages))
+	srv.AddResource("docs://{language}", func(language string) string { return language }, "",
+		WithVariableCompleter("language", languages))
+	session := newTestSession(t, srv)
+
+	result := request(t, session, "completion/complete", `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"language","value":"g"}}`).Result.(protocol.CompleteResult)
+	if want := []string{"go", "gleam"}; !reflect.DeepEqual(result.Completion.Values, want) || *result.Completion.Total != 2 {
+		t.Errorf("expected %v, got %+v", want, result.Completion)
+	}
+
+	result = request(t, session, "completion/complete", `{"ref":{"type":"ref/resource","uri":"docs://{language}"},"argument":{"name":"language","value":"py"}}`).Result.(protocol.CompleteResult)
+	if want := []string{"python"}; !reflect.DeepEqual(result.Completion.Values, want) {
+		t.Errorf("expected %v, got %+v", want, result.Completion)
+	}
+
+	result = request(t, session, "completion/complete", `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"other","value":""}}`).Result.(protocol.CompleteResult)
+	if len(result.Completion.Values) != 0 {
+		t.Errorf("expected no values without a completer, got %+v", result.Completion)
+	}
+
+	if rpcErr := requestError(t, session, "completion/complete", `{"ref":{"type":"ref/prompt","name":"missing"},"argument":{"name":"x","value":""}}`); rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("expected invalid params for unknown prompt, got %v", rpcErr)
+	}
+}
+
+func TestRequestMeta(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("trace", func(ctx context.Context) (protocol.CallToolResult, error) {
+		if err := SetResultMeta(ctx, "traceId", MetaFromContext(ctx)["traceId"]); err != nil {
+			return protocol.CallToolResult{}, err
+		}
+		SetResultMeta(ctx, "cost", 1)
+		result := protocol.CallToolResult{Content: []interface{}{protocol.NewTextContent("ok")}}
+		result.Meta = map[string]interface{}{"cost": 2}
+		return result, nil
+	}, "Echoes the trace ID")
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"trace","arguments":{},"_meta":{"traceId":"abc","progressToken":"tok"}}`)
+	if result.Meta["traceId"] != "abc" {
+		t.Errorf("expected traceId to be propagated, got %v", result.Meta)
+	}
+	if result.Meta["cost"] != 2 {
+		t.Errorf("expected the returned result meta to take precedence, got %v", result.Meta)
+	}
+
+	result = callTool(t, session, `{"name":"trace","arguments":{}}`)
+	if _, ok := result.Meta["traceId"]; !ok || result.Meta["traceId"] != nil {
+		t.Errorf("expected a nil traceId without request meta, got %v", result.Meta)
+	}
+}
+
+func TestRequestIDs(t *testing.T) {
+	for _, raw := range []string{`7`, `"7"`, `"abc"`, `null`} {
+		var id protocol.RequestID
+		if err := json.Unmarshal([]byte(raw), &id); err != nil {
+			t.Fatalf("unmarshal %s: %v", raw, err)
+		}
+		data, err := json.Marshal(id)
+		if err != nil || string(data) != raw {
+			t.Errorf("expected %s to round-trip, got %s (%v)", raw, data, err)
+		}
+	}
+
+	var id protocol.RequestID
+	if err := json.Unmarshal([]byte(`1.5`), &id); err == nil {
+		t.Errorf("expected an error for a fractional id")
+	}
+	if protocol.NewIntID(7) == protocol.NewStringID("7") {
+		t.Errorf("expected integer and string ids to differ")
+	}
+
+	// Responses to server requests are matched by ID type as well as value
+	srv := NewServer("test")
+	session := newTestSession(t, srv)
+	session.pending[protocol.NewIntID(1)] = make(chan *protocol.JSONRPCResponse, 1)
+	session.HandleResponse(&protocol.JSONRPCResponse{ID: protocol.NewStringID("1")})
+	if len(session.pending[protocol.NewIntID(1)]) != 0 {
+		t.Errorf("expected a string id not to match an integer request id")
+	}
+}
+
+func TestProtocolVersions(t *testing.T) {
+	type weather struct {
+		Temperature float64 `json:"temperature"`
+	}
+	srv := NewServer("test")
+	srv.AddTool("weather", func() weather { return weather{Temperature: 21.5} }, "Current weather",
+		WithToolTitle("Weather"),
+		WithToolAnnotations(protocol.ToolAnnotations{ReadOnlyHint: boolPtr(true)}),
+		WithOutputSchema(map[string]interface{}{"type": "object"}))
+
+	initialize := func(version string) *Session {
+		session := NewSession(context.Background(), srv)
+		newFakeClient(session).answer("elicitation/create", `{"action":"accept","content":{"name":"gopher"}}`)
+		result := request(t, session, "initialize", `{"protocolVersion":"`+version+`","capabilities":{"elicitation":{}},"clientInfo":{"name":"test","version":"1.0.0"}}`).Result.(protocol.InitializeResult)
+		if result.ProtocolVersion != session.ProtocolVersion() {
+			t.Errorf("expected the negotiated version %s in the result, got %s", session.ProtocolVersion(), result.ProtocolVersion)
+		}
+		return session
+	}
+
+	// Unknown revisions are answered with the latest one
+	if session := initialize("1999-01-01"); session.ProtocolVersion() != protocol.LatestProtocolVersion {
+		t.Errorf("expected %s, got %s", protocol.LatestProtocolVersion, session.ProtocolVersion())
+	}
+
+	latest := initialize(protocol.ProtocolVersion20250618)
+	tool := request(t, latest, "tools/list", `{}`).Result.(protocol.ListToolsResult).Tools[0]
+	if tool.Title != "Weather" || tool.Annotations == nil || tool.OutputSchema == nil {
+		t.Errorf("expected title, annotations and output schema, got %+v", tool)
+	}
+	result := callTool(t, latest, `{"name":"weather","arguments":{}}`)
+	if result.StructuredContent != (weather{Temperature: 21.5}) || len(result.Content) != 1 {
+		t.Errorf("expected structured and text content, got %+v", result)
+	}
+	elicited, err := latest.Elicit(context.Background(), "What is your name?", map[string]interface{}{"type": "object"})
+	if err != nil || elicited.Action != protocol.ElicitActionAccept || elicited.Content["name"] != "gopher" {
+		t.Errorf("expected accepted elicitation, got %+v, %v", elicited, err)
+	}
+
+	legacy := initialize(protocol.ProtocolVersion20241105)
+	tool = request(t, legacy, "tools/list", `{}`).Result.(protocol.ListToolsResult).Tools[0]
+	if tool.Title != "" || tool.Annotations != nil || tool.OutputSchema != nil {
+		t.Errorf("expected newer tool fields to be omitted, got %+v", tool)
+	}
+	if result := callTool(t, legacy, `{"name":"weather","arguments":{}}`); result.StructuredContent != nil {
+		t.Errorf("expected no structured content, got %+v", result.StructuredContent)
+	}
+	if _, err := legacy.Elicit(context.Background(), "?", nil); !errors.Is(err, ErrElicitationNotSupported) {
+		t.Errorf("expected ErrElicitationNotSupported, got %v", err)
+	}
+}
+
+func TestServerLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	srv := NewServer("test", WithLogger(logger))
+	srv.AddTool("echo", func(s string) string { return s }, "")
+	session := newTestSession(t, srv)
+
+	callTool(t, session, `{"name":"echo","arguments":{"arg0":"hi"}}`)
+	session.Close()
+
+	for _, msg := range []string{`"msg":"session created"`, `"msg":"tool call"`, `"tool":"echo"`, `"msg":"session closed"`} {
+		if !strings.Contains(buf.String(), msg) {
+			t.Errorf("expected log to contain %s, got %s", msg, buf.String())
+		}
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	srv := NewServer("test", WithRequestTimeout(20*time.Millisecond))
+	srv.AddTool("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, "")
+	srv.AddTool("fast", func() string { return "done" }, "")
+	session := newTestSession(t, srv)
+
+	err := requestError(t, session, "tools/call", `{"name":"slow","arguments":{}}`)
+	if err.Code != protocol.RequestTimeout || err.Message != "request timed out after 20ms" {
+		t.Errorf("expected timeout error, got %+v", err)
+	}
+	if result := callTool(t, session, `{"name":"fast","arguments":{}}`); resultText(result) != "done" {
+		t.Errorf("expected fast tool to finish, got %+v", result)
+	}
+}
+
+func TestInstructions(t *testing.T) {
+	initialize := `{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}`
+
+	srv := NewServer("test", WithInstructions("Call search before fetch."))
+	result := request(t, NewSession(context.Background(), srv), "initialize", initialize).Result.(protocol.InitializeResult)
+	if result.Instructions == nil || *result.Instructions != "Call search before fetch." {
+		t.Errorf("expected instructions, got %v", result.Instructions)
+	}
+
+	result = request(t, NewSession(context.Background(), NewServer("test")), "initialize", initialize).Result.(protocol.InitializeResult)
+	if result.Instructions != nil {
+		t.Errorf("expected no instructions, got %q", *result.Instructions)
+	}
+}
+
+// stopRecorder records when a transport is stopped
+type stopRecorder struct {
+	stopped chan struct{}
+}
+
+func (r *stopRecorder) Stop(ctx context.Context) error {
+	close(r.stopped)
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	release := make(chan struct{})
+	srv := NewServer("test")
+	srv.AddTool("slow", func() string {
+		<-release
+		return "done"
+	}, "")
+	transport := &stopRecorder{stopped: make(chan struct{})}
+	srv.RegisterTransport(transport)
+	session := newTestSession(t, srv)
+
+	called := make(chan protocol.CallToolResult)
+	go func() { called <- callTool(t, session, `{"name":"slow","arguments":{}}`) }()
+	// Wait for the call to be in flight
+	for {
+		srv.drain.mu.Lock()
+		active := srv.drain.active
+		srv.drain.mu.Unlock()
+		if active > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	shutdown := make(chan error)
+	go func() { shutdown <- srv.Shutdown(context.Background()) }()
+
+	// New requests are rejected while the slow call drains
+	for {
+		resp, _ := session.HandleRequest(&protocol.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      protocol.NewIntID(3),
+			Method:  "ping",
+		})
+		if resp.Error != nil {
+			if resp.Error.Message != ErrServerShuttingDown.Error() {
+				t.Errorf("expected shutdown error, got %+v", resp.Error)
+			}
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-transport.stopped:
+		t.Fatal("transport stopped before in-flight requests finished")
+	default:
+	}
+
+	close(release)
+	if result := <-called; resultText(result) != "done" {
+		t.Errorf("expected in-flight call to finish, got %+v", result)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	<-transport.stopped
+	if srv.Sessions().Len() != 0 {
+		t.Errorf("expected sessions to be closed, got %d", srv.Sessions().Len())
+	}
+}
+
+func TestMount(t *testing.T) {
+	fsServer := NewServer("fs")
+	fsServer.AddTool("read", func(path string) string { return "read " + path }, "")
+	fsServer.AddResource("file://{path}", func(path string) string { return "contents of " + path }, "")
+	fsServer.AddPrompt("summarize", func(path string) string { return "Summarize " + path }, "")
+
+	srv := NewServer("test")
+	if err := srv.Mount("fs", fsServer); err != nil {
+		t.Fatalf("Mount failed: %v", err)
+	}
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"fs_read","arguments":{"arg0":"a.txt"}}`)
+	if text := resultText(result); text != "read a.txt" {
+		t.Errorf("expected mounted tool result, got %q", text)
+	}
+
+	contents := readResource(t, session, "file://fs/a.txt")
+	if text := contents[0].(protocol.TextResourceContents); text.Text != "contents of a.txt" || text.URI != "file://fs/a.txt" {
+		t.Errorf("expected mounted resource contents, got %+v", text)
+	}
+
+	resp := request(t, session, "prompts/get", `{"name":"fs_summarize","arguments":{"arg0":"a.txt"}}`)
+	if _, ok := resp.Result.(protocol.GetPromptResult); !ok {
+		t.Errorf("expected GetPromptResult, got %T", resp.Result)
+	}
+
+	// Mounting again would redefine the prefixed names
+	if err := srv.Mount("fs", fsServer); err == nil {
+		t.Error("expected an error mounting conflicting names")
+	}
+}
+
+func TestManifest(t *testing.T) {
+	type Options struct {
+		Limit int    `json:"limit,omitempty" description:"Maximum results"`
+		Sort  string `json:"sort"`
+	}
+	srv := NewServer("test")
+	srv.AddTool("search", func(ctx context.Context, query string, opts Options) []string { return nil }, "Search")
+	srv.AddTool("echo", func(text string) string { return text }, "Echo")
+	srv.AddResource("config://app", func() string { return "" }, "Config")
+	srv.AddResource("file://{path}", func(path string) string { return path }, "Files")
+	srv.AddPrompt("greet", func(name string) string { return name }, "Greet")
+
+	manifest, err := srv.Manifest(context.Background())
+	if err != nil {
+		t.Fatalf("Manifest failed: %v", err)
+	}
+	if len(manifest.Tools) != 2 || manifest.Tools[0].Name != "echo" || manifest.Tools[1].Name != "search" {
+		t.Fatalf("expected tools sorted by name, got %+v", manifest.Tools)
+	}
+	if len(manifest.Resources) != 1 || len(manifest.ResourceTemplates) != 1 || len(manifest.Prompts) != 1 {
+		t.Errorf("expected one resource, template and prompt, got %+v", manifest)
+	}
+
+	expected := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"arg0": map[string]interface{}{"type": "string"},
+			"arg1": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"limit": map[string]interface{}{"type": "integer", "description": "Maximum results"},
+					"sort":  map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"sort"},
+			},
+		},
+		"required": []string{"arg0", "arg1"},
+	}
+	if !reflect.DeepEqual(manifest.Tools[1].InputSchema, expected) {
+		t.Errorf("expected input schema derived from the handler %v, got %v", expected, manifest.Tools[1].InputSchema)
+	}
+}
+
+func TestServerStats(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("fail", func() error { return errors.New("boom") }, "")
+	session := NewSession(context.Background(), srv)
+	request(t, session, "initialize", `{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}`)
+	callTool(t, session, `{"name":"fail","arguments":{}}`)
+	session.HandleRequest(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(9), Method: "bogus/method"})
+
+	stats := srv.Stats()
+	if stats.ActiveSessions != srv.Sessions().Len() {
+		t.Errorf("expected %d active sessions, got %d", srv.Sessions().Len(), stats.ActiveSessions)
+	}
+	if calls := stats.Requests["tools/call"]; calls.Count != 1 || calls.Latency.Count != 1 {
+		t.Errorf("expected one tools/call request, got %+v", calls)
+	}
+	if calls := stats.Requests[unknownMethod]; calls.Count != 1 || calls.Errors != 1 {
+		t.Errorf("expected one failed unknown request, got %+v", calls)
+	}
+	if calls := stats.Tools["fail"]; calls.Count != 1 || calls.Errors != 1 {
+		t.Errorf("expected one failed tool call, got %+v", calls)
+	}
+
+	var buf bytes.Buffer
+	if err := srv.WritePrometheus(&buf, "mcp"); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	for _, line := range []string{
+		`mcp_rpc_requests_total{method="tools/call"} 1`,
+		`mcp_tool_call_errors_total{tool="fail"} 1`,
+		`mcp_tool_call_duration_seconds_count{tool="fail"} 1`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("expected %q in:\n%s", line, buf.String())
+		}
+	}
+}
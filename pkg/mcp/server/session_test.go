@@ -0,0 +1,283 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+func TestSessionRoots(t *testing.T) {
+	srv := NewServer("test")
+	session := NewSession(context.Background(), srv)
+	if _, err := session.Roots(context.Background()); !errors.Is(err, ErrRootsNotSupported) {
+		t.Errorf("expected ErrRootsNotSupported before initialize, got %v", err)
+	}
+	request(t, session, "initialize", `{"protocolVersion":"2024-11-05","capabilities":{"roots":{"listChanged":true}},"clientInfo":{"name":"test","version":"1.0.0"}}`)
+
+	client := newFakeClient(session).answer("roots/list", `{"roots":[{"uri":"file:///work","name":"work"}]}`)
+
+	for i := 0; i < 2; i++ {
+		roots, err := session.Roots(context.Background())
+		if err != nil {
+			t.Fatalf("Roots failed: %v", err)
+		}
+		if len(roots) != 1 || roots[0].URI != "file:///work" {
+			t.Errorf("unexpected roots: %+v", roots)
+		}
+	}
+	if n := client.requestCount("roots/list"); n != 1 {
+		t.Errorf("expected cached roots, got %d requests", n)
+	}
+
+	client.answer("roots/list", `{"roots":[]}`)
+	session.HandleNotification(&protocol.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  "notifications/roots/list_changed",
+	})
+	if roots, err := session.Roots(context.Background()); err != nil || len(roots) != 0 || client.requestCount("roots/list") != 2 {
+		t.Errorf("expected refreshed roots after list_changed, got %+v, %v", roots, err)
+	}
+}
+
+func TestLoggingSetLevel(t *testing.T) {
+	srv := NewServer("test", WithDefaultCapabilities())
+	session := newTestSession(t, srv)
+	sender := &fakeClient{}
+	session.SetNotificationSender(sender)
+
+	srv.Log(protocol.LoggingLevelDebug, "db", "connecting")
+	srv.Log(protocol.LoggingLevelInfo, "db", "connected")
+	if len(sender.methods) != 1 {
+		t.Fatalf("expected only info message at the default level, got %v", sender.params)
+	}
+
+	request(t, session, "logging/setLevel", `{"level":"error"}`)
+	session.Log(protocol.LoggingLevelWarning, "db", "slow query")
+	session.Log(protocol.LoggingLevelCritical, "db", "connection lost")
+	if len(sender.methods) != 2 {
+		t.Fatalf("expected messages filtered by level, got %v", sender.params)
+	}
+	params := sender.params[1].(protocol.LoggingMessageNotificationParams)
+	if sender.methods[1] != "notifications/message" || params.Level != protocol.LoggingLevelCritical || params.Data != "connection lost" {
+		t.Errorf("unexpected log message: %s %+v", sender.methods[1], params)
+	}
+
+	if rpcErr := requestError(t, session, "logging/setLevel", `{"level":"verbose"}`); rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("expected invalid params for unknown level, got %v", rpcErr)
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	srv := NewServer("test", WithKeepAlive(5*time.Millisecond, 20*time.Millisecond))
+	session := NewSession(context.Background(), srv)
+	client := newFakeClient(session).answer("ping", `{}`)
+	request(t, session, "initialize", `{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}`)
+
+	for client.requestCount("ping") < 3 {
+		select {
+		case <-session.Done():
+			t.Fatalf("session closed while the client answered pings")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	client.silent.Store(true)
+	select {
+	case <-session.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected the session to close after unanswered pings")
+	}
+}
+
+func TestSessionAuthInfo(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("whoami", func(ctx context.Context) (string, error) {
+		info := AuthInfoFromContext(ctx)
+		if !info.HasScope("read") {
+			return "", fmt.Errorf("missing scope read")
+		}
+		return info.Principal, nil
+	}, "")
+	session := newTestSession(t, srv)
+
+	if result := callTool(t, session, `{"name":"whoami","arguments":{}}`); !result.IsError {
+		t.Errorf("expected an error without auth info, got %+v", result)
+	}
+
+	session.SetAuthInfo(&AuthInfo{Principal: "alice", Scopes: []string{"read"}})
+	if result := callTool(t, session, `{"name":"whoami","arguments":{}}`); resultText(result) != "alice" {
+		t.Errorf("expected alice, got %+v", result)
+	}
+}
+
+func TestSessionConnInfo(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("caller", func(ctx context.Context) string {
+		info := ConnInfoFromContext(ctx)
+		if info == nil {
+			return "unknown"
+		}
+		return info.Transport + " " + info.RemoteAddr + " " + info.Header.Get("X-Tenant-Id")
+	}, "")
+	session := newTestSession(t, srv)
+
+	if result := callTool(t, session, `{"name":"caller","arguments":{}}`); resultText(result) != "unknown" {
+		t.Errorf("expected unknown caller, got %+v", result)
+	}
+
+	session.SetConnInfo(&ConnInfo{
+		Transport:  "websocket",
+		RemoteAddr: "10.0.0.1:5000",
+		Header:     http.Header{"X-Tenant-Id": {"acme"}},
+	})
+	if result := callTool(t, session, `{"name":"caller","arguments":{}}`); resultText(result) != "websocket 10.0.0.1:5000 acme" {
+		t.Errorf("unexpected caller, got %+v", result)
+	}
+}
+
+func TestHandleRequestContext(t *testing.T) {
+	type traceKey struct{}
+
+	srv := NewServer("test")
+	srv.AddTool("trace", func(ctx context.Context) (string, error) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		trace, _ := ctx.Value(traceKey{}).(string)
+		return trace, nil
+	}, "")
+	session := newTestSession(t, srv)
+
+	// Values of the transport's context reach handlers, but its
+	// cancellation does not
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "abc"))
+	cancel()
+	resp, err := session.HandleRequestContext(ctx, &protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(2),
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"trace","arguments":{}}`),
+	})
+	if err != nil {
+		t.Fatalf("tools/call failed: %v", err)
+	}
+	result, ok := resp.Result.(protocol.CallToolResult)
+	if !ok {
+		t.Fatalf("expected CallToolResult, got %T", resp.Result)
+	}
+	if result.IsError || resultText(result) != "abc" {
+		t.Errorf("expected trace abc, got %+v", result)
+	}
+}
+
+func TestSessionManager(t *testing.T) {
+	srv := NewServer("test")
+	first := newTestSession(t, srv)
+	second := NewSession(context.Background(), srv)
+
+	if first.ID() == "" || first.ID() == second.ID() {
+		t.Fatalf("expected unique session IDs, got %q and %q", first.ID(), second.ID())
+	}
+	if got, ok := srv.Sessions().Get(first.ID()); !ok || got != first {
+		t.Errorf("expected to find session %s", first.ID())
+	}
+
+	infos := srv.Sessions().Info()
+	if len(infos) != 2 || infos[0].ID != first.ID() || infos[1].ID != second.ID() {
+		t.Fatalf("expected sessions oldest first, got %+v", infos)
+	}
+	if !infos[0].Initialized || infos[0].ClientInfo.Name != "test" || infos[1].Initialized {
+		t.Errorf("unexpected session info %+v", infos)
+	}
+
+	if err := srv.Sessions().Close(first.ID()); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	select {
+	case <-first.Done():
+	default:
+		t.Error("expected closed session to be done")
+	}
+	if srv.Sessions().Len() != 1 {
+		t.Errorf("expected 1 open session, got %d", srv.Sessions().Len())
+	}
+	if err := srv.Sessions().Close(first.ID()); err == nil {
+		t.Error("expected error closing an unknown session")
+	}
+
+	srv.Sessions().CloseAll()
+	if srv.Sessions().Len() != 0 {
+		t.Errorf("expected no open sessions, got %d", srv.Sessions().Len())
+	}
+}
+
+func TestCreateMessage(t *testing.T) {
+	srv := NewServer("test", WithClientRequestTimeout(20*time.Millisecond))
+	params := protocol.CreateMessageRequestParams{
+		Messages:  []protocol.SamplingMessage{{Role: protocol.RoleUser, Content: protocol.NewTextContent("Capital of France?")}},
+		MaxTokens: 10,
+	}
+
+	session := newTestSession(t, srv)
+	if _, err := session.CreateMessage(context.Background(), params); !errors.Is(err, ErrSamplingNotSupported) {
+		t.Errorf("expected ErrSamplingNotSupported, got %v", err)
+	}
+
+	session = NewSession(context.Background(), srv)
+	request(t, session, "initialize", `{"protocolVersion":"2025-03-26","capabilities":{"sampling":{}},"clientInfo":{"name":"test","version":"1.0.0"}}`)
+	client := newFakeClient(session).answer("sampling/createMessage", `{"role":"assistant","content":{"type":"text","text":"Paris"},"model":"test-model","stopReason":"endTurn"}`)
+
+	result, err := session.CreateMessage(context.Background(), params)
+	if err != nil {
+		t.Fatalf("CreateMessage failed: %v", err)
+	}
+	if text, ok := result.Content.(protocol.TextContent); !ok || text.Text != "Paris" || result.Model != "test-model" || result.StopReason != protocol.StopReasonEndTurn {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	// Unanswered requests time out and are cancelled
+	client.silent.Store(true)
+	if err := session.Ping(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected timeout, got %v", err)
+	}
+	if len(client.methods) != 1 || client.methods[0] != "notifications/cancelled" {
+		t.Errorf("expected cancellation notification, got %v", client.methods)
+	}
+}
+
+func TestSessionState(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("count", func(ctx context.Context) int {
+		count, _ := StateValue[int](ctx, "count")
+		StateFromContext(ctx).Set("count", count+1)
+		return count + 1
+	}, "")
+	first := newTestSession(t, srv)
+	second := newTestSession(t, srv)
+
+	for i := 0; i < 3; i++ {
+		callTool(t, first, `{"name":"count","arguments":{}}`)
+	}
+	if result := callTool(t, second, `{"name":"count","arguments":{}}`); resultText(result) != "1" {
+		t.Errorf("expected state to be per session, got %+v", result)
+	}
+	if count, ok := first.State().Get("count"); !ok || count != 3 {
+		t.Errorf("expected count 3, got %v", count)
+	}
+
+	first.State().Set("count", "three")
+	if result := callTool(t, first, `{"name":"count","arguments":{}}`); resultText(result) != "1" {
+		t.Errorf("expected mistyped value to be ignored, got %+v", result)
+	}
+
+	first.State().Delete("count")
+	if _, ok := first.State().Get("count"); ok {
+		t.Error("expected deleted value to be gone")
+	}
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// SessionManager tracks the sessions of a server, so a single server can
+// serve many clients at once. Sessions register themselves when created
+// and are removed when closed.
+type SessionManager struct {
+	server   *Server
+	sessions map[string]*Session
+	mu       sync.RWMutex
+}
+
+// SessionInfo describes a session
+type SessionInfo struct {
+	// ID uniquely identifies the session
+	ID string
+
+	// CreatedAt is when the session was created
+	CreatedAt time.Time
+
+	// ClientInfo is the name and version the client sent in initialize
+	ClientInfo protocol.Implementation
+
+	// ProtocolVersion is the negotiated protocol revision
+	ProtocolVersion string
+
+	// Initialized reports whether the client completed initialization
+	Initialized bool
+
+	// ConnInfo is the connection the session is served over, if recorded
+	ConnInfo *ConnInfo
+}
+
+// newSessionManager creates an empty session manager for a server
+func newSessionManager(server *Server) *SessionManager {
+	return &SessionManager{
+		server:   server,
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Sessions returns the server's session manager
+func (s *Server) Sessions() *SessionManager {
+	return s.sessions
+}
+
+// Create creates a new session, like NewSession
+func (m *SessionManager) Create(ctx context.Context) *Session {
+	return NewSession(ctx, m.server)
+}
+
+// Get returns the open session with the given ID
+func (m *SessionManager) Get(id string) (*Session, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.sessions[id]
+	return session, ok
+}
+
+// List returns the open sessions, oldest first
+func (m *SessionManager) List() []*Session {
+	m.mu.RLock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].createdAt.Before(sessions[j].createdAt)
+	})
+	return sessions
+}
+
+// Info describes the open sessions, oldest first
+func (m *SessionManager) Info() []SessionInfo {
+	sessions := m.List()
+	infos := make([]SessionInfo, len(sessions))
+	for i, session := range sessions {
+		infos[i] = session.Info()
+	}
+	return infos
+}
+
+// Len returns the number of open sessions
+func (m *SessionManager) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.sessions)
+}
+
+// Close closes the session with the given ID
+func (m *SessionManager) Close(id string) error {
+	session, ok := m.Get(id)
+	if !ok {
+		return fmt.Errorf("session %s not found", id)
+	}
+	return session.Close()
+}
+
+// CloseAll closes every open session
+func (m *SessionManager) CloseAll() {
+	for _, session := range m.List() {
+		session.Close()
+	}
+}
+
+// add registers a session
+func (m *SessionManager) add(session *Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[session.id] = session
+	m.server.logger.Debug("session created", "session", session.id)
+}
+
+// remove forgets a session
+func (m *SessionManager) remove(session *Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[session.id]; ok {
+		delete(m.sessions, session.id)
+		m.server.logger.Debug("session closed", "session", session.id)
+	}
+}
+
+// logger returns the server's logger annotated with the session ID
+func (s *Session) logger() *slog.Logger {
+	return s.log
+}
+
+// ID returns the session's unique identifier
+func (s *Session) ID() string {
+	return s.id
+}
+
+// CreatedAt returns when the session was created
+func (s *Session) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+// Info describes the session
+func (s *Session) Info() SessionInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return SessionInfo{
+		ID:              s.id,
+		CreatedAt:       s.createdAt,
+		ClientInfo:      s.clientInfo,
+		ProtocolVersion: s.version,
+		Initialized:     s.initialized,
+		ConnInfo:        s.connInfo,
+	}
+}
+
+// newSessionID generates a random session identifier
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate session ID: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrServerShuttingDown is reported to clients for requests that arrive
+// after Shutdown was called
+var ErrServerShuttingDown = errors.New("server is shutting down")
+
+// Stopper is a transport that can be stopped gracefully, flushing pending
+// output and closing its connections
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// RegisterTransport records a transport serving the server, so Shutdown
+// stops it once in-flight work has drained. Transports are stopped in the
+// order they were registered.
+func (s *Server) RegisterTransport(t Stopper) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transports = append(s.transports, t)
+}
+
+// Shutdown gracefully stops the server. It stops accepting new requests,
+// waits for in-flight requests and async tasks until ctx is done, stops the
+// registered transports so they flush pending notifications and responses,
+// and finally closes the remaining sessions. It returns ctx's error if the
+// wait was cut short, along with any transport errors.
+func (s *Server) Shutdown(ctx context.Context) error {
+	var errs []error
+	select {
+	case <-s.drain.close():
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+	}
+
+	s.mu.RLock()
+	transports := s.transports
+	s.mu.RUnlock()
+
+	for _, t := range transports {
+		if err := t.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.sessions.CloseAll()
+	s.logger.Info("server shut down")
+	return errors.Join(errs...)
+}
+
+// ShuttingDown reports whether Shutdown has been called
+func (s *Server) ShuttingDown() bool {
+	s.drain.mu.Lock()
+	defer s.drain.mu.Unlock()
+
+	return s.drain.closing
+}
+
+// drain counts in-flight requests and async tasks so Shutdown can wait for
+// them
+type drain struct {
+	mu      sync.Mutex
+	active  int
+	closing bool
+	idle    chan struct{}
+}
+
+// enter records a new request, returning false once the server is shutting
+// down
+func (d *drain) enter() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closing {
+		return false
+	}
+	d.active++
+	return true
+}
+
+// add records work started by an admitted request, which is accepted even
+// while shutting down
+func (d *drain) add() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.active++
+}
+
+// exit records the end of a request or task
+func (d *drain) exit() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.active--
+	if d.closing && d.active == 0 {
+		close(d.idle)
+	}
+}
+
+// close stops admitting requests and returns a channel that is closed once
+// no work is in flight
+func (d *drain) close() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.closing {
+		d.closing = true
+		d.idle = make(chan struct{})
+		if d.active == 0 {
+			close(d.idle)
+		}
+	}
+	return d.idle
+}
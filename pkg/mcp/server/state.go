@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+// State is a concurrency-safe key-value store kept for the lifetime of a
+// session, so handlers can keep per-client state such as cursors or caches
+type State struct {
+	mu     sync.RWMutex
+	values map[string]interface{}
+}
+
+// Set stores a value under key, replacing any previous value
+func (st *State) Set(key string, value interface{}) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.values == nil {
+		st.values = make(map[string]interface{})
+	}
+	st.values[key] = value
+}
+
+// Get returns the value stored under key
+func (st *State) Get(key string) (interface{}, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	value, ok := st.values[key]
+	return value, ok
+}
+
+// Delete removes the value stored under key
+func (st *State) Delete(key string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.values, key)
+}
+
+// State returns the session's key-value store
+func (s *Session) State() *State {
+	return &s.state
+}
+
+// StateFromContext returns the state of the session handling the current
+// request, or nil if there is none
+func StateFromContext(ctx context.Context) *State {
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return nil
+	}
+	return session.State()
+}
+
+// StateValue returns the value stored under key in the state of the session
+// handling the current request, if there is one of type T
+func StateValue[T any](ctx context.Context, key string) (T, bool) {
+	var zero T
+	state := StateFromContext(ctx)
+	if state == nil {
+		return zero, false
+	}
+
+	value, ok := state.Get(key)
+	if !ok {
+		return zero, false
+	}
+	typed, ok := value.(T)
+	return typed, ok
+}
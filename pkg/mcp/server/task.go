@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// TaskStatus describes the state of an async task
+type TaskStatus string
+
+const (
+	TaskRunning   TaskStatus = "running"
+	TaskCompleted TaskStatus = "completed"
+	TaskFailed    TaskStatus = "failed"
+)
+
+// Task represents the execution of an async tool
+type Task struct {
+	ID        string                   `json:"id"`
+	Tool      string                   `json:"tool"`
+	Status    TaskStatus               `json:"status"`
+	Result    *protocol.CallToolResult `json:"result,omitempty"`
+	Error     string                   `json:"error,omitempty"`
+	CreatedAt time.Time                `json:"createdAt"`
+	UpdatedAt time.Time                `json:"updatedAt"`
+}
+
+// ErrTaskNotFound is returned by a TaskStore when a task does not exist
+var ErrTaskNotFound = errors.New("task not found")
+
+// TaskStore persists async task state and results. Implementations must be
+// safe for concurrent use. A persistent store lets task results survive
+// server restarts and be shared between replicas.
+type TaskStore interface {
+	// Save creates or updates a task
+	Save(ctx context.Context, task *Task) error
+
+	// Get returns the task with the given ID, or ErrTaskNotFound
+	Get(ctx context.Context, id string) (*Task, error)
+
+	// List returns all tasks ordered by creation time
+	List(ctx context.Context) ([]*Task, error)
+
+	// Delete removes a task
+	Delete(ctx context.Context, id string) error
+}
+
+// WithTaskStore sets the store used to persist async tasks
+func WithTaskStore(store TaskStore) ServerOption {
+	return func(s *Server) {
+		s.taskStore = store
+	}
+}
+
+// Task returns the async task with the given ID
+func (s *Server) Task(ctx context.Context, id string) (*Task, error) {
+	return s.taskStore.Get(ctx, id)
+}
+
+// Tasks returns all known async tasks
+func (s *Server) Tasks(ctx context.Context) ([]*Task, error) {
+	return s.taskStore.List(ctx)
+}
+
+// startTask runs an async tool in the background and returns a
+// result referencing the created task
+func (s *Server) startTask(ctx context.Context, name string, tool Tool, arguments map[string]interface{}, args []reflect.Value) (protocol.CallToolResult, error) {
+	id, err := newTaskID()
+	if err != nil {
+		return protocol.CallToolResult{}, err
+	}
+
+	now := time.Now()
+	task := &Task{
+		ID:        id,
+		Tool:      name,
+		Status:    TaskRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	run := func() {
+		defer s.drain.exit()
+		defer s.releaseTool()
+		s.metrics.runningTasks.Add(1)
+		defer s.metrics.runningTasks.Add(-1)
+		result := callToolHandler(ctx, tool.Handler, tool.OutputSchema != nil, arguments, args)
+
+		task.Status = TaskCompleted
+		if result.IsError {
+			task.Status = TaskFailed
+			task.Error = resultText(result)
+		}
+		task.Result = &result
+		task.UpdatedAt = time.Now()
+		if err := s.taskStore.Save(ctx, task); err != nil {
+			s.logger.Error("failed to save task", "task", id, "tool", name, "error", err)
+		}
+	}
+
+	// Save before starting so the task is visible when the handler finishes
+	if err := s.taskStore.Save(ctx, task); err != nil {
+		return protocol.CallToolResult{}, fmt.Errorf("failed to save task: %w", err)
+	}
+	s.drain.add()
+	if err := s.goTool(ctx, run); err != nil {
+		s.drain.exit()
+		task.Status = TaskFailed
+		task.Error = err.Error()
+		task.UpdatedAt = time.Now()
+		s.taskStore.Save(ctx, task)
+		return protocol.CallToolResult{}, err
+	}
+
+	result := protocol.CallToolResult{
+		Content: []interface{}{protocol.NewTextContent(fmt.Sprintf("Task %s started", id))},
+	}
+	result.Meta = map[string]interface{}{"taskId": id}
+	return result, nil
+}
+
+// resultText returns the concatenated text content of a result
+func resultText(result protocol.CallToolResult) string {
+	var text string
+	for _, c := range result.Content {
+		if t, ok := c.(protocol.TextContent); ok {
+			text += t.Text
+		}
+	}
+	return text
+}
+
+// newTaskID generates a random task identifier
+func newTaskID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate task ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// MemoryTaskStore is an in-memory TaskStore. Tasks are lost when the
+// process exits.
+type MemoryTaskStore struct {
+	tasks map[string]Task
+	mu    sync.RWMutex
+}
+
+// NewMemoryTaskStore creates a new in-memory task store
+func NewMemoryTaskStore() *MemoryTaskStore {
+	return &MemoryTaskStore{
+		tasks: make(map[string]Task),
+	}
+}
+
+// Save creates or updates a task
+func (m *MemoryTaskStore) Save(ctx context.Context, task *Task) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tasks[task.ID] = *task
+	return nil
+}
+
+// Get returns the task with the given ID
+func (m *MemoryTaskStore) Get(ctx context.Context, id string) (*Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	task, exists := m.tasks[id]
+	if !exists {
+		return nil, ErrTaskNotFound
+	}
+	return &task, nil
+}
+
+// List returns all tasks ordered by creation time
+func (m *MemoryTaskStore) List(ctx context.Context) ([]*Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tasks := make([]*Task, 0, len(m.tasks))
+	for _, task := range m.tasks {
+		task := task
+		tasks = append(tasks, &task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	return tasks, nil
+}
+
+// Delete removes a task
+func (m *MemoryTaskStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.tasks, id)
+	return nil
+}
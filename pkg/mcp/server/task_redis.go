@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// RedisClient is the subset of Redis commands used by RedisTaskStore.
+// It is small enough to adapt any Redis client library in a few lines.
+type RedisClient interface {
+	// Get returns the value of a key and whether it exists
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// Set sets the value of a key
+	Set(ctx context.Context, key, value string) error
+
+	// Del deletes a key
+	Del(ctx context.Context, key string) error
+
+	// SAdd adds a member to a set
+	SAdd(ctx context.Context, key, member string) error
+
+	// SRem removes a member from a set
+	SRem(ctx context.Context, key, member string) error
+
+	// SMembers returns all members of a set
+	SMembers(ctx context.Context, key string) ([]string, error)
+}
+
+// RedisTaskStore is a TaskStore backed by Redis. Each task is stored as a
+// JSON value, and a set indexes the task IDs for listing.
+type RedisTaskStore struct {
+	client RedisClient
+	prefix string
+}
+
+// NewRedisTaskStore creates a Redis task store with keys under the given prefix
+func NewRedisTaskStore(client RedisClient, prefix string) *RedisTaskStore {
+	return &RedisTaskStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Save creates or updates a task
+func (r *RedisTaskStore) Save(ctx context.Context, task *Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+	if err := r.client.Set(ctx, r.taskKey(task.ID), string(data)); err != nil {
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+	if err := r.client.SAdd(ctx, r.indexKey(), task.ID); err != nil {
+		return fmt.Errorf("failed to index task: %w", err)
+	}
+	return nil
+}
+
+// Get returns the task with the given ID
+func (r *RedisTaskStore) Get(ctx context.Context, id string) (*Task, error) {
+	data, found, err := r.client.Get(ctx, r.taskKey(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if !found {
+		return nil, ErrTaskNotFound
+	}
+
+	var task Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+	return &task, nil
+}
+
+// List returns all tasks ordered by creation time
+func (r *RedisTaskStore) List(ctx context.Context) ([]*Task, error) {
+	ids, err := r.client.SMembers(ctx, r.indexKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	tasks := make([]*Task, 0, len(ids))
+	for _, id := range ids {
+		task, err := r.Get(ctx, id)
+		if err == ErrTaskNotFound {
+			// The task expired or was deleted concurrently
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	return tasks, nil
+}
+
+// Delete removes a task
+func (r *RedisTaskStore) Delete(ctx context.Context, id string) error {
+	if err := r.client.Del(ctx, r.taskKey(id)); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	if err := r.client.SRem(ctx, r.indexKey(), id); err != nil {
+		return fmt.Errorf("failed to unindex task: %w", err)
+	}
+	return nil
+}
+
+// taskKey returns the key holding a task
+func (r *RedisTaskStore) taskKey(id string) string {
+	return r.prefix + "task:" + id
+}
+
+// indexKey returns the key of the task ID set
+func (r *RedisTaskStore) indexKey() string {
+	return r.prefix + "tasks"
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// tableNameRegex matches table names that are safe to interpolate into SQL
+var tableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLTaskStore is a TaskStore backed by a SQL database. It targets SQLite
+// (any database/sql driver such as modernc.org/sqlite or mattn/go-sqlite3)
+// and other databases accepting ? placeholders and ON CONFLICT upserts.
+type SQLTaskStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLTaskStore creates a SQL task store, creating the table if needed
+func NewSQLTaskStore(ctx context.Context, db *sql.DB, table string) (*SQLTaskStore, error) {
+	if !tableNameRegex.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name: %s", table)
+	}
+
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id TEXT PRIMARY KEY,
+		tool TEXT NOT NULL,
+		status TEXT NOT NULL,
+		result TEXT,
+		error TEXT,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`, table)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to create task table: %w", err)
+	}
+
+	return &SQLTaskStore{
+		db:    db,
+		table: table,
+	}, nil
+}
+
+// Save creates or updates a task
+func (s *SQLTaskStore) Save(ctx context.Context, task *Task) error {
+	var result sql.NullString
+	if task.Result != nil {
+		data, err := json.Marshal(task.Result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal task result: %w", err)
+		}
+		result = sql.NullString{String: string(data), Valid: true}
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, tool, status, result, error, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			status = excluded.status,
+			result = excluded.result,
+			error = excluded.error,
+			updated_at = excluded.updated_at`, s.table)
+	_, err := s.db.ExecContext(ctx, query,
+		task.ID, task.Tool, string(task.Status), result, task.Error,
+		task.CreatedAt.UnixNano(), task.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+	return nil
+}
+
+// Get returns the task with the given ID
+func (s *SQLTaskStore) Get(ctx context.Context, id string) (*Task, error) {
+	query := fmt.Sprintf(`SELECT id, tool, status, result, error, created_at, updated_at
+		FROM %s WHERE id = ?`, s.table)
+	task, err := scanTask(s.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTaskNotFound
+	}
+	return task, err
+}
+
+// List returns all tasks ordered by creation time
+func (s *SQLTaskStore) List(ctx context.Context) ([]*Task, error) {
+	query := fmt.Sprintf(`SELECT id, tool, status, result, error, created_at, updated_at
+		FROM %s ORDER BY created_at`, s.table)
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// Delete removes a task
+func (s *SQLTaskStore) Delete(ctx context.Context, id string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, s.table)
+	if _, err := s.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
+
+// scanTask reads a task from a database row
+func scanTask(row interface{ Scan(...interface{}) error }) (*Task, error) {
+	var (
+		task               Task
+		status             string
+		result, taskError  sql.NullString
+		createdAt, updated int64
+	)
+	if err := row.Scan(&task.ID, &task.Tool, &status, &result, &taskError, &createdAt, &updated); err != nil {
+		return nil, err
+	}
+
+	task.Status = TaskStatus(status)
+	task.Error = taskError.String
+	task.CreatedAt = time.Unix(0, createdAt)
+	task.UpdatedAt = time.Unix(0, updated)
+	if result.Valid {
+		task.Result = &protocol.CallToolResult{}
+		if err := json.Unmarshal([]byte(result.String), task.Result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal task result: %w", err)
+		}
+	}
+	return &task, nil
+}
@@ -0,0 +1,321 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// testTaskStore checks the TaskStore contract on store. expire removes a
+// task behind the store's back, as a TTL would; it defaults to Delete.
+func testTaskStore(t *testing.T, store TaskStore, expire func(id string)) {
+	t.Helper()
+	ctx := context.Background()
+	if expire == nil {
+		expire = func(id string) {
+			if err := store.Delete(ctx, id); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Not found
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for a missing task, got %v", err)
+	}
+	if tasks, err := store.List(ctx); err != nil || len(tasks) != 0 {
+		t.Errorf("expected no tasks, got %v (%v)", tasks, err)
+	}
+
+	// Save and load, with timestamps surviving to the nanosecond
+	start := time.Date(2025, 6, 18, 12, 0, 0, 123456789, time.UTC)
+	tasks := []*Task{
+		{ID: "b", Tool: "slow", Status: TaskRunning, CreatedAt: start.Add(time.Second), UpdatedAt: start.Add(time.Second)},
+		{ID: "a", Tool: "slow", Status: TaskRunning, CreatedAt: start, UpdatedAt: start},
+		{ID: "c", Tool: "fast", Status: TaskRunning, CreatedAt: start.Add(2 * time.Second), UpdatedAt: start.Add(2 * time.Second)},
+	}
+	for _, task := range tasks {
+		if err := store.Save(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := store.Get(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Tool != "slow" || got.Status != TaskRunning || got.Result != nil || !got.CreatedAt.Equal(start) {
+		t.Errorf("unexpected task %+v", got)
+	}
+
+	// Saving again updates the task
+	result := protocol.CallToolResult{Content: []interface{}{protocol.NewTextContent("done")}}
+	update := *tasks[1]
+	update.Status = TaskCompleted
+	update.Result = &result
+	update.UpdatedAt = start.Add(time.Minute)
+	if err := store.Save(ctx, &update); err != nil {
+		t.Fatal(err)
+	}
+	failed := *tasks[2]
+	failed.Status = TaskFailed
+	failed.Error = "boom"
+	if err := store.Save(ctx, &failed); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = store.Get(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != TaskCompleted || !got.UpdatedAt.Equal(update.UpdatedAt) || got.Result == nil || resultText(*got.Result) != "done" {
+		t.Errorf("expected the updated task, got %+v", got)
+	}
+	if got, err = store.Get(ctx, "c"); err != nil || got.Status != TaskFailed || got.Error != "boom" {
+		t.Errorf("expected the failed task, got %+v (%v)", got, err)
+	}
+
+	// List orders by creation time
+	if ids := taskIDs(t, store); ids != "a,b,c" {
+		t.Errorf("expected tasks in creation order, got %s", ids)
+	}
+
+	// Expired and deleted tasks are gone
+	expire("b")
+	if _, err := store.Get(ctx, "b"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for an expired task, got %v", err)
+	}
+	if err := store.Delete(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, "missing"); err != nil {
+		t.Errorf("expected deleting a missing task to succeed, got %v", err)
+	}
+	if ids := taskIDs(t, store); ids != "a" {
+		t.Errorf("expected only task a to remain, got %s", ids)
+	}
+}
+
+// taskIDs returns the comma-separated IDs of the tasks listed by store
+func taskIDs(t *testing.T, store TaskStore) string {
+	t.Helper()
+	tasks, err := store.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestMemoryTaskStore(t *testing.T) {
+	testTaskStore(t, NewMemoryTaskStore(), nil)
+}
+
+func TestRedisTaskStore(t *testing.T) {
+	client := newFakeRedis()
+	store := NewRedisTaskStore(client, "mcp:")
+	testTaskStore(t, store, func(id string) {
+		// An expired key leaves its ID in the index
+		client.mu.Lock()
+		delete(client.values, "mcp:task:"+id)
+		client.mu.Unlock()
+	})
+	if members, _ := client.SMembers(context.Background(), "mcp:tasks"); len(members) != 2 {
+		t.Errorf("expected the index to keep the expired task and a, got %v", members)
+	}
+}
+
+func TestSQLTaskStore(t *testing.T) {
+	db := sql.OpenDB(&fakeSQLConnector{tables: make(map[string]map[string][]driver.Value)})
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := NewSQLTaskStore(ctx, db, "tasks; DROP TABLE tasks"); err == nil {
+		t.Error("expected an error for an unsafe table name")
+	}
+	store, err := NewSQLTaskStore(ctx, db, "mcp_tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testTaskStore(t, store, nil)
+}
+
+// fakeRedis is an in-memory RedisClient
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+	sets   map[string]map[string]bool
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: make(map[string]string), sets: make(map[string]map[string]bool)}
+}
+
+func (r *fakeRedis) Get(ctx context.Context, key string) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.values[key]
+	return value, ok, nil
+}
+
+func (r *fakeRedis) Set(ctx context.Context, key, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[key] = value
+	return nil
+}
+
+func (r *fakeRedis) Del(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.values, key)
+	return nil
+}
+
+func (r *fakeRedis) SAdd(ctx context.Context, key, member string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sets[key] == nil {
+		r.sets[key] = make(map[string]bool)
+	}
+	r.sets[key][member] = true
+	return nil
+}
+
+func (r *fakeRedis) SRem(ctx context.Context, key, member string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sets[key], member)
+	return nil
+}
+
+func (r *fakeRedis) SMembers(ctx context.Context, key string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var members []string
+	for member := range r.sets[key] {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// fakeSQLConnector is a database/sql driver understanding the statements
+// of SQLTaskStore, keeping rows in memory keyed by table and task ID. Rows
+// hold the columns in SQLTaskStore's order: id, tool, status, result,
+// error, created_at and updated_at.
+type fakeSQLConnector struct {
+	mu     sync.Mutex
+	tables map[string]map[string][]driver.Value
+}
+
+func (c *fakeSQLConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeSQLConn{c}, nil
+}
+
+func (c *fakeSQLConnector) Driver() driver.Driver { return nil }
+
+type fakeSQLConn struct{ db *fakeSQLConnector }
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{db: c.db, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (c *fakeSQLConn) Close() error { return nil }
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+type fakeSQLStmt struct {
+	db    *fakeSQLConnector
+	query string
+}
+
+func (s *fakeSQLStmt) Close() error  { return nil }
+func (s *fakeSQLStmt) NumInput() int { return -1 }
+
+// table returns the table named after keyword in the statement
+func (s *fakeSQLStmt) table(keyword string) string {
+	_, rest, _ := strings.Cut(s.query, keyword+" ")
+	name, _, _ := strings.Cut(rest, " ")
+	return name
+}
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS "):
+		name := s.table("EXISTS")
+		if s.db.tables[name] == nil {
+			s.db.tables[name] = make(map[string][]driver.Value)
+		}
+	case strings.HasPrefix(s.query, "INSERT INTO "):
+		rows := s.db.tables[s.table("INTO")]
+		id := args[0].(string)
+		if existing, ok := rows[id]; ok && strings.Contains(s.query, "ON CONFLICT(id) DO UPDATE") {
+			// The tool and creation time are kept on conflict
+			args[1], args[5] = existing[1], existing[5]
+		}
+		rows[id] = append([]driver.Value(nil), args...)
+	case strings.HasPrefix(s.query, "DELETE FROM "):
+		delete(s.db.tables[s.table("FROM")], args[0].(string))
+	default:
+		return nil, fmt.Errorf("unsupported statement: %s", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	rows := s.db.tables[s.table("FROM")]
+	var result [][]driver.Value
+	switch {
+	case strings.HasSuffix(s.query, "WHERE id = ?"):
+		if row, ok := rows[args[0].(string)]; ok {
+			result = append(result, row)
+		}
+	case strings.HasSuffix(s.query, "ORDER BY created_at"):
+		for _, row := range rows {
+			result = append(result, row)
+		}
+		sort.Slice(result, func(i, j int) bool {
+			return result[i][5].(int64) < result[j][5].(int64)
+		})
+	default:
+		return nil, fmt.Errorf("unsupported query: %s", s.query)
+	}
+	return &fakeSQLRows{rows: result}, nil
+}
+
+type fakeSQLRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	return []string{"id", "tool", "status", "result", "error", "created_at", "updated_at"}
+}
+
+func (r *fakeSQLRows) Close() error { return nil }
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRequestTimeout is reported to clients as a protocol.RequestTimeout
+// error for requests that ran past the server's request timeout
+var ErrRequestTimeout = errors.New("request timed out")
+
+// ErrToolTimeout is reported in the error result of tool calls that ran
+// past the tool's timeout
+var ErrToolTimeout = errors.New("tool timed out")
+
+// WithRequestTimeout gives every request a deadline of timeout after it
+// arrives. Handlers taking a context.Context see the deadline, and requests
+// still running when it passes are answered with a protocol.RequestTimeout
+// error. Async tools run past the deadline, as they outlive their request.
+func WithRequestTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.requestTimeout = timeout
+	}
+}
+
+// timeoutError creates the error reported for requests past the timeout
+func (s *Server) timeoutError() error {
+	return fmt.Errorf("%w after %v", ErrRequestTimeout, s.requestTimeout)
+}
+
+// toolTimeoutError creates the error reported for tool calls past their
+// timeout
+func toolTimeoutError(name string, timeout time.Duration) error {
+	return fmt.Errorf("%w: %s did not finish within %v", ErrToolTimeout, name, timeout)
+}
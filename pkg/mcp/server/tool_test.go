@@ -0,0 +1,734 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"image"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// callTool calls a tool through the session and returns the result
+func callTool(t *testing.T, session *Session, params string) protocol.CallToolResult {
+	t.Helper()
+
+	resp, err := session.HandleRequest(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(2),
+		Method:  "tools/call",
+		Params:  json.RawMessage(params),
+	})
+	if err != nil {
+		t.Fatalf("tools/call failed: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("tools/call failed: %v", resp.Error)
+	}
+	result, ok := resp.Result.(protocol.CallToolResult)
+	if !ok {
+		t.Fatalf("expected CallToolResult, got %T", resp.Result)
+	}
+	return result
+}
+
+func TestCallToolResultMarshaling(t *testing.T) {
+	type point struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	}
+
+	srv := NewServer("test")
+	srv.AddTool("text", func(s string) string { return s }, "")
+	srv.AddTool("number", func(f float64) float64 { return f * 2 }, "")
+	srv.AddTool("struct", func(f float64) (point, error) { return point{X: f, Y: f}, nil }, "")
+	srv.AddTool("map", func() map[string]int { return map[string]int{"a": 1} }, "")
+	srv.AddTool("nothing", func() {}, "")
+	srv.AddTool("errorOnly", func() error { return nil }, "")
+	session := newTestSession(t, srv)
+
+	tests := []struct {
+		params string
+		want   []interface{}
+	}{
+		{`{"name":"text","arguments":{"arg0":"hi"}}`, []interface{}{protocol.NewTextContent("hi")}},
+		{`{"name":"number","arguments":{"arg0":1.5}}`, []interface{}{protocol.NewTextContent("3")}},
+		{`{"name":"struct","arguments":{"arg0":2}}`, []interface{}{protocol.NewTextContent(`{"x":2,"y":2}`)}},
+		{`{"name":"map","arguments":{}}`, []interface{}{protocol.NewTextContent(`{"a":1}`)}},
+		{`{"name":"nothing","arguments":{}}`, []interface{}{}},
+		{`{"name":"errorOnly","arguments":{}}`, []interface{}{}},
+	}
+
+	for _, tt := range tests {
+		result := callTool(t, session, tt.params)
+		if result.IsError {
+			t.Errorf("%s: unexpected error result: %+v", tt.params, result.Content)
+			continue
+		}
+		got, _ := json.Marshal(result.Content)
+		want, _ := json.Marshal(tt.want)
+		if string(got) != string(want) {
+			t.Errorf("%s: expected content %s, got %s", tt.params, want, got)
+		}
+	}
+}
+
+func TestCallToolMultipleContent(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("mixed", func() []interface{} {
+		return []interface{}{
+			"first",
+			protocol.NewImageContent("aGVsbG8=", "image/png"),
+		}
+	}, "")
+	srv.AddTool("texts", func() []protocol.TextContent {
+		return []protocol.TextContent{protocol.NewTextContent("a"), protocol.NewTextContent("b")}
+	}, "")
+	srv.AddTool("result", func() (protocol.CallToolResult, error) {
+		return protocol.CallToolResult{
+			Content: []interface{}{protocol.NewTextContent("failed softly")},
+			IsError: true,
+		}, nil
+	}, "")
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"mixed","arguments":{}}`)
+	if len(result.Content) != 2 {
+		t.Fatalf("expected 2 content blocks, got %d", len(result.Content))
+	}
+	if _, ok := result.Content[1].(protocol.ImageContent); !ok {
+		t.Errorf("expected ImageContent, got %T", result.Content[1])
+	}
+
+	result = callTool(t, session, `{"name":"texts","arguments":{}}`)
+	if len(result.Content) != 2 {
+		t.Errorf("expected 2 content blocks, got %d", len(result.Content))
+	}
+
+	result = callTool(t, session, `{"name":"result","arguments":{}}`)
+	if !result.IsError || len(result.Content) != 1 {
+		t.Errorf("expected handler-provided error result, got %+v", result)
+	}
+}
+
+func TestCallToolImageResult(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("image", func() image.Image {
+		return image.NewRGBA(image.Rect(0, 0, 2, 2))
+	}, "")
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"image","arguments":{}}`)
+	if len(result.Content) != 1 {
+		t.Fatalf("expected 1 content block, got %d", len(result.Content))
+	}
+	img, ok := result.Content[0].(protocol.ImageContent)
+	if !ok {
+		t.Fatalf("expected ImageContent, got %T", result.Content[0])
+	}
+	if img.MimeType != "image/png" || img.Data == "" {
+		t.Errorf("expected base64 png data, got mimeType %q", img.MimeType)
+	}
+}
+
+func TestAudioContent(t *testing.T) {
+	srv := NewServer("test")
+	clip := []byte("RIFF....WAVEfmt ")
+	srv.AddTool("speak", func() protocol.AudioContent {
+		return protocol.NewAudioContentFromBytes(clip, "audio/wav")
+	}, "")
+	srv.AddPrompt("listen", func() protocol.AudioContent {
+		return protocol.NewAudioContentFromBytes(clip, "audio/wav")
+	}, "")
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"speak","arguments":{}}`)
+	audio, ok := result.Content[0].(protocol.AudioContent)
+	if !ok {
+		t.Fatalf("expected AudioContent, got %T", result.Content[0])
+	}
+	data, err := base64.StdEncoding.DecodeString(audio.Data)
+	if err != nil || !bytes.Equal(data, clip) || audio.Type != "audio" {
+		t.Errorf("unexpected audio content: %+v", audio)
+	}
+
+	prompt := request(t, session, "prompts/get", `{"name":"listen"}`).Result.(protocol.GetPromptResult)
+	if _, ok := prompt.Messages[0].Content.(protocol.AudioContent); !ok {
+		t.Errorf("expected audio prompt message, got %T", prompt.Messages[0].Content)
+	}
+}
+
+func TestCallToolResourceLinkResult(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("search", func() []protocol.Resource {
+		return []protocol.Resource{
+			{URI: "file:///a.txt", Name: "a.txt", MimeType: "text/plain"},
+			{URI: "file:///b.txt", Name: "b.txt"},
+		}
+	}, "")
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"search","arguments":{}}`)
+	if len(result.Content) != 2 {
+		t.Fatalf("expected 2 content blocks, got %d", len(result.Content))
+	}
+	data, err := json.Marshal(result.Content[0])
+	if err != nil {
+		t.Fatalf("failed to marshal resource link: %v", err)
+	}
+	want := `{"type":"resource_link","uri":"file:///a.txt","name":"a.txt","mimeType":"text/plain"}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
+
+func TestCallToolEmbeddedResourceResult(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("export", func(name string) (interface{}, error) {
+		return []interface{}{
+			"exported " + name,
+			protocol.NewTextResourceContents("file:///tmp/"+name, "text/csv", "a,b\n1,2\n"),
+		}, nil
+	}, "")
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"export","arguments":{"arg0":"report.csv"}}`)
+	if len(result.Content) != 2 {
+		t.Fatalf("expected 2 content blocks, got %d", len(result.Content))
+	}
+
+	data, err := json.Marshal(result.Content[1])
+	if err != nil {
+		t.Fatalf("failed to marshal content: %v", err)
+	}
+	want := `{"type":"resource","resource":{"uri":"file:///tmp/report.csv","mimeType":"text/csv","text":"a,b\n1,2\n"}}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
+
+func TestToolInterceptors(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("echo", func(s string) string { return s }, "")
+
+	var order []string
+	srv.UseToolInterceptor(
+		func(ctx context.Context, name string, args map[string]interface{}, next ToolInvoker) (protocol.CallToolResult, error) {
+			order = append(order, "outer")
+			return next(ctx, name, args)
+		},
+		func(ctx context.Context, name string, args map[string]interface{}, next ToolInvoker) (protocol.CallToolResult, error) {
+			order = append(order, "inner")
+			args["arg0"] = "intercepted"
+			return next(ctx, name, args)
+		},
+	)
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"echo","arguments":{"arg0":"hello"}}`)
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("unexpected interceptor order: %v", order)
+	}
+	if text := result.Content[0].(protocol.TextContent).Text; text != "intercepted" {
+		t.Errorf("expected rewritten argument, got %q", text)
+	}
+}
+
+func TestAuditInterceptor(t *testing.T) {
+	var logs bytes.Buffer
+	srv := NewServer("test", WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	srv.AddTool("fail", func(s string) error { return errors.New("boom") }, "")
+	srv.AddTool("login", func(user, pin string, extra map[string]interface{}) string { return user }, "", WithSensitiveArguments("arg1"))
+
+	var buf bytes.Buffer
+	var records []AuditRecord
+	srv.UseToolInterceptor(AuditInterceptor(
+		NewJSONLinesAuditSink(&buf),
+		AuditFunc(func(ctx context.Context, record AuditRecord) error {
+			records = append(records, record)
+			return nil
+		}),
+		AuditFunc(func(ctx context.Context, record AuditRecord) error {
+			return errors.New("sink unavailable")
+		}),
+	))
+	session := newTestSession(t, srv)
+
+	callTool(t, session, `{"name":"fail","arguments":{"arg0":"x"}}`)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 audit record, got %d", len(records))
+	}
+	record := records[0]
+	if record.Tool != "fail" || !record.IsError || record.Client.Name != "test" || record.Arguments["arg0"] != "x" {
+		t.Errorf("unexpected audit record: %+v", record)
+	}
+
+	var logged AuditRecord
+	if err := json.Unmarshal(buf.Bytes(), &logged); err != nil {
+		t.Fatalf("invalid audit log line %q: %v", buf.String(), err)
+	}
+	if logged.Tool != "fail" || !logged.IsError {
+		t.Errorf("unexpected logged record: %+v", logged)
+	}
+	if !strings.Contains(logs.String(), "failed to audit tool call") || !strings.Contains(logs.String(), "sink unavailable") {
+		t.Errorf("expected the sink error to be logged, got %q", logs.String())
+	}
+
+	// Sensitive arguments and those matching the default patterns are redacted
+	callTool(t, session, `{"name":"login","arguments":{"arg0":"ada","arg1":"1234","arg2":{"apiToken":"s3cret"}}}`)
+	want := map[string]interface{}{"arg0": "ada", "arg1": Redacted, "arg2": map[string]interface{}{"apiToken": Redacted}}
+	if got := records[1].Arguments; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected redacted arguments %v, got %v", want, got)
+	}
+	if strings.Contains(buf.String(), "1234") || strings.Contains(buf.String(), "s3cret") {
+		t.Errorf("audit log holds secrets: %s", buf.String())
+	}
+}
+
+func TestCallToolProgress(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("count", func(ctx context.Context, n int) (int, error) {
+		for i := 1; i <= n; i++ {
+			if err := ReportProgress(ctx, float64(i), float64(n), ""); err != nil {
+				return 0, err
+			}
+		}
+		return n, nil
+	}, "")
+	session := newTestSession(t, srv)
+	sender := &fakeClient{}
+	session.SetNotificationSender(sender)
+
+	result := callTool(t, session, `{"_meta":{"progressToken":"tok"},"name":"count","arguments":{"arg0":3}}`)
+	if result.IsError {
+		t.Fatalf("unexpected error result: %+v", result.Content)
+	}
+	if len(sender.methods) != 3 || sender.methods[0] != "notifications/progress" {
+		t.Fatalf("expected 3 progress notifications, got %v", sender.methods)
+	}
+	last := sender.params[2].(protocol.ProgressNotificationParams)
+	if last.ProgressToken != "tok" || last.Progress != 3 || *last.Total != 3 {
+		t.Errorf("unexpected progress params: %+v", last)
+	}
+
+	// Without a progress token no notifications are sent
+	sender.methods = nil
+	callTool(t, session, `{"name":"count","arguments":{"arg0":2}}`)
+	if len(sender.methods) != 0 {
+		t.Errorf("expected no notifications without a progress token, got %v", sender.methods)
+	}
+}
+
+func TestCancelRequest(t *testing.T) {
+	started := make(chan struct{})
+	srv := NewServer("test")
+	srv.AddTool("wait", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, "")
+	session := newTestSession(t, srv)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := session.HandleRequest(&protocol.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      protocol.NewIntID(7),
+			Method:  "tools/call",
+			Params:  json.RawMessage(`{"name":"wait","arguments":{}}`),
+		})
+		errc <- err
+	}()
+
+	<-started
+	err := session.HandleNotification(&protocol.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  "notifications/cancelled",
+		Params:  json.RawMessage(`{"requestId":7,"reason":"user aborted"}`),
+	})
+	if err != nil {
+		t.Fatalf("cancellation failed: %v", err)
+	}
+
+	if err := <-errc; !errors.Is(err, ErrRequestCancelled) {
+		t.Errorf("expected ErrRequestCancelled, got %v", err)
+	}
+}
+
+func TestAsyncToolTask(t *testing.T) {
+	release := make(chan struct{})
+	srv := NewServer("test")
+	srv.AddAsyncTool("slow", func(s string) (string, error) {
+		<-release
+		return "done " + s, nil
+	}, "")
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"slow","arguments":{"arg0":"job"}}`)
+	id, ok := result.Meta["taskId"].(string)
+	if !ok {
+		t.Fatalf("expected task ID in result meta, got %+v", result.Meta)
+	}
+
+	task, err := srv.Task(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if task.Status != TaskRunning {
+		t.Errorf("expected running task, got %s", task.Status)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for task.Status == TaskRunning && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		task, _ = srv.Task(context.Background(), id)
+	}
+	if task.Status != TaskCompleted {
+		t.Fatalf("expected completed task, got %s", task.Status)
+	}
+	if text := resultText(*task.Result); text != "done job" {
+		t.Errorf("expected task result %q, got %q", "done job", text)
+	}
+}
+
+func TestReplaceAsyncTool(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddAsyncTool("slow", func(s string) string { return "v1 " + s }, "")
+	srv.ReplaceTool("slow", func(s string) string { return "v2 " + s }, "")
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"slow","arguments":{"arg0":"job"}}`)
+	id, ok := result.Meta["taskId"].(string)
+	if !ok {
+		t.Fatalf("expected the replaced tool to stay async, got %+v", result)
+	}
+	deadline := time.Now().Add(time.Second)
+	task, _ := srv.Task(context.Background(), id)
+	for task.Status == TaskRunning && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		task, _ = srv.Task(context.Background(), id)
+	}
+	if task.Status != TaskCompleted || resultText(*task.Result) != "v2 job" {
+		t.Errorf("expected the replacement to complete the task, got %+v", task)
+	}
+}
+
+func TestWorkerPoolRejectsWhenFull(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := NewServer("test", WithWorkerPool(1, 0, QueueFullReject))
+	srv.AddTool("block", func() {
+		close(started)
+		<-release
+	}, "")
+	srv.AddTool("quick", func() string { return "ok" }, "")
+	session := newTestSession(t, srv)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		callTool(t, session, `{"name":"block","arguments":{}}`)
+	}()
+	<-started
+
+	if rpcErr := requestError(t, session, "tools/call", `{"name":"quick","arguments":{}}`); rpcErr.Message != ErrPoolFull.Error() {
+		t.Errorf("expected ErrPoolFull, got %v", rpcErr)
+	}
+
+	close(release)
+	<-done
+	if result := callTool(t, session, `{"name":"quick","arguments":{}}`); result.IsError {
+		t.Errorf("unexpected error result after pool drained: %+v", result.Content)
+	}
+}
+
+func TestToolConcurrencyLimit(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	busy := protocol.NewError(-32000, "busy, retry later")
+	srv := NewServer("test", WithToolConcurrencyLimit(1, 1, WithRejectionError(busy)))
+	srv.AddTool("block", func() {
+		close(started)
+		<-release
+	}, "")
+	srv.AddTool("quick", func() string { return "ok" }, "")
+
+	// The limit is shared by all sessions
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		callTool(t, newTestSession(t, srv), `{"name":"block","arguments":{}}`)
+	}()
+	<-started
+
+	queued := make(chan protocol.CallToolResult)
+	go func() {
+		queued <- callTool(t, newTestSession(t, srv), `{"name":"quick","arguments":{}}`)
+	}()
+	for srv.Stats().QueuedCalls != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	rpcErr := requestError(t, newTestSession(t, srv), "tools/call", `{"name":"quick","arguments":{}}`)
+	if rpcErr.Code != busy.Code || rpcErr.Message != busy.Message {
+		t.Errorf("expected rejection error %v, got %v", busy, rpcErr)
+	}
+
+	close(release)
+	<-blocked
+	if result := <-queued; result.IsError || resultText(result) != "ok" {
+		t.Errorf("expected queued call to run, got %+v", result.Content)
+	}
+
+	// Calls waiting longer than the queue timeout are rejected
+	srv = NewServer("test", WithToolConcurrencyLimit(1, 1, WithQueueTimeout(10*time.Millisecond)))
+	hold := make(chan struct{})
+	srv.AddTool("hold", func() { <-hold }, "")
+	srv.AddTool("quick", func() string { return "ok" }, "")
+	held := make(chan struct{})
+	go func() {
+		defer close(held)
+		callTool(t, newTestSession(t, srv), `{"name":"hold","arguments":{}}`)
+	}()
+	for len(srv.toolLimit.slots) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if rpcErr := requestError(t, newTestSession(t, srv), "tools/call", `{"name":"quick","arguments":{}}`); rpcErr.Message != ErrToolConcurrencyLimit.Error() {
+		t.Errorf("expected ErrToolConcurrencyLimit, got %v", rpcErr)
+	}
+	close(hold)
+	<-held
+}
+
+func TestContentRoundTrip(t *testing.T) {
+	messages := []protocol.PromptMessage{
+		{Role: protocol.RoleUser, Content: protocol.NewTextContent("hello")},
+		{Role: protocol.RoleUser, Content: protocol.NewImageContent("aW1n", "image/png")},
+		{Role: protocol.RoleUser, Content: protocol.NewEmbeddedResource(protocol.NewBlobResourceContents("file:///a.bin", "application/octet-stream", []byte("data")), nil)},
+		{Role: protocol.RoleAssistant, Content: protocol.NewEmbeddedResource(protocol.NewTextResourceContents("file:///a.txt", "text/plain", "text"), nil)},
+		{Role: protocol.RoleAssistant, Content: protocol.NewResourceLink(protocol.Resource{URI: "file:///b.txt", Name: "b"})},
+	}
+	srv := NewServer("test")
+	srv.AddPrompt("mixed", func() []protocol.PromptMessage { return messages }, "")
+	session := newTestSession(t, srv)
+
+	data, err := json.Marshal(request(t, session, "prompts/get", `{"name":"mixed"}`).Result)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var result protocol.GetPromptResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(result.Messages, messages) {
+		t.Errorf("expected %+v, got %+v", messages, result.Messages)
+	}
+
+	var message protocol.SamplingMessage
+	if err := json.Unmarshal([]byte(`{"role":"user","content":{"type":"video"}}`), &message); err == nil {
+		t.Errorf("expected an error for an unknown content type")
+	}
+}
+
+func TestToolTimeout(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("slow", func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}, "", WithToolTimeout(20*time.Millisecond))
+	srv.AddTool("fast", func() string { return "done" }, "", WithToolTimeout(time.Second))
+
+	// Handlers ignoring their context time out all the same
+	release := make(chan struct{})
+	defer close(release)
+	srv.AddTool("stuck", func() string {
+		<-release
+		return "done"
+	}, "", WithToolTimeout(20*time.Millisecond))
+	session := newTestSession(t, srv)
+
+	result := callTool(t, session, `{"name":"slow","arguments":{}}`)
+	if !result.IsError || resultText(result) != "tool timed out: slow did not finish within 20ms" {
+		t.Errorf("expected timeout error result, got %+v", result)
+	}
+	start := time.Now()
+	result = callTool(t, session, `{"name":"stuck","arguments":{}}`)
+	if !result.IsError || resultText(result) != "tool timed out: stuck did not finish within 20ms" {
+		t.Errorf("expected timeout error result, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the call to return at its deadline, took %v", elapsed)
+	}
+	if result := callTool(t, session, `{"name":"fast","arguments":{}}`); resultText(result) != "done" {
+		t.Errorf("expected fast tool to finish, got %+v", result)
+	}
+}
+
+func TestToolHandlerFunc(t *testing.T) {
+	schema := map[string]interface{}{"type": "object", "properties": map[string]interface{}{"q": map[string]interface{}{"type": "string"}}}
+	srv := NewServer("test")
+	srv.AddTool("search", ToolHandlerFunc(func(ctx context.Context, args map[string]interface{}) (protocol.CallToolResult, error) {
+		return protocol.CallToolResult{Content: []interface{}{protocol.NewTextContent("found " + args["q"].(string))}}, nil
+	}), "Search", WithInputSchema(schema))
+	session := newTestSession(t, srv)
+
+	tools := request(t, session, "tools/list", `{}`).Result.(protocol.ListToolsResult).Tools
+	if !reflect.DeepEqual(tools[0].InputSchema, schema) {
+		t.Errorf("expected input schema %v, got %v", schema, tools[0].InputSchema)
+	}
+
+	result := callTool(t, session, `{"name":"search","arguments":{"q":"go"}}`)
+	if text := resultText(result); text != "found go" {
+		t.Errorf("expected raw arguments to reach the handler, got %q", text)
+	}
+}
+
+func TestTypedTool(t *testing.T) {
+	type Query struct {
+		Text  string `json:"text" description:"Search text"`
+		Limit int    `json:"limit,omitempty"`
+	}
+	type Hits struct {
+		Count int `json:"count"`
+	}
+	srv := NewServer("test")
+	err := AddTypedTool(srv, "search", func(ctx context.Context, q Query) (Hits, error) {
+		if q.Text == "" {
+			return Hits{}, errors.New("empty query")
+		}
+		return Hits{Count: len(q.Text) + q.Limit}, nil
+	}, "Search")
+	if err != nil {
+		t.Fatalf("AddTypedTool failed: %v", err)
+	}
+	if err := AddTypedTool(srv, "bad", func(ctx context.Context, s string) (string, error) { return s, nil }, ""); err == nil {
+		t.Error("expected non-object arguments to be rejected")
+	}
+	session := NewSession(context.Background(), srv)
+	request(t, session, "initialize", `{"protocolVersion":"`+protocol.ProtocolVersion20250618+`","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}`)
+
+	tools := request(t, session, "tools/list", `{}`).Result.(protocol.ListToolsResult).Tools
+	if required := tools[0].InputSchema["required"]; !reflect.DeepEqual(required, []string{"text"}) {
+		t.Errorf("expected the schema to require text, got %v", required)
+	}
+
+	result := callTool(t, session, `{"name":"search","arguments":{"text":"go","limit":3}}`)
+	if hits, ok := result.StructuredContent.(Hits); !ok || hits.Count != 5 {
+		t.Errorf("expected structured hits, got %+v", result)
+	}
+	if result = callTool(t, session, `{"name":"search","arguments":{}}`); !result.IsError || resultText(result) != "empty query" {
+		t.Errorf("expected handler error result, got %+v", result)
+	}
+	if result = callTool(t, session, `{"name":"search","arguments":{"text":1}}`); !result.IsError {
+		t.Errorf("expected invalid arguments error result, got %+v", result)
+	}
+}
+
+// Types embedded in the arguments of TestEmbeddedFields
+type (
+	EmbeddedBase struct {
+		ID    string `json:"id"`
+		Token string `json:"token" sensitive:"true"`
+		Name  string
+	}
+	EmbeddedPage struct {
+		Cursor string `json:"cursor,omitempty"`
+		Name   string
+	}
+	EmbeddedNamed struct {
+		Value int `json:"value"`
+	}
+	embeddedHidden struct {
+		Hidden string `json:"hidden"`
+	}
+)
+
+func TestEmbeddedFields(t *testing.T) {
+	type Args struct {
+		EmbeddedBase
+		*EmbeddedPage
+		embeddedHidden
+		EmbeddedNamed `json:"named"`
+		ID            int `json:"id,omitempty"`
+		Query         string
+	}
+
+	// Promoted fields are named as encoding/json names them: the shallow ID
+	// hides the embedded one, and the Name both embedded structs hold at the
+	// same depth is dropped
+	schema := typeSchema(reflect.TypeOf(Args{}), make(map[reflect.Type]bool))
+	properties := schema["properties"].(map[string]interface{})
+	data, err := json.Marshal(Args{EmbeddedPage: &EmbeddedPage{Cursor: "c"}, ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var encoded map[string]interface{}
+	json.Unmarshal(data, &encoded)
+	for name := range encoded {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("schema misses property %q of %s", name, data)
+		}
+	}
+	if len(properties) != len(encoded) {
+		t.Errorf("expected the properties of %s, got %v", data, properties)
+	}
+	if properties["id"].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("expected the shallow id to win, got %v", properties["id"])
+	}
+	if required := schema["required"]; !reflect.DeepEqual(required, []string{"token", "hidden", "named", "Query"}) {
+		t.Errorf("unexpected required properties %v", required)
+	}
+
+	if names := sensitiveFields(reflect.TypeOf(Args{}), make(map[reflect.Type]bool)); !reflect.DeepEqual(names, []string{"token"}) {
+		t.Errorf("expected the promoted token to be sensitive, got %v", names)
+	}
+}
+
+func TestTypedParams(t *testing.T) {
+	srv := NewServer("test")
+	srv.AddTool("trace", func(ctx context.Context) (string, error) {
+		token, _ := ProgressTokenFromContext(ctx).(string)
+		trace, _ := MetaFromContext(ctx)["traceId"].(string)
+		return token + "/" + trace, nil
+	}, "")
+	session := NewSession(context.Background(), srv)
+	request(t, session, "initialize", `{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}`)
+
+	// Params passed in process are decoded like raw JSON
+	resp, err := session.HandleRequest(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(2),
+		Method:  "tools/call",
+		Params: protocol.CallToolRequestParams{
+			RequestParams: protocol.RequestParams{Meta: &protocol.Meta{
+				ProgressToken: "p1",
+				Fields:        map[string]interface{}{"traceId": "t1"},
+			}},
+			Name: "trace",
+		},
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("tools/call failed: %v %+v", err, resp.Error)
+	}
+	if text := resultText(resp.Result.(protocol.CallToolResult)); text != "p1/t1" {
+		t.Errorf("expected the _meta to reach the handler, got %q", text)
+	}
+
+	// Missing params are empty, and malformed params invalid
+	resp, _ = session.HandleRequest(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(3), Method: "tools/list"})
+	if resp.Error != nil {
+		t.Errorf("expected tools/list without params to succeed, got %+v", resp.Error)
+	}
+	resp, _ = session.HandleRequest(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(4), Method: "resources/read", Params: json.RawMessage(`{"uri":1}`)})
+	if resp.Error == nil || resp.Error.Code != protocol.InvalidParams {
+		t.Errorf("expected invalid params, got %+v", resp)
+	}
+}
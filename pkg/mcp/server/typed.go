@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// AddTypedTool adds a tool whose arguments are the fields of In, a struct
+// or map, rather than positional parameters. The input schema is derived
+// from In and, when Out is a struct, the output schema from Out, so
+// results carry Out as structured content. Handler errors and arguments
+// that don't decode into In are reported as error results. Fields tagged
+// `sensitive:"true"` are redacted from request logs.
+func AddTypedTool[In, Out any](s *Server, name string, handler func(context.Context, In) (Out, error), description string, opts ...ToolOption) error {
+	inType := reflect.TypeOf((*In)(nil)).Elem()
+	inputSchema := typeSchema(inType, make(map[reflect.Type]bool))
+	if inputSchema["type"] != "object" {
+		return fmt.Errorf("tool %s: arguments type %s is not a struct or map", name, inType)
+	}
+	typedOpts := []ToolOption{WithInputSchema(inputSchema)}
+	if sensitive := sensitiveFields(inType, make(map[reflect.Type]bool)); len(sensitive) > 0 {
+		typedOpts = append(typedOpts, WithSensitiveArguments(sensitive...))
+	}
+	outputSchema := typeSchema(reflect.TypeOf((*Out)(nil)).Elem(), make(map[reflect.Type]bool))
+	structured := outputSchema["type"] == "object" && outputSchema["properties"] != nil
+	if structured {
+		typedOpts = append(typedOpts, WithOutputSchema(outputSchema))
+	}
+
+	typed := func(ctx context.Context, arguments map[string]interface{}) (protocol.CallToolResult, error) {
+		if arguments == nil {
+			arguments = map[string]interface{}{}
+		}
+		in, err := convertArgument(arguments, inType)
+		if err != nil {
+			return protocol.CallToolResult{}, fmt.Errorf("invalid arguments: %w", err)
+		}
+		out, err := handler(ctx, in.Interface().(In))
+		if err != nil {
+			return protocol.CallToolResult{}, err
+		}
+		return resultValue(reflect.ValueOf(&out).Elem(), structured), nil
+	}
+	return s.AddTool(name, ToolHandlerFunc(typed), description, append(typedOpts, opts...)...)
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// uriTemplate is a parsed RFC 6570 URI template. It supports simple
+// ({var}), reserved ({+var}) and fragment ({#var}) expansion from levels 1
+// and 2, plus form-style query expansion ({?var} and {&var}) so resource
+// templates can take optional query parameters.
+type uriTemplate struct {
+	template  string
+	parts     []templatePart
+	regex     *regexp.Regexp
+	pathVars  []string
+	queryVars []string
+}
+
+// templatePart is a literal string or an expression of a URI template
+type templatePart struct {
+	literal  string
+	operator byte
+	vars     []string
+}
+
+// moreSpecific reports whether t is tried before other when both match a
+// URI: templates with more literal characters come first, then those with
+// fewer reserved or fragment expressions, which also match slashes, and
+// then by template so the order never varies
+func (t *uriTemplate) moreSpecific(other *uriTemplate) bool {
+	literals, reserved := t.specificity()
+	otherLiterals, otherReserved := other.specificity()
+	switch {
+	case literals != otherLiterals:
+		return literals > otherLiterals
+	case reserved != otherReserved:
+		return reserved < otherReserved
+	default:
+		return t.template < other.template
+	}
+}
+
+// specificity returns the number of literal characters and of reserved or
+// fragment expressions in the template
+func (t *uriTemplate) specificity() (literals, reserved int) {
+	for _, part := range t.parts {
+		switch {
+		case part.vars == nil:
+			literals += len(part.literal)
+		case part.operator == '+' || part.operator == '#':
+			reserved++
+		}
+	}
+	return literals, reserved
+}
+
+// Variables returns the template variable names in order of appearance
+func (t *uriTemplate) Variables() []string {
+	var vars []string
+	for _, part := range t.parts {
+		vars = append(vars, part.vars...)
+	}
+	return vars
+}
+
+// parseURITemplate parses and compiles a URI template
+func parseURITemplate(template string) (*uriTemplate, error) {
+	t := &uriTemplate{template: template}
+
+	rest := template
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			t.parts = append(t.parts, templatePart{literal: rest})
+			break
+		}
+		if start > 0 {
+			t.parts = append(t.parts, templatePart{literal: rest[:start]})
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed expression in template %s", template)
+		}
+		part, err := parseExpression(rest[start+1 : start+end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", template, err)
+		}
+		t.parts = append(t.parts, part)
+		rest = rest[start+end+1:]
+	}
+
+	if err := t.compile(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseExpression parses the contents of a {...} expression
+func parseExpression(expr string) (templatePart, error) {
+	var part templatePart
+	if expr != "" && strings.IndexByte("+#?&", expr[0]) >= 0 {
+		part.operator = expr[0]
+		expr = expr[1:]
+	}
+
+	for _, name := range strings.Split(expr, ",") {
+		if !templateVarRegex.MatchString(name) {
+			return templatePart{}, fmt.Errorf("invalid variable name %q", name)
+		}
+		part.vars = append(part.vars, name)
+	}
+	return part, nil
+}
+
+// templateVarRegex matches valid template variable names
+var templateVarRegex = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// compile builds the regex matching the non-query part of the template.
+// Query expressions are matched separately, so their order and presence
+// in the URI doesn't matter.
+func (t *uriTemplate) compile() error {
+	var b strings.Builder
+	b.WriteString("^")
+
+	hasQuery := false
+	for _, part := range t.parts {
+		switch {
+		case part.vars == nil:
+			b.WriteString(regexp.QuoteMeta(part.literal))
+		case part.operator == '?' || part.operator == '&':
+			hasQuery = true
+			t.queryVars = append(t.queryVars, part.vars...)
+		default:
+			b.WriteString(expressionRegex(part))
+			t.pathVars = append(t.pathVars, part.vars...)
+		}
+	}
+
+	if hasQuery {
+		b.WriteString(`(?:[?&](.*))?`)
+	}
+	b.WriteString("$")
+
+	regex, err := regexp.Compile(b.String())
+	if err != nil {
+		return fmt.Errorf("invalid template %s: %w", t.template, err)
+	}
+	t.regex = regex
+	return nil
+}
+
+// expressionRegex returns the regex matching a path expression
+func expressionRegex(part templatePart) string {
+	value := `([^/?#,]+)`
+	switch part.operator {
+	case '+':
+		value = `([^?#,]*)`
+	case '#':
+		value = `([^,]*)`
+	}
+
+	groups := make([]string, len(part.vars))
+	for i := range part.vars {
+		groups[i] = value
+	}
+	regex := strings.Join(groups, ",")
+	if part.operator == '#' {
+		regex = `(?:#` + regex + `)?`
+	}
+	return regex
+}
+
+// Match matches a URI against the template and returns the decoded
+// variable values. Query variables missing from the URI are omitted.
+func (t *uriTemplate) Match(uri string) (map[string]string, bool) {
+	matches := t.regex.FindStringSubmatch(uri)
+	if matches == nil {
+		return nil, false
+	}
+
+	values := make(map[string]string, len(t.pathVars)+len(t.queryVars))
+	for i, name := range t.pathVars {
+		value, err := url.PathUnescape(matches[i+1])
+		if err != nil {
+			return nil, false
+		}
+		values[name] = value
+	}
+
+	if len(t.queryVars) > 0 {
+		query, err := url.ParseQuery(matches[len(matches)-1])
+		if err != nil {
+			return nil, false
+		}
+		for _, name := range t.queryVars {
+			if query.Has(name) {
+				values[name] = query.Get(name)
+			}
+		}
+	}
+
+	return values, true
+}
+
+// Expand substitutes variable values into the template. Undefined
+// variables are omitted as RFC 6570 requires.
+func (t *uriTemplate) Expand(values map[string]string) string {
+	var b strings.Builder
+	for _, part := range t.parts {
+		if part.vars == nil {
+			b.WriteString(part.literal)
+			continue
+		}
+
+		first := true
+		for _, name := range part.vars {
+			value, ok := values[name]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case first && part.operator == '?':
+				b.WriteString("?")
+			case first && part.operator == '&':
+				b.WriteString("&")
+			case first && part.operator == '#':
+				b.WriteString("#")
+			case !first && (part.operator == '?' || part.operator == '&'):
+				b.WriteString("&")
+			case !first:
+				b.WriteString(",")
+			}
+			first = false
+
+			switch part.operator {
+			case '?', '&':
+				b.WriteString(url.QueryEscape(name) + "=" + escapeUnreserved(value))
+			case '+', '#':
+				b.WriteString(escapeReserved(value))
+			default:
+				b.WriteString(escapeUnreserved(value))
+			}
+		}
+	}
+	return b.String()
+}
+
+// escapeUnreserved percent-encodes everything except RFC 3986 unreserved characters
+func escapeUnreserved(s string) string {
+	return escapeTemplateValue(s, "")
+}
+
+// escapeReserved percent-encodes everything except unreserved and reserved characters
+func escapeReserved(s string) string {
+	return escapeTemplateValue(s, ":/?#[]@!$&'()*+,;=")
+}
+
+// escapeTemplateValue percent-encodes a value, leaving unreserved
+// characters and the given allowed characters intact
+func escapeTemplateValue(s, allowed string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUnreserved(c) || strings.IndexByte(allowed, c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// isUnreserved reports whether c is an RFC 3986 unreserved character
+func isUnreserved(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
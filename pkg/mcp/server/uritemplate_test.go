@@ -0,0 +1,70 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestURITemplateMatch(t *testing.T) {
+	tests := []struct {
+		template string
+		uri      string
+		want     map[string]string
+	}{
+		{"users://{id}", "users://42", map[string]string{"id": "42"}},
+		{"users://{id}", "users://a%20b", map[string]string{"id": "a b"}},
+		{"users://{id}", "users://42/posts", nil},
+		{"file://{+path}", "file:///etc/hosts", map[string]string{"path": "/etc/hosts"}},
+		{"maps://{lat,lng}", "maps://1.5,2.5", map[string]string{"lat": "1.5", "lng": "2.5"}},
+		{"search://{index}{?q,limit}", "search://docs?limit=5&q=go", map[string]string{"index": "docs", "q": "go", "limit": "5"}},
+		{"search://{index}{?q,limit}", "search://docs", map[string]string{"index": "docs"}},
+		{"docs://{page}{#section}", "docs://intro#usage", map[string]string{"page": "intro", "section": "usage"}},
+		{"config.json", "config.json", map[string]string{}},
+		{"config.json", "configxjson", nil},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := parseURITemplate(tt.template)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.template, err)
+		}
+		got, ok := tmpl.Match(tt.uri)
+		if tt.want == nil {
+			if ok {
+				t.Errorf("%s: expected %s not to match, got %v", tt.template, tt.uri, got)
+			}
+			continue
+		}
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %s to match with %v, got %v (ok=%v)", tt.template, tt.uri, tt.want, got, ok)
+		}
+	}
+}
+
+func TestURITemplateExpand(t *testing.T) {
+	values := map[string]string{"id": "a b", "path": "/etc/hosts", "q": "go&more"}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"users://{id}", "users://a%20b"},
+		{"file://{+path}", "file:///etc/hosts"},
+		{"search://all{?q,limit}", "search://all?q=go%26more"},
+		{"docs://x{#path}", "docs://x#/etc/hosts"},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := parseURITemplate(tt.template)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.template, err)
+		}
+		if got := tmpl.Expand(values); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.template, tt.want, got)
+		}
+	}
+
+	if _, err := parseURITemplate("users://{id"); err == nil {
+		t.Error("expected error for unclosed expression")
+	}
+}
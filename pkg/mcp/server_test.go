@@ -33,6 +33,28 @@ func TestAddTool(t *testing.T) {
 	}
 }
 
+func TestAddToolErrorOnly(t *testing.T) {
+	server := NewServer("test")
+
+	// Test error-only tool
+	err := server.AddTool("delete", func(path string) error { return nil }, "Delete a file")
+	if err != nil {
+		t.Errorf("unexpected error adding error-only tool: %v", err)
+	}
+
+	// Test zero-return tool
+	err = server.AddTool("noop", func() {}, "Do nothing")
+	if err != nil {
+		t.Errorf("unexpected error adding zero-return tool: %v", err)
+	}
+
+	// Test too many return values
+	err = server.AddTool("invalid", func() (int, int, error) { return 0, 0, nil }, "Invalid tool")
+	if err == nil {
+		t.Error("expected error for three return values, got nil")
+	}
+}
+
 func TestAddAsyncTool(t *testing.T) {
 	server := NewServer("test")
 
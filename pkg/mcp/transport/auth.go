@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// ErrUnauthorized is returned by authenticators when a request carries no
+// valid credentials
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Authenticator verifies the credentials of an HTTP request and returns the
+// authenticated client, which is attached to the session it opens
+type Authenticator interface {
+	Authenticate(r *http.Request) (*server.AuthInfo, error)
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface
+type AuthenticatorFunc func(r *http.Request) (*server.AuthInfo, error)
+
+// Authenticate calls f(r)
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*server.AuthInfo, error) {
+	return f(r)
+}
+
+// WithAuthenticator requires HTTP transports to authenticate every request,
+// answering those that fail with 401 Unauthorized
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(o *Options) {
+		o.Authenticator = authenticator
+	}
+}
+
+// BearerTokenAuthenticator authenticates requests carrying an
+// "Authorization: Bearer <token>" header, using verify to check the token
+func BearerTokenAuthenticator(verify func(ctx context.Context, token string) (*server.AuthInfo, error)) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (*server.AuthInfo, error) {
+		scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return nil, ErrUnauthorized
+		}
+		return verify(r.Context(), token)
+	})
+}
+
+// APIKeyAuthenticator authenticates requests carrying one of the given API
+// keys in the named header, such as X-API-Key
+func APIKeyAuthenticator(header string, keys map[string]server.AuthInfo) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (*server.AuthInfo, error) {
+		key := r.Header.Get(header)
+		if key == "" {
+			return nil, ErrUnauthorized
+		}
+		// Compare every key in constant time so timing does not leak
+		// which keys exist
+		var match *server.AuthInfo
+		for candidate, info := range keys {
+			if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+				match = &info
+			}
+		}
+		if match == nil {
+			return nil, ErrUnauthorized
+		}
+		return match, nil
+	})
+}
+
+// authenticate checks the credentials of a request when the transport
+// requires authentication. It writes a 401 response, or 403 for tokens
+// lacking a required scope, and returns false when they are not accepted.
+func (o Options) authenticate(w http.ResponseWriter, r *http.Request) (*server.AuthInfo, bool) {
+	if o.Authenticator == nil {
+		return nil, true
+	}
+
+	info, err := o.Authenticator.Authenticate(r)
+	switch {
+	case err == nil:
+		return info, true
+	case errors.Is(err, ErrInsufficientScope):
+		w.Header().Set("WWW-Authenticate", o.challenge("insufficient_scope"))
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, ErrInvalidToken):
+		w.Header().Set("WWW-Authenticate", o.challenge("invalid_token"))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	default:
+		w.Header().Set("WWW-Authenticate", o.challenge(""))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}
+	return nil, false
+}
+
+// authorizeSession checks that a request is authenticated as the same
+// principal that opened the session, writing an error response when not
+func (o Options) authorizeSession(w http.ResponseWriter, r *http.Request, session *server.Session) bool {
+	info, ok := o.authenticate(w, r)
+	if !ok {
+		return false
+	}
+	if expected := session.AuthInfo(); expected != nil && (info == nil || info.Principal != expected.Principal) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
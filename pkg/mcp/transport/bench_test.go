@@ -0,0 +1,182 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/gorilla/websocket"
+)
+
+// fanOutClients is the number of clients notifications are fanned out to
+const fanOutClients = 100
+
+// benchServer returns a server with an echo tool and a text resource
+func benchServer() *server.Server {
+	srv := server.NewServer("bench", server.WithLogger(discardLogger))
+	srv.AddTool("echo", func(text string) string { return text }, "Echo the text")
+	srv.AddResource("file:///notes.txt", func() string { return "notes" }, "Notes")
+	return srv
+}
+
+// initialize initializes a session as a client would
+func initialize(b *testing.B, session *server.Session) {
+	b.Helper()
+
+	resp, err := session.HandleRequest(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(0),
+		Method:  "initialize",
+		Params:  json.RawMessage(`{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"bench","version":"1.0.0"}}`),
+	})
+	if err != nil || resp.Error != nil {
+		b.Fatalf("initialize failed: %v %+v", err, resp)
+	}
+}
+
+// benchStdio sends the same request over a line connection b.N times,
+// waiting for each response
+func benchStdio(b *testing.B, request string) {
+	session := server.NewSession(context.Background(), benchServer())
+	initialize(b, session)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := newLineConn(session, inR, outW, defaultOptions(), discardLogger, newMetrics())
+	done := make(chan struct{})
+	go c.serve(done)
+	defer func() {
+		close(done)
+		inW.Close()
+		outR.Close()
+	}()
+
+	line := []byte(request + "\n")
+	responses := bufio.NewReader(outR)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := inW.Write(line); err != nil {
+			b.Fatal(err)
+		}
+		resp, err := responses.ReadSlice('\n')
+		if err != nil {
+			b.Fatal(err)
+		}
+		if strings.Contains(string(resp), `"error"`) {
+			b.Fatalf("request failed: %s", resp)
+		}
+	}
+}
+
+func BenchmarkStdioToolCall(b *testing.B) {
+	benchStdio(b, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"arg0":"hello"}}}`)
+}
+
+func BenchmarkStdioReadResource(b *testing.B) {
+	benchStdio(b, `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"file:///notes.txt"}}`)
+}
+
+// drain receives the events queued on a stream until done is closed
+func drain(events <-chan []byte, done <-chan struct{}) {
+	for {
+		select {
+		case <-events:
+		case <-done:
+			return
+		}
+	}
+}
+
+func BenchmarkSSENotificationFanOut(b *testing.B) {
+	t := NewSSETransport(server.NewServer("bench")).(*SSETransport)
+	done := make(chan struct{})
+	defer close(done)
+	for i := 0; i < fanOutClients; i++ {
+		client := &sseClient{
+			session: server.NewSession(context.Background(), server.NewServer("bench")),
+			events:  make(chan []byte, 64),
+			logger:  discardLogger,
+			metrics: t.metrics,
+		}
+		t.clients[client.session.ID()] = client
+		go drain(client.events, done)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := t.SendNotification("notifications/resources/updated", benchParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStreamableNotificationFanOut(b *testing.B) {
+	t := NewStreamableHTTPTransport(server.NewServer("bench")).(*StreamableHTTPTransport)
+	done := make(chan struct{})
+	defer close(done)
+	for i := 0; i < fanOutClients; i++ {
+		stream := make(chan []byte, 64)
+		client := &streamableClient{
+			session: server.NewSession(context.Background(), server.NewServer("bench")),
+			streams: map[chan []byte]struct{}{stream: {}},
+			metrics: t.metrics,
+		}
+		t.clients[client.session.ID()] = client
+		go drain(stream, done)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := t.SendNotification("notifications/resources/updated", benchParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWebSocketNotificationFanOut(b *testing.B) {
+	t := NewWebSocketTransport(server.NewServer("bench", server.WithLogger(discardLogger)), WithLogger(discardLogger)).(*WebSocketTransport)
+	mux := http.NewServeMux()
+	t.routes(mux)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	for i := 0; i < fanOutClients; i++ {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer conn.Close()
+		go func() {
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+	}
+	for deadline := time.Now().Add(5 * time.Second); t.ConnectionStats().Active < fanOutClients; {
+		if time.Now().After(deadline) {
+			b.Fatal("clients did not connect")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := t.SendNotification("notifications/resources/updated", benchParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package transport
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// WithCompression enables compression on HTTP transports: permessage-deflate
+// for WebSocket connections and gzip for HTTP responses and event streams to
+// clients that accept it
+func WithCompression() Option {
+	return func(o *Options) {
+		o.Compression = true
+	}
+}
+
+// gzipResponseWriter compresses a response body, flushing the compressor
+// whenever the response is flushed so streamed events are not held back
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+// compress wraps w to gzip the response when compression is enabled and the
+// client accepts gzip. The returned function must be called to finish the
+// response.
+func (o Options) compress(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, func()) {
+	if !o.Compression || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		return w, func() {}
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+	gw := &gzipResponseWriter{ResponseWriter: w}
+	return gw, gw.close
+}
+
+// WriteHeader starts compressing unless the response has no body
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if code != http.StatusNoContent && code != http.StatusNotModified {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Del("Content-Length")
+			w.gz = gzip.NewWriter(w.ResponseWriter)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write compresses b into the response
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.gz.Write(b)
+}
+
+// Flush writes the compressed data so far to the client
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// close finishes the compressed stream
+func (w *gzipResponseWriter) close() {
+	if w.gz != nil {
+		w.gz.Close()
+	}
+}
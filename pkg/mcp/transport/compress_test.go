@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/gorilla/websocket"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+
+// postInitialize posts an initialize request with the given Accept-Encoding
+func postInitialize(t *testing.T, url, acceptEncoding string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(initializeRequest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestCompression(t *testing.T) {
+	srv := server.NewServer("compress", server.WithLogger(discardLogger))
+
+	t.Run("streamable", func(t *testing.T) {
+		ts := httptest.NewServer(NewStreamableHTTPTransport(srv, WithLogger(discardLogger), WithCompression()).(*StreamableHTTPTransport))
+		defer ts.Close()
+
+		// Responses are compressed for clients accepting gzip
+		resp := postInitialize(t, ts.URL, "gzip")
+		if resp.Header.Get("Content-Encoding") != "gzip" {
+			t.Fatalf("expected a gzip response, got headers %v", resp.Header)
+		}
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(gz)
+		if !strings.Contains(string(body), `"protocolVersion"`) {
+			t.Errorf("unexpected response %s", body)
+		}
+
+		// and left alone for others
+		resp = postInitialize(t, ts.URL, "identity")
+		if resp.Header.Get("Content-Encoding") != "" {
+			t.Errorf("expected an uncompressed response, got headers %v", resp.Header)
+		}
+	})
+
+	t.Run("sse", func(t *testing.T) {
+		_, ts := serveSSE(t, srv, WithCompression())
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/events", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.Header.Get("Content-Encoding") != "gzip" {
+			t.Fatalf("expected a gzip event stream, got headers %v", resp.Header)
+		}
+
+		// Events are flushed through the compressor as they are written
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		events := bufio.NewReader(gz)
+		line, err := events.ReadString('\n')
+		if err != nil || line != "event: endpoint\n" {
+			t.Fatalf("expected the endpoint event, got %q (%v)", line, err)
+		}
+		line, _ = events.ReadString('\n')
+		endpoint := ts.URL + strings.TrimSpace(strings.TrimPrefix(line, "data: "))
+
+		post, err := http.Post(endpoint, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":7,"method":"ping"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		post.Body.Close()
+		for {
+			line, err := events.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				var pong protocol.JSONRPCResponse
+				if err := json.Unmarshal([]byte(data), &pong); err != nil {
+					t.Errorf("unexpected event %q", line)
+				}
+				break
+			}
+		}
+	})
+
+	t.Run("websocket", func(t *testing.T) {
+		_, url := serveWebSocket(t, srv, WithCompression())
+		dialer := websocket.Dialer{EnableCompression: true}
+		conn, resp, err := dialer.Dial(url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		if ext := resp.Header.Get("Sec-WebSocket-Extensions"); !strings.Contains(ext, "permessage-deflate") {
+			t.Errorf("expected permessage-deflate to be negotiated, got %q", ext)
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		if _, data, err := conn.ReadMessage(); err != nil || !strings.Contains(string(data), `"result"`) {
+			t.Errorf("expected the ping to be answered, got %s (%v)", data, err)
+		}
+	})
+}
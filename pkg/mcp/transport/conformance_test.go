@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/conformance"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// quietServer keeps the servers of the conformance checks from logging
+var quietServer = conformance.WithServerOptions(server.WithLogger(discardLogger))
+
+func TestTCPConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, srv *server.Server) client.Conn {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr := NewTCPTransport(srv, WithLogger(discardLogger)).(*TCPTransport)
+		go tr.Serve(listener)
+		t.Cleanup(func() { tr.Stop(context.Background()) })
+
+		conn, err := client.DialTCP(context.Background(), listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return conn
+	}, quietServer)
+}
+
+func TestWebSocketConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, srv *server.Server) client.Conn {
+		tr := NewWebSocketTransport(srv, WithLogger(discardLogger)).(*WebSocketTransport)
+		mux := http.NewServeMux()
+		tr.routes(mux)
+		ts := httptest.NewServer(mux)
+		t.Cleanup(ts.Close)
+
+		conn, err := client.DialWebSocket(context.Background(), "ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return conn
+	}, quietServer)
+}
+
+func TestSSEConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, srv *server.Server) client.Conn {
+		_, ts := serveSSE(t, srv)
+		return dialSSE(t, ts.URL)
+	}, quietServer)
+}
+
+func TestStreamableHTTPConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, srv *server.Server) client.Conn {
+		ts := httptest.NewServer(NewStreamableHTTPTransport(srv, WithLogger(discardLogger)).(*StreamableHTTPTransport))
+		t.Cleanup(ts.Close)
+		return client.NewStreamableHTTPConn(ts.URL, nil, nil)
+	}, quietServer)
+}
+
+func TestNATSConformance(t *testing.T) {
+	sessions := 0
+	conformance.Run(t, func(t *testing.T, srv *server.Server) client.Conn {
+		_, bus := startNATS(t, srv)
+		sessions++
+		return newNATSClientConn(bus, "mcp", fmt.Sprintf("session%d", sessions))
+	}, quietServer)
+}
@@ -4,12 +4,17 @@
 //   - Stdio transport for command-line applications
 //   - WebSocket transport for web applications
 //   - Server-Sent Events (SSE) transport for web browsers
+//   - Streamable HTTP transport from the 2025-03-26 protocol revision
+//   - TCP transport for embedded deployments without HTTP
+//   - NATS transport for deployments behind a message bus
 //
 // Each transport implements the Transport interface:
 //
 //	type Transport interface {
 //	    Start() error
-//	    Stop() error
+//	    SendNotification(method string, params interface{}) error
+//	    Stop(ctx context.Context) error
+//	    Stats() Stats
 //	}
 //
 // Stdio Transport:
@@ -22,12 +27,22 @@
 //	    log.Fatal(err)
 //	}
 //
+//	// Start returns at EOF, when the session closes or on Stop, which
+//	// waits for in-flight requests before closing the session
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	t.Stop(ctx)
+//
 // WebSocket Transport:
 //
-//	// Create a WebSocket transport with options
-//	t := transport.NewWebSocketTransport(session,
+//	// Create a WebSocket transport with options. The server acts as the
+//	// SessionFactory, creating an isolated session for each connection.
+//	// Clients are pinged every 54s and dropped if not heard from in 60s;
+//	// WithPingInterval, WithPongWait and WithWriteTimeout tune this.
+//	t := transport.NewWebSocketTransport(srv,
 //	    transport.WithAddress(":8080"),
-//	    transport.WithPath("/ws"),
+//	    transport.WithPingInterval(20*time.Second),
+//	    transport.WithPongWait(30*time.Second),
 //	)
 //
 //	// Start the transport
@@ -37,10 +52,11 @@
 //
 // SSE Transport:
 //
-//	// Create an SSE transport with options
-//	t := transport.NewSSETransport(session,
+//	// Create an SSE transport with options. Each GET /sse stream gets
+//	// its own session and an endpoint event naming the URL, such as
+//	// /message?sessionId=..., to post its messages to.
+//	t := transport.NewSSETransport(srv,
 //	    transport.WithAddress(":8080"),
-//	    transport.WithPath("/events"),
 //	)
 //
 //	// Start the transport
@@ -48,6 +64,156 @@
 //	    log.Fatal(err)
 //	}
 //
+// Streamable HTTP Transport:
+//
+//	// Serve the MCP endpoint at /mcp. Clients POST messages and may open
+//	// a GET event stream for server-initiated messages.
+//	t := transport.NewStreamableHTTPTransport(srv,
+//	    transport.WithAddress(":8080"),
+//	)
+//
+//	// Or mount it on an existing mux
+//	mux.Handle("/mcp", t.(http.Handler))
+//
+// Serving Every HTTP Protocol:
+//
+//	// Serve WebSocket (/ws), SSE (/sse and /message) and streamable
+//	// HTTP (/mcp) clients on one port, sharing connection limits and
+//	// metrics
+//	t := transport.NewMultiHTTPTransport(srv, transport.WithAddress(":8080"))
+//
+// TCP Transport:
+//
+//	// Serve newline-delimited JSON, the stdio framing, over raw TCP with a
+//	// session per connection
+//	t := transport.NewTCPTransport(srv, transport.WithAddress(":9000"))
+//
+//	// Clients dial the server and exchange framed messages
+//	c, err := transport.DialTCP(ctx, "localhost:9000")
+//	c.Send(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(1), Method: "ping"})
+//	resp, err := c.Receive()
+//
+// NATS Transport:
+//
+//	// Clients publish to mcp.<sessionID>, using request/reply for
+//	// requests, and receive notifications on mcp.<sessionID>.events.
+//	// NATSConn is a small interface; adapt a nats.go connection to it.
+//	t := transport.NewNATSTransport(natsConn, "mcp", srv)
+//
+// Authentication:
+//
+// HTTP transports can require every request to be authenticated. Requests
+// without valid credentials get 401 Unauthorized, and requests for an
+// existing session must authenticate as the principal that opened it. The
+// authenticated AuthInfo is attached to the session.
+//
+//	verify := func(ctx context.Context, token string) (*server.AuthInfo, error) {
+//	    // Check the token, returning transport.ErrUnauthorized if invalid
+//	    return &server.AuthInfo{Principal: "alice", Scopes: []string{"read"}}, nil
+//	}
+//	t := transport.NewStreamableHTTPTransport(srv,
+//	    transport.WithAuthenticator(transport.BearerTokenAuthenticator(verify)),
+//	)
+//
+// OAuth Authorization:
+//
+// Remote servers following the MCP authorization spec act as OAuth 2.1
+// protected resources. The transport serves its metadata at
+// /.well-known/oauth-protected-resource and points clients to it from 401
+// challenges. Tokens are checked by the TokenVerifier, such as a JWT
+// validator, and must name the server in their audience.
+//
+//	const resource = "https://mcp.example.com"
+//	t := transport.NewStreamableHTTPTransport(srv,
+//	    transport.WithAuthenticator(transport.OAuthAuthenticator(resource, verifyJWT, "mcp:tools")),
+//	    transport.WithProtectedResource(transport.ProtectedResourceMetadata{
+//	        Resource:             resource,
+//	        AuthorizationServers: []string{"https://auth.example.com"},
+//	    }),
+//	)
+//
+// Connection Info:
+//
+// Transports record the connection of each session, its remote address,
+// TLS state and selected headers of the request that opened it, which
+// handlers read with server.ConnInfoFromContext.
+//
+//	t := transport.NewStreamableHTTPTransport(srv,
+//	    transport.WithForwardedHeaders("X-Tenant-ID"),
+//	)
+//
+// Handlers on HTTP transports can also read the headers of the HTTP request
+// that carried the current MCP request:
+//
+//	srv.AddTool("query", func(ctx context.Context, sql string) (string, error) {
+//	    traceID := transport.HeadersFromContext(ctx).Get("X-Trace-ID")
+//	    return runQuery(ctx, traceID, sql)
+//	}, "Run a query")
+//
+// Logging:
+//
+// Transports log connection events at debug level and errors to a
+// slog.Logger, by default a text logger on stderr. Diagnostics are never
+// written to the protocol stream, so stdout stays clean for stdio framing.
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//	t := transport.NewWebSocketTransport(srv, transport.WithLogger(logger))
+//
+// Wire Tap:
+//
+// To debug a host against the server, a wire tap mirrors every frame
+// received or sent, pretty-printed with its time, direction and session.
+// Frames include secrets as sent, so tap only while debugging.
+//
+//	f, err := os.Create("/tmp/mcp-wire.log")
+//	t := transport.NewStdioTransport(session, transport.WithWireTap(f))
+//
+// Recording and Replay:
+//
+// A recorder writes every frame as a JSON line, for golden tests of
+// dispatch: record a session once, from a real host or a test client, then
+// replay the client's frames into the server and compare what it sends.
+//
+//	f, err := os.Create("testdata/session.jsonl")
+//	t := transport.NewStdioTransport(session, transport.WithRecorder(transport.NewRecorder(f)))
+//
+//	// In a test
+//	f, err := os.Open("testdata/session.jsonl")
+//	want, err := transport.ReadFrames(f)
+//	got, err := transport.Replay(ctx, srv, want)
+//	if diff := transport.DiffFrames(want, got); diff != "" {
+//	    t.Errorf("server behavior changed:\n%s", diff)
+//	}
+//
+// After an intended change, WriteFrames saves the transcript of the replay
+// as the new golden file.
+//
+// Metrics:
+//
+// Every transport counts connections, messages in and out, dropped
+// notifications and request latency; Stats returns a snapshot. Sessions
+// count as connections on the streamable HTTP and NATS transports.
+//
+//	// Serve the stats at /debug/vars
+//	transport.PublishExpvar("mcp", t)
+//
+//	// Or in the Prometheus text format
+//	mux.Handle("/metrics", transport.MetricsHandler("mcp", t))
+//
+// HTTP transports can serve /metrics themselves, including the request,
+// tool call, session and task metrics of the *server.Server they serve:
+//
+//	t := transport.NewStreamableHTTPTransport(srv, transport.WithMetrics("mcp"))
+//
+// Health Checks:
+//
+// HTTP transports can serve /healthz, which succeeds while the process
+// serves HTTP, and /readyz, which succeeds once the transport is listening
+// and fails with 503 as soon as server.Shutdown begins, so load balancers
+// stop routing new clients while in-flight requests drain.
+//
+//	t := transport.NewStreamableHTTPTransport(srv, transport.WithHealthChecks())
+//
 // Transport Options:
 //
 // Each transport type supports configuration through options:
@@ -59,6 +225,29 @@
 //	WithPath(path string)         // Set the endpoint path
 //	WithTLSConfig(config *tls.Config) // Configure TLS
 //
+//	// Largest accepted message, 4 MiB by default. Larger stdio and TCP
+//	// lines, WebSocket messages and HTTP bodies get an invalid request
+//	// error (-32600).
+//	WithMaxMessageSize(size int64)
+//
+//	// Compress WebSocket messages (permessage-deflate) and gzip HTTP
+//	// responses and event streams for clients that accept it
+//	WithCompression()
+//
+//	// Cap WebSocket connections and SSE event streams, rejecting those
+//	// beyond the limit with 503 or queueing them; ConnectionStats
+//	// reports active, queued and rejected connections
+//	WithMaxConnections(max int, policy ConnectionLimitPolicy)
+//
+//	// Send responses in request order for hosts that expect it; requests
+//	// are still handled concurrently (ResponseOrderUnordered by default)
+//	WithResponseOrder(order ResponseOrder)
+//
+//	// WebSocket options
+//	WithPingInterval(d time.Duration) // Ping clients every d
+//	WithPongWait(d time.Duration)     // Drop clients silent for d
+//	WithWriteTimeout(d time.Duration) // Bound each write
+//
 // The transport package handles all the low-level communication details,
 // allowing the server to focus on business logic.
 package transport
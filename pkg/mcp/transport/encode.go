@@ -0,0 +1,84 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+)
+
+// maxPooledBuffer is the capacity above which buffers are dropped rather
+// than pooled, so one large message doesn't pin its memory
+const maxPooledBuffer = 64 << 10
+
+// encodeBuffer is a buffer with a JSON encoder writing to it, reused across
+// messages through encodeBuffers
+type encodeBuffer struct {
+	bytes.Buffer
+	enc *json.Encoder
+}
+
+// encodeBuffers pools the buffers messages are encoded into
+var encodeBuffers = sync.Pool{
+	New: func() interface{} {
+		b := &encodeBuffer{}
+		b.enc = json.NewEncoder(&b.Buffer)
+		return b
+	},
+}
+
+// getEncodeBuffer returns an empty buffer from the pool
+func getEncodeBuffer() *encodeBuffer {
+	b := encodeBuffers.Get().(*encodeBuffer)
+	b.Reset()
+	return b
+}
+
+// encode appends v to the buffer as JSON followed by a newline
+func (b *encodeBuffer) encode(v interface{}) error {
+	return b.enc.Encode(v)
+}
+
+// release returns the buffer to the pool. Its bytes must no longer be used.
+func (b *encodeBuffer) release() {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	encodeBuffers.Put(b)
+}
+
+// eventPrefix and eventSuffix frame a message as a server-sent event
+var (
+	eventPrefix = []byte("event: message\ndata: ")
+	eventSuffix = []byte("\n\n")
+)
+
+// writeEvent writes data as a server-sent message event with a single
+// write
+func writeEvent(w io.Writer, data []byte) error {
+	b := getEncodeBuffer()
+	defer b.release()
+
+	b.Write(eventPrefix)
+	b.Write(data)
+	b.Write(eventSuffix)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// marshalNotification returns the JSON encoding of a notification, kept by
+// the event streams it is queued on
+func marshalNotification(method string, params interface{}) ([]byte, error) {
+	data, err := json.Marshal(&protocol.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/gorilla/websocket"
+)
+
+// benchResponse is a typical tool call response
+var benchResponse = &protocol.JSONRPCResponse{
+	JSONRPC: "2.0",
+	ID:      protocol.NewIntID(42),
+	Result: protocol.CallToolResult{
+		Content: []interface{}{protocol.NewTextContent(strings.Repeat("result text ", 20))},
+	},
+}
+
+// benchParams are the params of a typical notification
+var benchParams = map[string]interface{}{"uri": "file:///workspace/notes.txt"}
+
+// discardLogger drops the logs of benchmarked transports
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func BenchmarkLineConnWrite(b *testing.B) {
+	session := server.NewSession(context.Background(), server.NewServer("bench"))
+	c := newLineConn(session, strings.NewReader(""), io.Discard, defaultOptions(), discardLogger, newMetrics())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := c.write(benchResponse); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWebSocketWrite(b *testing.B) {
+	serverConns := make(chan *websocket.Conn, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			b.Error(err)
+			return
+		}
+		serverConns <- conn
+	}))
+	defer ts.Close()
+
+	clientConn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer clientConn.Close()
+	go func() {
+		for {
+			if _, _, err := clientConn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	conn := <-serverConns
+	defer conn.Close()
+	session := server.NewSession(context.Background(), server.NewServer("bench"))
+	c := &wsClient{conn: conn, session: session, logger: discardLogger, metrics: newMetrics()}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := c.writeJSON(benchResponse); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteEvent(b *testing.B) {
+	data, err := marshalNotification("notifications/resources/updated", benchParams)
+	if err != nil {
+		b.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w.Body.Reset()
+		if err := writeEvent(w, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
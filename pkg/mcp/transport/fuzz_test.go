@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// fuzzMessages seeds the fuzz targets with well-formed and malformed
+// frames
+var fuzzMessages = []string{
+	`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"fuzz","version":"1.0.0"}}}`,
+	`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"arg0":"hi"}}}`,
+	`{"jsonrpc":"2.0","id":"a","method":"resources/read","params":{"uri":"file:///notes.txt"}}`,
+	`{"jsonrpc":"2.0","id":1e999,"method":"ping"}`,
+	`{"jsonrpc":"2.0","id":-9223372036854775809,"method":"ping"}`,
+	`{"jsonrpc":"2.0","id":{},"method":"ping"}`,
+	`{"jsonrpc":"2.0","id":[1],"result":{}}`,
+	`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":[1,2,3]}`,
+	`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":"echo"}`,
+	`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":null}}`,
+	`{"jsonrpc":"2.0","id":4,"error":{"code":"x","message":1}}`,
+	`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"echo","arguments":{"arg0":1e400}}}`,
+	`{"jsonrpc":`,
+	`[{"jsonrpc":"2.0","id":6,"method":"ping"}]`,
+	`null`,
+}
+
+// FuzzLineConn checks that no input to the stdio and TCP framing, however
+// malformed, panics the transport or the server
+func FuzzLineConn(f *testing.F) {
+	for _, msg := range fuzzMessages {
+		f.Add([]byte(fuzzMessages[0] + "\n" + msg + "\n"))
+	}
+
+	srv := benchServer()
+	f.Fuzz(func(t *testing.T, input []byte) {
+		session := server.NewSession(context.Background(), srv)
+		defer session.Close()
+
+		c := newLineConn(session, bytes.NewReader(input), io.Discard, defaultOptions(), discardLogger, newMetrics())
+		c.serve(make(chan struct{}))
+	})
+}
+
+// FuzzStreamablePost checks that no message posted to the streamable HTTP
+// transport, however malformed, panics the transport or the server, with
+// or without a session
+func FuzzStreamablePost(f *testing.F) {
+	for _, msg := range fuzzMessages {
+		f.Add([]byte(msg))
+	}
+
+	tr := NewStreamableHTTPTransport(benchServer(), WithLogger(discardLogger)).(*StreamableHTTPTransport)
+	init := httptest.NewRecorder()
+	tr.ServeHTTP(init, postMessage(fuzzMessages[0], ""))
+	sessionID := init.Header().Get(sessionIDHeader)
+	if sessionID == "" {
+		f.Fatalf("initialize failed: %s", init.Body)
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		for _, id := range []string{"", sessionID} {
+			rec := httptest.NewRecorder()
+			tr.ServeHTTP(rec, postMessage(string(body), id))
+
+			// Drop the sessions fuzzed initialize requests create
+			if created := rec.Header().Get(sessionIDHeader); created != "" && created != sessionID {
+				tr.removeClient(created)
+			}
+		}
+	})
+}
+
+// postMessage returns a request posting a message in the given session
+func postMessage(body, sessionID string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set(sessionIDHeader, sessionID)
+	}
+	return req
+}
@@ -0,0 +1,23 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+)
+
+// headersContextKey is the context key for the headers of an HTTP request
+type headersContextKey struct{}
+
+// HeadersFromContext returns the headers of the HTTP request that carried
+// the current MCP request, or of the upgrade request for WebSocket
+// connections, so handlers can read trace or tenant IDs. It returns nil for
+// transports not based on HTTP.
+func HeadersFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headersContextKey{}).(http.Header)
+	return header
+}
+
+// withHeaders returns a context carrying the headers of an HTTP request
+func withHeaders(r *http.Request) context.Context {
+	return context.WithValue(r.Context(), headersContextKey{}, r.Header.Clone())
+}
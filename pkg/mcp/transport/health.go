@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// Paths of the health check endpoints served by HTTP transports
+const (
+	HealthPath    = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// WithHealthChecks serves liveness and readiness endpoints at /healthz and
+// /readyz alongside the MCP endpoints of HTTP transports, for load
+// balancers and Kubernetes probes. Liveness always succeeds while the
+// process serves HTTP; readiness succeeds once the transport is listening
+// and fails again once the server starts shutting down or the transport
+// stops.
+func WithHealthChecks() Option {
+	return func(o *Options) {
+		o.HealthChecks = true
+	}
+}
+
+// health tracks whether an HTTP transport is ready to serve
+type health struct {
+	sessions SessionFactory
+	ready    atomic.Bool
+}
+
+// newHealth creates the health state of a transport serving sessions
+func newHealth(sessions SessionFactory) *health {
+	return &health{sessions: sessions}
+}
+
+// isReady reports whether the transport is listening and its server, when
+// it reports shutdowns, is not shutting down
+func (h *health) isReady() bool {
+	if s, ok := h.sessions.(interface{ ShuttingDown() bool }); ok && s.ShuttingDown() {
+		return false
+	}
+	return h.ready.Load()
+}
+
+// listenAndServe serves srv on its address, over TLS when srv has a TLS
+// config, reporting ready once listening
+func (h *health) listenAndServe(srv *http.Server) error {
+	addr := srv.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	if srv.TLSConfig != nil {
+		ln = tls.NewListener(ln, srv.TLSConfig)
+	}
+
+	h.ready.Store(true)
+	defer h.ready.Store(false)
+	return srv.Serve(ln)
+}
+
+// handleHealth adds the health check endpoints to mux when enabled
+func (o Options) handleHealth(mux *http.ServeMux, h *health) {
+	if !o.HealthChecks {
+		return
+	}
+
+	mux.HandleFunc(HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc(ReadinessPath, func(w http.ResponseWriter, r *http.Request) {
+		if !h.isReady() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// freeAddr returns a local address with a free port
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// status returns the status code of a GET request, or 0 when it fails
+func status(url string) int {
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestHealthChecks(t *testing.T) {
+	srv := server.NewServer("health", server.WithLogger(discardLogger))
+	tr := NewStreamableHTTPTransport(srv, WithLogger(discardLogger), WithHealthChecks()).(*StreamableHTTPTransport)
+
+	// The transport is not ready before it listens
+	rec := httptest.NewRecorder()
+	tr.mux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected not to be ready before listening, got %d", rec.Code)
+	}
+
+	addr := freeAddr(t)
+	served := make(chan error, 1)
+	go func() { served <- tr.StartHTTP(addr) }()
+	base := "http://" + addr
+	if !eventually(t, func() bool { return status(base+ReadinessPath) == http.StatusOK }) {
+		t.Fatal("expected the transport to become ready once listening")
+	}
+	if code := status(base + HealthPath); code != http.StatusOK {
+		t.Errorf("expected to be live, got %d", code)
+	}
+
+	// Shutting down the server fails readiness but not liveness
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if code := status(base + ReadinessPath); code != http.StatusServiceUnavailable {
+		t.Errorf("expected not to be ready while shutting down, got %d", code)
+	}
+	if code := status(base + HealthPath); code != http.StatusOK {
+		t.Errorf("expected to stay live while shutting down, got %d", code)
+	}
+
+	if err := tr.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-served
+	if tr.health.isReady() {
+		t.Error("expected not to be ready once stopped")
+	}
+}
+
+func TestHealthChecksDisabled(t *testing.T) {
+	tr := NewSSETransport(server.NewServer("health"), WithLogger(discardLogger)).(*SSETransport)
+	for _, path := range []string{HealthPath, ReadinessPath} {
+		rec := httptest.NewRecorder()
+		tr.mux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected no %s endpoint without WithHealthChecks, got %d", path, rec.Code)
+		}
+	}
+}
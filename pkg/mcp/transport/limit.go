@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// ConnectionLimitPolicy determines what happens to connections opened while
+// an HTTP transport is at its connection limit
+type ConnectionLimitPolicy int
+
+const (
+	// ConnectionLimitReject answers the connection with 503 Service
+	// Unavailable
+	ConnectionLimitReject ConnectionLimitPolicy = iota
+
+	// ConnectionLimitQueue holds the connection until another closes or the
+	// client gives up
+	ConnectionLimitQueue
+)
+
+// ConnectionStats reports the long-lived connections of an HTTP transport:
+// WebSocket connections and SSE event streams
+type ConnectionStats struct {
+	// Active is the number of open connections
+	Active int64
+
+	// Queued is the number of connections waiting for a free slot
+	Queued int64
+
+	// Rejected counts connections refused at the limit since the transport
+	// was created
+	Rejected uint64
+}
+
+// WithMaxConnections caps the simultaneous WebSocket connections and SSE
+// event streams of an HTTP transport. The policy decides whether
+// connections beyond the limit are rejected or queued.
+func WithMaxConnections(max int, policy ConnectionLimitPolicy) Option {
+	return func(o *Options) {
+		o.MaxConnections = max
+		o.ConnectionLimitPolicy = policy
+	}
+}
+
+// connLimiter bounds and counts the connections of a transport
+type connLimiter struct {
+	slots    chan struct{}
+	policy   ConnectionLimitPolicy
+	active   atomic.Int64
+	queued   atomic.Int64
+	rejected atomic.Uint64
+}
+
+// newConnLimiter creates a limiter for the configured maximum, which only
+// counts connections when there is none
+func newConnLimiter(opts Options) *connLimiter {
+	l := &connLimiter{policy: opts.ConnectionLimitPolicy}
+	if opts.MaxConnections > 0 {
+		l.slots = make(chan struct{}, opts.MaxConnections)
+	}
+	return l
+}
+
+// acquire takes a connection slot for the request. It writes a 503 response
+// and returns false when the connection is rejected, or the client goes
+// away while queued. A successful acquire must be followed by release.
+func (l *connLimiter) acquire(w http.ResponseWriter, r *http.Request) bool {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			if !l.wait(r) {
+				l.rejected.Add(1)
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "Too many connections", http.StatusServiceUnavailable)
+				return false
+			}
+		}
+	}
+
+	l.active.Add(1)
+	return true
+}
+
+// wait queues for a slot when the policy allows it, reporting whether one
+// was taken
+func (l *connLimiter) wait(r *http.Request) bool {
+	if l.policy != ConnectionLimitQueue {
+		return false
+	}
+
+	l.queued.Add(1)
+	defer l.queued.Add(-1)
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// release frees the slot of a closed connection
+func (l *connLimiter) release() {
+	l.active.Add(-1)
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// stats returns the current connection counts
+func (l *connLimiter) stats() ConnectionStats {
+	return ConnectionStats{
+		Active:   l.active.Load(),
+		Queued:   l.queued.Load(),
+		Rejected: l.rejected.Load(),
+	}
+}
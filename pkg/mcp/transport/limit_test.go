@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+func TestMaxConnections(t *testing.T) {
+	srv := server.NewServer("limits", server.WithLogger(discardLogger))
+	tr, ts := serveSSE(t, srv, WithMaxConnections(2, ConnectionLimitReject))
+
+	// Streams beyond the limit are rejected
+	first, second := dialSSE(t, ts.URL), dialSSE(t, ts.URL)
+	defer second.Close()
+	resp, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("expected the third stream to be rejected, got %s", resp.Status)
+	}
+	if stats := tr.ConnectionStats(); stats.Active != 2 || stats.Rejected != 1 {
+		t.Errorf("unexpected connection stats %+v", stats)
+	}
+
+	// Closing a stream frees its slot
+	first.Close()
+	if !eventually(t, func() bool { return tr.ConnectionStats().Active == 1 }) {
+		t.Fatalf("expected the closed stream to be released, got %+v", tr.ConnectionStats())
+	}
+	dialSSE(t, ts.URL)
+}
+
+func TestMaxConnectionsQueue(t *testing.T) {
+	srv := server.NewServer("limits", server.WithLogger(discardLogger))
+	tr, ts := serveSSE(t, srv, WithMaxConnections(1, ConnectionLimitQueue))
+	first := dialSSE(t, ts.URL)
+
+	// A stream beyond the limit waits for a free slot
+	opened := make(chan *http.Response, 1)
+	go func() {
+		if resp, err := http.Get(ts.URL + "/events"); err == nil {
+			opened <- resp
+		}
+	}()
+	if !eventually(t, func() bool { return tr.ConnectionStats().Queued == 1 }) {
+		t.Fatalf("expected the second stream to be queued, got %+v", tr.ConnectionStats())
+	}
+	first.Close()
+	select {
+	case resp := <-opened:
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected the queued stream to open, got %s", resp.Status)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the queued stream to open once a slot was freed")
+	}
+
+	// Clients giving up leave the queue
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events", nil)
+	if resp, err := http.DefaultClient.Do(req); err == nil {
+		resp.Body.Close()
+		t.Errorf("expected the request to wait until cancelled, got %s", resp.Status)
+	}
+	if !eventually(t, func() bool { stats := tr.ConnectionStats(); return stats.Queued == 0 && stats.Rejected == 1 }) {
+		t.Errorf("expected the abandoned stream to leave the queue, got %+v", tr.ConnectionStats())
+	}
+}
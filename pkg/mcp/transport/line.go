@@ -0,0 +1,308 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// lineConn serves a session over a stream of newline-delimited JSON-RPC
+// messages, the framing shared by the stdio and TCP transports
+type lineConn struct {
+	session *server.Session
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	maxSize int64
+	order   *sequencer
+	logger  *slog.Logger
+	metrics *metrics
+	tap     io.Writer
+
+	// deadline, when set, bounds each write to writeTimeout
+	deadline     interface{ SetWriteDeadline(time.Time) error }
+	writeTimeout time.Duration
+}
+
+// lineRead is a line read from the stream, or the error that ended reading
+type lineRead struct {
+	data []byte
+	err  error
+}
+
+// newLineConn creates a connection serving session over r and w, delivering
+// the session's notifications and requests through it. Lines longer than
+// the maximum message size are rejected, and responses are sent in the
+// configured order.
+func newLineConn(session *server.Session, r io.Reader, w io.Writer, opts Options, logger *slog.Logger, m *metrics) *lineConn {
+	c := &lineConn{
+		session: session,
+		reader:  bufio.NewReader(r),
+		writer:  bufio.NewWriter(w),
+		maxSize: opts.MaxMessageSize,
+		order:   newSequencer(opts.ResponseOrder),
+		logger:  logger,
+		metrics: m,
+		tap:     opts.WireTap,
+	}
+	session.SetNotificationSender(c)
+	return c
+}
+
+// withWriteDeadline bounds each write to conn by timeout, so a client that
+// stops reading cannot block writers indefinitely
+func (c *lineConn) withWriteDeadline(conn net.Conn, timeout time.Duration) *lineConn {
+	if timeout > 0 {
+		c.deadline = conn
+		c.writeTimeout = timeout
+	}
+	return c
+}
+
+// serve handles messages until the stream ends, the session closes or done
+// is closed, then waits for in-flight requests so their responses are
+// written
+func (c *lineConn) serve(done <-chan struct{}) error {
+	defer c.wg.Wait()
+
+	// Reads cannot be interrupted, so lines are read in the background. A
+	// read pending at shutdown ends when the stream is closed, or is
+	// abandoned for streams such as stdin that are never closed.
+	lines := make(chan lineRead)
+	quit := make(chan struct{})
+	defer close(quit)
+	go func() {
+		for {
+			data, err := c.readLine()
+			select {
+			case lines <- lineRead{data: data, err: err}:
+			case <-quit:
+				return
+			}
+			if err != nil && !errors.Is(err, ErrMessageTooLarge) {
+				return
+			}
+		}
+	}()
+
+	for {
+		var l lineRead
+		select {
+		case <-done:
+			return nil
+		case <-c.session.Done():
+			return nil
+		case l = <-lines:
+		}
+		if errors.Is(l.err, ErrMessageTooLarge) {
+			c.writeError(nil, protocol.InvalidRequest, "Message too large", l.err)
+			continue
+		}
+		if l.err != nil {
+			if l.err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read message: %w", l.err)
+		}
+		c.metrics.messagesIn.Add(1)
+		tapFrame(c.tap, wireIn, c.session.ID(), l.data)
+
+		// Parse the message
+		var msg struct {
+			JSONRPC string              `json:"jsonrpc"`
+			ID      *protocol.RequestID `json:"id,omitempty"`
+			Method  string              `json:"method"`
+			Params  json.RawMessage     `json:"params,omitempty"`
+			Result  json.RawMessage     `json:"result,omitempty"`
+			Error   *protocol.ErrorData `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(l.data, &msg); err != nil {
+			c.writeError(nil, protocol.ParseError, "Parse error", err)
+			continue
+		}
+
+		// Handle the message
+		if msg.ID != nil && msg.Method == "" {
+			// This is a response to a request sent by the server
+			c.session.HandleResponse(&protocol.JSONRPCResponse{
+				JSONRPC: msg.JSONRPC,
+				ID:      *msg.ID,
+				Result:  msg.Result,
+				Error:   msg.Error,
+			})
+		} else if msg.ID != nil {
+			// This is a request
+			req := &protocol.JSONRPCRequest{
+				JSONRPC: msg.JSONRPC,
+				ID:      *msg.ID,
+				Method:  msg.Method,
+				Params:  msg.Params,
+			}
+			// Requests are handled concurrently so that notifications such
+			// as cancellations can be processed while they run
+			ticket := c.order.ticket()
+			c.wg.Add(1)
+			go func() {
+				defer c.wg.Done()
+				c.handleRequest(req, ticket)
+			}()
+		} else {
+			// This is a notification
+			notif := &protocol.JSONRPCNotification{
+				JSONRPC: msg.JSONRPC,
+				Method:  msg.Method,
+				Params:  msg.Params,
+			}
+			c.handleNotification(notif)
+		}
+	}
+}
+
+// readLine reads the next line. Lines over the maximum size are discarded
+// and reported as ErrMessageTooLarge.
+func (c *lineConn) readLine() ([]byte, error) {
+	var buf []byte
+	tooLarge := false
+	for {
+		chunk, err := c.reader.ReadSlice('\n')
+		if !tooLarge {
+			if c.maxSize > 0 && int64(len(buf)+len(chunk)) > c.maxSize {
+				tooLarge = true
+				buf = nil
+			} else {
+				buf = append(buf, chunk...)
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if tooLarge && err == nil {
+			return nil, ErrMessageTooLarge
+		}
+		return buf, err
+	}
+}
+
+// flush writes any buffered output
+func (c *lineConn) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.writer.Flush(); err != nil {
+		c.logger.Error("failed to flush output", "error", err)
+	}
+}
+
+// handleRequest processes a request and writes the response in the turn
+// of its ticket
+func (c *lineConn) handleRequest(req *protocol.JSONRPCRequest, ticket uint64) {
+	resp, err := c.metrics.handleRequest(context.Background(), c.session, req)
+	switch {
+	case errors.Is(err, server.ErrRequestCancelled):
+		c.order.deliver(ticket, nil)
+	case err != nil:
+		c.order.deliver(ticket, func() {
+			c.writeError(&req.ID, protocol.InternalError, "Internal error", err)
+		})
+	default:
+		c.order.deliver(ticket, func() { c.writeResponse(resp) })
+	}
+}
+
+// handleNotification processes a notification
+func (c *lineConn) handleNotification(notif *protocol.JSONRPCNotification) {
+	if err := c.session.HandleNotification(notif); err != nil {
+		// Log the error but don't send a response for notifications
+		c.logger.Error("failed to handle notification", "method", notif.Method, "error", err)
+	}
+}
+
+// write writes a message as a line and flushes it
+func (c *lineConn) write(v interface{}) error {
+	b := getEncodeBuffer()
+	defer b.release()
+	if err := b.encode(v); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.deadline != nil {
+		if err := c.deadline.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return err
+		}
+	}
+	// Tap the frame before the peer can see it, so that taps record
+	// frames in the order they happened
+	tapFrame(c.tap, wireOut, c.session.ID(), b.Bytes())
+	if _, err := c.writer.Write(b.Bytes()); err != nil {
+		return err
+	}
+	if err := c.writer.Flush(); err != nil {
+		return err
+	}
+	c.metrics.messagesOut.Add(1)
+	return nil
+}
+
+// writeResponse writes a JSON-RPC response
+func (c *lineConn) writeResponse(resp *protocol.JSONRPCResponse) {
+	if err := c.write(resp); err != nil {
+		c.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// writeError writes a JSON-RPC error response, with a null ID when id is nil
+func (c *lineConn) writeError(id *protocol.RequestID, code int, message string, err error) {
+	errResp := &protocol.JSONRPCError{
+		JSONRPC: "2.0",
+		Error: protocol.ErrorData{
+			Code:    code,
+			Message: message,
+			Data:    err.Error(),
+		},
+	}
+
+	if id != nil {
+		errResp.ID = *id
+	}
+
+	if err := c.write(errResp); err != nil {
+		c.logger.Error("failed to write error response", "error", err)
+	}
+}
+
+// SendNotification sends a notification to the client
+func (c *lineConn) SendNotification(method string, params interface{}) error {
+	notif := &protocol.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	}
+
+	if err := c.write(notif); err != nil {
+		c.metrics.dropped.Add(1)
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+	return nil
+}
+
+// SendRequest sends a server-initiated request to the client
+func (c *lineConn) SendRequest(req *protocol.JSONRPCRequest) error {
+	if err := c.write(req); err != nil {
+		return fmt.Errorf("failed to write request: %w", err)
+	}
+	return nil
+}
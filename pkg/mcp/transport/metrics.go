@@ -0,0 +1,217 @@
+package transport
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// MetricsPath is the path of the metrics endpoint served by HTTP
+// transports created with WithMetrics
+const MetricsPath = "/metrics"
+
+// latencyBuckets are the upper bounds of the request latency histogram
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	time.Minute,
+}
+
+// Stats is a snapshot of a transport's metrics
+type Stats struct {
+	// ActiveConnections is the number of open connections or sessions
+	ActiveConnections int64
+
+	// TotalConnections counts connections or sessions opened since the
+	// transport was created
+	TotalConnections uint64
+
+	// MessagesIn counts messages received from clients
+	MessagesIn uint64
+
+	// MessagesOut counts responses, notifications and requests sent to
+	// clients
+	MessagesOut uint64
+
+	// DroppedNotifications counts notifications that could not be
+	// delivered, such as to full event stream buffers
+	DroppedNotifications uint64
+
+	// RequestLatency is the distribution of request handling times
+	RequestLatency LatencyStats
+}
+
+// LatencyStats is a histogram of request handling times
+type LatencyStats = server.LatencyStats
+
+// LatencyBucket counts the requests handled within UpperBound
+type LatencyBucket = server.LatencyBucket
+
+// metrics collects the metrics of a transport
+type metrics struct {
+	active      atomic.Int64
+	total       atomic.Uint64
+	messagesIn  atomic.Uint64
+	messagesOut atomic.Uint64
+	dropped     atomic.Uint64
+	count       atomic.Uint64
+	sum         atomic.Int64
+	buckets     []atomic.Uint64
+}
+
+// newMetrics creates empty metrics
+func newMetrics() *metrics {
+	return &metrics{buckets: make([]atomic.Uint64, len(latencyBuckets))}
+}
+
+// connected records an opened connection, returning the function that
+// records its closing
+func (m *metrics) connected() func() {
+	m.active.Add(1)
+	m.total.Add(1)
+	return func() { m.active.Add(-1) }
+}
+
+// handleRequest handles a request on the session, recording it and its
+// handling time
+func (m *metrics) handleRequest(ctx context.Context, session *server.Session, req *protocol.JSONRPCRequest) (*protocol.JSONRPCResponse, error) {
+	start := time.Now()
+	resp, err := session.HandleRequestContext(ctx, req)
+	elapsed := time.Since(start)
+
+	m.count.Add(1)
+	m.sum.Add(int64(elapsed))
+	for i, bound := range latencyBuckets {
+		if elapsed <= bound {
+			m.buckets[i].Add(1)
+		}
+	}
+	return resp, err
+}
+
+// stats returns a snapshot of the metrics
+func (m *metrics) stats() Stats {
+	latency := LatencyStats{
+		Count:   m.count.Load(),
+		Sum:     time.Duration(m.sum.Load()),
+		Buckets: make([]LatencyBucket, len(latencyBuckets)),
+	}
+	for i, bound := range latencyBuckets {
+		latency.Buckets[i] = LatencyBucket{UpperBound: bound, Count: m.buckets[i].Load()}
+	}
+
+	return Stats{
+		ActiveConnections:    m.active.Load(),
+		TotalConnections:     m.total.Load(),
+		MessagesIn:           m.messagesIn.Load(),
+		MessagesOut:          m.messagesOut.Load(),
+		DroppedNotifications: m.dropped.Load(),
+		RequestLatency:       latency,
+	}
+}
+
+// WithMetrics serves the transport's metrics at /metrics alongside the
+// MCP endpoints of HTTP transports, in the Prometheus text exposition
+// format with metric names starting with prefix, such as "mcp". When the
+// transport serves a *server.Server, the server's request, tool call,
+// session and task metrics are included.
+func WithMetrics(prefix string) Option {
+	return func(o *Options) {
+		o.MetricsPrefix = prefix
+	}
+}
+
+// prometheusWriter is implemented by session factories reporting their own
+// metrics, such as *server.Server
+type prometheusWriter interface {
+	WritePrometheus(w io.Writer, prefix string) error
+}
+
+// handleMetrics adds the metrics endpoint to mux when enabled
+func (o Options) handleMetrics(mux *http.ServeMux, t Transport, sessions SessionFactory) {
+	if o.MetricsPrefix == "" {
+		return
+	}
+
+	mux.HandleFunc(MetricsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := WritePrometheus(w, o.MetricsPrefix, t.Stats()); err != nil {
+			return
+		}
+		if s, ok := sessions.(prometheusWriter); ok {
+			s.WritePrometheus(w, o.MetricsPrefix)
+		}
+	})
+}
+
+// PublishExpvar publishes the transport's stats as an expvar variable, so
+// they are served at /debug/vars. Like expvar.Publish, it panics if the
+// name is already in use.
+func PublishExpvar(name string, t Transport) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return t.Stats()
+	}))
+}
+
+// MetricsHandler serves the transport's stats in the Prometheus text
+// exposition format, with metric names starting with prefix, such as "mcp"
+func MetricsHandler(prefix string, t Transport) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		WritePrometheus(w, prefix, t.Stats())
+	})
+}
+
+// WritePrometheus writes stats in the Prometheus text exposition format
+func WritePrometheus(w io.Writer, prefix string, stats Stats) error {
+	metric := func(name, kind, help string, value interface{}) error {
+		_, err := fmt.Fprintf(w, "# HELP %s_%s %s\n# TYPE %s_%s %s\n%s_%s %v\n",
+			prefix, name, help, prefix, name, kind, prefix, name, value)
+		return err
+	}
+
+	if err := metric("connections_active", "gauge", "Open connections or sessions.", stats.ActiveConnections); err != nil {
+		return err
+	}
+	if err := metric("connections_total", "counter", "Connections or sessions opened.", stats.TotalConnections); err != nil {
+		return err
+	}
+	if err := metric("messages_received_total", "counter", "Messages received from clients.", stats.MessagesIn); err != nil {
+		return err
+	}
+	if err := metric("messages_sent_total", "counter", "Messages sent to clients.", stats.MessagesOut); err != nil {
+		return err
+	}
+	if err := metric("notifications_dropped_total", "counter", "Notifications that could not be delivered.", stats.DroppedNotifications); err != nil {
+		return err
+	}
+
+	name := prefix + "_request_duration_seconds"
+	if _, err := fmt.Fprintf(w, "# HELP %s Request handling time.\n# TYPE %s histogram\n", name, name); err != nil {
+		return err
+	}
+	for _, bucket := range stats.RequestLatency.Buckets {
+		if _, err := fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, bucket.UpperBound.Seconds(), bucket.Count); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n%s_sum %g\n%s_count %d\n",
+		name, stats.RequestLatency.Count,
+		name, stats.RequestLatency.Sum.Seconds(),
+		name, stats.RequestLatency.Count)
+	return err
+}
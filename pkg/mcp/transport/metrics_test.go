@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+func TestMetrics(t *testing.T) {
+	srv := server.NewServer("metrics", server.WithLogger(discardLogger))
+	tr := NewWebSocketTransport(srv, WithLogger(discardLogger), WithMetrics("mcp")).(*WebSocketTransport)
+	ts := httptest.NewServer(tr.mux())
+	defer ts.Close()
+
+	conn, err := client.DialWebSocket(context.Background(), "ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for i := range 3 {
+		conn.Write(context.Background(), []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"ping"}`, i)))
+		if _, err := conn.Read(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := tr.Stats()
+	if stats.ActiveConnections != 1 || stats.TotalConnections != 1 || stats.MessagesIn != 3 || stats.MessagesOut != 3 || stats.RequestLatency.Count != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if last := stats.RequestLatency.Buckets[len(stats.RequestLatency.Buckets)-1]; last.Count != 3 {
+		t.Errorf("expected every request in the last bucket, got %+v", last)
+	}
+
+	// The metrics endpoint serves the transport's and the server's metrics
+	resp, err := http.Get(ts.URL + MetricsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	for _, line := range []string{
+		"# TYPE mcp_connections_active gauge",
+		"mcp_connections_active 1",
+		"mcp_connections_total 1",
+		"mcp_messages_received_total 3",
+		"mcp_messages_sent_total 3",
+		"mcp_notifications_dropped_total 0",
+		"# TYPE mcp_request_duration_seconds histogram",
+		`mcp_request_duration_seconds_bucket{le="+Inf"} 3`,
+		"mcp_request_duration_seconds_count 3",
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("expected %q in metrics:\n%s", line, body)
+		}
+	}
+
+	// The stats are also available through a handler and expvar
+	rec := httptest.NewRecorder()
+	MetricsHandler("custom", tr).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rec.Body.String(), "custom_messages_received_total 3\n") {
+		t.Errorf("unexpected handler output:\n%s", rec.Body)
+	}
+
+	name := fmt.Sprintf("transport_%p", tr)
+	PublishExpvar(name, tr)
+	var published Stats
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &published); err != nil {
+		t.Fatal(err)
+	}
+	if published.MessagesIn != 3 || published.ActiveConnections != 1 {
+		t.Errorf("unexpected published stats %+v", published)
+	}
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	tr := NewWebSocketTransport(server.NewServer("metrics"), WithLogger(discardLogger)).(*WebSocketTransport)
+	rec := httptest.NewRecorder()
+	tr.mux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected no metrics endpoint without WithMetrics, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// MultiHTTPTransport serves the WebSocket, SSE and streamable HTTP
+// transports on one address, so clients pick their protocol by path: /ws
+// for WebSocket, /sse and /message for SSE (with /events as an alias of
+// /sse), and /mcp for streamable HTTP. Options apply to all three;
+// connection limits and metrics are shared.
+type MultiHTTPTransport struct {
+	ws         *WebSocketTransport
+	sse        *SSETransport
+	streamable *StreamableHTTPTransport
+	opts       Options
+	health     *health
+	limiter    *connLimiter
+	metrics    *metrics
+	srv        *http.Server
+}
+
+// NewMultiHTTPTransport creates a transport serving WebSocket, SSE and
+// streamable HTTP clients on one address
+func NewMultiHTTPTransport(sessions SessionFactory, options ...Option) HTTPTransport {
+	opts := defaultOptions()
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	t := &MultiHTTPTransport{
+		ws:         NewWebSocketTransport(sessions, options...).(*WebSocketTransport),
+		sse:        NewSSETransport(sessions, options...).(*SSETransport),
+		streamable: NewStreamableHTTPTransport(sessions, options...).(*StreamableHTTPTransport),
+		opts:       opts,
+		health:     newHealth(sessions),
+		limiter:    newConnLimiter(opts),
+		metrics:    newMetrics(),
+	}
+	t.ws.limiter, t.sse.limiter, t.streamable.limiter = t.limiter, t.limiter, t.limiter
+	t.ws.metrics, t.sse.metrics, t.streamable.metrics = t.metrics, t.metrics, t.metrics
+	return t
+}
+
+// Start starts the transport on the default address
+func (t *MultiHTTPTransport) Start() error {
+	return t.StartHTTP(t.opts.Address)
+}
+
+// mux returns a mux serving the transport's endpoints, along with the
+// metadata, health and metrics endpoints enabled by its options
+func (t *MultiHTTPTransport) mux() *http.ServeMux {
+	mux := http.NewServeMux()
+	t.ws.routes(mux)
+	t.sse.routes(mux)
+	t.streamable.routes(mux)
+	t.opts.handleProtectedResource(mux)
+	t.opts.handleHealth(mux, t.health)
+	t.opts.handleMetrics(mux, t, t.health.sessions)
+	return mux
+}
+
+// StartHTTP starts the transport on the given address
+func (t *MultiHTTPTransport) StartHTTP(addr string) error {
+	t.srv = &http.Server{
+		Addr:      addr,
+		Handler:   t.mux(),
+		TLSConfig: t.opts.TLSConfig,
+	}
+
+	return t.health.listenAndServe(t.srv)
+}
+
+// Stop stops the transport, closing the connections of all three protocols
+func (t *MultiHTTPTransport) Stop(ctx context.Context) error {
+	t.health.ready.Store(false)
+
+	var errs []error
+	for _, sub := range []Transport{t.ws, t.sse, t.streamable} {
+		if err := sub.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if t.srv != nil {
+		if err := t.srv.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SendNotification sends a notification to the clients of all three
+// protocols
+func (t *MultiHTTPTransport) SendNotification(method string, params interface{}) error {
+	return errors.Join(
+		t.ws.SendNotification(method, params),
+		t.sse.SendNotification(method, params),
+		t.streamable.SendNotification(method, params),
+	)
+}
+
+// ConnectionStats returns the transport's connection limit statistics
+func (t *MultiHTTPTransport) ConnectionStats() ConnectionStats {
+	return t.limiter.stats()
+}
+
+// Stats returns a snapshot of the transport's metrics
+func (t *MultiHTTPTransport) Stats() Stats {
+	return t.metrics.stats()
+}
@@ -0,0 +1,373 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// NATSMsg is a message received on a NATS subject
+type NATSMsg struct {
+	Subject string
+	Reply   string
+	Data    []byte
+}
+
+// NATSSubscription is an active NATS subscription
+type NATSSubscription interface {
+	Unsubscribe() error
+}
+
+// NATSConn is the subset of a NATS connection used by NATSTransport. It is
+// small enough to adapt the nats.go client in a few lines.
+type NATSConn interface {
+	// Publish sends data to a subject
+	Publish(subject string, data []byte) error
+
+	// Subscribe calls handler for each message on a subject, which may
+	// contain wildcards
+	Subscribe(subject string, handler func(msg *NATSMsg)) (NATSSubscription, error)
+}
+
+// NATSTransport exchanges JSON-RPC messages over NATS subjects, so that MCP
+// servers can be fronted by a message bus. Under a subject prefix such as
+// "mcp":
+//
+//   - clients pick a unique session ID and publish their messages to
+//     mcp.<sessionID>, using NATS request/reply for requests
+//   - the server publishes notifications and server-initiated requests to
+//     mcp.<sessionID>.events
+//
+// The first message for a session ID creates its session from the session
+// factory. NATS has no notion of disconnection, so sessions last until they
+// are closed, such as by missed keepalive pings, or the transport stops.
+type NATSTransport struct {
+	conn     NATSConn
+	subject  string
+	sessions SessionFactory
+	clients  map[string]*natsClient
+	sub      NATSSubscription
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	opts     Options
+	metrics  *metrics
+
+	// done is closed by Stop
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// natsClient is a session and the subject its events are published to
+type natsClient struct {
+	conn    NATSConn
+	session *server.Session
+	events  string
+	logger  *slog.Logger
+	metrics *metrics
+	order   *sequencer
+	tap     io.Writer
+}
+
+// NewNATSTransport creates a new NATS transport serving sessions under the
+// given subject prefix
+func NewNATSTransport(conn NATSConn, subject string, sessions SessionFactory, options ...Option) Transport {
+	opts := defaultOptions()
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return &NATSTransport{
+		conn:     conn,
+		subject:  subject,
+		sessions: sessions,
+		clients:  make(map[string]*natsClient),
+		opts:     opts,
+		metrics:  newMetrics(),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start subscribes to the session subjects and serves messages until Stop
+// is called
+func (t *NATSTransport) Start() error {
+	sub, err := t.conn.Subscribe(t.subject+".*", t.handleMessage)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", t.subject, err)
+	}
+
+	t.mu.Lock()
+	t.sub = sub
+	t.mu.Unlock()
+
+	<-t.done
+	return nil
+}
+
+// Stop unsubscribes and waits for in-flight requests to complete and their
+// responses to be published. If ctx ends first, ctx's error is returned.
+// All sessions are closed.
+func (t *NATSTransport) Stop(ctx context.Context) error {
+	t.stopOnce.Do(func() { close(t.done) })
+
+	t.mu.Lock()
+	if t.sub != nil {
+		if err := t.sub.Unsubscribe(); err != nil {
+			t.opts.Logger.Error("failed to unsubscribe", "subject", t.subject, "error", err)
+		}
+	}
+	t.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(stopped)
+	}()
+
+	var err error
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	t.mu.Lock()
+	for sessionID, client := range t.clients {
+		client.session.Close()
+		delete(t.clients, sessionID)
+	}
+	t.mu.Unlock()
+
+	return err
+}
+
+// handleMessage handles a message published by a client
+func (t *NATSTransport) handleMessage(m *NATSMsg) {
+	sessionID := strings.TrimPrefix(m.Subject, t.subject+".")
+
+	client, ok := t.client(sessionID)
+	if !ok {
+		return
+	}
+	t.metrics.messagesIn.Add(1)
+	tapFrame(t.opts.WireTap, wireIn, client.session.ID(), m.Data)
+
+	if max := t.opts.MaxMessageSize; max > 0 && int64(len(m.Data)) > max {
+		client.reply(m.Reply, &protocol.JSONRPCError{
+			JSONRPC: "2.0",
+			Error: protocol.ErrorData{
+				Code:    protocol.InvalidRequest,
+				Message: "Message too large",
+				Data:    ErrMessageTooLarge.Error(),
+			},
+		})
+		return
+	}
+
+	// Parse the message
+	var msg struct {
+		JSONRPC string              `json:"jsonrpc"`
+		ID      *protocol.RequestID `json:"id,omitempty"`
+		Method  string              `json:"method"`
+		Params  json.RawMessage     `json:"params,omitempty"`
+		Result  json.RawMessage     `json:"result,omitempty"`
+		Error   *protocol.ErrorData `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		client.reply(m.Reply, &protocol.JSONRPCError{
+			JSONRPC: "2.0",
+			Error: protocol.ErrorData{
+				Code:    protocol.ParseError,
+				Message: "Parse error",
+				Data:    err.Error(),
+			},
+		})
+		return
+	}
+
+	// Handle the message
+	if msg.ID != nil && msg.Method == "" {
+		// This is a response to a request sent by the server
+		client.session.HandleResponse(&protocol.JSONRPCResponse{
+			JSONRPC: msg.JSONRPC,
+			ID:      *msg.ID,
+			Result:  msg.Result,
+			Error:   msg.Error,
+		})
+	} else if msg.ID != nil {
+		// This is a request. Requests are handled concurrently so that
+		// notifications such as cancellations can be processed while they
+		// run.
+		req := &protocol.JSONRPCRequest{
+			JSONRPC: msg.JSONRPC,
+			ID:      *msg.ID,
+			Method:  msg.Method,
+			Params:  msg.Params,
+		}
+		ticket := client.order.ticket()
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			client.handleRequest(m.Reply, req, ticket)
+		}()
+	} else {
+		// This is a notification
+		notif := &protocol.JSONRPCNotification{
+			JSONRPC: msg.JSONRPC,
+			Method:  msg.Method,
+			Params:  msg.Params,
+		}
+		if err := client.session.HandleNotification(notif); err != nil {
+			// Log the error but don't send a response for notifications
+			client.logger.Error("failed to handle notification", "method", notif.Method, "error", err)
+		}
+	}
+}
+
+// client returns the client for a session ID, creating its session on first
+// use. It returns false once the transport is stopping.
+func (t *NATSTransport) client(sessionID string) (*natsClient, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	select {
+	case <-t.done:
+		return nil, false
+	default:
+	}
+
+	if client, ok := t.clients[sessionID]; ok {
+		return client, true
+	}
+
+	client := &natsClient{
+		conn:    t.conn,
+		session: t.sessions.NewSession(context.Background()),
+		events:  t.subject + "." + sessionID + ".events",
+		logger:  t.opts.Logger.With("session", sessionID),
+		metrics: t.metrics,
+		tap:     t.opts.WireTap,
+		order:   newSequencer(t.opts.ResponseOrder),
+	}
+	client.session.SetNotificationSender(client)
+	client.session.SetConnInfo(&server.ConnInfo{Transport: "nats"})
+	t.clients[sessionID] = client
+	client.logger.Debug("session created")
+	disconnected := t.metrics.connected()
+
+	// Forget sessions once they close, such as after missed keepalive
+	// pings, so a later message for the ID starts a new session
+	go func() {
+		<-client.session.Done()
+		disconnected()
+		t.mu.Lock()
+		if t.clients[sessionID] == client {
+			delete(t.clients, sessionID)
+		}
+		t.mu.Unlock()
+		client.logger.Debug("session closed")
+	}()
+
+	return client, true
+}
+
+// sessionClients returns the clients of the open sessions
+func (t *NATSTransport) sessionClients() []*natsClient {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clients := make([]*natsClient, 0, len(t.clients))
+	for _, client := range t.clients {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// SendNotification sends a notification to all sessions. Publishing happens
+// outside the transport's lock, so a connection slow to accept messages
+// does not stop sessions from being created or closed meanwhile.
+func (t *NATSTransport) SendNotification(method string, params interface{}) error {
+	var lastErr error
+	for _, client := range t.sessionClients() {
+		if err := client.SendNotification(method, params); err != nil {
+			lastErr = err
+			t.metrics.dropped.Add(1)
+			client.logger.Error("failed to send notification", "method", method, "error", err)
+		}
+	}
+
+	return lastErr
+}
+
+// handleRequest processes a request and publishes the response to the
+// request's reply subject in the turn of its ticket
+func (c *natsClient) handleRequest(replyTo string, req *protocol.JSONRPCRequest, ticket uint64) {
+	resp, err := c.metrics.handleRequest(context.Background(), c.session, req)
+	if errors.Is(err, server.ErrRequestCancelled) {
+		// Cancelled requests receive no response
+		c.order.deliver(ticket, nil)
+		return
+	}
+	if err != nil {
+		resp = &protocol.JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   protocol.NewError(protocol.InternalError, err.Error()),
+		}
+	}
+
+	c.order.deliver(ticket, func() { c.reply(replyTo, resp) })
+}
+
+// reply publishes a message to a reply subject, or to the events subject
+// for messages published without one
+func (c *natsClient) reply(replyTo string, v interface{}) {
+	if replyTo == "" {
+		replyTo = c.events
+	}
+	if err := c.publish(replyTo, v); err != nil {
+		c.logger.Error("failed to publish response", "error", err)
+	}
+}
+
+// publish marshals a message and publishes it to a subject
+func (c *natsClient) publish(subject string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	if err := c.conn.Publish(subject, data); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", subject, err)
+	}
+	c.metrics.messagesOut.Add(1)
+	tapFrame(c.tap, wireOut, c.session.ID(), data)
+	return nil
+}
+
+// SendNotification publishes a notification to the session's events subject
+func (c *natsClient) SendNotification(method string, params interface{}) error {
+	return c.publish(c.events, &protocol.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+}
+
+// SendRequest publishes a server-initiated request to the session's events
+// subject. The client publishes its response to the session subject.
+func (c *natsClient) SendRequest(req *protocol.JSONRPCRequest) error {
+	return c.publish(c.events, req)
+}
+
+// Stats returns a snapshot of the transport's metrics, counting sessions as
+// connections
+func (t *NATSTransport) Stats() Stats {
+	return t.metrics.stats()
+}
@@ -0,0 +1,285 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// memoryNATS is an in-memory NATS server delivering messages to the
+// subscriptions matching their subject, where * matches one token
+type memoryNATS struct {
+	mu   sync.Mutex
+	subs map[*memorySubscription]struct{}
+}
+
+type memorySubscription struct {
+	bus     *memoryNATS
+	subject string
+	handler func(msg *NATSMsg)
+}
+
+func newMemoryNATS() *memoryNATS {
+	return &memoryNATS{subs: make(map[*memorySubscription]struct{})}
+}
+
+func (b *memoryNATS) Publish(subject string, data []byte) error {
+	return b.publishMsg(&NATSMsg{Subject: subject, Data: data})
+}
+
+// publishMsg delivers a message, which may have a reply subject
+func (b *memoryNATS) publishMsg(msg *NATSMsg) error {
+	b.mu.Lock()
+	var matching []*memorySubscription
+	for sub := range b.subs {
+		if subjectMatches(sub.subject, msg.Subject) {
+			matching = append(matching, sub)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, sub := range matching {
+		sub.handler(&NATSMsg{Subject: msg.Subject, Reply: msg.Reply, Data: append([]byte(nil), msg.Data...)})
+	}
+	return nil
+}
+
+func (b *memoryNATS) Subscribe(subject string, handler func(msg *NATSMsg)) (NATSSubscription, error) {
+	sub := &memorySubscription{bus: b, subject: subject, handler: handler}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub, nil
+}
+
+// subscribed reports whether a subscription matches subject
+func (b *memoryNATS) subscribed(subject string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if subjectMatches(sub.subject, subject) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *memorySubscription) Unsubscribe() error {
+	s.bus.mu.Lock()
+	delete(s.bus.subs, s)
+	s.bus.mu.Unlock()
+	return nil
+}
+
+// subjectMatches reports whether a subject matches a subscription pattern
+func subjectMatches(pattern, subject string) bool {
+	patternTokens, subjectTokens := strings.Split(pattern, "."), strings.Split(subject, ".")
+	if len(patternTokens) != len(subjectTokens) {
+		return false
+	}
+	for i, token := range patternTokens {
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// natsClientConn is a client connection to a NATS transport, publishing to
+// the session subject and reading replies and events
+type natsClientConn struct {
+	bus      *memoryNATS
+	subject  string
+	inbox    string
+	subs     []NATSSubscription
+	messages chan []byte
+	done     chan struct{}
+	once     sync.Once
+}
+
+func newNATSClientConn(bus *memoryNATS, prefix, sessionID string) *natsClientConn {
+	c := &natsClientConn{
+		bus:      bus,
+		subject:  prefix + "." + sessionID,
+		inbox:    "_INBOX." + sessionID,
+		messages: make(chan []byte, 64),
+		done:     make(chan struct{}),
+	}
+	for _, subject := range []string{c.inbox, c.subject + ".events"} {
+		sub, _ := bus.Subscribe(subject, func(msg *NATSMsg) {
+			select {
+			case c.messages <- msg.Data:
+			case <-c.done:
+			}
+		})
+		c.subs = append(c.subs, sub)
+	}
+	return c
+}
+
+func (c *natsClientConn) Read() ([]byte, error) {
+	select {
+	case msg := <-c.messages:
+		return msg, nil
+	case <-c.done:
+		return nil, io.EOF
+	}
+}
+
+func (c *natsClientConn) Write(ctx context.Context, data []byte) error {
+	return c.bus.publishMsg(&NATSMsg{Subject: c.subject, Reply: c.inbox, Data: data})
+}
+
+func (c *natsClientConn) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+		for _, sub := range c.subs {
+			sub.Unsubscribe()
+		}
+	})
+	return nil
+}
+
+// startNATS starts a NATS transport for srv on a new bus
+func startNATS(t *testing.T, srv *server.Server, options ...Option) (*NATSTransport, *memoryNATS) {
+	t.Helper()
+
+	bus := newMemoryNATS()
+	tr := NewNATSTransport(bus, "mcp", srv, append([]Option{WithLogger(discardLogger)}, options...)...).(*NATSTransport)
+	go tr.Start()
+	t.Cleanup(func() { tr.Stop(context.Background()) })
+
+	if !eventually(t, func() bool { return bus.subscribed("mcp.session") }) {
+		t.Fatal("transport did not subscribe")
+	}
+	return tr, bus
+}
+
+// eventually polls cond until it holds or a second passes
+func eventually(t *testing.T, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+func TestNATSSessions(t *testing.T) {
+	srv := server.NewServer("nats", server.WithLogger(discardLogger))
+	srv.AddTool("whoami", func(ctx context.Context) string {
+		return server.SessionFromContext(ctx).ID()
+	}, "Returns the session ID")
+	tr, bus := startNATS(t, srv)
+
+	// Each session ID gets its own session
+	ctx := context.Background()
+	ids := make(map[string]bool)
+	for _, sessionID := range []string{"a", "b"} {
+		c, err := client.Connect(ctx, newNATSClientConn(bus, "mcp", sessionID))
+		if err != nil {
+			t.Fatalf("failed to connect session %s: %v", sessionID, err)
+		}
+		defer c.Close()
+		result, err := c.CallTool(ctx, "whoami", nil)
+		if err != nil {
+			t.Fatalf("failed to call tool: %v", err)
+		}
+		ids[result.Content[0].(protocol.TextContent).Text] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("expected 2 distinct sessions, got %v", ids)
+	}
+	if stats := tr.Stats(); stats.ActiveConnections != 2 {
+		t.Errorf("expected 2 connections, got %+v", stats)
+	}
+
+	// Invalid messages are answered with a parse error on the reply subject
+	conn := newNATSClientConn(bus, "mcp", "c")
+	defer conn.Close()
+	conn.Write(ctx, []byte("{not json"))
+	data, err := conn.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp protocol.JSONRPCError
+	if err := json.Unmarshal(data, &resp); err != nil || resp.Error.Code != protocol.ParseError {
+		t.Errorf("expected a parse error, got %s", data)
+	}
+
+	// Stopping closes every session
+	if err := tr.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(t, func() bool { return tr.Stats().ActiveConnections == 0 }) {
+		t.Errorf("expected the sessions to be closed, got %+v", tr.Stats())
+	}
+	if bus.subscribed("mcp.a") {
+		t.Error("expected the transport to unsubscribe")
+	}
+}
+
+func TestNATSSlowPublish(t *testing.T) {
+	srv := server.NewServer("nats", server.WithLogger(discardLogger))
+	tr, bus := startNATS(t, srv)
+
+	ctx := context.Background()
+	a, err := client.Connect(ctx, newNATSClientConn(bus, "mcp", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	// A subscriber of session a's events blocks publishing until released
+	release := make(chan struct{})
+	blocked := make(chan struct{}, 1)
+	sub, _ := bus.Subscribe("mcp.a.events", func(msg *NATSMsg) {
+		select {
+		case blocked <- struct{}{}:
+		default:
+		}
+		<-release
+	})
+	defer sub.Unsubscribe()
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- tr.SendNotification("notifications/message", map[string]interface{}{"level": "info", "data": "hello"})
+	}()
+	<-blocked
+
+	// New sessions are served while the notification is being published
+	connected := make(chan error, 1)
+	go func() {
+		b, err := client.Connect(ctx, newNATSClientConn(bus, "mcp", "b"))
+		if err == nil {
+			b.Close()
+		}
+		connected <- err
+	}()
+	select {
+	case err := <-connected:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a new session while publishing was blocked")
+	}
+
+	close(release)
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// ProtectedResourcePath is the well-known path serving the OAuth protected
+// resource metadata (RFC 9728) of an MCP server
+const ProtectedResourcePath = "/.well-known/oauth-protected-resource"
+
+// Errors returned by OAuth token authentication
+var (
+	// ErrInvalidToken is returned for tokens that are malformed, expired or
+	// were issued for another resource
+	ErrInvalidToken = fmt.Errorf("%w: invalid token", ErrUnauthorized)
+
+	// ErrInsufficientScope is returned for valid tokens lacking a required
+	// scope
+	ErrInsufficientScope = errors.New("insufficient scope")
+)
+
+// ProtectedResourceMetadata describes the MCP server as an OAuth protected
+// resource, telling clients which authorization servers issue its tokens
+type ProtectedResourceMetadata struct {
+	// Resource is the canonical URL of the MCP server, which tokens must
+	// name as their audience
+	Resource string `json:"resource"`
+
+	// AuthorizationServers lists the issuers clients can obtain tokens from
+	AuthorizationServers []string `json:"authorization_servers"`
+
+	// ScopesSupported lists the scopes the server understands
+	ScopesSupported []string `json:"scopes_supported,omitempty"`
+
+	// BearerMethodsSupported lists how tokens may be presented
+	BearerMethodsSupported []string `json:"bearer_methods_supported,omitempty"`
+
+	// ResourceDocumentation is a URL of human-readable documentation
+	ResourceDocumentation string `json:"resource_documentation,omitempty"`
+}
+
+// TokenClaims are the verified contents of an OAuth access token
+type TokenClaims struct {
+	// Subject identifies the user or service the token was issued to
+	Subject string
+
+	// Audience lists the resources the token was issued for
+	Audience []string
+
+	// Scopes lists the scopes granted by the token
+	Scopes []string
+
+	// ExpiresAt is when the token expires, or zero if it does not
+	ExpiresAt time.Time
+
+	// Extra holds any other claims
+	Extra map[string]interface{}
+}
+
+// TokenVerifier checks the signature or introspects an access token and
+// returns its claims, such as by validating a JWT against the issuer's keys
+type TokenVerifier func(ctx context.Context, token string) (*TokenClaims, error)
+
+// WithProtectedResource configures an HTTP transport as an OAuth protected
+// resource. It serves the metadata at ProtectedResourcePath and points
+// clients to it, on the origin of metadata.Resource, from the
+// WWW-Authenticate challenge of 401 responses.
+func WithProtectedResource(metadata ProtectedResourceMetadata) Option {
+	return func(o *Options) {
+		o.ProtectedResource = &metadata
+	}
+}
+
+// OAuthAuthenticator authenticates requests carrying OAuth 2.1 bearer
+// tokens. Tokens must be accepted by verify, be unexpired, name resource in
+// their audience and grant every required scope.
+func OAuthAuthenticator(resource string, verify TokenVerifier, requiredScopes ...string) Authenticator {
+	return BearerTokenAuthenticator(func(ctx context.Context, token string) (*server.AuthInfo, error) {
+		claims, err := verify(ctx, token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+		if claims == nil {
+			return nil, fmt.Errorf("%w: verifier returned no claims", ErrInvalidToken)
+		}
+		if !claims.ExpiresAt.IsZero() && time.Now().After(claims.ExpiresAt) {
+			return nil, fmt.Errorf("%w: token expired", ErrInvalidToken)
+		}
+		// Tokens issued for other resources must not be accepted, so that a
+		// token cannot be replayed across servers
+		if !slices.Contains(claims.Audience, resource) {
+			return nil, fmt.Errorf("%w: token audience does not include %s", ErrInvalidToken, resource)
+		}
+		for _, scope := range requiredScopes {
+			if !slices.Contains(claims.Scopes, scope) {
+				return nil, fmt.Errorf("%w: %s", ErrInsufficientScope, scope)
+			}
+		}
+
+		return &server.AuthInfo{
+			Principal: claims.Subject,
+			Scopes:    claims.Scopes,
+			Extra:     claims.Extra,
+		}, nil
+	})
+}
+
+// ProtectedResourceHandler serves the protected resource metadata, for
+// transports mounted on an existing mux at ProtectedResourcePath
+func ProtectedResourceHandler(metadata ProtectedResourceMetadata) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metadata)
+	})
+}
+
+// handleProtectedResource registers the metadata endpoint on a transport's
+// mux when the transport is a protected resource
+func (o Options) handleProtectedResource(mux *http.ServeMux) {
+	if o.ProtectedResource != nil {
+		mux.Handle(ProtectedResourcePath, ProtectedResourceHandler(*o.ProtectedResource))
+	}
+}
+
+// challenge builds the WWW-Authenticate header for a failed authentication.
+// The metadata URL is derived from the configured resource, never from the
+// request, so clients cannot redirect it with their Host header.
+func (o Options) challenge(errorCode string) string {
+	var params []string
+	if o.ProtectedResource != nil {
+		if metadataURL := protectedResourceURL(o.ProtectedResource.Resource); metadataURL != "" {
+			params = append(params, fmt.Sprintf("resource_metadata=%q", metadataURL))
+		}
+	}
+	if errorCode != "" {
+		params = append(params, fmt.Sprintf("error=%q", errorCode))
+	}
+	if len(params) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// protectedResourceURL returns the URL of the metadata of a resource, on
+// the resource's origin, or "" when the resource is not an absolute URL
+func protectedResourceURL(resource string) string {
+	u, err := url.Parse(resource)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: ProtectedResourcePath}).String()
+}
@@ -0,0 +1,148 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+const testResource = "https://mcp.example.com/mcp"
+
+// testVerifier accepts tokens named in claims
+func testVerifier(claims map[string]*TokenClaims) TokenVerifier {
+	return func(ctx context.Context, token string) (*TokenClaims, error) {
+		c, ok := claims[token]
+		if !ok {
+			return nil, errors.New("unknown token")
+		}
+		return c, nil
+	}
+}
+
+func TestOAuthAuthenticator(t *testing.T) {
+	verify := testVerifier(map[string]*TokenClaims{
+		"good":        {Subject: "ann", Audience: []string{testResource}, Scopes: []string{"mcp:read", "mcp:write"}, ExpiresAt: time.Now().Add(time.Hour)},
+		"no-expiry":   {Subject: "ann", Audience: []string{testResource}, Scopes: []string{"mcp:read"}},
+		"other":       {Subject: "ann", Audience: []string{"https://other.example.com"}, Scopes: []string{"mcp:read"}},
+		"expired":     {Subject: "ann", Audience: []string{testResource}, Scopes: []string{"mcp:read"}, ExpiresAt: time.Now().Add(-time.Minute)},
+		"read-only":   {Subject: "ann", Audience: []string{testResource}, Scopes: []string{"mcp:write"}},
+		"null-claims": nil,
+	})
+	opts := Options{
+		Authenticator:     OAuthAuthenticator(testResource, verify, "mcp:read"),
+		ProtectedResource: &ProtectedResourceMetadata{Resource: testResource, AuthorizationServers: []string{"https://auth.example.com"}},
+	}
+	const metadata = `resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource"`
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		challenge     string
+		principal     string
+	}{
+		{name: "valid token", authorization: "Bearer good", status: http.StatusOK, principal: "ann"},
+		{name: "token without expiry", authorization: "bearer no-expiry", status: http.StatusOK, principal: "ann"},
+		{name: "missing token", authorization: "", status: http.StatusUnauthorized, challenge: "Bearer " + metadata},
+		{name: "malformed header", authorization: "Bearer", status: http.StatusUnauthorized, challenge: "Bearer " + metadata},
+		{name: "wrong scheme", authorization: "Basic Z29vZA==", status: http.StatusUnauthorized, challenge: "Bearer " + metadata},
+		{name: "unknown token", authorization: "Bearer forged", status: http.StatusUnauthorized, challenge: "Bearer " + metadata + `, error="invalid_token"`},
+		{name: "wrong audience", authorization: "Bearer other", status: http.StatusUnauthorized, challenge: "Bearer " + metadata + `, error="invalid_token"`},
+		{name: "expired token", authorization: "Bearer expired", status: http.StatusUnauthorized, challenge: "Bearer " + metadata + `, error="invalid_token"`},
+		{name: "missing scope", authorization: "Bearer read-only", status: http.StatusForbidden, challenge: "Bearer " + metadata + `, error="insufficient_scope"`},
+		{name: "nil claims", authorization: "Bearer null-claims", status: http.StatusUnauthorized, challenge: "Bearer " + metadata + `, error="invalid_token"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			r.Host = "attacker.example.com"
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			info, ok := opts.authenticate(w, r)
+			if ok != (tt.status == http.StatusOK) {
+				t.Fatalf("authenticate returned %v, want status %d", ok, tt.status)
+			}
+			if ok {
+				if info == nil || info.Principal != tt.principal {
+					t.Errorf("got auth info %+v, want principal %q", info, tt.principal)
+				}
+				return
+			}
+			if w.Code != tt.status {
+				t.Errorf("got status %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.challenge {
+				t.Errorf("got challenge %q, want %q", got, tt.challenge)
+			}
+		})
+	}
+}
+
+func TestChallengeWithoutAbsoluteResource(t *testing.T) {
+	opts := Options{ProtectedResource: &ProtectedResourceMetadata{Resource: "mcp"}}
+	if got := opts.challenge("invalid_token"); got != `Bearer error="invalid_token"` {
+		t.Errorf("got challenge %q", got)
+	}
+}
+
+func TestProtectedResourceEndpoint(t *testing.T) {
+	metadata := ProtectedResourceMetadata{
+		Resource:             testResource,
+		AuthorizationServers: []string{"https://auth.example.com"},
+		ScopesSupported:      []string{"mcp:read"},
+	}
+	srv := server.NewServer("oauth", server.WithLogger(discardLogger))
+	tr := NewStreamableHTTPTransport(srv, WithLogger(discardLogger), WithProtectedResource(metadata),
+		WithAuthenticator(OAuthAuthenticator(testResource, testVerifier(nil)))).(*StreamableHTTPTransport)
+	ts := httptest.NewServer(tr.mux())
+	defer ts.Close()
+
+	// The metadata is public, so clients can discover how to authenticate
+	resp, err := http.Get(ts.URL + ProtectedResourcePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		t.Fatalf("got status %d and content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"resource":              testResource,
+		"authorization_servers": []interface{}{"https://auth.example.com"},
+		"scopes_supported":      []interface{}{"mcp:read"},
+	}
+	if !jsonEqual(body, want) {
+		t.Errorf("got metadata %v, want %v", body, want)
+	}
+
+	initialize := `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26"}}`
+	post, err := http.Post(ts.URL+"/mcp", "application/json", strings.NewReader(initialize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Body.Close()
+	if post.StatusCode != http.StatusUnauthorized || post.Header.Get("WWW-Authenticate") == "" {
+		t.Errorf("unauthenticated initialization got status %d and challenge %q", post.StatusCode, post.Header.Get("WWW-Authenticate"))
+	}
+}
+
+// jsonEqual reports whether two values marshal to the same JSON
+func jsonEqual(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
@@ -0,0 +1,88 @@
+package transport
+
+import "sync"
+
+// ResponseOrder determines the order in which a connection's responses are
+// sent when its requests are handled concurrently
+type ResponseOrder int
+
+const (
+	// ResponseOrderUnordered sends each response as soon as its request
+	// has been handled
+	ResponseOrderUnordered ResponseOrder = iota
+
+	// ResponseOrderFIFO sends responses in the order their requests
+	// arrived, holding back responses to later requests until earlier
+	// ones are answered. Requests are still handled concurrently.
+	ResponseOrderFIFO
+)
+
+// WithResponseOrder sets the order in which responses are sent on stdio,
+// TCP, WebSocket, SSE and NATS connections. Streamable HTTP answers each
+// request in its own HTTP response, so it is unaffected.
+func WithResponseOrder(order ResponseOrder) Option {
+	return func(o *Options) {
+		o.ResponseOrder = order
+	}
+}
+
+// sequencer delivers the responses of a connection in request order
+type sequencer struct {
+	mu      sync.Mutex
+	next    uint64
+	turn    uint64
+	pending map[uint64]func()
+}
+
+// newSequencer creates a sequencer for FIFO ordering, or returns nil, which
+// delivers responses immediately
+func newSequencer(order ResponseOrder) *sequencer {
+	if order != ResponseOrderFIFO {
+		return nil
+	}
+	return &sequencer{pending: make(map[uint64]func())}
+}
+
+// ticket reserves the next position in the response order for a request
+// that just arrived. Every ticket must be passed to deliver exactly once.
+func (s *sequencer) ticket() uint64 {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.next
+	s.next++
+	return t
+}
+
+// deliver runs send once the responses of all earlier tickets have been
+// delivered. send is nil for requests that receive no response, such as
+// cancelled ones.
+func (s *sequencer) deliver(ticket uint64, send func()) {
+	if s == nil {
+		if send != nil {
+			send()
+		}
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if send == nil {
+		send = func() {}
+	}
+	s.pending[ticket] = send
+	for {
+		send, ok := s.pending[s.turn]
+		if !ok {
+			return
+		}
+		delete(s.pending, s.turn)
+		s.turn++
+		send()
+	}
+}
@@ -0,0 +1,135 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+func TestSequencer(t *testing.T) {
+	s := newSequencer(ResponseOrderFIFO)
+	var sent []int
+	send := func(i int) func() {
+		return func() { sent = append(sent, i) }
+	}
+
+	tickets := make([]uint64, 5)
+	for i := range tickets {
+		tickets[i] = s.ticket()
+	}
+
+	// Later responses wait for the first, and the cancelled request in the
+	// middle does not hold back the ones after it
+	s.deliver(tickets[3], send(3))
+	s.deliver(tickets[1], send(1))
+	s.deliver(tickets[2], nil)
+	s.deliver(tickets[4], send(4))
+	if len(sent) != 0 {
+		t.Fatalf("expected responses to wait for the first, got %v", sent)
+	}
+	s.deliver(tickets[0], send(0))
+	if want := []int{0, 1, 3, 4}; !slices.Equal(sent, want) {
+		t.Errorf("got responses %v, want %v", sent, want)
+	}
+
+	// Tickets taken afterwards continue the order
+	next := s.ticket()
+	s.deliver(next, send(5))
+	if sent[len(sent)-1] != 5 {
+		t.Errorf("expected the next response to be sent immediately, got %v", sent)
+	}
+}
+
+func TestSequencerConcurrent(t *testing.T) {
+	s := newSequencer(ResponseOrderFIFO)
+	var sent []uint64
+	tickets := make([]uint64, 100)
+	for i := range tickets {
+		tickets[i] = s.ticket()
+	}
+	rand.Shuffle(len(tickets), func(i, j int) { tickets[i], tickets[j] = tickets[j], tickets[i] })
+
+	var wg sync.WaitGroup
+	for _, ticket := range tickets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ticket%10 == 5 {
+				s.deliver(ticket, nil)
+				return
+			}
+			s.deliver(ticket, func() { sent = append(sent, ticket) })
+		}()
+	}
+	wg.Wait()
+
+	if len(sent) != 90 || !slices.IsSorted(sent) {
+		t.Errorf("expected 90 responses in ticket order, got %v", sent)
+	}
+}
+
+func TestUnorderedSequencer(t *testing.T) {
+	s := newSequencer(ResponseOrderUnordered)
+	sent := false
+	s.deliver(s.ticket(), func() { sent = true })
+	s.deliver(s.ticket(), nil)
+	if !sent {
+		t.Error("expected the response to be sent immediately")
+	}
+}
+
+func TestFIFOResponses(t *testing.T) {
+	srv := server.NewServer("order", server.WithLogger(discardLogger))
+	srv.AddTool("slow", func() string {
+		time.Sleep(50 * time.Millisecond)
+		return "slow"
+	}, "Answers slowly")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := NewTCPTransport(srv, WithLogger(discardLogger), WithResponseOrder(ResponseOrderFIFO)).(*TCPTransport)
+	go tr.Serve(listener)
+	defer tr.Stop(context.Background())
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	lines := bufio.NewScanner(conn)
+	lines.Buffer(nil, 1<<20)
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}` + "\n"))
+	lines.Scan()
+	conn.Write([]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"))
+
+	// The ping is answered first but its response waits for the slow call
+	conn.Write([]byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"slow"}}` + "\n"))
+	conn.Write([]byte(`{"jsonrpc":"2.0","id":3,"method":"ping"}` + "\n"))
+	var ids []string
+	for range 2 {
+		if !lines.Scan() {
+			t.Fatalf("failed to read response: %v", lines.Err())
+		}
+		var resp protocol.JSONRPCResponse
+		if err := json.Unmarshal(lines.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		id, _ := json.Marshal(resp.ID)
+		ids = append(ids, string(id))
+	}
+	if want := []string{"2", "3"}; !slices.Equal(ids, want) {
+		t.Errorf("got responses %v, want %v", ids, want)
+	}
+}
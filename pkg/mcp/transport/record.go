@@ -0,0 +1,143 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Directions of recorded frames
+const (
+	// FrameIn is a frame the client sent to the server
+	FrameIn = "in"
+	// FrameOut is a frame the server sent to the client
+	FrameOut = "out"
+)
+
+// Frame is a JSON-RPC frame of a recorded session
+type Frame struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	Session   string    `json:"session,omitempty"`
+
+	// Message is the frame, or empty when it is not valid JSON
+	Message json.RawMessage `json:"message,omitempty"`
+
+	// Raw holds a frame that is not valid JSON, as received
+	Raw string `json:"raw,omitempty"`
+}
+
+// newFrame creates a frame from its data, compacting JSON
+func newFrame(direction, session string, data []byte) Frame {
+	frame := Frame{Time: time.Now(), Direction: direction, Session: session}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		frame.Raw = string(bytes.TrimSpace(data))
+	} else {
+		frame.Message = compact.Bytes()
+	}
+	return frame
+}
+
+// data returns the frame as it crossed the wire
+func (f Frame) data() []byte {
+	if f.Message == nil {
+		return []byte(f.Raw)
+	}
+	return f.Message
+}
+
+// Recorder records the frames of sessions as JSON lines, one Frame per
+// line, for replaying them into a server with Replay. Recordings hold
+// secrets as sent, like a wire tap.
+type Recorder struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewRecorder creates a recorder writing frames to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// WithRecorder records every frame a transport receives or sends to r.
+// Transports created with the same recorder share it safely, and it can be
+// combined with a wire tap.
+func WithRecorder(r *Recorder) Option {
+	return func(o *Options) {
+		o.WireTap = joinTaps(o.WireTap, recorderTap{r})
+	}
+}
+
+// record writes a frame as a line
+func (r *Recorder) record(frame Frame) error {
+	data, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err = r.w.Write(append(data, '\n'))
+	return err
+}
+
+// recorderTap plugs a recorder into the wire tap of transports
+type recorderTap struct {
+	r *Recorder
+}
+
+// Write discards text; frames are recorded through tapFrame
+func (t recorderTap) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (t recorderTap) tapFrame(direction, session string, data []byte) {
+	if direction == wireIn {
+		direction = FrameIn
+	} else {
+		direction = FrameOut
+	}
+	t.r.record(newFrame(direction, session, data))
+}
+
+// ReadFrames reads the frames written by a Recorder or WriteFrames
+func ReadFrames(r io.Reader) ([]Frame, error) {
+	var frames []Frame
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var frame Frame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("invalid frame on line %d: %w", line, err)
+		}
+		if frame.Direction != FrameIn && frame.Direction != FrameOut {
+			return nil, fmt.Errorf("invalid frame on line %d: unknown direction %q", line, frame.Direction)
+		}
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read frames: %w", err)
+	}
+	return frames, nil
+}
+
+// WriteFrames writes frames in the format of a Recorder, such as the
+// transcript of a replay to update a golden file
+func WriteFrames(w io.Writer, frames []Frame) error {
+	r := NewRecorder(w)
+	for _, frame := range frames {
+		if err := r.record(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
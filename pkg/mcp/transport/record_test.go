@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+func TestRecordReplay(t *testing.T) {
+	newServer := func(echo func(string) string) *server.Server {
+		srv := server.NewServer("golden", server.WithLogger(discardLogger))
+		srv.AddTool("echo", echo, "Echoes the text")
+		return srv
+	}
+	srv := newServer(func(text string) string { return text })
+
+	// Record a session, with a wire tap alongside the recorder
+	var recording, wire bytes.Buffer
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := NewTCPTransport(srv, WithLogger(discardLogger), WithWireTap(&wire), WithRecorder(NewRecorder(&recording))).(*TCPTransport)
+	go tr.Serve(listener)
+
+	ctx := context.Background()
+	conn, err := client.DialTCP(ctx, listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.Connect(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CallTool(ctx, "echo", map[string]interface{}{"arg0": "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CallTool(ctx, "unknown", nil); err == nil {
+		t.Fatal("expected an error calling an unknown tool")
+	}
+	c.Close()
+	tr.Stop(ctx)
+
+	if !strings.Contains(wire.String(), wireIn) {
+		t.Errorf("wire tap missed the frames: %q", wire.String())
+	}
+	frames, err := ReadFrames(&recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(serverFrames(frames)); n != 3 {
+		t.Fatalf("expected 3 server frames, got %d in %+v", n, frames)
+	}
+
+	// Replaying into the same server reproduces the recording
+	transcript, err := Replay(ctx, srv, frames, WithLogger(discardLogger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := DiffFrames(frames, transcript); diff != "" {
+		t.Errorf("replay differs from the recording:\n%s", diff)
+	}
+
+	// Transcripts survive a round trip through their file format
+	var golden bytes.Buffer
+	if err := WriteFrames(&golden, transcript); err != nil {
+		t.Fatal(err)
+	}
+	reread, err := ReadFrames(&golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := DiffFrames(transcript, reread); diff != "" {
+		t.Errorf("transcript changed in a round trip:\n%s", diff)
+	}
+
+	// A change in behavior shows in the diff
+	changed := newServer(strings.ToUpper)
+	transcript, err = Replay(ctx, changed, frames, WithLogger(discardLogger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := DiffFrames(frames, transcript); !strings.Contains(diff, "HELLO") {
+		t.Errorf("expected the changed result in the diff, got %q", diff)
+	}
+}
@@ -0,0 +1,160 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// replayWait bounds the wait for each frame the server sent in a recording
+const replayWait = time.Second
+
+// replayQuiet is how long a replay waits for frames the server sends
+// beyond the recording
+const replayQuiet = 100 * time.Millisecond
+
+// Replay feeds the frames a client sent in a recorded session into a new
+// session created by sessions, over the newline-delimited framing of the
+// stdio transport, and returns the transcript of the replay for comparing
+// with DiffFrames. Frames are replayed in the recorded order: for each
+// frame the server sent, the replay waits for the server to send one.
+// Responses are sent in request order unless options set another, so that
+// transcripts are deterministic. Recordings should hold a single session.
+func Replay(ctx context.Context, sessions SessionFactory, frames []Frame, options ...Option) ([]Frame, error) {
+	opts := defaultOptions()
+	opts.ResponseOrder = ResponseOrderFIFO
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	clientConn, serverConn := net.Pipe()
+	session := sessions.NewSession(ctx)
+	session.SetConnInfo(&server.ConnInfo{Transport: "replay"})
+	c := newLineConn(session, serverConn, serverConn, opts, opts.Logger, newMetrics())
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		if err := c.serve(ctx.Done()); err != nil {
+			c.logger.Error("failed to serve replay", "error", err)
+		}
+		session.Close()
+		serverConn.Close()
+	}()
+
+	// Read the frames of the server in the background
+	out := make(chan []byte)
+	go func() {
+		defer close(out)
+		reader := bufio.NewReader(clientConn)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				out <- line
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	defer func() {
+		clientConn.Close()
+		for range out {
+		}
+		<-served
+	}()
+
+	var transcript []Frame
+	receive := func(wait time.Duration) (bool, error) {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case data, ok := <-out:
+			if ok {
+				transcript = append(transcript, newFrame(FrameOut, session.ID(), data))
+			}
+			return ok, nil
+		case <-timer.C:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+
+	for i, frame := range frames {
+		if frame.Direction == FrameOut {
+			// A server sending fewer frames than recorded shows in the diff
+			if _, err := receive(replayWait); err != nil {
+				return transcript, err
+			}
+			continue
+		}
+
+		data := append(append([]byte(nil), frame.data()...), '\n')
+		if _, err := clientConn.Write(data); err != nil {
+			return transcript, fmt.Errorf("failed to replay frame %d: %w", i+1, err)
+		}
+		transcript = append(transcript, newFrame(FrameIn, session.ID(), frame.data()))
+	}
+
+	// Collect the frames the server sends beyond the recording
+	for {
+		ok, err := receive(replayQuiet)
+		if err != nil {
+			return transcript, err
+		}
+		if !ok {
+			return transcript, nil
+		}
+	}
+}
+
+// DiffFrames compares the frames the server sent in two transcripts, such
+// as a golden recording and its replay, ignoring times, sessions and JSON
+// formatting. It returns a description of the differences, or "" when the
+// server sent the same frames.
+func DiffFrames(want, got []Frame) string {
+	wantOut, gotOut := serverFrames(want), serverFrames(got)
+	var diff strings.Builder
+	for i := 0; i < max(len(wantOut), len(gotOut)); i++ {
+		switch {
+		case i >= len(gotOut):
+			fmt.Fprintf(&diff, "server frame %d: missing, want %s\n", i+1, wantOut[i].data())
+		case i >= len(wantOut):
+			fmt.Fprintf(&diff, "server frame %d: unexpected %s\n", i+1, gotOut[i].data())
+		case !sameFrame(wantOut[i], gotOut[i]):
+			fmt.Fprintf(&diff, "server frame %d:\n  want %s\n  got  %s\n", i+1, wantOut[i].data(), gotOut[i].data())
+		}
+	}
+	return diff.String()
+}
+
+// serverFrames returns the frames the server sent
+func serverFrames(frames []Frame) []Frame {
+	var out []Frame
+	for _, frame := range frames {
+		if frame.Direction == FrameOut {
+			out = append(out, frame)
+		}
+	}
+	return out
+}
+
+// sameFrame reports whether two frames hold the same JSON, or the same
+// data when they are not JSON
+func sameFrame(a, b Frame) bool {
+	if a.Message == nil || b.Message == nil {
+		return a.Message == nil && b.Message == nil && a.Raw == b.Raw
+	}
+	var va, vb interface{}
+	if json.Unmarshal(a.Message, &va) != nil || json.Unmarshal(b.Message, &vb) != nil {
+		return string(a.Message) == string(b.Message)
+	}
+	return reflect.DeepEqual(va, vb)
+}
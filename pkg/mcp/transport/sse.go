@@ -3,7 +3,9 @@ package transport
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sync"
 
@@ -11,26 +13,47 @@ import (
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
 )
 
-// SSETransport implements a Server-Sent Events transport for MCP
+// messagePath is the endpoint SSE clients post their messages to
+const messagePath = "/message"
+
+// SSETransport implements the Server-Sent Events transport for MCP. Each
+// event stream gets its own session from the session factory and is told,
+// through an endpoint event, the URL to post its messages to.
 type SSETransport struct {
+	sessions SessionFactory
+	clients  map[string]*sseClient
+	mu       sync.RWMutex
+	opts     Options
+	limiter  *connLimiter
+	health   *health
+	metrics  *metrics
+	srv      *http.Server
+}
+
+// sseClient is a connected event stream and the session it serves
+type sseClient struct {
 	session *server.Session
-	clients map[string]chan []byte
-	mu      sync.RWMutex
-	opts    Options
-	srv     *http.Server
+	events  chan []byte
+	logger  *slog.Logger
+	metrics *metrics
+	order   *sequencer
 }
 
-// NewSSETransport creates a new SSE transport
-func NewSSETransport(session *server.Session, options ...Option) HTTPTransport {
+// NewSSETransport creates a new SSE transport that creates a session per
+// event stream
+func NewSSETransport(sessions SessionFactory, options ...Option) HTTPTransport {
 	opts := defaultOptions()
 	for _, opt := range options {
 		opt(&opts)
 	}
 
 	return &SSETransport{
-		session: session,
-		clients: make(map[string]chan []byte, opts.BufferSize),
-		opts:    opts,
+		sessions: sessions,
+		clients:  make(map[string]*sseClient),
+		opts:     opts,
+		limiter:  newConnLimiter(opts),
+		health:   newHealth(sessions),
+		metrics:  newMetrics(),
 	}
 }
 
@@ -39,107 +62,187 @@ func (t *SSETransport) Start() error {
 	return t.StartHTTP(t.opts.Address)
 }
 
-// StartHTTP starts the SSE transport on the given address
-func (t *SSETransport) StartHTTP(addr string) error {
+// mux returns a mux serving the transport's endpoints, along with the
+// metadata, health and metrics endpoints enabled by its options
+func (t *SSETransport) mux() *http.ServeMux {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/events", t.handleSSE)
-	mux.HandleFunc("/", t.handleRequest)
+	t.routes(mux)
+	t.opts.handleProtectedResource(mux)
+	t.opts.handleHealth(mux, t.health)
+	t.opts.handleMetrics(mux, t, t.sessions)
+	return mux
+}
 
+// StartHTTP starts the SSE transport on the given address
+func (t *SSETransport) StartHTTP(addr string) error {
 	t.srv = &http.Server{
-		Addr:    addr,
-		Handler: mux,
+		Addr:      addr,
+		Handler:   t.mux(),
+		TLSConfig: t.opts.TLSConfig,
 	}
 
-	return t.srv.ListenAndServe()
+	return t.health.listenAndServe(t.srv)
+}
+
+// routes registers the transport's MCP endpoints on mux. Event streams are
+// opened at /sse; /events is kept as an alias for existing clients.
+func (t *SSETransport) routes(mux *http.ServeMux) {
+	mux.HandleFunc("/sse", t.handleSSE)
+	mux.HandleFunc("/events", t.handleSSE)
+	mux.HandleFunc(messagePath, t.handleRequest)
 }
 
 // Stop stops the transport
 func (t *SSETransport) Stop(ctx context.Context) error {
+	t.health.ready.Store(false)
 	if t.srv != nil {
 		return t.srv.Shutdown(ctx)
 	}
 	return nil
 }
 
-// handleSSE handles SSE connections
+// handleSSE handles SSE connections, creating a session for each
 func (t *SSETransport) handleSSE(w http.ResponseWriter, r *http.Request) {
-	// Set SSE headers
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	authInfo, ok := t.opts.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	if !t.limiter.acquire(w, r) {
+		return
+	}
+	defer t.limiter.release()
 
-	// Create a channel for this client
-	clientChan := make(chan []byte, t.opts.BufferSize)
-	clientID := r.RemoteAddr
+	w, finish := t.opts.compress(w, r)
+	defer finish()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// The session lives as long as the event stream
+	session := t.sessions.NewSession(r.Context())
+	sessionID := session.ID()
+	client := &sseClient{
+		session: session,
+		events:  make(chan []byte, t.opts.BufferSize),
+		logger:  t.opts.Logger.With("session", sessionID, "remote", r.RemoteAddr),
+		metrics: t.metrics,
+		order:   newSequencer(t.opts.ResponseOrder),
+	}
+	client.session.SetNotificationSender(client)
+	client.session.SetAuthInfo(authInfo)
+	client.session.SetConnInfo(t.opts.httpConnInfo("sse", r))
 
 	// Register the client
 	t.mu.Lock()
-	t.clients[clientID] = clientChan
+	t.clients[sessionID] = client
 	t.mu.Unlock()
+	client.logger.Debug("client connected")
+	defer t.metrics.connected()()
 
 	// Clean up when the connection is closed
 	defer func() {
 		t.mu.Lock()
-		delete(t.clients, clientID)
-		close(clientChan)
+		delete(t.clients, sessionID)
 		t.mu.Unlock()
+		client.session.Close()
+		client.logger.Debug("client disconnected")
 	}()
 
-	// Create a context that's cancelled when the client disconnects
-	ctx, cancel := context.WithCancel(r.Context())
-	defer cancel()
+	// Set SSE headers
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
 
-	// Start the event loop
-	flusher, ok := w.(http.Flusher)
-	if !ok {
-		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
-		return
-	}
+	// Tell the client where to post its messages
+	fmt.Fprintf(w, "event: endpoint\ndata: %s?sessionId=%s\n\n", messagePath, sessionID)
+	flusher.Flush()
 
+	// Start the event loop
 	for {
 		select {
-		case <-ctx.Done():
+		case <-r.Context().Done():
 			return
-		case msg, ok := <-clientChan:
-			if !ok {
-				return
-			}
-			fmt.Fprintf(w, "data: %s\n\n", msg)
+		case <-client.session.Done():
+			return
+		case msg := <-client.events:
+			writeEvent(w, msg)
 			flusher.Flush()
+			t.metrics.messagesOut.Add(1)
+			tapFrame(t.opts.WireTap, wireOut, sessionID, msg)
 		}
 	}
 }
 
-// handleRequest handles incoming JSON-RPC requests
+// handleRequest handles messages posted to a session's endpoint. Responses
+// are delivered on the session's event stream.
 func (t *SSETransport) handleRequest(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	sessionID := r.URL.Query().Get("sessionId")
+	if sessionID == "" {
+		http.Error(w, "Missing sessionId", http.StatusBadRequest)
+		return
+	}
+	t.mu.RLock()
+	client, ok := t.clients[sessionID]
+	t.mu.RUnlock()
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if !t.opts.authorizeSession(w, r, client.session) {
+		return
+	}
+
 	// Parse the request
 	var msg struct {
 		JSONRPC string              `json:"jsonrpc"`
 		ID      *protocol.RequestID `json:"id,omitempty"`
 		Method  string              `json:"method"`
 		Params  json.RawMessage     `json:"params,omitempty"`
+		Result  json.RawMessage     `json:"result,omitempty"`
+		Error   *protocol.ErrorData `json:"error,omitempty"`
 	}
+	t.opts.limitBody(w, r)
 	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
-		t.writeError(w, nil, -32700, "Parse error", err)
+		if isTooLarge(err) {
+			t.writeError(w, nil, protocol.InvalidRequest, "Message too large", err)
+			return
+		}
+		t.writeError(w, nil, protocol.ParseError, "Parse error", err)
 		return
 	}
+	t.metrics.messagesIn.Add(1)
+	tapFrame(t.opts.WireTap, wireIn, sessionID, msg)
 
 	// Handle the message
-	if msg.ID != nil {
-		// This is a request
+	if msg.ID != nil && msg.Method == "" {
+		// This is a response to a request sent by the server
+		client.session.HandleResponse(&protocol.JSONRPCResponse{
+			JSONRPC: msg.JSONRPC,
+			ID:      *msg.ID,
+			Result:  msg.Result,
+			Error:   msg.Error,
+		})
+	} else if msg.ID != nil {
+		// This is a request. Requests are handled concurrently so that
+		// notifications such as cancellations can be processed while
+		// they run.
 		req := &protocol.JSONRPCRequest{
 			JSONRPC: msg.JSONRPC,
 			ID:      *msg.ID,
 			Method:  msg.Method,
 			Params:  msg.Params,
 		}
-		t.handleJSONRPCRequest(w, req)
+		go client.handleRequest(withHeaders(r), req, client.order.ticket())
 	} else {
 		// This is a notification
 		notif := &protocol.JSONRPCNotification{
@@ -147,32 +250,41 @@ func (t *SSETransport) handleRequest(w http.ResponseWriter, r *http.Request) {
 			Method:  msg.Method,
 			Params:  msg.Params,
 		}
-		t.handleJSONRPCNotification(w, notif)
+		if err := client.session.HandleNotification(notif); err != nil {
+			// Log the error but don't send a response for notifications
+			client.logger.Error("failed to handle notification", "method", notif.Method, "error", err)
+		}
 	}
+	w.WriteHeader(http.StatusAccepted)
 }
 
-// handleJSONRPCRequest processes a JSON-RPC request and writes the response
-func (t *SSETransport) handleJSONRPCRequest(w http.ResponseWriter, req *protocol.JSONRPCRequest) {
-	resp, err := t.session.HandleRequest(req)
-	if err != nil {
-		t.writeErrorWithID(w, req.ID, -32603, "Internal error", err)
+// handleRequest processes a JSON-RPC request and sends the response on the
+// event stream in the turn of its ticket
+func (c *sseClient) handleRequest(ctx context.Context, req *protocol.JSONRPCRequest, ticket uint64) {
+	resp, err := c.metrics.handleRequest(ctx, c.session, req)
+	if errors.Is(err, server.ErrRequestCancelled) {
+		// Cancelled requests receive no response
+		c.order.deliver(ticket, nil)
 		return
 	}
+	if err != nil {
+		resp = &protocol.JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   protocol.NewError(protocol.InternalError, err.Error()),
+		}
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
-}
-
-// handleJSONRPCNotification processes a JSON-RPC notification
-func (t *SSETransport) handleJSONRPCNotification(w http.ResponseWriter, notif *protocol.JSONRPCNotification) {
-	if err := t.session.HandleNotification(notif); err != nil {
-		// Log the error but don't send a response for notifications
-		fmt.Printf("Error handling notification: %v\n", err)
+	data, err := json.Marshal(resp)
+	if err != nil {
+		c.logger.Error("failed to marshal response", "error", err)
+		c.order.deliver(ticket, nil)
+		return
 	}
-	w.WriteHeader(http.StatusNoContent)
+	c.order.deliver(ticket, func() { c.send(data) })
 }
 
-// writeError writes a JSON-RPC error response with no ID
+// writeError writes a JSON-RPC error response, with a null ID when id is nil
 func (t *SSETransport) writeError(w http.ResponseWriter, id *protocol.RequestID, code int, message string, err error) {
 	errResp := &protocol.JSONRPCError{
 		JSONRPC: "2.0",
@@ -190,48 +302,100 @@ func (t *SSETransport) writeError(w http.ResponseWriter, id *protocol.RequestID,
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusBadRequest)
 	json.NewEncoder(w).Encode(errResp)
+	tapFrame(t.opts.WireTap, wireOut, "", errResp)
 }
 
-// writeErrorWithID writes a JSON-RPC error response with a specific ID
-func (t *SSETransport) writeErrorWithID(w http.ResponseWriter, id protocol.RequestID, code int, message string, err error) {
-	errResp := &protocol.JSONRPCError{
-		JSONRPC: "2.0",
-		ID:      id,
-		Error: protocol.ErrorData{
-			Code:    code,
-			Message: message,
-			Data:    err.Error(),
-		},
+// SendNotification sends a notification to all connected clients
+func (t *SSETransport) SendNotification(method string, params interface{}) error {
+	// Marshal once; clients only read the queued events
+	data, err := marshalNotification(method, params)
+	if err != nil {
+		return err
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-	json.NewEncoder(w).Encode(errResp)
+	for _, client := range t.snapshot() {
+		client.notify(data)
+	}
+	return nil
 }
 
-// SendNotification sends a notification to all connected clients
-func (t *SSETransport) SendNotification(method string, params interface{}) error {
-	notif := &protocol.JSONRPCNotification{
-		JSONRPC: "2.0",
-		Method:  method,
-		Params:  params,
+// snapshot returns the connected clients, so that they can be sent to
+// without holding the lock
+func (t *SSETransport) snapshot() []*sseClient {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	clients := make([]*sseClient, 0, len(t.clients))
+	for _, client := range t.clients {
+		clients = append(clients, client)
 	}
+	return clients
+}
 
-	data, err := json.Marshal(notif)
+// SendNotification queues a notification on the client's event stream
+func (c *sseClient) SendNotification(method string, params interface{}) error {
+	data, err := marshalNotification(method, params)
 	if err != nil {
-		return fmt.Errorf("failed to marshal notification: %w", err)
+		return err
 	}
+	c.notify(data)
+	return nil
+}
 
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+// notify queues an encoded notification without waiting, counting it as
+// dropped if the client's event stream is full or its session has ended
+func (c *sseClient) notify(data []byte) {
+	select {
+	case <-c.session.Done():
+		c.metrics.dropped.Add(1)
+		return
+	default:
+	}
 
-	for _, ch := range t.clients {
-		select {
-		case ch <- data:
-		default:
-			// Skip clients that aren't ready to receive
-		}
+	select {
+	case c.events <- data:
+	default:
+		c.metrics.dropped.Add(1)
+	}
+}
+
+// SendRequest queues a server-initiated request on the client's event
+// stream. The client posts its response to the session's endpoint.
+func (c *sseClient) SendRequest(req *protocol.JSONRPCRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	if !c.send(data) {
+		return errors.New("session closed before the request was sent")
+	}
 	return nil
 }
+
+// send queues an event for the client, waiting for room in the stream
+// unless the session ends first. It reports whether the event was queued.
+func (c *sseClient) send(data []byte) bool {
+	select {
+	case <-c.session.Done():
+		return false
+	default:
+	}
+
+	select {
+	case c.events <- data:
+		return true
+	case <-c.session.Done():
+		return false
+	}
+}
+
+// ConnectionStats reports the transport's open event streams
+func (t *SSETransport) ConnectionStats() ConnectionStats {
+	return t.limiter.stats()
+}
+
+// Stats returns a snapshot of the transport's metrics
+func (t *SSETransport) Stats() Stats {
+	return t.metrics.stats()
+}
@@ -0,0 +1,243 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// sseConn is a client connection over an SSE event stream, posting its
+// messages to the endpoint announced by the stream
+type sseConn struct {
+	body     io.ReadCloser
+	events   *bufio.Reader
+	endpoint string
+	once     sync.Once
+}
+
+// dialSSE opens an event stream of the transport served at baseURL and
+// waits for its endpoint event
+func dialSSE(t *testing.T, baseURL string) *sseConn {
+	t.Helper()
+
+	resp, err := http.Get(baseURL + "/sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		t.Fatalf("failed to open event stream: %s", resp.Status)
+	}
+	c := &sseConn{body: resp.Body, events: bufio.NewReader(resp.Body)}
+	t.Cleanup(func() { c.Close() })
+
+	event, data, err := c.next()
+	if err != nil || event != "endpoint" {
+		t.Fatalf("expected an endpoint event, got %q %q (%v)", event, data, err)
+	}
+	c.endpoint = baseURL + string(data)
+	return c
+}
+
+// next reads the next event of the stream
+func (c *sseConn) next() (event string, data []byte, err error) {
+	for {
+		line, err := c.events.ReadString('\n')
+		if err != nil {
+			return "", nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			if data != nil {
+				return event, data, nil
+			}
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = append(data, strings.TrimPrefix(line, "data: ")...)
+		}
+	}
+}
+
+// sessionID returns the session ID of the stream's endpoint
+func (c *sseConn) sessionID() string {
+	_, id, _ := strings.Cut(c.endpoint, "sessionId=")
+	return id
+}
+
+func (c *sseConn) Read() ([]byte, error) {
+	_, data, err := c.next()
+	return data, err
+}
+
+func (c *sseConn) Write(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (c *sseConn) Close() error {
+	c.once.Do(func() { c.body.Close() })
+	return nil
+}
+
+// serveSSE serves an SSE transport for sessions
+func serveSSE(t *testing.T, sessions SessionFactory, options ...Option) (*SSETransport, *httptest.Server) {
+	t.Helper()
+
+	tr := NewSSETransport(sessions, append([]Option{WithLogger(discardLogger)}, options...)...).(*SSETransport)
+	ts := httptest.NewServer(tr.mux())
+	t.Cleanup(ts.Close)
+	return tr, ts
+}
+
+func TestSSESessions(t *testing.T) {
+	srv := server.NewServer("sse", server.WithLogger(discardLogger))
+	srv.AddTool("whoami", func(ctx context.Context) string {
+		return server.SessionFromContext(ctx).ID()
+	}, "Returns the session ID")
+	_, ts := serveSSE(t, srv)
+	ctx := context.Background()
+
+	// Each stream gets its own session, whose ID is in its endpoint
+	var conns []*sseConn
+	for range 2 {
+		conn := dialSSE(t, ts.URL)
+		c, err := client.Connect(ctx, conn)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer c.Close()
+		result, err := c.CallTool(ctx, "whoami", nil)
+		if err != nil {
+			t.Fatalf("failed to call tool: %v", err)
+		}
+		if id := result.Content[0].(protocol.TextContent).Text; id != conn.sessionID() {
+			t.Errorf("stream with endpoint %s served by session %s", conn.endpoint, id)
+		}
+		conns = append(conns, conn)
+	}
+	if conns[0].sessionID() == conns[1].sessionID() {
+		t.Errorf("expected distinct sessions, both got %s", conns[0].sessionID())
+	}
+
+	// Messages for unknown or missing sessions are rejected
+	for query, status := range map[string]int{"?sessionId=unknown": http.StatusNotFound, "": http.StatusBadRequest} {
+		resp, err := http.Post(ts.URL+messagePath+query, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("posting to %q returned %s, want %d", query, resp.Status, status)
+		}
+	}
+}
+
+func TestSSEResponseRouting(t *testing.T) {
+	_, ts := serveSSE(t, server.NewServer("sse", server.WithLogger(discardLogger)))
+	a, b := dialSSE(t, ts.URL), dialSSE(t, ts.URL)
+	ctx := context.Background()
+
+	// A response is delivered on the stream of the session that posted the
+	// request. Were it also sent to b, b would read it before its own.
+	if err := a.Write(ctx, []byte(`{"jsonrpc":"2.0","id":"a","method":"ping"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Write(ctx, []byte(`{"jsonrpc":"2.0","id":"b","method":"ping"}`)); err != nil {
+		t.Fatal(err)
+	}
+	for name, conn := range map[string]*sseConn{"a": a, "b": b} {
+		data, err := conn.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var resp protocol.JSONRPCResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if id, _ := json.Marshal(resp.ID); string(id) != `"`+name+`"` {
+			t.Errorf("stream %s received %s", name, data)
+		}
+	}
+
+	// Invalid messages are answered on the POST itself
+	resp, err := http.Post(a.endpoint, "application/json", strings.NewReader("{not json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var errResp protocol.JSONRPCError
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || resp.StatusCode != http.StatusBadRequest || errResp.Error.Code != protocol.ParseError {
+		t.Errorf("expected a parse error, got %s %+v", resp.Status, errResp)
+	}
+}
+
+func TestSSESlowClient(t *testing.T) {
+	srv := server.NewServer("sse", server.WithLogger(discardLogger))
+	tr := NewSSETransport(srv, WithLogger(discardLogger)).(*SSETransport)
+	newClient := func(id string) *sseClient {
+		c := &sseClient{
+			session: srv.NewSession(context.Background()),
+			events:  make(chan []byte, 1),
+			logger:  discardLogger,
+			metrics: tr.metrics,
+		}
+		tr.clients[id] = c
+		return c
+	}
+	slow, fast := newClient("slow"), newClient("fast")
+
+	// A client whose event stream is full misses notifications rather than
+	// holding up those of the others
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			tr.SendNotification("notifications/message", nil)
+			<-fast.events
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected notifications not to wait for the slow client")
+	}
+	if len(slow.events) != 1 {
+		t.Errorf("expected the slow client to hold one event, got %d", len(slow.events))
+	}
+	if dropped := tr.Stats().DroppedNotifications; dropped != 2 {
+		t.Errorf("expected 2 dropped notifications, got %d", dropped)
+	}
+
+	// Requests to a closed session are reported as not sent
+	slow.session.Close()
+	if err := slow.SendRequest(&protocol.JSONRPCRequest{JSONRPC: "2.0", Method: "ping"}); err == nil {
+		t.Error("expected an error sending a request to a closed session")
+	}
+}
@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// startStdio starts a stdio transport for session over pipes in place of
+// stdin and stdout, returning the client's ends
+func startStdio(t *testing.T, session *server.Session) (*StdioTransport, io.WriteCloser, *bufio.Scanner, <-chan error) {
+	t.Helper()
+
+	tr := NewStdioTransport(session, WithLogger(discardLogger)).(*StdioTransport)
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	tr.conn = newLineConn(session, serverR, serverW, tr.opts, discardLogger, tr.metrics)
+	t.Cleanup(func() { clientW.Close() })
+
+	started := make(chan error, 1)
+	go func() { started <- tr.Start() }()
+	return tr, clientW, bufio.NewScanner(clientR), started
+}
+
+func TestStdioStop(t *testing.T) {
+	release := make(chan struct{})
+	srv := server.NewServer("stdio", server.WithLogger(discardLogger))
+	srv.AddTool("wait", func(ctx context.Context) string {
+		select {
+		case <-release:
+			return "released"
+		case <-ctx.Done():
+			return "cancelled"
+		}
+	}, "Waits to be released")
+	session := srv.NewSession(context.Background())
+	tr, stdin, stdout, started := startStdio(t, session)
+
+	io.WriteString(stdin, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`+"\n")
+	stdout.Scan()
+	io.WriteString(stdin, `{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n")
+	io.WriteString(stdin, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"wait"}}`+"\n")
+
+	// Stop waits for the in-flight call, whose response is written
+	response := make(chan string, 1)
+	go func() {
+		stdout.Scan()
+		response <- stdout.Text()
+	}()
+	stopped := make(chan error, 1)
+	time.Sleep(20 * time.Millisecond)
+	go func() { stopped <- tr.Stop(context.Background()) }()
+	select {
+	case err := <-stopped:
+		t.Fatalf("Stop returned before the call completed: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if err := <-stopped; err != nil {
+		t.Errorf("Stop failed: %v", err)
+	}
+	if err := <-started; err != nil {
+		t.Errorf("Start failed: %v", err)
+	}
+	if text := <-response; !strings.Contains(text, "released") {
+		t.Errorf("expected the call's response, got %s", text)
+	}
+	select {
+	case <-session.Done():
+	default:
+		t.Error("expected the session to be closed")
+	}
+}
+
+func TestStdioStopTimeout(t *testing.T) {
+	srv := server.NewServer("stdio", server.WithLogger(discardLogger))
+	srv.AddTool("wait", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, "Waits until cancelled")
+	session := srv.NewSession(context.Background())
+	tr, stdin, stdout, started := startStdio(t, session)
+
+	io.WriteString(stdin, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`+"\n")
+	stdout.Scan()
+	go func() {
+		for stdout.Scan() {
+		}
+	}()
+	io.WriteString(stdin, `{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n")
+	io.WriteString(stdin, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"wait"}}`+"\n")
+	time.Sleep(20 * time.Millisecond)
+
+	// A call outlasting the deadline is cancelled by closing the session
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := tr.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to pass, got %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Start to return once the session closed")
+	}
+}
+
+func TestStdioStopBeforeStart(t *testing.T) {
+	session := server.NewServer("stdio").NewSession(context.Background())
+	tr := NewStdioTransport(session, WithLogger(discardLogger))
+	if err := tr.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-session.Done():
+	default:
+		t.Error("expected the session to be closed")
+	}
+}
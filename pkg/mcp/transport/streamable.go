@@ -0,0 +1,487 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// Headers used by the streamable HTTP transport
+const (
+	sessionIDHeader       = "Mcp-Session-Id"
+	protocolVersionHeader = "Mcp-Protocol-Version"
+)
+
+// StreamableHTTPTransport implements the streamable HTTP transport introduced
+// in the 2025-03-26 protocol revision. Clients POST messages to a single
+// endpoint and receive responses in the reply, and may open a GET event
+// stream for server-initiated notifications and requests. Each initialize
+// request creates a session from the session factory, identified by the
+// Mcp-Session-Id header on later requests.
+type StreamableHTTPTransport struct {
+	sessions SessionFactory
+	clients  map[string]*streamableClient
+	mu       sync.RWMutex
+	opts     Options
+	limiter  *connLimiter
+	health   *health
+	metrics  *metrics
+	srv      *http.Server
+}
+
+// streamableClient is a session and the event streams its client opened
+type streamableClient struct {
+	session      *server.Session
+	streams      map[chan []byte]struct{}
+	mu           sync.RWMutex
+	metrics      *metrics
+	tap          io.Writer
+	disconnected func()
+}
+
+// NewStreamableHTTPTransport creates a new streamable HTTP transport
+func NewStreamableHTTPTransport(sessions SessionFactory, options ...Option) HTTPTransport {
+	opts := defaultOptions()
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return &StreamableHTTPTransport{
+		sessions: sessions,
+		clients:  make(map[string]*streamableClient),
+		opts:     opts,
+		limiter:  newConnLimiter(opts),
+		health:   newHealth(sessions),
+		metrics:  newMetrics(),
+	}
+}
+
+// Start starts the streamable HTTP transport on the default address
+func (t *StreamableHTTPTransport) Start() error {
+	return t.StartHTTP(t.opts.Address)
+}
+
+// mux returns a mux serving the transport's endpoints, along with the
+// metadata, health and metrics endpoints enabled by its options
+func (t *StreamableHTTPTransport) mux() *http.ServeMux {
+	mux := http.NewServeMux()
+	t.routes(mux)
+	t.opts.handleProtectedResource(mux)
+	t.opts.handleHealth(mux, t.health)
+	t.opts.handleMetrics(mux, t, t.sessions)
+	return mux
+}
+
+// StartHTTP starts the streamable HTTP transport on the given address,
+// serving the MCP endpoint at /mcp
+func (t *StreamableHTTPTransport) StartHTTP(addr string) error {
+	t.srv = &http.Server{
+		Addr:      addr,
+		Handler:   t.mux(),
+		TLSConfig: t.opts.TLSConfig,
+	}
+
+	return t.health.listenAndServe(t.srv)
+}
+
+// routes registers the transport's MCP endpoints on mux
+func (t *StreamableHTTPTransport) routes(mux *http.ServeMux) {
+	mux.Handle("/mcp", t)
+}
+
+// Stop stops the transport
+func (t *StreamableHTTPTransport) Stop(ctx context.Context) error {
+	t.health.ready.Store(false)
+	if t.srv != nil {
+		return t.srv.Shutdown(ctx)
+	}
+	return nil
+}
+
+// ServeHTTP handles requests to the MCP endpoint, so the transport can be
+// mounted on an existing mux
+func (t *StreamableHTTPTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if version := r.Header.Get(protocolVersionHeader); version != "" && !protocol.IsSupportedProtocolVersion(version) {
+		http.Error(w, fmt.Sprintf("Unsupported protocol version: %s", version), http.StatusBadRequest)
+		return
+	}
+
+	w, finish := t.opts.compress(w, r)
+	defer finish()
+
+	switch r.Method {
+	case http.MethodPost:
+		t.handlePost(w, r)
+	case http.MethodGet:
+		if client := t.client(w, r); client != nil {
+			if t.limiter.acquire(w, r) {
+				defer t.limiter.release()
+				client.handleStream(w, r, t.opts.BufferSize)
+			}
+		}
+	case http.MethodDelete:
+		if client := t.client(w, r); client != nil {
+			t.removeClient(r.Header.Get(sessionIDHeader))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// client returns the client named by the session ID header, writing an
+// error response when the header is missing, the session is unknown or
+// the request is not authenticated as the session's principal
+func (t *StreamableHTTPTransport) client(w http.ResponseWriter, r *http.Request) *streamableClient {
+	sessionID := r.Header.Get(sessionIDHeader)
+	if sessionID == "" {
+		http.Error(w, "Missing session ID", http.StatusBadRequest)
+		return nil
+	}
+
+	t.mu.RLock()
+	client, ok := t.clients[sessionID]
+	t.mu.RUnlock()
+
+	if ok {
+		select {
+		case <-client.session.Done():
+			// Closed sessions, such as after missed keepalive pings, are gone
+			t.removeClient(sessionID)
+			ok = false
+		default:
+		}
+	}
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return nil
+	}
+	if !t.opts.authorizeSession(w, r, client.session) {
+		return nil
+	}
+	return client
+}
+
+// removeClient closes a session and forgets its ID
+func (t *StreamableHTTPTransport) removeClient(sessionID string) {
+	t.mu.Lock()
+	client, ok := t.clients[sessionID]
+	delete(t.clients, sessionID)
+	t.mu.Unlock()
+
+	if ok {
+		client.session.Close()
+		client.disconnected()
+		t.opts.Logger.Debug("session closed", "session", sessionID)
+	}
+}
+
+// handlePost processes a message posted by the client
+func (t *StreamableHTTPTransport) handlePost(w http.ResponseWriter, r *http.Request) {
+	// Parse the message
+	var msg struct {
+		JSONRPC string              `json:"jsonrpc"`
+		ID      *protocol.RequestID `json:"id,omitempty"`
+		Method  string              `json:"method"`
+		Params  json.RawMessage     `json:"params,omitempty"`
+		Result  json.RawMessage     `json:"result,omitempty"`
+		Error   *protocol.ErrorData `json:"error,omitempty"`
+	}
+	t.opts.limitBody(w, r)
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		if isTooLarge(err) {
+			t.writeError(w, nil, protocol.InvalidRequest, "Message too large", err)
+			return
+		}
+		t.writeError(w, nil, protocol.ParseError, "Parse error", err)
+		return
+	}
+	t.metrics.messagesIn.Add(1)
+
+	// Initialization creates the session, so it is the only message
+	// accepted without a session ID. Frames are tapped once their session
+	// is known, or without one when it can't be.
+	if msg.Method == "initialize" && msg.ID != nil && r.Header.Get(sessionIDHeader) == "" {
+		authInfo, ok := t.opts.authenticate(w, r)
+		if !ok {
+			tapFrame(t.opts.WireTap, wireIn, "", msg)
+			return
+		}
+		t.handleInitialize(w, r, authInfo, &protocol.JSONRPCRequest{
+			JSONRPC: msg.JSONRPC,
+			ID:      *msg.ID,
+			Method:  msg.Method,
+			Params:  msg.Params,
+		})
+		return
+	}
+
+	client := t.client(w, r)
+	if client == nil {
+		tapFrame(t.opts.WireTap, wireIn, "", msg)
+		return
+	}
+	tapFrame(t.opts.WireTap, wireIn, client.session.ID(), msg)
+
+	// Handle the message
+	if msg.ID != nil && msg.Method == "" {
+		// This is a response to a request sent by the server
+		client.session.HandleResponse(&protocol.JSONRPCResponse{
+			JSONRPC: msg.JSONRPC,
+			ID:      *msg.ID,
+			Result:  msg.Result,
+			Error:   msg.Error,
+		})
+		w.WriteHeader(http.StatusAccepted)
+	} else if msg.ID != nil {
+		// This is a request
+		req := &protocol.JSONRPCRequest{
+			JSONRPC: msg.JSONRPC,
+			ID:      *msg.ID,
+			Method:  msg.Method,
+			Params:  msg.Params,
+		}
+		t.handleJSONRPCRequest(w, r, client.session, req)
+	} else {
+		// This is a notification
+		notif := &protocol.JSONRPCNotification{
+			JSONRPC: msg.JSONRPC,
+			Method:  msg.Method,
+			Params:  msg.Params,
+		}
+		if err := client.session.HandleNotification(notif); err != nil {
+			// Log the error but don't send a response for notifications
+			t.opts.Logger.Error("failed to handle notification", "session", r.Header.Get(sessionIDHeader), "method", notif.Method, "error", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// handleInitialize creates a session for an initialize request and registers
+// it under its ID when initialization succeeds
+func (t *StreamableHTTPTransport) handleInitialize(w http.ResponseWriter, r *http.Request, authInfo *server.AuthInfo, req *protocol.JSONRPCRequest) {
+	client := &streamableClient{
+		session: t.sessions.NewSession(context.Background()),
+		streams: make(map[chan []byte]struct{}),
+		metrics: t.metrics,
+		tap:     t.opts.WireTap,
+	}
+	client.session.SetNotificationSender(client)
+	client.session.SetAuthInfo(authInfo)
+	client.session.SetConnInfo(t.opts.httpConnInfo("streamable-http", r))
+	tapFrame(t.opts.WireTap, wireIn, client.session.ID(), req)
+
+	resp, err := t.metrics.handleRequest(withHeaders(r), client.session, req)
+	if err != nil {
+		client.session.Close()
+		t.writeError(w, &req.ID, protocol.InternalError, "Internal error", err)
+		return
+	}
+
+	if resp.Error != nil {
+		client.session.Close()
+	} else {
+		client.disconnected = t.metrics.connected()
+		t.mu.Lock()
+		t.clients[client.session.ID()] = client
+		t.mu.Unlock()
+		w.Header().Set(sessionIDHeader, client.session.ID())
+		t.opts.Logger.Debug("session created", "session", client.session.ID())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if json.NewEncoder(w).Encode(resp) == nil {
+		t.metrics.messagesOut.Add(1)
+		tapFrame(t.opts.WireTap, wireOut, client.session.ID(), resp)
+	}
+}
+
+// handleJSONRPCRequest processes a JSON-RPC request and writes the response
+func (t *StreamableHTTPTransport) handleJSONRPCRequest(w http.ResponseWriter, r *http.Request, session *server.Session, req *protocol.JSONRPCRequest) {
+	resp, err := t.metrics.handleRequest(withHeaders(r), session, req)
+	if errors.Is(err, server.ErrRequestCancelled) {
+		// Cancelled requests receive no response
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if err != nil {
+		t.writeError(w, &req.ID, protocol.InternalError, "Internal error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if json.NewEncoder(w).Encode(resp) == nil {
+		t.metrics.messagesOut.Add(1)
+		tapFrame(t.opts.WireTap, wireOut, session.ID(), resp)
+	}
+}
+
+// handleStream serves an event stream for server-initiated messages
+func (c *streamableClient) handleStream(w http.ResponseWriter, r *http.Request, bufferSize int) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Register the stream
+	stream := make(chan []byte, bufferSize)
+	c.mu.Lock()
+	c.streams[stream] = struct{}{}
+	c.mu.Unlock()
+
+	// Clean up when the connection is closed
+	defer func() {
+		c.mu.Lock()
+		delete(c.streams, stream)
+		c.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-c.session.Done():
+			return
+		case msg := <-stream:
+			writeEvent(w, msg)
+			flusher.Flush()
+			c.metrics.messagesOut.Add(1)
+			tapFrame(c.tap, wireOut, c.session.ID(), msg)
+		}
+	}
+}
+
+// writeError writes a JSON-RPC error response
+func (t *StreamableHTTPTransport) writeError(w http.ResponseWriter, id *protocol.RequestID, code int, message string, err error) {
+	errResp := &protocol.JSONRPCError{
+		JSONRPC: "2.0",
+		Error: protocol.ErrorData{
+			Code:    code,
+			Message: message,
+			Data:    err.Error(),
+		},
+	}
+
+	if id != nil {
+		errResp.ID = *id
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(errResp)
+	tapFrame(t.opts.WireTap, wireOut, "", errResp)
+}
+
+// SendNotification sends a notification to the event streams of all sessions
+func (t *StreamableHTTPTransport) SendNotification(method string, params interface{}) error {
+	// Marshal once; streams only read the queued events
+	data, err := marshalNotification(method, params)
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, client := range t.clients {
+		client.notify(data)
+	}
+	return nil
+}
+
+// SendNotification sends a notification to the session's event streams
+func (c *streamableClient) SendNotification(method string, params interface{}) error {
+	data, err := marshalNotification(method, params)
+	if err != nil {
+		return err
+	}
+	c.notify(data)
+	return nil
+}
+
+// notify queues an encoded notification on the session's event streams,
+// counting it as dropped if no stream has room
+func (c *streamableClient) notify(data []byte) {
+	if c.broadcast(data) == 0 {
+		c.metrics.dropped.Add(1)
+	}
+}
+
+// SendRequest sends a server-initiated request on one of the session's
+// event streams. Clients post their response to the MCP endpoint. It
+// returns server.ErrRequestNotDelivered when no stream has room for it.
+func (c *streamableClient) SendRequest(req *protocol.JSONRPCRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	if !c.deliver(data) {
+		return server.ErrRequestNotDelivered
+	}
+	return nil
+}
+
+// deliver queues an event for the first open event stream of the session
+// with room for it, reporting whether there was one
+func (c *streamableClient) deliver(data []byte) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for stream := range c.streams {
+		select {
+		case stream <- data:
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+// broadcast queues an event for every open event stream of the session,
+// returning the number of streams it was queued for
+func (c *streamableClient) broadcast(data []byte) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	queued := 0
+	for stream := range c.streams {
+		select {
+		case stream <- data:
+			queued++
+		default:
+			// Skip streams that aren't ready to receive
+		}
+	}
+	return queued
+}
+
+// ConnectionStats reports the transport's open event streams
+func (t *StreamableHTTPTransport) ConnectionStats() ConnectionStats {
+	return t.limiter.stats()
+}
+
+// Stats returns a snapshot of the transport's metrics, counting sessions as
+// connections
+func (t *StreamableHTTPTransport) Stats() Stats {
+	return t.metrics.stats()
+}
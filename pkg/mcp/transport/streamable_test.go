@@ -0,0 +1,78 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+func TestStreamableServerRequests(t *testing.T) {
+	srv := server.NewServer("streamable", server.WithLogger(discardLogger), server.WithKeepAlive(5*time.Millisecond, 20*time.Millisecond))
+	tr := NewStreamableHTTPTransport(srv, WithLogger(discardLogger)).(*StreamableHTTPTransport)
+	ts := httptest.NewServer(tr)
+	defer ts.Close()
+
+	// Initialize without opening an event stream
+	resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	tr.mu.RLock()
+	streamless := tr.clients[resp.Header.Get(sessionIDHeader)]
+	tr.mu.RUnlock()
+	if streamless == nil {
+		t.Fatalf("expected a session, got status %d", resp.StatusCode)
+	}
+
+	// Requests fail fast rather than waiting for an answer that cannot come
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := streamless.session.Ping(ctx); !errors.Is(err, server.ErrRequestNotDelivered) {
+		t.Errorf("expected ErrRequestNotDelivered without an event stream, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the ping to fail fast, took %v", elapsed)
+	}
+
+	// Keepalive leaves the session open
+	select {
+	case <-streamless.session.Done():
+		t.Error("expected keepalive to keep a session without an event stream")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Clients with an event stream receive requests and answer them
+	c, err := client.Connect(ctx, client.NewStreamableHTTPConn(ts.URL, nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	var streamed *streamableClient
+	if !eventually(t, func() bool {
+		tr.mu.RLock()
+		defer tr.mu.RUnlock()
+		for _, client := range tr.clients {
+			client.mu.RLock()
+			open := len(client.streams) > 0
+			client.mu.RUnlock()
+			if open {
+				streamed = client
+			}
+		}
+		return streamed != nil
+	}) {
+		t.Fatal("expected the client to open an event stream")
+	}
+	if err := streamed.session.Ping(ctx); err != nil {
+		t.Errorf("expected the client to answer the ping, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// TCPTransport serves MCP over raw TCP connections using the same
+// newline-delimited JSON framing as the stdio transport. Each connection
+// gets its own session from the session factory.
+type TCPTransport struct {
+	sessions SessionFactory
+	listener net.Listener
+	conns    map[*lineConn]net.Conn
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	opts     Options
+	metrics  *metrics
+
+	// done is closed by Stop
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTCPTransport creates a new TCP transport that creates a session per
+// connection
+func NewTCPTransport(sessions SessionFactory, options ...Option) Transport {
+	opts := defaultOptions()
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return &TCPTransport{
+		sessions: sessions,
+		conns:    make(map[*lineConn]net.Conn),
+		opts:     opts,
+		metrics:  newMetrics(),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start listens on the configured address and serves connections until
+// Stop is called
+func (t *TCPTransport) Start() error {
+	listener, err := net.Listen("tcp", t.opts.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	if t.opts.TLSConfig != nil {
+		listener = tls.NewListener(listener, t.opts.TLSConfig)
+	}
+	return t.Serve(listener)
+}
+
+// Serve accepts connections on the listener, such as a TLS listener, until
+// Stop is called
+func (t *TCPTransport) Serve(listener net.Listener) error {
+	t.mu.Lock()
+	select {
+	case <-t.done:
+		// Stopped before serving
+		t.mu.Unlock()
+		listener.Close()
+		return nil
+	default:
+	}
+	t.listener = listener
+	t.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-t.done:
+				return nil
+			default:
+				return fmt.Errorf("failed to accept connection: %w", err)
+			}
+		}
+
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			t.handleConn(conn)
+		}()
+	}
+}
+
+// handleConn serves a session over the connection until either ends
+func (t *TCPTransport) handleConn(conn net.Conn) {
+	logger := t.opts.Logger.With("remote", conn.RemoteAddr().String())
+	info := &server.ConnInfo{
+		Transport:  "tcp",
+		RemoteAddr: conn.RemoteAddr().String(),
+	}
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		// Complete the handshake so the session sees the TLS state
+		if err := tlsConn.Handshake(); err != nil {
+			logger.Error("TLS handshake failed", "error", err)
+			conn.Close()
+			return
+		}
+		state := tlsConn.ConnectionState()
+		info.TLS = &state
+	}
+
+	// The session lives as long as the connection
+	session := t.sessions.NewSession(context.Background())
+	session.SetConnInfo(info)
+	c := newLineConn(session, conn, conn, t.opts, logger, t.metrics).withWriteDeadline(conn, t.opts.WriteTimeout)
+
+	t.mu.Lock()
+	t.conns[c] = conn
+	t.mu.Unlock()
+	c.logger.Debug("client connected")
+	defer t.metrics.connected()()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.conns, c)
+		t.mu.Unlock()
+
+		session.Close()
+		c.flush()
+		conn.Close()
+		c.logger.Debug("client disconnected")
+	}()
+
+	if err := c.serve(t.done); err != nil && !errors.Is(err, net.ErrClosed) {
+		c.logger.Error("failed to serve connection", "error", err)
+	}
+}
+
+// Stop stops accepting connections and waits for in-flight requests to
+// complete and their responses to be written. If ctx ends first, the
+// remaining connections are closed and ctx's error is returned.
+func (t *TCPTransport) Stop(ctx context.Context) error {
+	t.stopOnce.Do(func() { close(t.done) })
+
+	t.mu.Lock()
+	if t.listener != nil {
+		t.listener.Close()
+	}
+	t.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		for c, conn := range t.conns {
+			c.session.Close()
+			conn.Close()
+		}
+		t.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// connections returns the open connections
+func (t *TCPTransport) connections() []*lineConn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conns := make([]*lineConn, 0, len(t.conns))
+	for c := range t.conns {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// SendNotification sends a notification to all connected clients. Writes
+// happen outside the transport's lock, each bounded by the write timeout,
+// so a client that stops reading delays neither other clients nor
+// connections coming and going.
+func (t *TCPTransport) SendNotification(method string, params interface{}) error {
+	var lastErr error
+	for _, c := range t.connections() {
+		if err := c.SendNotification(method, params); err != nil {
+			lastErr = err
+			c.logger.Error("failed to send notification", "method", method, "error", err)
+		}
+	}
+
+	return lastErr
+}
+
+// Stats returns a snapshot of the transport's metrics
+func (t *TCPTransport) Stats() Stats {
+	return t.metrics.stats()
+}
+
+// TCPClient is a client connection to a TCP transport, exchanging
+// newline-delimited JSON-RPC messages
+type TCPClient struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// DialTCP connects to a TCP transport listening on addr
+func DialTCP(ctx context.Context, addr string) (*TCPClient, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+
+	return &TCPClient{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}, nil
+}
+
+// Send writes a message, such as a *protocol.JSONRPCRequest, to the server
+func (c *TCPClient) Send(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if _, err := c.conn.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// Receive reads the next message from the server, which may be a response,
+// a notification or a server-initiated request. It is not safe to call
+// concurrently.
+func (c *TCPClient) Receive() (json.RawMessage, error) {
+	data, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+	return json.RawMessage(data), nil
+}
+
+// Close closes the connection
+func (c *TCPClient) Close() error {
+	return c.conn.Close()
+}
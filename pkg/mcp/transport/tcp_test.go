@@ -0,0 +1,159 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// serveTCP serves a TCP transport for srv on a local port
+func serveTCP(t *testing.T, srv *server.Server, options ...Option) (*TCPTransport, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := NewTCPTransport(srv, append([]Option{WithLogger(discardLogger)}, options...)...).(*TCPTransport)
+	go tr.Serve(listener)
+	t.Cleanup(func() { tr.Stop(context.Background()) })
+	return tr, listener.Addr().String()
+}
+
+// receive reads the next message of c into v
+func receive(t *testing.T, c *TCPClient, v interface{}) {
+	t.Helper()
+	data, err := c.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to decode %s: %v", data, err)
+	}
+}
+
+func TestTCPTransport(t *testing.T) {
+	srv := server.NewServer("tcp", server.WithLogger(discardLogger))
+	srv.AddTool("remote", func(ctx context.Context) string {
+		info := server.SessionFromContext(ctx).ConnInfo()
+		return info.Transport + " " + info.RemoteAddr
+	}, "Returns the connection info")
+	tr, addr := serveTCP(t, srv)
+
+	c, err := DialTCP(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// Messages make the round trip as JSON lines
+	c.Send(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(1),
+		Method:  "initialize",
+		Params:  map[string]interface{}{"protocolVersion": "2025-03-26", "capabilities": map[string]interface{}{}, "clientInfo": map[string]interface{}{"name": "test", "version": "1"}},
+	})
+	var initialized protocol.JSONRPCResponse
+	receive(t, c, &initialized)
+	c.Send(&protocol.JSONRPCNotification{JSONRPC: "2.0", Method: "notifications/initialized"})
+
+	c.Send(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(2), Method: "tools/call", Params: map[string]interface{}{"name": "remote"}})
+	var resp struct {
+		Result protocol.CallToolResult `json:"result"`
+	}
+	receive(t, c, &resp)
+	if len(resp.Result.Content) != 1 || resp.Result.Content[0].(protocol.TextContent).Text != "tcp "+c.conn.LocalAddr().String() {
+		t.Errorf("unexpected result %+v", resp.Result)
+	}
+
+	// Notifications sent through the transport reach every connection
+	if err := tr.SendNotification("notifications/message", map[string]interface{}{"level": "info", "data": "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	var notif protocol.JSONRPCNotification
+	receive(t, c, &notif)
+	if notif.Method != "notifications/message" {
+		t.Errorf("unexpected notification %+v", notif)
+	}
+
+	// Stopping closes the connection
+	if err := tr.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Receive(); err == nil || !strings.Contains(err.Error(), "failed to read message") {
+		t.Errorf("expected the connection to be closed, got %v", err)
+	}
+}
+
+func TestTCPSlowClient(t *testing.T) {
+	srv := server.NewServer("tcp", server.WithLogger(discardLogger))
+	tr, addr := serveTCP(t, srv, WithWriteTimeout(50*time.Millisecond))
+
+	// One client reads everything, the other nothing
+	fast, err := DialTCP(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fast.Close()
+	received := make(chan struct{}, 1)
+	go func() {
+		for {
+			if _, err := fast.Receive(); err != nil {
+				return
+			}
+			select {
+			case received <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	slow, err := DialTCP(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slow.Close()
+	if !eventually(t, func() bool { return len(tr.connections()) == 2 }) {
+		t.Fatal("clients did not connect")
+	}
+
+	// Once the slow client's buffers fill, its writes time out instead of
+	// blocking the broadcast
+	params := map[string]interface{}{"level": "info", "data": strings.Repeat("x", 64<<10)}
+	var sendErr error
+	for i := 0; i < 1000 && sendErr == nil; i++ {
+		start := time.Now()
+		sendErr = tr.SendNotification("notifications/message", params)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("expected writes to time out, a notification took %v", elapsed)
+		}
+	}
+	if sendErr == nil {
+		t.Fatal("expected a notification to the slow client to fail")
+	}
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Error("expected the fast client to receive notifications")
+	}
+
+	// New connections are still served
+	c, err := DialTCP(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	c.Send(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(1), Method: "ping"})
+	var resp protocol.JSONRPCResponse
+	receive(t, c, &resp)
+	if resp.ID != protocol.NewIntID(1) {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
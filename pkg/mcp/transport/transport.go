@@ -1,6 +1,21 @@
 package transport
 
-import "context"
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// ErrMessageTooLarge is returned when a message exceeds the transport's
+// maximum message size
+var ErrMessageTooLarge = errors.New("message too large")
 
 // Transport defines the interface that all MCP transports must implement
 type Transport interface {
@@ -9,6 +24,13 @@ type Transport interface {
 
 	// SendNotification sends a notification to connected clients
 	SendNotification(method string, params interface{}) error
+
+	// Stop gracefully stops the transport, waiting for in-flight requests
+	// until ctx ends
+	Stop(ctx context.Context) error
+
+	// Stats returns a snapshot of the transport's metrics
+	Stats() Stats
 }
 
 // HTTPTransport extends Transport for HTTP-based transports
@@ -18,8 +40,15 @@ type HTTPTransport interface {
 	// StartHTTP starts the transport on the given address
 	StartHTTP(addr string) error
 
-	// Stop gracefully stops the transport
-	Stop(ctx context.Context) error
+	// ConnectionStats reports the transport's long-lived connections
+	ConnectionStats() ConnectionStats
+}
+
+// SessionFactory creates the session serving a client connection. Transports
+// that accept many clients create a session per connection so that each
+// client is initialized independently. *server.Server implements it.
+type SessionFactory interface {
+	NewSession(ctx context.Context) *server.Session
 }
 
 // Options represents configuration options for transports
@@ -30,6 +59,72 @@ type Options struct {
 	// BufferSize is the size of notification channels
 	BufferSize int
 
+	// MaxMessageSize is the largest message, in bytes, accepted from a
+	// client, or zero for no limit. Larger messages are answered with an
+	// invalid request error.
+	MaxMessageSize int64
+
+	// Authenticator, when set, verifies the credentials of every request
+	// to an HTTP transport
+	Authenticator Authenticator
+
+	// ProtectedResource, when set, is served as OAuth protected resource
+	// metadata and referenced from authentication challenges
+	ProtectedResource *ProtectedResourceMetadata
+
+	// MaxConnections caps the simultaneous long-lived connections of HTTP
+	// transports, or zero for no limit
+	MaxConnections int
+
+	// ConnectionLimitPolicy decides what happens to connections beyond
+	// MaxConnections
+	ConnectionLimitPolicy ConnectionLimitPolicy
+
+	// ForwardedHeaders names the headers of the HTTP request opening a
+	// session that are kept in the session's ConnInfo
+	ForwardedHeaders []string
+
+	// Logger receives connection events and errors. Transports never write
+	// diagnostics to the protocol stream.
+	Logger *slog.Logger
+
+	// Compression enables permessage-deflate for WebSocket connections and
+	// gzip for HTTP responses
+	Compression bool
+
+	// PingInterval is how often the WebSocket transport pings clients,
+	// or zero to disable pings
+	PingInterval time.Duration
+
+	// PongWait is how long the WebSocket transport waits for a pong or
+	// message before closing an unresponsive connection, or zero to wait
+	// indefinitely
+	PongWait time.Duration
+
+	// WriteTimeout bounds each WebSocket and TCP write, or zero for no
+	// deadline
+	WriteTimeout time.Duration
+
+	// HealthChecks enables the /healthz and /readyz endpoints of HTTP
+	// transports
+	HealthChecks bool
+
+	// ResponseOrder is the order in which responses to concurrently
+	// handled requests are sent
+	ResponseOrder ResponseOrder
+
+	// TLSConfig, when set, makes network transports listening on their
+	// address serve TLS
+	TLSConfig *tls.Config
+
+	// MetricsPrefix, when set, makes HTTP transports serve their metrics
+	// and those of their server at /metrics, with metric names starting
+	// with it
+	MetricsPrefix string
+
+	// WireTap, when set, receives a copy of every frame received or sent
+	WireTap io.Writer
+
 	// Additional options can be added here
 }
 
@@ -50,10 +145,125 @@ func WithBufferSize(size int) Option {
 	}
 }
 
+// WithForwardedHeaders keeps the named headers of the HTTP request that
+// opens a session, such as X-Tenant-ID, in the session's ConnInfo
+func WithForwardedHeaders(names ...string) Option {
+	return func(o *Options) {
+		o.ForwardedHeaders = append(o.ForwardedHeaders, names...)
+	}
+}
+
+// WithLogger sets the logger for connection events and errors, which
+// defaults to a text logger on stderr. A nil logger discards them.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		if logger == nil {
+			logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+		}
+		o.Logger = logger
+	}
+}
+
+// WithMaxMessageSize sets the largest message accepted from clients, such as
+// a stdio or TCP line, WebSocket message or HTTP request body
+func WithMaxMessageSize(size int64) Option {
+	return func(o *Options) {
+		o.MaxMessageSize = size
+	}
+}
+
+// WithPingInterval sets how often the WebSocket transport pings clients
+func WithPingInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.PingInterval = interval
+	}
+}
+
+// WithPongWait sets how long the WebSocket transport waits to hear from a
+// client before closing its connection. It should exceed the ping interval.
+func WithPongWait(wait time.Duration) Option {
+	return func(o *Options) {
+		o.PongWait = wait
+	}
+}
+
+// WithTLSConfig serves TLS on the transports listening on their address,
+// such as HTTP and TCP transports started with Start
+func WithTLSConfig(config *tls.Config) Option {
+	return func(o *Options) {
+		o.TLSConfig = config
+	}
+}
+
+// WithWriteTimeout sets the deadline for each WebSocket and TCP write
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.WriteTimeout = timeout
+	}
+}
+
 // defaultOptions returns the default transport options
 func defaultOptions() Options {
 	return Options{
-		Address:    ":8080",
-		BufferSize: 100,
+		Address:        ":8080",
+		BufferSize:     100,
+		MaxMessageSize: 4 << 20,
+		PingInterval:   54 * time.Second,
+		PongWait:       60 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		Logger:         slog.New(slog.NewTextHandler(os.Stderr, nil)),
+	}
+}
+
+// readLimited reads a message of at most max bytes, or any size when max is
+// zero. Larger messages are discarded and ErrMessageTooLarge is returned.
+func readLimited(r io.Reader, max int64) ([]byte, error) {
+	if max <= 0 {
+		return io.ReadAll(r)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			return nil, err
+		}
+		return nil, ErrMessageTooLarge
+	}
+	return data, nil
+}
+
+// limitBody caps the size of an HTTP request body at the maximum message
+// size
+func (o Options) limitBody(w http.ResponseWriter, r *http.Request) {
+	if o.MaxMessageSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, o.MaxMessageSize)
+	}
+}
+
+// isTooLarge reports whether err came from reading a message over the
+// maximum message size
+func isTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.Is(err, ErrMessageTooLarge) || errors.As(err, &maxBytesErr)
+}
+
+// httpConnInfo describes the connection of an HTTP request opening a session
+func (o Options) httpConnInfo(transport string, r *http.Request) *server.ConnInfo {
+	info := &server.ConnInfo{
+		Transport:  transport,
+		RemoteAddr: r.RemoteAddr,
+		TLS:        r.TLS,
+	}
+	for _, name := range o.ForwardedHeaders {
+		if values := r.Header.Values(name); len(values) > 0 {
+			if info.Header == nil {
+				info.Header = make(http.Header)
+			}
+			info.Header[http.CanonicalHeaderKey(name)] = values
+		}
 	}
+	return info
 }
@@ -0,0 +1,328 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// recordingFactory creates sessions of a server, recording them
+type recordingFactory struct {
+	srv      *server.Server
+	mu       sync.Mutex
+	sessions []*server.Session
+}
+
+func (f *recordingFactory) NewSession(ctx context.Context) *server.Session {
+	session := f.srv.NewSession(ctx)
+	f.mu.Lock()
+	f.sessions = append(f.sessions, session)
+	f.mu.Unlock()
+	return session
+}
+
+// created returns the sessions created so far
+func (f *recordingFactory) created() []*server.Session {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*server.Session(nil), f.sessions...)
+}
+
+func TestSessionPerConnection(t *testing.T) {
+	tests := []struct {
+		name string
+		// serve serves a transport for sessions and returns a function
+		// dialing it
+		serve func(t *testing.T, sessions SessionFactory) func() client.Conn
+	}{
+		{"tcp", func(t *testing.T, sessions SessionFactory) func() client.Conn {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tr := NewTCPTransport(sessions, WithLogger(discardLogger)).(*TCPTransport)
+			go tr.Serve(listener)
+			t.Cleanup(func() { tr.Stop(context.Background()) })
+			return func() client.Conn {
+				conn, err := client.DialTCP(context.Background(), listener.Addr().String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				return conn
+			}
+		}},
+		{"websocket", func(t *testing.T, sessions SessionFactory) func() client.Conn {
+			tr := NewWebSocketTransport(sessions, WithLogger(discardLogger)).(*WebSocketTransport)
+			ts := httptest.NewServer(tr.mux())
+			t.Cleanup(ts.Close)
+			return func() client.Conn {
+				conn, err := client.DialWebSocket(context.Background(), "ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return conn
+			}
+		}},
+		{"sse", func(t *testing.T, sessions SessionFactory) func() client.Conn {
+			_, ts := serveSSE(t, sessions)
+			return func() client.Conn { return dialSSE(t, ts.URL) }
+		}},
+		{"streamable", func(t *testing.T, sessions SessionFactory) func() client.Conn {
+			ts := httptest.NewServer(NewStreamableHTTPTransport(sessions, WithLogger(discardLogger)).(*StreamableHTTPTransport))
+			t.Cleanup(ts.Close)
+			return func() client.Conn { return client.NewStreamableHTTPConn(ts.URL, nil, nil) }
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := &recordingFactory{srv: server.NewServer("sessions", server.WithLogger(discardLogger))}
+			dial := tt.serve(t, factory)
+			ctx := context.Background()
+
+			var clients []*client.Client
+			for range 2 {
+				c, err := client.Connect(ctx, dial())
+				if err != nil {
+					t.Fatalf("failed to connect: %v", err)
+				}
+				defer c.Close()
+				clients = append(clients, c)
+			}
+			sessions := factory.created()
+			if len(sessions) != 2 || sessions[0] == sessions[1] {
+				t.Fatalf("expected a session per connection, got %d", len(sessions))
+			}
+
+			// Disconnecting closes the connection's session only
+			clients[0].Close()
+			select {
+			case <-sessions[0].Done():
+			case <-time.After(2 * time.Second):
+				t.Fatal("expected the session to be closed on disconnect")
+			}
+			select {
+			case <-sessions[1].Done():
+				t.Error("expected the other session to stay open")
+			default:
+			}
+			if err := clients[1].Ping(ctx); err != nil {
+				t.Errorf("expected the other connection to keep working: %v", err)
+			}
+		})
+	}
+}
+
+// expectTooLarge checks that data is the error response to an oversized
+// message
+func expectTooLarge(t *testing.T, data []byte) {
+	t.Helper()
+	var resp protocol.JSONRPCError
+	if err := json.Unmarshal(data, &resp); err != nil || resp.Error.Code != protocol.InvalidRequest || resp.Error.Message != "Message too large" {
+		t.Errorf("expected a message too large error, got %s", data)
+	}
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	const max = 1024
+	large := []byte(`{"jsonrpc":"2.0","id":1,"method":"ping","params":{"pad":"` + strings.Repeat("x", 2*max) + `"}}`)
+	ping := []byte(`{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	srv := server.NewServer("limits", server.WithLogger(discardLogger))
+
+	// Oversized messages on connections are answered with an error, and
+	// the connection carries on
+	connTests := []struct {
+		name string
+		dial func(t *testing.T) client.Conn
+	}{
+		{"line", func(t *testing.T) client.Conn {
+			clientR, serverW := io.Pipe()
+			serverR, clientW := io.Pipe()
+			c := newLineConn(srv.NewSession(context.Background()), serverR, serverW, Options{MaxMessageSize: max}, discardLogger, newMetrics())
+			done := make(chan struct{})
+			go c.serve(done)
+			t.Cleanup(func() {
+				close(done)
+				clientW.Close()
+			})
+			return client.NewStreamConn(struct {
+				io.Reader
+				io.Writer
+				io.Closer
+			}{clientR, clientW, clientW})
+		}},
+		{"websocket", func(t *testing.T) client.Conn {
+			tr := NewWebSocketTransport(srv, WithLogger(discardLogger), WithMaxMessageSize(max)).(*WebSocketTransport)
+			ts := httptest.NewServer(tr.mux())
+			t.Cleanup(ts.Close)
+			conn, err := client.DialWebSocket(context.Background(), "ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return conn
+		}},
+		{"nats", func(t *testing.T) client.Conn {
+			_, bus := startNATS(t, srv, WithMaxMessageSize(max))
+			return newNATSClientConn(bus, "mcp", "limits")
+		}},
+	}
+	for _, tt := range connTests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := tt.dial(t)
+			defer conn.Close()
+			ctx := context.Background()
+
+			for _, msg := range [][]byte{large, ping} {
+				if err := conn.Write(ctx, msg); err != nil {
+					t.Fatal(err)
+				}
+			}
+			data, err := conn.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectTooLarge(t, data)
+			if data, err = conn.Read(); err != nil || !strings.Contains(string(data), `"id":2,"result"`) {
+				t.Errorf("expected the ping to be answered, got %s (%v)", data, err)
+			}
+		})
+	}
+
+	// Oversized HTTP request bodies are answered with a 400
+	httpTests := []struct {
+		name string
+		url  func(t *testing.T) string
+	}{
+		{"sse", func(t *testing.T) string {
+			_, ts := serveSSE(t, srv, WithMaxMessageSize(max))
+			return dialSSE(t, ts.URL).endpoint
+		}},
+		{"streamable", func(t *testing.T) string {
+			ts := httptest.NewServer(NewStreamableHTTPTransport(srv, WithLogger(discardLogger), WithMaxMessageSize(max)).(*StreamableHTTPTransport))
+			t.Cleanup(ts.Close)
+			return ts.URL
+		}},
+	}
+	for _, tt := range httpTests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, tt.url(t), bytes.NewReader(large))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", "application/json, text/event-stream")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			data, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("got status %s", resp.Status)
+			}
+			expectTooLarge(t, data)
+		})
+	}
+}
+
+// logRecorder is a slog handler's output, safe for concurrent use
+type logRecorder struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (r *logRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buf.Write(p)
+}
+
+// records returns the logged records
+func (r *logRecorder) records() []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(r.buf.String()), "\n") {
+		var record map[string]interface{}
+		if json.Unmarshal([]byte(line), &record) == nil {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// find returns the first record with the message
+func (r *logRecorder) find(msg string) map[string]interface{} {
+	for _, record := range r.records() {
+		if record["msg"] == msg {
+			return record
+		}
+	}
+	return nil
+}
+
+func TestLogging(t *testing.T) {
+	srv := server.NewServer("logging", server.WithLogger(discardLogger))
+	tests := []struct {
+		name string
+		dial func(t *testing.T, logger *slog.Logger) client.Conn
+	}{
+		{"tcp", func(t *testing.T, logger *slog.Logger) client.Conn {
+			_, addr := serveTCP(t, srv, WithLogger(logger))
+			conn, err := client.DialTCP(context.Background(), addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return conn
+		}},
+		{"websocket", func(t *testing.T, logger *slog.Logger) client.Conn {
+			_, url := serveWebSocket(t, srv, WithLogger(logger))
+			conn, err := client.DialWebSocket(context.Background(), url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return conn
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &logRecorder{}
+			logger := slog.New(slog.NewJSONHandler(recorder, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			conn := tt.dial(t, logger)
+
+			// Errors are logged with the connection's attributes rather than
+			// written to the protocol stream
+			conn.Write(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/unknown"}`))
+			conn.Write(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+			if data, err := conn.Read(); err != nil || !strings.Contains(string(data), `"id":1,"result"`) {
+				t.Errorf("expected only the ping response, got %s (%v)", data, err)
+			}
+			conn.Close()
+
+			if !eventually(t, func() bool { return recorder.find("client disconnected") != nil }) {
+				t.Fatalf("expected the disconnect to be logged, got %v", recorder.records())
+			}
+			connected := recorder.find("client connected")
+			if connected == nil || connected["level"] != "DEBUG" || connected["remote"] == nil {
+				t.Errorf("expected a debug record of the connection, got %v", connected)
+			}
+			failed := recorder.find("failed to handle notification")
+			if failed == nil || failed["level"] != "ERROR" || failed["method"] != "notifications/unknown" || failed["remote"] != connected["remote"] {
+				t.Errorf("expected an error record of the notification, got %v", failed)
+			}
+		})
+	}
+}
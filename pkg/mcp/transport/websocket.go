@@ -3,41 +3,66 @@ package transport
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
 	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
 	"github.com/gorilla/websocket"
 )
 
-// WebSocketTransport implements a WebSocket-based transport for MCP
+// WebSocketTransport implements a WebSocket-based transport for MCP. Each
+// connection gets its own session from the session factory.
 type WebSocketTransport struct {
-	session  *server.Session
+	sessions SessionFactory
 	upgrader websocket.Upgrader
-	clients  map[string]*websocket.Conn
+	clients  map[*wsClient]struct{}
 	mu       sync.RWMutex
 	opts     Options
+	limiter  *connLimiter
+	health   *health
+	metrics  *metrics
 	srv      *http.Server
 }
 
-// NewWebSocketTransport creates a new WebSocket transport
-func NewWebSocketTransport(session *server.Session, options ...Option) HTTPTransport {
+// wsClient is a WebSocket connection and the session it serves
+type wsClient struct {
+	conn         *websocket.Conn
+	session      *server.Session
+	writeMu      sync.Mutex
+	writeTimeout time.Duration
+	logger       *slog.Logger
+	metrics      *metrics
+	order        *sequencer
+	tap          io.Writer
+}
+
+// NewWebSocketTransport creates a new WebSocket transport that creates a
+// session per connection
+func NewWebSocketTransport(sessions SessionFactory, options ...Option) HTTPTransport {
 	opts := defaultOptions()
 	for _, opt := range options {
 		opt(&opts)
 	}
 
 	return &WebSocketTransport{
-		session: session,
+		sessions: sessions,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true // Allow all origins in this example
 			},
+			EnableCompression: opts.Compression,
 		},
-		clients: make(map[string]*websocket.Conn),
+		clients: make(map[*wsClient]struct{}),
 		opts:    opts,
+		limiter: newConnLimiter(opts),
+		health:  newHealth(sessions),
+		metrics: newMetrics(),
 	}
 }
 
@@ -46,26 +71,42 @@ func (t *WebSocketTransport) Start() error {
 	return t.StartHTTP(t.opts.Address)
 }
 
-// StartHTTP starts the WebSocket transport on the given address
-func (t *WebSocketTransport) StartHTTP(addr string) error {
+// mux returns a mux serving the transport's endpoints, along with the
+// metadata, health and metrics endpoints enabled by its options
+func (t *WebSocketTransport) mux() *http.ServeMux {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/ws", t.handleWebSocket)
+	t.routes(mux)
+	t.opts.handleProtectedResource(mux)
+	t.opts.handleHealth(mux, t.health)
+	t.opts.handleMetrics(mux, t, t.sessions)
+	return mux
+}
 
+// StartHTTP starts the WebSocket transport on the given address
+func (t *WebSocketTransport) StartHTTP(addr string) error {
 	t.srv = &http.Server{
-		Addr:    addr,
-		Handler: mux,
+		Addr:      addr,
+		Handler:   t.mux(),
+		TLSConfig: t.opts.TLSConfig,
 	}
 
-	return t.srv.ListenAndServe()
+	return t.health.listenAndServe(t.srv)
+}
+
+// routes registers the transport's MCP endpoints on mux
+func (t *WebSocketTransport) routes(mux *http.ServeMux) {
+	mux.HandleFunc("/ws", t.handleWebSocket)
 }
 
 // Stop stops the transport
 func (t *WebSocketTransport) Stop(ctx context.Context) error {
+	t.health.ready.Store(false)
+
 	t.mu.Lock()
-	for _, conn := range t.clients {
-		conn.Close()
+	for client := range t.clients {
+		client.conn.Close()
 	}
-	t.clients = make(map[string]*websocket.Conn)
+	t.clients = make(map[*wsClient]struct{})
 	t.mu.Unlock()
 
 	if t.srv != nil {
@@ -76,30 +117,88 @@ func (t *WebSocketTransport) Stop(ctx context.Context) error {
 
 // handleWebSocket handles WebSocket connections
 func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	authInfo, ok := t.opts.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	if !t.limiter.acquire(w, r) {
+		return
+	}
+	defer t.limiter.release()
+
 	conn, err := t.upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		fmt.Printf("Failed to upgrade connection: %v\n", err)
+		t.opts.Logger.Error("failed to upgrade connection", "remote", r.RemoteAddr, "error", err)
 		return
 	}
 
-	clientID := r.RemoteAddr
+	// The session lives as long as the connection
+	client := &wsClient{
+		conn:         conn,
+		session:      t.sessions.NewSession(context.Background()),
+		writeTimeout: t.opts.WriteTimeout,
+		logger:       t.opts.Logger.With("remote", r.RemoteAddr),
+		metrics:      t.metrics,
+		tap:          t.opts.WireTap,
+		order:        newSequencer(t.opts.ResponseOrder),
+	}
+	client.session.SetNotificationSender(client)
+	client.session.SetAuthInfo(authInfo)
+	client.session.SetConnInfo(t.opts.httpConnInfo("websocket", r))
+	ctx := withHeaders(r)
+
 	t.mu.Lock()
-	t.clients[clientID] = conn
+	t.clients[client] = struct{}{}
 	t.mu.Unlock()
+	client.logger.Debug("client connected")
+	defer t.metrics.connected()()
 
 	defer func() {
 		conn.Close()
+		client.session.Close()
 		t.mu.Lock()
-		delete(t.clients, clientID)
+		delete(t.clients, client)
 		t.mu.Unlock()
+		client.logger.Debug("client disconnected")
 	}()
 
+	// Unresponsive clients are detected by read deadlines, which pongs and
+	// messages extend
+	extendDeadline := func() error {
+		if t.opts.PongWait <= 0 {
+			return nil
+		}
+		return conn.SetReadDeadline(time.Now().Add(t.opts.PongWait))
+	}
+	extendDeadline()
+	conn.SetPongHandler(func(string) error { return extendDeadline() })
+
+	// Ping the client, and drop the connection when the session closes,
+	// such as after missed keepalive pings
+	done := make(chan struct{})
+	defer close(done)
+	go client.pingLoop(t.opts.PingInterval, done)
+
 	for {
 		// Read message
-		messageType, message, err := conn.ReadMessage()
+		messageType, reader, err := conn.NextReader()
+		var message []byte
+		if err == nil {
+			message, err = readLimited(reader, t.opts.MaxMessageSize)
+		}
+		if err == nil || errors.Is(err, ErrMessageTooLarge) {
+			if deadlineErr := extendDeadline(); deadlineErr != nil {
+				err = deadlineErr
+			}
+		}
+		if errors.Is(err, ErrMessageTooLarge) {
+			client.writeError(nil, protocol.InvalidRequest, "Message too large", err)
+			continue
+		}
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				fmt.Printf("Error reading message: %v\n", err)
+				client.logger.Error("failed to read message", "error", err)
 			}
 			return
 		}
@@ -107,6 +206,8 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 		if messageType != websocket.TextMessage {
 			continue
 		}
+		t.metrics.messagesIn.Add(1)
+		tapFrame(t.opts.WireTap, wireIn, client.session.ID(), message)
 
 		// Parse the message
 		var msg struct {
@@ -114,14 +215,24 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 			ID      *protocol.RequestID `json:"id,omitempty"`
 			Method  string              `json:"method"`
 			Params  json.RawMessage     `json:"params,omitempty"`
+			Result  json.RawMessage     `json:"result,omitempty"`
+			Error   *protocol.ErrorData `json:"error,omitempty"`
 		}
 		if err := json.Unmarshal(message, &msg); err != nil {
-			t.writeError(conn, nil, -32700, "Parse error", err)
+			client.writeError(nil, protocol.ParseError, "Parse error", err)
 			continue
 		}
 
 		// Handle the message
-		if msg.ID != nil {
+		if msg.ID != nil && msg.Method == "" {
+			// This is a response to a request sent by the server
+			client.session.HandleResponse(&protocol.JSONRPCResponse{
+				JSONRPC: msg.JSONRPC,
+				ID:      *msg.ID,
+				Result:  msg.Result,
+				Error:   msg.Error,
+			})
+		} else if msg.ID != nil {
 			// This is a request
 			req := &protocol.JSONRPCRequest{
 				JSONRPC: msg.JSONRPC,
@@ -129,7 +240,9 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 				Method:  msg.Method,
 				Params:  msg.Params,
 			}
-			t.handleRequest(conn, req)
+			// Requests are handled concurrently so that notifications such
+			// as cancellations can be processed while they run
+			go client.handleRequest(ctx, req, client.order.ticket())
 		} else {
 			// This is a notification
 			notif := &protocol.JSONRPCNotification{
@@ -137,39 +250,76 @@ func (t *WebSocketTransport) handleWebSocket(w http.ResponseWriter, r *http.Requ
 				Method:  msg.Method,
 				Params:  msg.Params,
 			}
-			t.handleNotification(conn, notif)
+			client.handleNotification(notif)
 		}
 	}
 }
 
-// handleRequest processes a request and writes the response
-func (t *WebSocketTransport) handleRequest(conn *websocket.Conn, req *protocol.JSONRPCRequest) {
-	resp, err := t.session.HandleRequest(req)
-	if err != nil {
-		t.writeErrorWithID(conn, req.ID, -32603, "Internal error", err)
-		return
+// pingLoop pings the client every interval until done is closed, closing
+// the connection when a ping cannot be written or the session closes
+func (c *wsClient) pingLoop(interval time.Duration, done <-chan struct{}) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
 	}
 
-	t.writeResponse(conn, resp)
+	for {
+		select {
+		case <-tick:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, c.deadline()); err != nil {
+				c.conn.Close()
+				return
+			}
+		case <-c.session.Done():
+			c.conn.Close()
+			return
+		case <-done:
+			return
+		}
+	}
 }
 
-// handleNotification processes a notification
-func (t *WebSocketTransport) handleNotification(conn *websocket.Conn, notif *protocol.JSONRPCNotification) {
-	if err := t.session.HandleNotification(notif); err != nil {
-		// Log the error but don't send a response for notifications
-		fmt.Printf("Error handling notification: %v\n", err)
+// deadline returns the deadline for a write starting now, or the zero time
+// when writes have no timeout
+func (c *wsClient) deadline() time.Time {
+	if c.writeTimeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(c.writeTimeout)
+}
+
+// handleRequest processes a request and writes the response in the turn
+// of its ticket
+func (c *wsClient) handleRequest(ctx context.Context, req *protocol.JSONRPCRequest, ticket uint64) {
+	resp, err := c.metrics.handleRequest(ctx, c.session, req)
+	switch {
+	case errors.Is(err, server.ErrRequestCancelled):
+		c.order.deliver(ticket, nil)
+	case err != nil:
+		c.order.deliver(ticket, func() {
+			c.writeError(&req.ID, protocol.InternalError, "Internal error", err)
+		})
+	default:
+		c.order.deliver(ticket, func() {
+			if err := c.writeJSON(resp); err != nil {
+				c.logger.Error("failed to write response", "error", err)
+			}
+		})
 	}
 }
 
-// writeResponse writes a JSON-RPC response to the WebSocket connection
-func (t *WebSocketTransport) writeResponse(conn *websocket.Conn, resp *protocol.JSONRPCResponse) {
-	if err := conn.WriteJSON(resp); err != nil {
-		fmt.Printf("Error writing response: %v\n", err)
+// handleNotification processes a notification
+func (c *wsClient) handleNotification(notif *protocol.JSONRPCNotification) {
+	if err := c.session.HandleNotification(notif); err != nil {
+		// Log the error but don't send a response for notifications
+		c.logger.Error("failed to handle notification", "method", notif.Method, "error", err)
 	}
 }
 
-// writeError writes a JSON-RPC error response with no ID
-func (t *WebSocketTransport) writeError(conn *websocket.Conn, id *protocol.RequestID, code int, message string, err error) {
+// writeError writes a JSON-RPC error response, with a null ID when id is nil
+func (c *wsClient) writeError(id *protocol.RequestID, code int, message string, err error) {
 	errResp := &protocol.JSONRPCError{
 		JSONRPC: "2.0",
 		Error: protocol.ErrorData{
@@ -183,46 +333,97 @@ func (t *WebSocketTransport) writeError(conn *websocket.Conn, id *protocol.Reque
 		errResp.ID = *id
 	}
 
-	if err := conn.WriteJSON(errResp); err != nil {
-		fmt.Printf("Error writing error response: %v\n", err)
+	if err := c.writeJSON(errResp); err != nil {
+		c.logger.Error("failed to write error response", "error", err)
 	}
 }
 
-// writeErrorWithID writes a JSON-RPC error response with a specific ID
-func (t *WebSocketTransport) writeErrorWithID(conn *websocket.Conn, id protocol.RequestID, code int, message string, err error) {
-	errResp := &protocol.JSONRPCError{
-		JSONRPC: "2.0",
-		ID:      id,
-		Error: protocol.ErrorData{
-			Code:    code,
-			Message: message,
-			Data:    err.Error(),
-		},
+// writeJSON writes a message to the connection, serializing concurrent writers
+func (c *wsClient) writeJSON(v interface{}) error {
+	b := getEncodeBuffer()
+	defer b.release()
+	if err := b.encode(v); err != nil {
+		return err
 	}
+	return c.writeMessage(b.Bytes())
+}
+
+// writeMessage writes an encoded message to the connection
+func (c *wsClient) writeMessage(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
 
-	if err := conn.WriteJSON(errResp); err != nil {
-		fmt.Printf("Error writing error response: %v\n", err)
+	if err := c.conn.SetWriteDeadline(c.deadline()); err != nil {
+		return err
+	}
+	if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return err
 	}
+	c.metrics.messagesOut.Add(1)
+	tapFrame(c.tap, wireOut, c.session.ID(), data)
+	return nil
 }
 
-// SendNotification sends a notification to all connected clients
+// connections returns the connected clients
+func (t *WebSocketTransport) connections() []*wsClient {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	clients := make([]*wsClient, 0, len(t.clients))
+	for client := range t.clients {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// SendNotification sends a notification to all connected clients. Writes
+// happen outside the transport's lock, each bounded by the write timeout,
+// so a client that stops reading delays neither other clients nor
+// connections coming and going.
 func (t *WebSocketTransport) SendNotification(method string, params interface{}) error {
-	notif := &protocol.JSONRPCNotification{
+	// Encode once for all clients
+	b := getEncodeBuffer()
+	defer b.release()
+	if err := b.encode(&protocol.JSONRPCNotification{
 		JSONRPC: "2.0",
 		Method:  method,
 		Params:  params,
+	}); err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-
 	var lastErr error
-	for _, conn := range t.clients {
-		if err := conn.WriteJSON(notif); err != nil {
+	for _, client := range t.connections() {
+		if err := client.writeMessage(b.Bytes()); err != nil {
 			lastErr = err
-			fmt.Printf("Error sending notification to client: %v\n", err)
+			t.metrics.dropped.Add(1)
+			client.logger.Error("failed to send notification", "method", method, "error", err)
 		}
 	}
 
 	return lastErr
 }
+
+// SendNotification sends a notification to the client
+func (c *wsClient) SendNotification(method string, params interface{}) error {
+	return c.writeJSON(&protocol.JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+}
+
+// SendRequest sends a server-initiated request to the client
+func (c *wsClient) SendRequest(req *protocol.JSONRPCRequest) error {
+	return c.writeJSON(req)
+}
+
+// ConnectionStats reports the transport's open WebSocket connections
+func (t *WebSocketTransport) ConnectionStats() ConnectionStats {
+	return t.limiter.stats()
+}
+
+// Stats returns a snapshot of the transport's metrics
+func (t *WebSocketTransport) Stats() Stats {
+	return t.metrics.stats()
+}
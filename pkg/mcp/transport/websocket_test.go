@@ -0,0 +1,139 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+	"github.com/gorilla/websocket"
+)
+
+// serveWebSocket serves a WebSocket transport for sessions and returns its
+// WebSocket URL
+func serveWebSocket(t *testing.T, sessions SessionFactory, options ...Option) (*WebSocketTransport, string) {
+	t.Helper()
+
+	tr := NewWebSocketTransport(sessions, append([]Option{WithLogger(discardLogger)}, options...)...).(*WebSocketTransport)
+	ts := httptest.NewServer(tr.mux())
+	t.Cleanup(ts.Close)
+	return tr, "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+}
+
+func TestWebSocketKeepalive(t *testing.T) {
+	srv := server.NewServer("keepalive", server.WithLogger(discardLogger))
+	tr, url := serveWebSocket(t, srv, WithPingInterval(20*time.Millisecond), WithPongWait(100*time.Millisecond))
+
+	// Clients answering pings stay connected past the pong wait
+	responsive, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer responsive.Close()
+	pings := make(chan struct{}, 100)
+	responsive.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return responsive.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	messages := make(chan []byte, 1)
+	go func() {
+		for {
+			_, data, err := responsive.ReadMessage()
+			if err != nil {
+				close(messages)
+				return
+			}
+			messages <- data
+		}
+	}()
+
+	// Clients ignoring pings are dropped
+	unresponsive, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unresponsive.Close()
+	unresponsive.SetPingHandler(func(string) error { return nil })
+	dropped := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := unresponsive.NextReader(); err != nil {
+				dropped <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-dropped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the unresponsive client to be dropped")
+	}
+	if len(pings) == 0 {
+		t.Error("expected the server to ping")
+	}
+	if !eventually(t, func() bool { return tr.Stats().ActiveConnections == 1 }) {
+		t.Errorf("expected only the responsive client to stay connected, got %+v", tr.Stats())
+	}
+
+	// The responsive connection still serves requests
+	if err := responsive.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case data, ok := <-messages:
+		if !ok || !strings.Contains(string(data), `"id":1,"result"`) {
+			t.Errorf("expected the ping to be answered, got %s", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("expected the ping to be answered")
+	}
+}
+
+func TestWebSocketSlowClient(t *testing.T) {
+	srv := server.NewServer("slow", server.WithLogger(discardLogger))
+	tr, url := serveWebSocket(t, srv)
+
+	slow, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slow.Close()
+	if !eventually(t, func() bool { return len(tr.connections()) == 1 }) {
+		t.Fatal("client did not connect")
+	}
+
+	// Hold the client's write lock, as a write to a client that stopped
+	// reading would until its deadline
+	client := tr.connections()[0]
+	client.writeMu.Lock()
+	sent := make(chan error, 1)
+	go func() {
+		sent <- tr.SendNotification("notifications/message", map[string]interface{}{"level": "info", "data": "hello"})
+	}()
+
+	// New connections are served while the notification waits
+	c, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := c.WriteJSON(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(1), Method: "ping"}); err != nil {
+		t.Fatal(err)
+	}
+	var resp protocol.JSONRPCResponse
+	if _, data, err := c.ReadMessage(); err != nil {
+		t.Fatalf("expected a response while the notification waits, got %v", err)
+	} else if err := json.Unmarshal(data, &resp); err != nil || resp.ID != protocol.NewIntID(1) {
+		t.Errorf("unexpected response %s (%v)", data, err)
+	}
+
+	client.writeMu.Unlock()
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+}
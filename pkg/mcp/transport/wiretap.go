@@ -0,0 +1,130 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Directions of the frames recorded by a wire tap
+const (
+	wireIn  = "<-- in"
+	wireOut = "--> out"
+)
+
+// WithWireTap mirrors every JSON-RPC frame a transport receives or sends
+// to w, pretty-printed after a line with its time, direction and session,
+// for debugging hosts against the server. Transports created with the same
+// option share w safely. Frames are recorded as they cross the wire, so
+// taps slow transports down and show secrets in arguments; use them only
+// while debugging.
+func WithWireTap(w io.Writer) Option {
+	tap := &wireTap{w: w}
+	return func(o *Options) {
+		o.WireTap = joinTaps(o.WireTap, tap)
+	}
+}
+
+// frameTap is implemented by taps recording frames in their own format
+// rather than as the text of a wire tap
+type frameTap interface {
+	tapFrame(direction, session string, data []byte)
+}
+
+// taps mirrors frames to several taps, such as a wire tap and a recorder
+type taps []io.Writer
+
+// Write writes p to every tap
+func (ts taps) Write(p []byte) (int, error) {
+	for _, tap := range ts {
+		if _, err := tap.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (ts taps) tapFrame(direction, session string, data []byte) {
+	for _, tap := range ts {
+		tapData(tap, direction, session, data)
+	}
+}
+
+// joinTaps returns a tap mirroring frames to tap and then to next
+func joinTaps(tap, next io.Writer) io.Writer {
+	switch t := tap.(type) {
+	case nil:
+		return next
+	case taps:
+		return append(t[:len(t):len(t)], next)
+	default:
+		return taps{tap, next}
+	}
+}
+
+// wireTap serializes the frames recorded by concurrent connections
+type wireTap struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// Write writes p with a single write to the tapped writer
+func (t *wireTap) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.w.Write(p)
+}
+
+// tapFrame records a frame to a wire tap, if any. Frames are raw JSON or
+// values marshaled to JSON; session is empty when unknown. The frame's type
+// is a type parameter so frames aren't boxed when there is no tap.
+func tapFrame[F any](tap io.Writer, direction, session string, frame F) {
+	if tap == nil {
+		return
+	}
+
+	var data []byte
+	switch f := interface{}(frame).(type) {
+	case []byte:
+		data = f
+	case json.RawMessage:
+		data = f
+	case string:
+		data = []byte(f)
+	default:
+		var err error
+		if data, err = json.Marshal(f); err != nil {
+			data = []byte(fmt.Sprintf("%q", fmt.Sprint(f)))
+		}
+	}
+
+	tapData(tap, direction, session, data)
+}
+
+// tapData records the JSON of a frame to a tap
+func tapData(tap io.Writer, direction, session string, data []byte) {
+	if t, ok := tap.(frameTap); ok {
+		t.tapFrame(direction, session, data)
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s", time.Now().Format(time.RFC3339Nano), direction)
+	if session != "" {
+		fmt.Fprintf(&buf, " session=%s", session)
+	}
+	buf.WriteByte('\n')
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimSpace(data), "", "  "); err != nil {
+		// Record frames that aren't valid JSON as they are
+		buf.Write(bytes.TrimSpace(data))
+	} else {
+		buf.Write(indented.Bytes())
+	}
+	buf.WriteString("\n\n")
+	tap.Write(buf.Bytes())
+}
@@ -0,0 +1,174 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/client"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/server"
+)
+
+// syncBuffer is a buffer safe for a transport writing while a test reads
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// tappedFrame is a frame parsed from the output of a wire tap
+type tappedFrame struct {
+	direction string
+	session   string
+	message   struct {
+		ID     *protocol.RequestID `json:"id"`
+		Method string              `json:"method"`
+	}
+}
+
+// parseTap parses the frames recorded by a wire tap
+func parseTap(t *testing.T, output string) []tappedFrame {
+	t.Helper()
+	var frames []tappedFrame
+	for _, record := range strings.Split(strings.TrimSpace(output), "\n\n") {
+		header, body, _ := strings.Cut(record, "\n")
+		fields := strings.Fields(header)
+		if len(fields) < 3 {
+			t.Fatalf("malformed frame header %q", header)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, fields[0]); err != nil {
+			t.Errorf("expected a timestamp in %q: %v", header, err)
+		}
+		frame := tappedFrame{direction: fields[1] + " " + fields[2]}
+		if len(fields) > 3 {
+			frame.session = strings.TrimPrefix(fields[3], "session=")
+		}
+		if err := json.Unmarshal([]byte(body), &frame.message); err != nil {
+			t.Fatalf("expected a JSON frame, got %q: %v", body, err)
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+func TestWireTap(t *testing.T) {
+	srv := server.NewServer("tap", server.WithLogger(discardLogger))
+	var tap syncBuffer
+	tr, addr := serveTCP(t, srv, WithWireTap(&tap))
+
+	c, err := DialTCP(context.Background(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	c.Send(&protocol.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      protocol.NewIntID(1),
+		Method:  "initialize",
+		Params:  map[string]interface{}{"protocolVersion": "2025-03-26", "capabilities": map[string]interface{}{}, "clientInfo": map[string]interface{}{"name": "test", "version": "1"}},
+	})
+	var resp protocol.JSONRPCResponse
+	receive(t, c, &resp)
+	c.Send(&protocol.JSONRPCNotification{JSONRPC: "2.0", Method: "notifications/initialized"})
+	c.Send(&protocol.JSONRPCRequest{JSONRPC: "2.0", ID: protocol.NewIntID(2), Method: "ping"})
+	receive(t, c, &resp)
+	if err := tr.SendNotification("notifications/tools/list_changed", nil); err != nil {
+		t.Fatal(err)
+	}
+	var notif protocol.JSONRPCNotification
+	receive(t, c, &notif)
+	if err := tr.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Frames in both directions are recorded in the order they happened,
+	// each with the session they belong to
+	frames := parseTap(t, tap.String())
+	want := []struct {
+		direction string
+		id        int64
+		method    string
+	}{
+		{wireIn, 1, "initialize"},
+		{wireOut, 1, ""},
+		{wireIn, 0, "notifications/initialized"},
+		{wireIn, 2, "ping"},
+		{wireOut, 2, ""},
+		{wireOut, 0, "notifications/tools/list_changed"},
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("expected %d frames, got %d:\n%s", len(want), len(frames), tap.String())
+	}
+	for i, w := range want {
+		frame := frames[i]
+		idMatches := frame.message.ID == nil && w.id == 0 || frame.message.ID != nil && *frame.message.ID == protocol.NewIntID(w.id)
+		if frame.direction != w.direction || frame.message.Method != w.method || !idMatches {
+			t.Errorf("frame %d: expected %s %d %q, got %s %v %q", i, w.direction, w.id, w.method, frame.direction, frame.message.ID, frame.message.Method)
+		}
+		if frame.session == "" || frame.session != frames[0].session {
+			t.Errorf("frame %d: expected session %q, got %q", i, frames[0].session, frame.session)
+		}
+	}
+}
+
+func TestWireTapSessions(t *testing.T) {
+	tests := map[string]func(t *testing.T, srv *server.Server, tap Option) (conn client.Conn, stop func()){
+		"nats": func(t *testing.T, srv *server.Server, tap Option) (client.Conn, func()) {
+			tr, bus := startNATS(t, srv, tap)
+			return newNATSClientConn(bus, "mcp", "subject-token"), func() { tr.Stop(context.Background()) }
+		},
+		"streamable": func(t *testing.T, srv *server.Server, tap Option) (client.Conn, func()) {
+			ts := httptest.NewServer(NewStreamableHTTPTransport(srv, WithLogger(discardLogger), tap).(*StreamableHTTPTransport))
+			return client.NewStreamableHTTPConn(ts.URL, nil, nil), ts.Close
+		},
+	}
+	for name, serve := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := server.NewServer("tap", server.WithLogger(discardLogger))
+			var tap syncBuffer
+			conn, stop := serve(t, srv, WithWireTap(&tap))
+
+			ctx := context.Background()
+			c, err := client.Connect(ctx, conn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Ping(ctx); err != nil {
+				t.Fatal(err)
+			}
+			c.Close()
+			stop()
+
+			// Both directions of the conversation, initialize included, are
+			// recorded under the session's ID
+			frames := parseTap(t, tap.String())
+			if len(frames) < 5 {
+				t.Fatalf("expected the initialize and ping exchanges, got:\n%s", tap.String())
+			}
+			for i, frame := range frames {
+				if frame.session == "" || frame.session == "subject-token" || frame.session != frames[0].session {
+					t.Errorf("frame %d (%s %q): expected the session ID %q, got %q", i, frame.direction, frame.message.Method, frames[0].session, frame.session)
+				}
+			}
+		})
+	}
+}
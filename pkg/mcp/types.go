@@ -3,6 +3,8 @@ package mcp
 import (
 	"context"
 	"image"
+
+	"github.com/SetiabudiResearch/mcp-go-sdk/pkg/mcp/protocol"
 )
 
 // Server represents an MCP server instance
@@ -78,6 +80,11 @@ func NewImage(img image.Image, format string) *Image {
 	}
 }
 
+// ImageContent encodes the image as base64 protocol image content
+func (i *Image) ImageContent() (protocol.ImageContent, error) {
+	return protocol.NewImageContentFromImage(i.Data, i.Format)
+}
+
 // WithDependencies configures the server with additional dependencies
 func WithDependencies(deps []string) ServerOption {
 	return func(s *Server) {